@@ -3,15 +3,30 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/api"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/api/handler"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/api/middleware"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/api/rpc"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/auth"
 	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/config"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/health"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/metrics"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/notify"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/ratelimit"
 	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/repository/postgres"
+	internalservice "github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/service"
 	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/service/subscription"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/tracing"
 	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/logger"
 
 	// Import generated docs
@@ -34,6 +49,11 @@ import (
 // @BasePath /
 // @schemes http https
 
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description JWT in the form "Bearer <token>", the sub claim is the caller's user ID
+
 func main() {
 	cfg, err := config.Load()
 	if err != nil {
@@ -43,6 +63,30 @@ func main() {
 
 	logger.Init(cfg.App.LogLevel)
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err := runMigrate(context.Background(), cfg.DB, os.Args[2:])
+		if errors.Is(err, errUsage) {
+			fmt.Fprintln(os.Stderr, migrateUsage)
+			os.Exit(2)
+		}
+		if err != nil {
+			slog.Error("migration failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.Tracing)
+	if err != nil {
+		slog.Error("failed to initialize tracing", "error", err)
+		os.Exit(1)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			slog.Error("failed to flush traces", "error", err)
+		}
+	}()
+
 	db, err := postgres.New(context.Background(), cfg.DB)
 	if err != nil {
 		slog.Error("failed to initialize repository", "error", err)
@@ -50,12 +94,99 @@ func main() {
 	}
 	defer db.Close()
 
-	service := subscription.NewService(&db)
-	router := api.NewRouter(service)
+	var verifier *auth.Verifier
+	if cfg.Auth.JWKSFile != "" || cfg.Auth.HMACSecret != "" {
+		verifier, err = auth.NewVerifier(cfg.Auth)
+		if err != nil {
+			slog.Error("failed to initialize token verifier", "error", err)
+			os.Exit(1)
+		}
+	} else {
+		slog.Warn("authentication is disabled, set AUTH_JWKS_FILE or AUTH_HS256_SECRET to enable it")
+	}
+
+	m := metrics.New()
+	m.RegisterPool(db.Stat)
+	m.RegisterSubscriptionStats(&db)
+
+	service := tracing.Service(subscription.NewService(m.Repository(&db)))
+	limiters := ratelimit.NewLimiters(cfg.App.RateLimit, cfg.App.RateLimitRoutes, cfg.App.RateLimitMaxClients)
+	router := api.NewRouter(service, verifier, limiters, cfg.App, cfg.GraphQL)
+
+	schemaVersion, err := postgres.LatestMigrationVersion()
+	if err != nil {
+		slog.Error("failed to read migrations", "error", err)
+		os.Exit(1)
+	}
+	checker := health.NewChecker()
+	checker.Add("database", db.Ping)
+	checker.Add("migrations", db.CheckSchema(schemaVersion))
+	healthHandler := handler.NewHealthHandler(checker)
+	router.HandleFunc("GET /healthz", healthHandler.Liveness)
+	router.HandleFunc("GET /readyz", healthHandler.Readiness)
+
+	// Watch streams end when the watcher stops, so it is stopped before the gRPC server shuts down
+	watchCtx, stopWatcher := context.WithCancel(context.Background())
+	defer stopWatcher()
+	watcher := subscription.NewWatcher(&db)
+	go watcher.Run(watchCtx)
+
+	remindersCtx, stopReminders := context.WithCancel(context.Background())
+	defer stopReminders()
+	if cfg.Reminders.Enabled {
+		notifiers := map[string]internalservice.Notifier{
+			models.ChannelLog:     notify.NewLog(),
+			models.ChannelWebhook: notify.NewWebhook(cfg.Reminders.WebhookSecret, cfg.Reminders.WebhookTimeout),
+		}
+		if cfg.SMTP.Host != "" {
+			notifiers[models.ChannelEmail] = notify.NewSMTP(cfg.SMTP)
+		} else {
+			slog.Warn("email reminders are disabled, set SMTP_HOST to enable them")
+		}
+		go subscription.NewReminders(&db, notifiers, cfg.Reminders.Interval).Run(remindersCtx)
+	}
+
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+	if cfg.Scheduler.Enabled {
+		go subscription.NewScheduler(&db, cfg.Scheduler.Interval).Run(schedulerCtx)
+	}
+
+	var grpcServer *rpc.Server
+	if cfg.GRPC.Address != "" {
+		lis, err := net.Listen("tcp", cfg.GRPC.Address)
+		if err != nil {
+			slog.Error("failed to listen for gRPC", "error", err)
+			os.Exit(1)
+		}
+		grpcServer = rpc.NewServer(service, watcher, verifier, limiters, cfg.App, cfg.GRPC)
+		go func() {
+			slog.Info("starting gRPC server", "port", cfg.GRPC.Address)
+			if err := grpcServer.Serve(lis); err != nil {
+				slog.Error("gRPC server error", "error", err)
+				os.Exit(1)
+			}
+		}()
+	}
 
 	server := &http.Server{
 		Addr:    cfg.App.Address,
-		Handler: router,
+		Handler: middleware.Tracing()(middleware.Logging()(middleware.Metrics(m)(router))),
+	}
+
+	// Metrics are served on a separate internal listener, so they are not reachable through the public API port
+	var metricsServer *http.Server
+	if cfg.App.MetricsAddress != "" {
+		metricsMux := http.NewServeMux()
+		metricsMux.Handle("GET /metrics", m.Handler())
+		metricsServer = &http.Server{Addr: cfg.App.MetricsAddress, Handler: metricsMux}
+		go func() {
+			slog.Info("starting metrics server", "port", cfg.App.MetricsAddress)
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				slog.Error("metrics server error", "error", err)
+				os.Exit(1)
+			}
+		}()
 	}
 
 	// Create a channel to listen for interrupt signals
@@ -75,11 +206,32 @@ func main() {
 	sig := <-sigChan
 	slog.Info("received signal, shutting down gracefully with "+cfg.App.ShutdownTimeout.String()+" seconds deadline", "signal", sig)
 
+	// Fail readiness first so that the orchestrator stops routing new traffic here
+	checker.SetDraining()
+	if grpcServer != nil {
+		grpcServer.SetDraining()
+	}
+	slog.Info("draining before shutdown", "delay", cfg.App.DrainDelay.String())
+	time.Sleep(cfg.App.DrainDelay)
+
 	// Create a context with timeout for shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.App.ShutdownTimeout)
 	defer cancel()
 
 	// Attempt graceful shutdown
+	stopWatcher()
+	stopReminders()
+	stopScheduler()
+	if grpcServer != nil {
+		if err := grpcServer.Shutdown(ctx); err != nil {
+			slog.Error("gRPC server forced to shutdown", "error", err)
+		}
+	}
+	if metricsServer != nil {
+		if err := metricsServer.Shutdown(ctx); err != nil {
+			slog.Error("metrics server forced to shutdown", "error", err)
+		}
+	}
 	if err := server.Shutdown(ctx); err != nil {
 		slog.Error("server forced to shutdown", "error", err)
 		os.Exit(1)
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/config"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/repository/postgres"
+)
+
+const migrateUsage = `usage: server migrate <command>
+
+commands:
+  up             apply all pending migrations
+  down [N]       roll back N migrations (default 1)
+  status         print the applied and the latest schema version
+  force VERSION  set the schema version without migrating and clear the dirty flag`
+
+var errUsage = errors.New("invalid migrate command")
+
+// runMigrate handles "server migrate ..." subcommands
+func runMigrate(ctx context.Context, cfg config.DBConfig, args []string) error {
+	if len(args) == 0 || !slices.Contains([]string{"up", "down", "status", "force"}, args[0]) {
+		return errUsage
+	}
+	if args[0] == "force" && len(args) < 2 {
+		return errUsage
+	}
+
+	migrator, err := postgres.NewMigrator(cfg)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	switch args[0] {
+	case "up":
+		return migrator.Up(ctx)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+		}
+		return migrator.Down(ctx, steps)
+	case "status":
+		version, dirty, err := migrator.Version()
+		if err != nil {
+			return fmt.Errorf("failed to read schema version: %w", err)
+		}
+		latest, err := postgres.LatestMigrationVersion()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("version: %d\ndirty: %t\nlatest: %d\n", version, dirty, latest)
+		return nil
+	case "force":
+		version, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		return migrator.Force(ctx, version)
+	default:
+		return errUsage
+	}
+}
@@ -0,0 +1,849 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/validation"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/monthyear"
+)
+
+var validator = validation.New()
+
+func (a *app) create(ctx context.Context, args []string) error {
+	var req models.CreateSubscriptionRequest
+	fs := newFlagSet("create", "-service NAME|-service-id ID|-plan ID [-price N] -user ID -start MM-YYYY [-end MM-YYYY] [-billing-day D] [-trial-months N -trial-price N] [-category C] [-tag T]... [-account LABEL] [-payment-method ID]")
+	fs.StringVar(&req.ServiceName, "service", "", "service name, matched to the catalog by name or alias")
+	fs.Func("service-id", "catalog service ID", func(s string) error {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return err
+		}
+		req.ServiceID = &id
+		return nil
+	})
+	fs.Func("plan", "catalog plan ID, the price defaults to its monthly price", func(s string) error {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return err
+		}
+		req.PlanID = &id
+		return nil
+	})
+	fs.Func("price", "monthly price in rubles, 0 for a free subscription (defaults to the price of the plan)", func(s string) error {
+		price, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+		req.Price = &price
+		return nil
+	})
+	fs.Var(uuidValue{&req.UserID}, "user", "user ID")
+	fs.Var(monthValue{&req.StartDate}, "start", "start month, MM-YYYY")
+	fs.Var(monthValue{&req.EndDate}, "end", "end month, MM-YYYY (optional)")
+	fs.IntVar(&req.BillingDay, "billing-day", 0, "day of month charges happen on, 1-31 (defaults to 1)")
+	fs.IntVar(&req.TrialMonths, "trial-months", 0, "trial months from the start month")
+	fs.IntVar(&req.TrialPrice, "trial-price", 0, "monthly price in rubles during the trial")
+	fs.StringVar(&req.Category, "category", "", "category (defaults to the category of the catalog service)")
+	fs.StringVar(&req.AccountLabel, "account", "", "account label, to tell several accounts of the service apart")
+	fs.Var(tagsValue{&req.Tags}, "tag", "tag; repeatable")
+	fs.Func("payment-method", "payment method ID of the user the subscription is charged to", func(s string) error {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return err
+		}
+		req.PaymentMethodID = &id
+		return nil
+	})
+	if err := parse(fs, args, 0); err != nil {
+		return err
+	}
+	if err := validator.Struct(&req); err != nil {
+		return err
+	}
+
+	sub, err := a.client.CreateSubscription(ctx, req)
+	if err != nil {
+		return err
+	}
+	return a.printSubscriptions([]models.SubscriptionResponse{sub})
+}
+
+func (a *app) get(ctx context.Context, args []string) error {
+	fs := newFlagSet("get", "ID")
+	id, err := parseID(fs, args)
+	if err != nil {
+		return err
+	}
+
+	sub, err := a.client.GetSubscription(ctx, id)
+	if err != nil {
+		return err
+	}
+	return a.printSubscriptions([]models.SubscriptionResponse{sub})
+}
+
+func (a *app) list(ctx context.Context, args []string) error {
+	req := models.ListSubscriptionsRequest{}
+	var (
+		cursor models.SubscriptionCursor
+		after  *monthyear.MonthYear
+		all    bool
+	)
+	fs := newFlagSet("list", "[-limit N] [-after-id ID -after-start MM-YYYY] [-all] [-tag T]...")
+	fs.IntVar(&req.Limit, "limit", 30, "page size")
+	fs.Var(uuidValue{&cursor.ID}, "after-id", "ID of the last subscription of the previous page")
+	fs.Var(monthValue{&after}, "after-start", "start month of the last subscription of the previous page")
+	fs.BoolVar(&all, "all", false, "follow pages until the last one")
+	fs.Var(tagsValue{&req.Tags}, "tag", "only subscriptions having this tag; repeatable, all must match")
+	if err := parse(fs, args, 0); err != nil {
+		return err
+	}
+	if (cursor.ID == uuid.Nil) != (after == nil) {
+		return errors.New("-after-id and -after-start must be set together")
+	}
+	if after != nil {
+		cursor.StartDate = *after
+		req.Cursor = &cursor
+	}
+
+	var (
+		subs []models.SubscriptionResponse
+		err  error
+	)
+	if all {
+		subs, err = a.client.ListAllSubscriptions(ctx, req.Limit, req.Tags...)
+	} else {
+		subs, err = a.client.ListSubscriptions(ctx, req)
+	}
+	if err != nil {
+		return err
+	}
+	return a.printSubscriptions(subs)
+}
+
+func (a *app) update(ctx context.Context, args []string) error {
+	var req models.UpdateSubscriptionRequest
+	fs := newFlagSet("update", "ID [-service NAME] [-plan ID] [-price N] [-end MM-YYYY] [-billing-day D] [-trial-months N] [-trial-price N] [-category C] [-add-tag T]... [-remove-tag T]... [-account LABEL] [-payment-method ID]")
+	fs.Func("service", "new service name", func(s string) error {
+		req.ServiceName = &s
+		return nil
+	})
+	fs.Func("plan", "plan from the start month, the price defaults to its monthly price", func(s string) error {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return err
+		}
+		req.PlanID = &id
+		return nil
+	})
+	fs.Func("price", "new monthly price in rubles", func(s string) error {
+		price, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+		req.Price = &price
+		return nil
+	})
+	fs.Var(monthValue{&req.EndDate}, "end", "new end month, MM-YYYY")
+	fs.Func("billing-day", "new day of month charges happen on, 1-31", func(s string) error {
+		day, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+		req.BillingDay = &day
+		return nil
+	})
+	fs.Func("trial-months", "new trial length in months", func(s string) error {
+		months, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+		req.TrialMonths = &months
+		return nil
+	})
+	fs.Func("trial-price", "new monthly price in rubles during the trial", func(s string) error {
+		price, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+		req.TrialPrice = &price
+		return nil
+	})
+	fs.Func("category", "new category, empty to clear it", func(s string) error {
+		req.Category = &s
+		return nil
+	})
+	fs.Func("account", "new account label, empty to clear it", func(s string) error {
+		req.AccountLabel = &s
+		return nil
+	})
+	fs.Var(tagsValue{&req.AddTags}, "add-tag", "tag to add; repeatable")
+	fs.Var(tagsValue{&req.RemoveTags}, "remove-tag", "tag to remove; repeatable")
+	fs.Func("payment-method", "new payment method ID, empty to unlink the subscription", func(s string) error {
+		id := uuid.Nil
+		if s != "" {
+			var err error
+			if id, err = uuid.Parse(s); err != nil {
+				return err
+			}
+		}
+		req.PaymentMethodID = &id
+		return nil
+	})
+	id, err := parseID(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := validator.Struct(&req); err != nil {
+		return err
+	}
+
+	sub, err := a.client.UpdateSubscription(ctx, id, req)
+	if err != nil {
+		return err
+	}
+	return a.printSubscriptions([]models.SubscriptionResponse{sub})
+}
+
+func (a *app) pause(ctx context.Context, args []string) error {
+	var req models.PauseSubscriptionRequest
+	fs := newFlagSet("pause", "ID [-start MM-YYYY] [-end MM-YYYY]")
+	fs.Var(monthValue{&req.StartDate}, "start", "first paused month, MM-YYYY (defaults to the current month)")
+	fs.Var(monthValue{&req.EndDate}, "end", "last paused month, MM-YYYY (defaults to until resumed)")
+	id, err := parseID(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := validator.Struct(&req); err != nil {
+		return err
+	}
+
+	sub, err := a.client.PauseSubscription(ctx, id, req)
+	if err != nil {
+		return err
+	}
+	return a.printSubscriptions([]models.SubscriptionResponse{sub})
+}
+
+func (a *app) resume(ctx context.Context, args []string) error {
+	var req models.ResumeSubscriptionRequest
+	fs := newFlagSet("resume", "ID [-month MM-YYYY]")
+	fs.Var(monthValue{&req.Month}, "month", "first month billed again, MM-YYYY (defaults to the current month)")
+	id, err := parseID(fs, args)
+	if err != nil {
+		return err
+	}
+
+	sub, err := a.client.ResumeSubscription(ctx, id, req)
+	if err != nil {
+		return err
+	}
+	return a.printSubscriptions([]models.SubscriptionResponse{sub})
+}
+
+func (a *app) changePlan(ctx context.Context, args []string) error {
+	var req models.ChangePlanRequest
+	fs := newFlagSet("change-plan", "ID -plan ID [-price N] [-start MM-YYYY]")
+	fs.Var(uuidValue{&req.PlanID}, "plan", "new plan ID")
+	fs.Func("price", "monthly price in rubles (defaults to the monthly price of the plan)", func(s string) error {
+		price, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+		req.Price = &price
+		return nil
+	})
+	fs.Var(monthValue{&req.StartDate}, "start", "first month of the new plan, MM-YYYY (defaults to the current month)")
+	id, err := parseID(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := validator.Struct(&req); err != nil {
+		return err
+	}
+
+	sub, err := a.client.ChangeSubscriptionPlan(ctx, id, req)
+	if err != nil {
+		return err
+	}
+	return a.printSubscriptions([]models.SubscriptionResponse{sub})
+}
+
+func (a *app) planChanges(ctx context.Context, args []string) error {
+	fs := newFlagSet("plan-changes", "ID")
+	id, err := parseID(fs, args)
+	if err != nil {
+		return err
+	}
+
+	changes, err := a.client.ListPlanChanges(ctx, id)
+	if err != nil {
+		return err
+	}
+	return a.printPlanChanges(changes)
+}
+
+func (a *app) schedule(ctx context.Context, args []string) error {
+	var (
+		req          models.ScheduleChangeRequest
+		cancel, from *monthyear.MonthYear
+	)
+	fs := newFlagSet("schedule", "ID -cancel MM-YYYY | -price N -from MM-YYYY")
+	fs.Var(monthValue{&cancel}, "cancel", "last billed month, MM-YYYY; the subscription ends after it")
+	fs.Func("price", "new monthly price in rubles", func(s string) error {
+		price, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+		req.Price = &price
+		return nil
+	})
+	fs.Var(monthValue{&from}, "from", "first month at the new price, MM-YYYY")
+	id, err := parseID(fs, args)
+	if err != nil {
+		return err
+	}
+	switch {
+	case cancel != nil && req.Price == nil && from == nil:
+		req.Action, req.Month = models.ScheduledCancel, cancel
+	case cancel == nil && req.Price != nil:
+		req.Action, req.Month = models.ScheduledPrice, from
+	default:
+		fs.Usage()
+		return errUsage
+	}
+	if err := validator.Struct(&req); err != nil {
+		return err
+	}
+
+	change, err := a.client.ScheduleChange(ctx, id, req)
+	if err != nil {
+		return err
+	}
+	return a.printScheduledChanges([]models.ScheduledChangeResponse{change})
+}
+
+func (a *app) scheduled(ctx context.Context, args []string) error {
+	fs := newFlagSet("scheduled", "ID")
+	id, err := parseID(fs, args)
+	if err != nil {
+		return err
+	}
+
+	changes, err := a.client.ListScheduledChanges(ctx, id)
+	if err != nil {
+		return err
+	}
+	return a.printScheduledChanges(changes)
+}
+
+func (a *app) unschedule(ctx context.Context, args []string) error {
+	var changeID uuid.UUID
+	fs := newFlagSet("unschedule", "ID -change CHANGE_ID")
+	fs.Var(uuidValue{&changeID}, "change", "scheduled change ID")
+	id, err := parseID(fs, args)
+	if err != nil {
+		return err
+	}
+	if changeID == uuid.Nil {
+		fs.Usage()
+		return errUsage
+	}
+	return a.client.CancelScheduledChange(ctx, id, changeID)
+}
+
+func (a *app) members(ctx context.Context, args []string) error {
+	fs := newFlagSet("members", "ID")
+	id, err := parseID(fs, args)
+	if err != nil {
+		return err
+	}
+
+	members, err := a.client.GetMembers(ctx, id)
+	if err != nil {
+		return err
+	}
+	return a.printMembers(members)
+}
+
+func (a *app) setMembers(ctx context.Context, args []string) error {
+	req := models.SetMembersRequest{SplitRule: models.SplitEqual, Members: []models.SubscriptionMemberRequest{}}
+	fs := newFlagSet("set-members", "ID [-rule equal|percent|fixed] [-member USER[:SHARE]]...")
+	fs.StringVar(&req.SplitRule, "rule", req.SplitRule, "split rule: equal, percent or fixed")
+	fs.Func("member", "member user ID with a percent or a monthly amount in rubles after a colon; repeatable, none stops sharing", func(s string) error {
+		userID, share, _ := strings.Cut(s, ":")
+		member := models.SubscriptionMemberRequest{}
+		if err := (uuidValue{&member.UserID}).Set(userID); err != nil {
+			return err
+		}
+		if share != "" {
+			n, err := strconv.Atoi(share)
+			if err != nil {
+				return err
+			}
+			member.Share = n
+		}
+		req.Members = append(req.Members, member)
+		return nil
+	})
+	id, err := parseID(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := validator.Struct(&req); err != nil {
+		return err
+	}
+
+	members, err := a.client.SetMembers(ctx, id, req)
+	if err != nil {
+		return err
+	}
+	return a.printMembers(members)
+}
+
+func (a *app) delete(ctx context.Context, args []string) error {
+	fs := newFlagSet("delete", "ID")
+	id, err := parseID(fs, args)
+	if err != nil {
+		return err
+	}
+	return a.client.DeleteSubscription(ctx, id)
+}
+
+func (a *app) totalCost(ctx context.Context, args []string) error {
+	var req models.TotalCostRequest
+	fs := newFlagSet("total-cost", "[-user ID] [-service NAME] [-service-id ID] [-start MM-YYYY] [-end MM-YYYY] [-tag T]... [-category C]")
+	costFlags(fs, &req)
+	if err := parse(fs, args, 0); err != nil {
+		return err
+	}
+	if err := validator.Struct(&req); err != nil {
+		return err
+	}
+
+	resp, err := a.client.GetTotalCost(ctx, req)
+	if err != nil {
+		return err
+	}
+	return a.printTotalCost(resp)
+}
+
+func (a *app) breakdown(ctx context.Context, args []string) error {
+	var req models.CostBreakdownRequest
+	fs := newFlagSet("breakdown", "[-by service|user|month|category|tag|payment_method] [-user ID] [-service NAME] [-service-id ID] [-start MM-YYYY] [-end MM-YYYY] [-tag T]... [-category C]")
+	fs.StringVar(&req.GroupBy, "by", "service", "grouping: service, user, month, category, tag or payment_method")
+	costFlags(fs, &req.TotalCostRequest)
+	if err := parse(fs, args, 0); err != nil {
+		return err
+	}
+	if err := validator.Struct(&req); err != nil {
+		return err
+	}
+
+	resp, err := a.client.GetCostBreakdown(ctx, req)
+	if err != nil {
+		return err
+	}
+	return a.printBreakdown(resp)
+}
+
+func (a *app) trialEnding(ctx context.Context, args []string) error {
+	var req models.TrialEndingRequest
+	fs := newFlagSet("trial-ending", "[-month MM-YYYY] [-user ID]")
+	fs.Var(monthValue{&req.Month}, "month", "first month at the regular price, MM-YYYY (defaults to the next month)")
+	fs.Func("user", "only subscriptions of this user ID", func(s string) error {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return err
+		}
+		req.UserID = &id
+		return nil
+	})
+	if err := parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	subs, err := a.client.ListTrialsEnding(ctx, req)
+	if err != nil {
+		return err
+	}
+	return a.printSubscriptions(subs)
+}
+
+func (a *app) upcoming(ctx context.Context, args []string) error {
+	req := models.UpcomingChargesRequest{Days: 7}
+	fs := newFlagSet("upcoming", "[-days N] [-user ID]")
+	fs.IntVar(&req.Days, "days", req.Days, "number of days from today")
+	fs.Func("user", "only charges of this user ID", func(s string) error {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return err
+		}
+		req.UserID = &id
+		return nil
+	})
+	if err := parse(fs, args, 0); err != nil {
+		return err
+	}
+	if err := validator.Struct(&req); err != nil {
+		return err
+	}
+
+	charges, err := a.client.ListUpcomingCharges(ctx, req)
+	if err != nil {
+		return err
+	}
+	return a.printCharges(charges)
+}
+
+func (a *app) duplicates(ctx context.Context, args []string) error {
+	var req models.DuplicatesRequest
+	fs := newFlagSet("duplicates", "[-month MM-YYYY] [-user ID]")
+	fs.Var(monthValue{&req.Month}, "month", "month to compare subscriptions billed in, MM-YYYY (defaults to the current month)")
+	fs.Func("user", "only subscriptions of this user ID", func(s string) error {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return err
+		}
+		req.UserID = &id
+		return nil
+	})
+	if err := parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	resp, err := a.client.FindDuplicates(ctx, req)
+	if err != nil {
+		return err
+	}
+	return a.printDuplicates(resp)
+}
+
+func (a *app) reminders(ctx context.Context, args []string) error {
+	fs := newFlagSet("reminders", "USER_ID")
+	userID, err := parseID(fs, args)
+	if err != nil {
+		return err
+	}
+
+	prefs, err := a.client.GetNotificationPreferences(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return a.printNotificationPreferences(prefs)
+}
+
+func (a *app) setReminders(ctx context.Context, args []string) error {
+	req := models.NotificationPreferencesRequest{DaysBefore: 3, Channel: models.ChannelLog}
+	fs := newFlagSet("set-reminders", "USER_ID [-channel log|email|webhook] [-email ADDRESS] [-webhook URL] [-days N] [-disable]")
+	fs.StringVar(&req.Channel, "channel", req.Channel, "channel: log, email or webhook")
+	fs.StringVar(&req.Email, "email", "", "address of the email channel")
+	fs.StringVar(&req.WebhookURL, "webhook", "", "URL of the webhook channel")
+	fs.IntVar(&req.DaysBefore, "days", req.DaysBefore, "days before a billing day or the end of a subscription to remind")
+	disable := fs.Bool("disable", false, "stop sending reminders")
+	userID, err := parseID(fs, args)
+	if err != nil {
+		return err
+	}
+	enabled := !*disable
+	req.Enabled = &enabled
+	if err := validator.Struct(&req); err != nil {
+		return err
+	}
+
+	prefs, err := a.client.SetNotificationPreferences(ctx, userID, req)
+	if err != nil {
+		return err
+	}
+	return a.printNotificationPreferences(prefs)
+}
+
+func (a *app) payments(ctx context.Context, args []string) error {
+	fs := newFlagSet("payments", "USER_ID")
+	userID, err := parseID(fs, args)
+	if err != nil {
+		return err
+	}
+
+	methods, err := a.client.ListPaymentMethods(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return a.printPaymentMethods(methods)
+}
+
+func (a *app) addPayment(ctx context.Context, args []string) error {
+	var (
+		req  models.PaymentMethodRequest
+		bank bool
+	)
+	fs := newFlagSet("add-payment", "USER_ID -card LAST4 -expires MM-YYYY [-brand B] [-label L] | -bank -label L")
+	fs.StringVar(&req.Last4, "card", "", "last four digits of the card")
+	fs.Var(monthValue{&req.Expires}, "expires", "expiry month of the card, MM-YYYY")
+	fs.StringVar(&req.Brand, "brand", "", "card brand, e.g. Visa")
+	fs.BoolVar(&bank, "bank", false, "a bank account instead of a card")
+	fs.StringVar(&req.Label, "label", "", "name of the payment method, required for a bank account")
+	userID, err := parseID(fs, args)
+	if err != nil {
+		return err
+	}
+	req.Kind = models.PaymentCard
+	if bank {
+		req.Kind = models.PaymentBank
+	}
+	if err := validator.Struct(&req); err != nil {
+		return err
+	}
+
+	method, err := a.client.CreatePaymentMethod(ctx, userID, req)
+	if err != nil {
+		return err
+	}
+	return a.printPaymentMethods([]models.PaymentMethodResponse{method})
+}
+
+func (a *app) dropPayment(ctx context.Context, args []string) error {
+	var (
+		methodID      uuid.UUID
+		replacementID uuid.UUID
+		req           models.DeletePaymentMethodRequest
+	)
+	fs := newFlagSet("drop-payment", "USER_ID -method METHOD_ID [-replacement METHOD_ID]")
+	fs.Var(uuidValue{&methodID}, "method", "payment method ID")
+	fs.Var(uuidValue{&replacementID}, "replacement", "payment method ID to move the subscriptions to (defaults to unlinking them)")
+	userID, err := parseID(fs, args)
+	if err != nil {
+		return err
+	}
+	if methodID == uuid.Nil {
+		fs.Usage()
+		return errUsage
+	}
+	if replacementID != uuid.Nil {
+		req.ReplacementID = &replacementID
+	}
+	return a.client.DeletePaymentMethod(ctx, userID, methodID, req)
+}
+
+func (a *app) expiringCards(ctx context.Context, args []string) error {
+	var req models.ExpiringCardsRequest
+	fs := newFlagSet("expiring-cards", "[-user ID]")
+	fs.Func("user", "only subscriptions of this user ID", func(s string) error {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return err
+		}
+		req.UserID = &id
+		return nil
+	})
+	if err := parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	cards, err := a.client.ListExpiringCards(ctx, req)
+	if err != nil {
+		return err
+	}
+	return a.printExpiringCards(cards)
+}
+
+func (a *app) services(ctx context.Context, args []string) error {
+	var query string
+	fs := newFlagSet("services", "[-q NAME]")
+	fs.StringVar(&query, "q", "", "only services whose name or alias contains this (partial match)")
+	if err := parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	services, err := a.client.ListServices(ctx, query)
+	if err != nil {
+		return err
+	}
+	return a.printServices(services)
+}
+
+func (a *app) addService(ctx context.Context, args []string) error {
+	var (
+		req     models.ServiceRequest
+		aliases string
+	)
+	fs := newFlagSet("add-service", "-name NAME [-aliases A,B] [-category C] [-website URL] [-plan NAME:PRICE[:year]]...")
+	fs.StringVar(&req.Name, "name", "", "canonical service name")
+	fs.StringVar(&aliases, "aliases", "", "comma separated alternative names")
+	fs.StringVar(&req.Category, "category", "", "service category")
+	fs.StringVar(&req.Website, "website", "", "service website")
+	fs.Func("plan", "plan as NAME:PRICE, or NAME:PRICE:year for a yearly price; repeatable", func(s string) error {
+		parts := strings.Split(s, ":")
+		if len(parts) < 2 || len(parts) > 3 {
+			return errors.New("expected NAME:PRICE or NAME:PRICE:PERIOD")
+		}
+		price, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return err
+		}
+		plan := models.ServicePlan{Name: parts[0], Price: price}
+		if len(parts) == 3 {
+			plan.BillingPeriod = parts[2]
+		}
+		req.Plans = append(req.Plans, plan)
+		return nil
+	})
+	if err := parse(fs, args, 0); err != nil {
+		return err
+	}
+	if aliases != "" {
+		req.Aliases = strings.Split(aliases, ",")
+	}
+	if err := validator.Struct(&req); err != nil {
+		return err
+	}
+
+	svc, err := a.client.CreateService(ctx, req)
+	if err != nil {
+		return err
+	}
+	return a.printServices([]models.ServiceResponse{svc})
+}
+
+// costFlags registers the filters shared by cost reports
+func costFlags(fs *flag.FlagSet, req *models.TotalCostRequest) {
+	fs.Func("user", "only subscriptions of this user ID", func(s string) error {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return err
+		}
+		req.UserID = &id
+		return nil
+	})
+	fs.Func("service", "only services matching this name or alias (partial match)", func(s string) error {
+		req.ServiceName = &s
+		return nil
+	})
+	fs.Func("service-id", "only subscriptions of this catalog service ID", func(s string) error {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return err
+		}
+		req.ServiceID = &id
+		return nil
+	})
+	fs.Var(monthValue{&req.StartDate}, "start", "period start, MM-YYYY")
+	fs.Var(monthValue{&req.EndDate}, "end", "period end, MM-YYYY (defaults to the current month)")
+	fs.Var(tagsValue{&req.Tags}, "tag", "only subscriptions having this tag; repeatable, all must match")
+	fs.Func("category", "only subscriptions of this category", func(s string) error {
+		req.Category = &s
+		return nil
+	})
+}
+
+func newFlagSet(name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: subctl %s %s\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse parses command flags and checks the number of positional arguments
+func parse(fs *flag.FlagSet, args []string, nargs int) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	if fs.NArg() != nargs {
+		fs.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+// parseID parses a subscription ID given before or after the command flags
+func parseID(fs *flag.FlagSet, args []string) (uuid.UUID, error) {
+	var raw string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		raw, args = args[0], args[1:]
+		if err := parse(fs, args, 0); err != nil {
+			return uuid.Nil, err
+		}
+	} else {
+		if err := parse(fs, args, 1); err != nil {
+			return uuid.Nil, err
+		}
+		raw = fs.Arg(0)
+	}
+
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid subscription ID %q: %w", raw, err)
+	}
+	return id, nil
+}
+
+// monthValue is a flag.Value for an optional MM-YYYY month
+type monthValue struct {
+	p **monthyear.MonthYear
+}
+
+func (v monthValue) String() string {
+	if v.p == nil || *v.p == nil {
+		return ""
+	}
+	return formatMonth(*v.p)
+}
+
+func (v monthValue) Set(s string) error {
+	var my monthyear.MonthYear
+	if err := my.UnmarshalJSON([]byte(s)); err != nil {
+		return err
+	}
+	*v.p = &my
+	return nil
+}
+
+// tagsValue is a repeatable flag.Value collecting tags
+type tagsValue struct {
+	p *[]string
+}
+
+func (v tagsValue) String() string {
+	if v.p == nil {
+		return ""
+	}
+	return strings.Join(*v.p, ",")
+}
+
+func (v tagsValue) Set(s string) error {
+	*v.p = append(*v.p, s)
+	return nil
+}
+
+type uuidValue struct {
+	p *uuid.UUID
+}
+
+func (v uuidValue) String() string {
+	if v.p == nil || *v.p == uuid.Nil {
+		return ""
+	}
+	return v.p.String()
+}
+
+func (v uuidValue) Set(s string) error {
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return err
+	}
+	*v.p = id
+	return nil
+}
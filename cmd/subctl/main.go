@@ -0,0 +1,171 @@
+// Command subctl manages subscriptions and builds cost reports through the HTTP API.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/client"
+)
+
+const usage = `usage: subctl [flags] <command> [command flags] [args]
+
+Commands:
+  create           create a subscription
+  get ID           show a subscription
+  list             list subscriptions
+  update ID        change service name, plan, price, end date, billing day or trial of a subscription
+  delete ID        delete a subscription
+  pause ID         pause a subscription, paused months are not billed
+  resume ID        resume a paused subscription
+  change-plan ID   move a subscription to another plan from a month
+  plan-changes ID  list plan changes of a subscription
+  schedule ID      cancel a subscription or change its price from a future month
+  scheduled ID     list scheduled changes of a subscription
+  unschedule ID    cancel a pending scheduled change
+  members ID       show how a shared subscription is split
+  set-members ID   share a subscription with other users
+  total-cost       total cost of subscriptions for a period
+  breakdown        cost of subscriptions for a period by service, user, month or payment method
+  trial-ending     subscriptions whose trial ends before a month
+  upcoming         charges in the next days
+  duplicates       subscriptions to the same service and services of one category
+  reminders ID     show reminder preferences of a user
+  set-reminders ID set when and how a user is reminded of renewals and endings
+  payments ID      list payment methods of a user
+  add-payment ID   add a card or a bank account of a user
+  drop-payment ID  delete a payment method, moving its subscriptions to a replacement
+  expiring-cards   subscriptions whose next charge comes after their card expires
+  services         list catalog services
+  add-service      add a service to the catalog
+  export           write all subscriptions as JSON or CSV
+  import           create subscriptions from a JSON or CSV file
+
+Run "subctl <command> -h" for command flags.
+
+Flags:
+`
+
+// errUsage reports invalid arguments, the usage is already printed by the flag set
+var errUsage = errors.New("invalid usage")
+
+// app holds the global flags shared by all commands
+type app struct {
+	client *client.Client
+	format string
+	stdout io.Writer
+	stderr io.Writer
+	stdin  io.Reader
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	fs := flag.NewFlagSet("subctl", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), usage)
+		fs.PrintDefaults()
+	}
+	baseURL := fs.String("url", envOr("SUBCTL_URL", "http://localhost:8080"), "API base URL (env SUBCTL_URL)")
+	token := fs.String("token", os.Getenv("SUBCTL_TOKEN"), "bearer token (env SUBCTL_TOKEN)")
+	tenant := fs.String("tenant", os.Getenv("SUBCTL_TENANT"), "tenant ID sent in X-Tenant-ID (env SUBCTL_TENANT)")
+	format := fs.String("o", "table", "output format: table, json or csv")
+	timeout := fs.Duration("timeout", 30*time.Second, "timeout of a single request")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+	if err := checkFormat(*format); err != nil {
+		fmt.Fprintln(os.Stderr, "subctl:", err)
+		return 2
+	}
+
+	a := &app{
+		client: client.New(*baseURL,
+			client.WithToken(*token),
+			client.WithTenant(*tenant),
+			client.WithHTTPClient(&http.Client{Timeout: *timeout}),
+		),
+		format: *format,
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+		stdin:  os.Stdin,
+	}
+
+	commands := map[string]func(context.Context, []string) error{
+		"create":         a.create,
+		"get":            a.get,
+		"list":           a.list,
+		"update":         a.update,
+		"delete":         a.delete,
+		"pause":          a.pause,
+		"resume":         a.resume,
+		"change-plan":    a.changePlan,
+		"plan-changes":   a.planChanges,
+		"schedule":       a.schedule,
+		"scheduled":      a.scheduled,
+		"unschedule":     a.unschedule,
+		"members":        a.members,
+		"set-members":    a.setMembers,
+		"total-cost":     a.totalCost,
+		"breakdown":      a.breakdown,
+		"trial-ending":   a.trialEnding,
+		"upcoming":       a.upcoming,
+		"duplicates":     a.duplicates,
+		"reminders":      a.reminders,
+		"set-reminders":  a.setReminders,
+		"payments":       a.payments,
+		"add-payment":    a.addPayment,
+		"drop-payment":   a.dropPayment,
+		"expiring-cards": a.expiringCards,
+		"services":       a.services,
+		"add-service":    a.addService,
+		"export":         a.export,
+		"import":         a.importSubscriptions,
+	}
+	name := fs.Arg(0)
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "subctl: unknown command %q\n", name)
+		fs.Usage()
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := cmd(ctx, fs.Args()[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		if errors.Is(err, errUsage) {
+			return 2
+		}
+		fmt.Fprintf(os.Stderr, "subctl %s: %v\n", name, err)
+		return 1
+	}
+	return 0
+}
+
+func envOr(key, fallback string) string {
+	if v, ok := os.LookupEnv(key); ok {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/monthyear"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+)
+
+func checkFormat(format string) error {
+	switch format {
+	case formatTable, formatJSON, formatCSV:
+		return nil
+	}
+	return fmt.Errorf("unknown output format %q, expected table, json or csv", format)
+}
+
+var subscriptionHeader = []string{"id", "user_id", "service_name", "price", "current_price", "start_date", "end_date", "trial_months", "trial_price", "state", "category", "tags", "account_label", "billing_day", "payment_method_id"}
+
+// tagSeparator joins tags in a table or CSV cell
+const tagSeparator = ";"
+
+func subscriptionRecord(sub models.SubscriptionResponse) []string {
+	return []string{
+		sub.ID.String(),
+		sub.UserID.String(),
+		sub.ServiceName,
+		strconv.Itoa(sub.Price),
+		strconv.Itoa(sub.CurrentPrice),
+		formatMonth(sub.StartDate),
+		formatMonth(sub.EndDate),
+		strconv.Itoa(sub.TrialMonths),
+		strconv.Itoa(sub.TrialPrice),
+		sub.State,
+		sub.Category,
+		strings.Join(sub.Tags, tagSeparator),
+		sub.AccountLabel,
+		strconv.Itoa(sub.BillingDay),
+		formatID(sub.PaymentMethodID),
+	}
+}
+
+func (a *app) printSubscriptions(subs []models.SubscriptionResponse) error {
+	return writeSubscriptions(a.stdout, a.format, subs)
+}
+
+func writeSubscriptions(w io.Writer, format string, subs []models.SubscriptionResponse) error {
+	if format == formatJSON {
+		return writeJSON(w, subs)
+	}
+	records := make([][]string, len(subs))
+	for i, sub := range subs {
+		records[i] = subscriptionRecord(sub)
+	}
+	return writeRecords(w, format, subscriptionHeader, records)
+}
+
+func (a *app) printServices(services []models.ServiceResponse) error {
+	if a.format == formatJSON {
+		return writeJSON(a.stdout, services)
+	}
+	records := make([][]string, len(services))
+	for i, svc := range services {
+		plans := make([]string, len(svc.Plans))
+		for j, plan := range svc.Plans {
+			plans[j] = fmt.Sprintf("%s:%d/%s", plan.Name, plan.Price, plan.BillingPeriod)
+		}
+		records[i] = []string{svc.ID.String(), svc.Name, strings.Join(svc.Aliases, ","), svc.Category, svc.Website,
+			strings.Join(plans, ",")}
+	}
+	return writeRecords(a.stdout, a.format, []string{"id", "name", "aliases", "category", "website", "plans"}, records)
+}
+
+func (a *app) printPlanChanges(changes []models.PlanChangeResponse) error {
+	if a.format == formatJSON {
+		return writeJSON(a.stdout, changes)
+	}
+	records := make([][]string, len(changes))
+	for i, change := range changes {
+		planID := ""
+		if change.PlanID != nil {
+			planID = change.PlanID.String()
+		}
+		records[i] = []string{formatMonth(change.StartDate), planID, strconv.Itoa(change.Price)}
+	}
+	return writeRecords(a.stdout, a.format, []string{"start_date", "plan_id", "price"}, records)
+}
+
+func (a *app) printScheduledChanges(changes []models.ScheduledChangeResponse) error {
+	if a.format == formatJSON {
+		return writeJSON(a.stdout, changes)
+	}
+	records := make([][]string, len(changes))
+	for i, change := range changes {
+		price, appliedAt := "", ""
+		if change.Price != nil {
+			price = strconv.Itoa(*change.Price)
+		}
+		if change.AppliedAt != nil {
+			appliedAt = change.AppliedAt.Format(time.RFC3339)
+		}
+		records[i] = []string{change.ID.String(), change.Action, formatMonth(change.Month), price, change.Status, appliedAt}
+	}
+	return writeRecords(a.stdout, a.format, []string{"id", "action", "month", "price", "status", "applied_at"}, records)
+}
+
+func (a *app) printMembers(resp models.MembersResponse) error {
+	if a.format == formatJSON {
+		return writeJSON(a.stdout, resp)
+	}
+	records := make([][]string, 0, len(resp.Members)+1)
+	records = append(records, []string{resp.OwnerID.String(), "owner", resp.SplitRule, "", strconv.Itoa(resp.OwnerShare)})
+	for _, member := range resp.Members {
+		records = append(records, []string{member.UserID.String(), "member", resp.SplitRule, strconv.Itoa(member.Share), strconv.Itoa(member.CurrentShare)})
+	}
+	return writeRecords(a.stdout, a.format, []string{"user_id", "role", "split_rule", "share", "current_share"}, records)
+}
+
+func (a *app) printCharges(charges []models.ChargeResponse) error {
+	if a.format == formatJSON {
+		return writeJSON(a.stdout, charges)
+	}
+	records := make([][]string, len(charges))
+	for i, charge := range charges {
+		records[i] = []string{charge.Date, charge.ID.String(), charge.UserID.String(), charge.ServiceName, strconv.Itoa(charge.Amount)}
+	}
+	return writeRecords(a.stdout, a.format, []string{"date", "id", "user_id", "service_name", "amount"}, records)
+}
+
+func (a *app) printPaymentMethods(methods []models.PaymentMethodResponse) error {
+	if a.format == formatJSON {
+		return writeJSON(a.stdout, methods)
+	}
+	records := make([][]string, len(methods))
+	for i, method := range methods {
+		records[i] = []string{method.ID.String(), method.Kind, method.Name, method.Brand, method.Last4, formatMonth(method.Expires)}
+	}
+	return writeRecords(a.stdout, a.format, []string{"id", "kind", "name", "brand", "last4", "expires"}, records)
+}
+
+func (a *app) printExpiringCards(cards []models.ExpiringCardResponse) error {
+	if a.format == formatJSON {
+		return writeJSON(a.stdout, cards)
+	}
+	records := make([][]string, len(cards))
+	for i, card := range cards {
+		records[i] = []string{card.NextBillingDate, card.Subscription.ID.String(), card.Subscription.UserID.String(),
+			card.Subscription.ServiceName, strconv.Itoa(card.Amount), card.PaymentMethod.Name, formatMonth(card.PaymentMethod.Expires)}
+	}
+	return writeRecords(a.stdout, a.format,
+		[]string{"next_billing_date", "id", "user_id", "service_name", "amount", "payment_method", "expires"}, records)
+}
+
+func (a *app) printNotificationPreferences(prefs models.NotificationPreferencesResponse) error {
+	if a.format == formatJSON {
+		return writeJSON(a.stdout, prefs)
+	}
+	record := []string{prefs.UserID.String(), strconv.FormatBool(prefs.Enabled), strconv.Itoa(prefs.DaysBefore),
+		prefs.Channel, prefs.Email, prefs.WebhookURL}
+	return writeRecords(a.stdout, a.format, []string{"user_id", "enabled", "days_before", "channel", "email", "webhook_url"},
+		[][]string{record})
+}
+
+// printDuplicates writes a row per subscription of each group, the first one of a group is the one to keep.
+// Category overlaps follow the duplicates with the category instead of a reason and no savings.
+func (a *app) printDuplicates(resp models.DuplicatesResponse) error {
+	if a.format == formatJSON {
+		return writeJSON(a.stdout, resp)
+	}
+	var records [][]string
+	for i, group := range resp.Groups {
+		for _, sub := range group.Subscriptions {
+			records = append(records, []string{strconv.Itoa(i + 1), group.Reason, "", group.UserID.String(), sub.ID.String(),
+				sub.ServiceName, strconv.Itoa(sub.MonthCost), strconv.Itoa(group.PotentialSavings)})
+		}
+	}
+	for i, overlap := range resp.CategoryOverlaps {
+		for _, sub := range overlap.Subscriptions {
+			records = append(records, []string{strconv.Itoa(len(resp.Groups) + i + 1), "", overlap.Category, overlap.UserID.String(),
+				sub.ID.String(), sub.ServiceName, strconv.Itoa(sub.MonthCost), ""})
+		}
+	}
+	return writeRecords(a.stdout, a.format,
+		[]string{"group", "reason", "category", "user_id", "id", "service_name", "month_cost", "potential_savings"}, records)
+}
+
+func (a *app) printTotalCost(resp models.TotalCostResponse) error {
+	if a.format == formatJSON {
+		return writeJSON(a.stdout, resp)
+	}
+	return writeRecords(a.stdout, a.format, []string{"total_cost"}, [][]string{{strconv.Itoa(resp.TotalCost)}})
+}
+
+func (a *app) printBreakdown(resp models.CostBreakdownResponse) error {
+	if a.format == formatJSON {
+		return writeJSON(a.stdout, resp)
+	}
+	records := make([][]string, 0, len(resp.Items)+1)
+	for _, item := range resp.Items {
+		records = append(records, []string{item.Key, strconv.Itoa(item.TotalCost)})
+	}
+	if a.format == formatTable {
+		records = append(records, []string{"TOTAL", strconv.Itoa(resp.TotalCost)})
+	}
+	return writeRecords(a.stdout, a.format, []string{resp.GroupBy, "total_cost"}, records)
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// writeRecords writes rows as an aligned table or as CSV with a header line
+func writeRecords(w io.Writer, format string, header []string, records [][]string) error {
+	if format == formatCSV {
+		cw := csv.NewWriter(w)
+		if err := cw.Write(header); err != nil {
+			return err
+		}
+		if err := cw.WriteAll(records); err != nil {
+			return err
+		}
+		return cw.Error()
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for i, column := range header {
+		if i > 0 {
+			fmt.Fprint(tw, "\t")
+		}
+		fmt.Fprint(tw, strings.ToUpper(column))
+	}
+	fmt.Fprintln(tw)
+	for _, record := range records {
+		for i, field := range record {
+			if i > 0 {
+				fmt.Fprint(tw, "\t")
+			}
+			fmt.Fprint(tw, field)
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
+
+func formatMonth(my *monthyear.MonthYear) string {
+	if my == nil {
+		return ""
+	}
+	return time.Time(*my).Format(monthyear.DateLayout)
+}
+
+func formatID(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+)
+
+func TestWriteSubscriptions(t *testing.T) {
+	subs := testSubscriptions()
+
+	t.Run("table", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := writeSubscriptions(&out, formatTable, subs); err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+		if len(lines) != len(subs)+1 {
+			t.Fatalf("table has %d lines, want %d:\n%s", len(lines), len(subs)+1, out.String())
+		}
+		if header := strings.Fields(lines[0]); !slices.Equal(header[:3], []string{"ID", "USER_ID", "SERVICE_NAME"}) {
+			t.Errorf("table header %v", header)
+		}
+		// Columns are aligned, so the service names start at the same offset
+		column := strings.Index(lines[0], "SERVICE_NAME")
+		for i, sub := range subs {
+			if !strings.HasPrefix(lines[i+1][column:], sub.ServiceName) {
+				t.Errorf("line %d %q does not have %q in the service_name column", i+1, lines[i+1], sub.ServiceName)
+			}
+		}
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := writeSubscriptions(&out, formatCSV, subs); err != nil {
+			t.Fatal(err)
+		}
+		records, err := csv.NewReader(&out).ReadAll()
+		if err != nil {
+			t.Fatalf("output is not CSV: %v", err)
+		}
+		if !slices.Equal(records[0], subscriptionHeader) {
+			t.Errorf("CSV header %v, want %v", records[0], subscriptionHeader)
+		}
+		for i, sub := range subs {
+			if !slices.Equal(records[i+1], subscriptionRecord(sub)) {
+				t.Errorf("CSV record %d = %v, want %v", i+1, records[i+1], subscriptionRecord(sub))
+			}
+		}
+		if tags := records[1][slices.Index(subscriptionHeader, "tags")]; tags != "home;weekend" {
+			t.Errorf("tags cell = %q, want home;weekend", tags)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := writeSubscriptions(&out, formatJSON, subs); err != nil {
+			t.Fatal(err)
+		}
+		var got []models.SubscriptionResponse
+		if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+			t.Fatalf("output is not JSON: %v", err)
+		}
+		if !slices.EqualFunc(withoutIDs(got), withoutIDs(subs), slices.Equal) {
+			t.Errorf("JSON output %v, want %v", withoutIDs(got), withoutIDs(subs))
+		}
+	})
+}
+
+func TestPrintDuplicates(t *testing.T) {
+	subs := testSubscriptions()
+	duplicate := func(sub models.SubscriptionResponse, cost int) models.DuplicateSubscriptionResponse {
+		return models.DuplicateSubscriptionResponse{SubscriptionResponse: sub, MonthCost: cost}
+	}
+	userID := uuid.New()
+	resp := models.DuplicatesResponse{
+		Groups: []models.DuplicateGroupResponse{{
+			Reason: models.DuplicateSimilarName, UserID: userID, MonthCost: 1198, PotentialSavings: 399,
+			Subscriptions: []models.DuplicateSubscriptionResponse{duplicate(subs[0], 799), duplicate(subs[1], 399)},
+		}},
+		CategoryOverlaps: []models.CategoryOverlapResponse{{
+			Category: "video", UserID: userID, MonthCost: 799,
+			Subscriptions: []models.DuplicateSubscriptionResponse{duplicate(subs[0], 799)},
+		}},
+	}
+
+	var out bytes.Buffer
+	a := &app{format: formatCSV, stdout: &out}
+	if err := a.printDuplicates(resp); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not CSV: %v", err)
+	}
+	want := [][]string{
+		{"group", "reason", "category", "user_id", "id", "service_name", "month_cost", "potential_savings"},
+		{"1", "similar_name", "", userID.String(), subs[0].ID.String(), "Netflix", "799", "399"},
+		{"1", "similar_name", "", userID.String(), subs[1].ID.String(), "Yandex Plus", "399", "399"},
+		{"2", "", "video", userID.String(), subs[0].ID.String(), "Netflix", "799", ""},
+	}
+	if !slices.EqualFunc(records, want, slices.Equal) {
+		t.Errorf("duplicates CSV = %v, want %v", records, want)
+	}
+}
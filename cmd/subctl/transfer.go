@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/client"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+)
+
+func (a *app) export(ctx context.Context, args []string) error {
+	var file, format string
+	var pageSize int
+	fs := newFlagSet("export", "[-file PATH] [-format json|csv] [-page N]")
+	fs.StringVar(&file, "file", "-", `output file, "-" for stdout`)
+	fs.StringVar(&format, "format", "", "json or csv (defaults to the file extension, then json)")
+	fs.IntVar(&pageSize, "page", 100, "subscriptions fetched per request")
+	if err := parse(fs, args, 0); err != nil {
+		return err
+	}
+	format, err := fileFormat(file, format)
+	if err != nil {
+		return err
+	}
+
+	subs, err := a.client.ListAllSubscriptions(ctx, pageSize)
+	if err != nil {
+		return err
+	}
+
+	if file == "-" {
+		return writeSubscriptions(a.stdout, format, subs)
+	}
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	if err := writeSubscriptions(f, format, subs); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", file, err)
+	}
+	return f.Close()
+}
+
+// importSubscriptions creates subscriptions read from a file in the export format.
+// Subscriptions that already exist are skipped, so an interrupted import can be run again.
+func (a *app) importSubscriptions(ctx context.Context, args []string) error {
+	var file, format string
+	var dryRun, stopOnError bool
+	fs := newFlagSet("import", "-file PATH [-format json|csv] [-dry-run] [-stop-on-error]")
+	fs.StringVar(&file, "file", "", `input file, "-" for stdin`)
+	fs.StringVar(&format, "format", "", "json or csv (defaults to the file extension, then json)")
+	fs.BoolVar(&dryRun, "dry-run", false, "only validate the file")
+	fs.BoolVar(&stopOnError, "stop-on-error", false, "stop at the first subscription that fails")
+	if err := parse(fs, args, 0); err != nil {
+		return err
+	}
+	if file == "" {
+		fs.Usage()
+		return errUsage
+	}
+	format, err := fileFormat(file, format)
+	if err != nil {
+		return err
+	}
+
+	r := a.stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	var reqs []models.CreateSubscriptionRequest
+	if format == formatCSV {
+		reqs, err = readCSV(r)
+	} else {
+		err = json.NewDecoder(r).Decode(&reqs)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+
+	var created, skipped, failed int
+	for i, req := range reqs {
+		err := validator.Struct(&req)
+		if err == nil && !dryRun {
+			_, err = a.client.CreateSubscription(ctx, req)
+		}
+
+		var apiErr *client.Error
+		switch {
+		case err == nil:
+			created++
+		case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict:
+			skipped++
+		default:
+			failed++
+			fmt.Fprintf(a.stderr, "subscription #%d (%s, user %s): %v\n", i+1, req.ServiceName, req.UserID, err)
+			if stopOnError || ctx.Err() != nil {
+				return errors.New("import stopped")
+			}
+		}
+	}
+
+	status := "created"
+	if dryRun {
+		status = "valid"
+	}
+	err = writeRecords(a.stdout, formatTable, []string{status, "skipped", "failed"},
+		[][]string{{strconv.Itoa(created), strconv.Itoa(skipped), strconv.Itoa(failed)}})
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d subscriptions failed", failed, len(reqs))
+	}
+	return nil
+}
+
+// fileFormat returns the explicit format or the one implied by the file extension
+func fileFormat(file, format string) (string, error) {
+	if format == "" {
+		format = formatJSON
+		if strings.EqualFold(filepath.Ext(file), ".csv") {
+			format = formatCSV
+		}
+	}
+	if format != formatJSON && format != formatCSV {
+		return "", fmt.Errorf("unknown file format %q, expected json or csv", format)
+	}
+	return format, nil
+}
+
+// readCSV reads subscriptions from CSV with a header line, columns are matched by name and id is ignored.
+// Tags are separated by semicolons.
+func readCSV(r io.Reader) ([]models.CreateSubscriptionRequest, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, name := range []string{"user_id", "service_name", "price", "start_date"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing column %q", name)
+		}
+	}
+
+	var reqs []models.CreateSubscriptionRequest
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return reqs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		field := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		req := models.CreateSubscriptionRequest{
+			ServiceName:  field("service_name"),
+			Category:     field("category"),
+			AccountLabel: field("account_label"),
+		}
+		if tags := field("tags"); tags != "" {
+			req.Tags = strings.Split(tags, tagSeparator)
+		}
+		if req.UserID, err = uuid.Parse(field("user_id")); err != nil {
+			return nil, fmt.Errorf("line %d: invalid user_id: %w", line, err)
+		}
+		price, err := strconv.Atoi(field("price"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid price: %w", line, err)
+		}
+		req.Price = &price
+		if err := (monthValue{&req.StartDate}).Set(field("start_date")); err != nil {
+			return nil, fmt.Errorf("line %d: invalid start_date: %w", line, err)
+		}
+		if end := field("end_date"); end != "" {
+			if err := (monthValue{&req.EndDate}).Set(end); err != nil {
+				return nil, fmt.Errorf("line %d: invalid end_date: %w", line, err)
+			}
+		}
+		if trialMonths := field("trial_months"); trialMonths != "" {
+			if req.TrialMonths, err = strconv.Atoi(trialMonths); err != nil {
+				return nil, fmt.Errorf("line %d: invalid trial_months: %w", line, err)
+			}
+		}
+		if trialPrice := field("trial_price"); trialPrice != "" {
+			if req.TrialPrice, err = strconv.Atoi(trialPrice); err != nil {
+				return nil, fmt.Errorf("line %d: invalid trial_price: %w", line, err)
+			}
+		}
+		if billingDay := field("billing_day"); billingDay != "" {
+			if req.BillingDay, err = strconv.Atoi(billingDay); err != nil {
+				return nil, fmt.Errorf("line %d: invalid billing_day: %w", line, err)
+			}
+		}
+		if paymentMethodID := field("payment_method_id"); paymentMethodID != "" {
+			id, err := uuid.Parse(paymentMethodID)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid payment_method_id: %w", line, err)
+			}
+			req.PaymentMethodID = &id
+		}
+		reqs = append(reqs, req)
+	}
+}
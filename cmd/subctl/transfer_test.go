@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/client"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/monthyear"
+)
+
+// fakeAPI serves the list and create subscription endpoints from memory. Creating a subscription
+// with the user, service and start month of an existing one fails with 409, services named in reject with 400.
+type fakeAPI struct {
+	reject string
+
+	mu        sync.Mutex
+	subs      []models.SubscriptionResponse
+	listCalls int
+}
+
+func newFakeAPI(t *testing.T, subs ...models.SubscriptionResponse) (*fakeAPI, *httptest.Server) {
+	t.Helper()
+
+	api := &fakeAPI{subs: subs}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /subscriptions", api.list)
+	mux.HandleFunc("POST /subscriptions", api.create)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return api, server
+}
+
+func (api *fakeAPI) list(w http.ResponseWriter, r *http.Request) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	api.listCalls++
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	from := 0
+	if previous := r.URL.Query().Get("previous_id"); previous != "" {
+		from = slices.IndexFunc(api.subs, func(sub models.SubscriptionResponse) bool { return sub.ID.String() == previous }) + 1
+	}
+	page := api.subs[from:min(from+limit, len(api.subs))]
+	_ = json.NewEncoder(w).Encode(page)
+}
+
+func (api *fakeAPI) create(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	problem := func(status int, code string) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(models.Problem{Status: status, Title: http.StatusText(status), Code: code})
+	}
+	if req.ServiceName == api.reject {
+		problem(http.StatusBadRequest, "validation_failed")
+		return
+	}
+	for _, sub := range api.subs {
+		if sub.UserID == req.UserID && sub.ServiceName == req.ServiceName && time.Time(*sub.StartDate).Equal(time.Time(*req.StartDate)) {
+			problem(http.StatusConflict, "already_exists")
+			return
+		}
+	}
+
+	sub := models.SubscriptionResponse{
+		ID: uuid.New(), UserID: req.UserID, ServiceName: req.ServiceName, AccountLabel: req.AccountLabel,
+		Price: *req.Price, CurrentPrice: *req.Price, StartDate: req.StartDate, EndDate: req.EndDate,
+		BillingDay: req.BillingDay, TrialMonths: req.TrialMonths, TrialPrice: req.TrialPrice, State: models.StateActive,
+		Category: req.Category, Tags: req.Tags, PaymentMethodID: req.PaymentMethodID,
+	}
+	api.subs = append(api.subs, sub)
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(sub)
+}
+
+func newTestApp(server *httptest.Server) (*app, *bytes.Buffer) {
+	stdout := &bytes.Buffer{}
+	return &app{client: client.New(server.URL), format: formatTable, stdout: stdout, stderr: &bytes.Buffer{}}, stdout
+}
+
+func month(year int, m time.Month) *monthyear.MonthYear {
+	my := monthyear.MonthYear(time.Date(year, m, 1, 0, 0, 0, 0, time.UTC))
+	return &my
+}
+
+func testSubscriptions() []models.SubscriptionResponse {
+	userID, paymentMethodID := uuid.New(), uuid.New()
+	return []models.SubscriptionResponse{
+		{ID: uuid.New(), UserID: userID, ServiceName: "Netflix", AccountLabel: "family", Price: 799, CurrentPrice: 799,
+			StartDate: month(2024, time.January), EndDate: month(2024, time.December), BillingDay: 15, State: models.StateActive,
+			Category: "video", Tags: []string{"home", "weekend"}, PaymentMethodID: &paymentMethodID},
+		{ID: uuid.New(), UserID: userID, ServiceName: "Yandex Plus", Price: 399, CurrentPrice: 399, StartDate: month(2024, time.March),
+			BillingDay: 1, TrialMonths: 2, TrialPrice: 1, State: models.StateActive},
+		{ID: uuid.New(), UserID: uuid.New(), ServiceName: "Spotify, Duo", Price: 0, CurrentPrice: 0, StartDate: month(2025, time.February),
+			BillingDay: 28, State: models.StateActive, Category: "music"},
+	}
+}
+
+// withoutIDs returns the records of subs without their IDs, which are assigned by the server
+func withoutIDs(subs []models.SubscriptionResponse) [][]string {
+	records := make([][]string, len(subs))
+	for i, sub := range subs {
+		records[i] = subscriptionRecord(sub)[1:]
+	}
+	return records
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	for _, format := range []string{formatJSON, formatCSV} {
+		t.Run(format, func(t *testing.T) {
+			ctx := context.Background()
+			source, sourceServer := newFakeAPI(t, testSubscriptions()...)
+			exporter, exported := newTestApp(sourceServer)
+
+			if err := exporter.export(ctx, []string{"-format", format, "-page", "2"}); err != nil {
+				t.Fatalf("export: %v", err)
+			}
+			if source.listCalls != 2 {
+				t.Errorf("export listed %d pages, want 2", source.listCalls)
+			}
+
+			file := filepath.Join(t.TempDir(), "subscriptions."+format)
+			if err := os.WriteFile(file, exported.Bytes(), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			target, targetServer := newFakeAPI(t)
+			importer, out := newTestApp(targetServer)
+			if err := importer.importSubscriptions(ctx, []string{"-file", file}); err != nil {
+				t.Fatalf("import: %v", err)
+			}
+			if !slices.EqualFunc(withoutIDs(target.subs), withoutIDs(source.subs), slices.Equal) {
+				t.Errorf("imported %v, want %v", withoutIDs(target.subs), withoutIDs(source.subs))
+			}
+			if got := strings.Fields(out.String()); !slices.Equal(got, []string{"CREATED", "SKIPPED", "FAILED", "3", "0", "0"}) {
+				t.Errorf("import output %v", got)
+			}
+
+			// Subscriptions created by an interrupted import are skipped when it is run again
+			out.Reset()
+			if err := importer.importSubscriptions(ctx, []string{"-file", file}); err != nil {
+				t.Fatalf("second import: %v", err)
+			}
+			if got := strings.Fields(out.String()); !slices.Equal(got, []string{"CREATED", "SKIPPED", "FAILED", "0", "3", "0"}) {
+				t.Errorf("second import output %v", got)
+			}
+			if len(target.subs) != 3 {
+				t.Errorf("server has %d subscriptions after the second import, want 3", len(target.subs))
+			}
+		})
+	}
+}
+
+func TestImportReportsFailures(t *testing.T) {
+	subs := testSubscriptions()
+	api, server := newFakeAPI(t, subs[0])
+	api.reject = subs[1].ServiceName
+
+	var exported bytes.Buffer
+	if err := writeSubscriptions(&exported, formatJSON, subs); err != nil {
+		t.Fatal(err)
+	}
+	a, out := newTestApp(server)
+	a.stdin = &exported
+
+	err := a.importSubscriptions(context.Background(), []string{"-file", "-"})
+	if err == nil || err.Error() != "1 of 3 subscriptions failed" {
+		t.Fatalf("import error = %v, want 1 of 3 subscriptions failed", err)
+	}
+	if got := strings.Fields(out.String()); !slices.Equal(got, []string{"CREATED", "SKIPPED", "FAILED", "1", "1", "1"}) {
+		t.Errorf("import output %v", got)
+	}
+	if stderr := a.stderr.(*bytes.Buffer).String(); !strings.Contains(stderr, "subscription #2 (Yandex Plus") {
+		t.Errorf("import errors %q do not name the failed subscription", stderr)
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	const header = "user_id,service_name,price,start_date,end_date,tags,billing_day\n"
+	userID := uuid.New().String()
+
+	reqs, err := readCSV(strings.NewReader(header + userID + `, Netflix ,799,01-2024,12-2024,home;weekend,15` + "\n" +
+		userID + ",Okko,0,03-2024,,,\n"))
+	if err != nil {
+		t.Fatalf("readCSV() error = %v", err)
+	}
+	if len(reqs) != 2 {
+		t.Fatalf("readCSV() = %d subscriptions, want 2", len(reqs))
+	}
+	netflix := reqs[0]
+	if netflix.ServiceName != "Netflix" || *netflix.Price != 799 || formatMonth(netflix.StartDate) != "01-2024" ||
+		formatMonth(netflix.EndDate) != "12-2024" || !slices.Equal(netflix.Tags, []string{"home", "weekend"}) || netflix.BillingDay != 15 {
+		t.Errorf("readCSV() first subscription = %+v", netflix)
+	}
+	if okko := reqs[1]; *okko.Price != 0 || okko.EndDate != nil || okko.Tags != nil || okko.BillingDay != 0 {
+		t.Errorf("readCSV() second subscription = %+v", okko)
+	}
+}
+
+func TestReadCSVErrors(t *testing.T) {
+	userID := uuid.New().String()
+
+	tests := []struct {
+		name    string
+		csv     string
+		wantErr string
+	}{
+		{name: "empty", csv: "", wantErr: "EOF"},
+		{name: "missing column", csv: "user_id,service_name,price\n", wantErr: `missing column "start_date"`},
+		{name: "invalid user_id", csv: "user_id,service_name,price,start_date\nabc,Netflix,799,01-2024\n", wantErr: "line 2: invalid user_id"},
+		{name: "invalid price", csv: "user_id,service_name,price,start_date\n" + userID + ",Netflix,7.99,01-2024\n", wantErr: "line 2: invalid price"},
+		{name: "missing start_date", csv: "user_id,service_name,price,start_date\n" + userID + ",Netflix,799,\n", wantErr: "line 2: invalid start_date"},
+		{name: "invalid end_date", csv: "user_id,service_name,price,start_date,end_date\n" + userID + ",Netflix,799,01-2024,2024-12\n", wantErr: "line 2: invalid end_date"},
+		{name: "invalid trial_months", csv: "user_id,service_name,price,start_date,trial_months\n" + userID + ",Netflix,799,01-2024,one\n", wantErr: "line 2: invalid trial_months"},
+		{name: "invalid payment_method_id", csv: "user_id,service_name,price,start_date,payment_method_id\n" + userID + ",Netflix,799,01-2024,card\n", wantErr: "line 2: invalid payment_method_id"},
+		{name: "error on a later line", csv: "user_id,service_name,price,start_date\n" + userID + ",Netflix,799,01-2024\n" + userID + ",Okko,free,01-2024\n", wantErr: "line 3: invalid price"},
+		{name: "wrong number of fields", csv: "user_id,service_name,price,start_date\n" + userID + ",Netflix,799\n", wantErr: "wrong number of fields"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readCSV(strings.NewReader(tt.csv))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("readCSV() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
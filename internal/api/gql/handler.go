@@ -0,0 +1,122 @@
+package gql
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/config"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/service"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/validation"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/logger"
+)
+
+// requestErrors is the response to a request rejected before execution, it has no data entry
+type requestErrors struct {
+	Errors []gqlerrors.FormattedError `json:"errors"`
+}
+
+// Handler serves GraphQL queries over the service layer
+type Handler struct {
+	schema  graphql.Schema
+	service service.SubscriptionService
+	cfg     config.GraphQLConfig
+}
+
+// NewHandler builds the schema, it panics on an invalid schema since the schema is static
+func NewHandler(s service.SubscriptionService, cfg config.GraphQLConfig) *Handler {
+	schema, err := newSchema(&resolver{service: s, validator: validation.New()})
+	if err != nil {
+		panic(fmt.Sprintf("invalid GraphQL schema: %v", err))
+	}
+	return &Handler{schema: schema, service: s, cfg: cfg}
+}
+
+// ServeHTTP godoc
+// @Summary GraphQL query
+// @Description Execute a GraphQL query over subscriptions, users derived from their user_id and cost reports.
+// @Description Nested user fields are loaded in batches. Queries deeper or more complex than GRAPHQL_MAX_DEPTH
+// @Description and GRAPHQL_MAX_COMPLEXITY are rejected, introspection is limited by GRAPHQL_MAX_INTROSPECTION_DEPTH.
+// @Description GET accepts query, operationName and variables parameters.
+// @Tags graphql
+// @Accept json
+// @Produce json
+// @Param request body models.GraphQLRequest true "GraphQL request"
+// @Param X-Tenant-ID header string false "Tenant ID (defaults to the tenant_id claim or APP_DEFAULT_TENANT)"
+// @Success 200 {object} map[string]interface{} "data and errors of the executed query"
+// @Failure 400 {object} map[string]interface{} "Invalid query or limits exceeded"
+// @Failure 401 {object} models.Problem "Missing or invalid token"
+// @Failure 429 {object} models.Problem "Rate limit exceeded"
+// @Security BearerAuth
+// @Router /graphql [post]
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeRequest(r)
+	if err != nil {
+		writeResult(w, r, http.StatusBadRequest, requestErrors{Errors: gqlerrors.FormatErrors(err)})
+		return
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		writeResult(w, r, http.StatusBadRequest, requestErrors{Errors: gqlerrors.FormatErrors(err)})
+		return
+	}
+	if vr := graphql.ValidateDocument(&h.schema, doc, nil); !vr.IsValid {
+		writeResult(w, r, http.StatusBadRequest, requestErrors{Errors: vr.Errors})
+		return
+	}
+	l := limits{cfg: h.cfg, schema: &h.schema, variables: req.Variables}
+	if err := l.check(doc, req.OperationName); err != nil {
+		formatted := gqlerrors.FormatError(err)
+		formatted.Extensions = err.Extensions()
+		writeResult(w, r, http.StatusBadRequest, requestErrors{Errors: []gqlerrors.FormattedError{formatted}})
+		return
+	}
+
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        h.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       withLoaders(r.Context(), newLoaders(h.service, h.cfg.BatchWait)),
+	})
+	writeResult(w, r, http.StatusOK, result)
+}
+
+// decodeRequest reads a JSON body of POST requests and query parameters of GET requests
+func decodeRequest(r *http.Request) (models.GraphQLRequest, error) {
+	var req models.GraphQLRequest
+	if r.Method == http.MethodGet {
+		query := r.URL.Query()
+		req.Query = query.Get("query")
+		req.OperationName = query.Get("operationName")
+		if variables := query.Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				return req, fmt.Errorf("invalid variables: %w", err)
+			}
+		}
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return req, fmt.Errorf("invalid JSON format: %w", err)
+	}
+
+	if req.Query == "" {
+		return req, fmt.Errorf("query is required")
+	}
+	return req, nil
+}
+
+func writeResult(w http.ResponseWriter, r *http.Request, status int, result any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		logger.FromContext(r.Context()).ErrorContext(r.Context(), "failed to write GraphQL response", "error", err)
+	}
+}
@@ -0,0 +1,173 @@
+package gql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/config"
+)
+
+// limits measures the depth and complexity of an operation before it is executed.
+// Every field costs 1, fields below a list are counted once per expected list element:
+// the "first" argument, the number of "ids" or config.GraphQLConfig.ListSize.
+// Introspection is not part of the complexity, its depth is capped by MaxIntrospectionDepth.
+type limits struct {
+	cfg       config.GraphQLConfig
+	schema    *graphql.Schema
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]any
+	// introspectionDepth is the deepest __schema or __type selection of the operation
+	introspectionDepth int
+}
+
+// check returns an error for an operation exceeding the configured limits.
+// Unknown operations are left to the executor to report.
+func (l *limits) check(doc *ast.Document, operationName string) *Error {
+	var op *ast.OperationDefinition
+	l.fragments = make(map[string]*ast.FragmentDefinition)
+	for _, def := range doc.Definitions {
+		switch def := def.(type) {
+		case *ast.OperationDefinition:
+			if operationName == "" || (def.Name != nil && def.Name.Value == operationName) {
+				op = def
+			}
+		case *ast.FragmentDefinition:
+			l.fragments[def.Name.Value] = def
+		}
+	}
+	if op == nil || op.Operation != ast.OperationTypeQuery {
+		return nil
+	}
+
+	complexity, depth := l.measure(l.schema.QueryType(), op.SelectionSet)
+	if l.introspectionDepth > l.cfg.MaxIntrospectionDepth {
+		return &Error{Message: fmt.Sprintf("introspection depth %d exceeds the limit of %d", l.introspectionDepth, l.cfg.MaxIntrospectionDepth), Code: "QUERY_TOO_DEEP"}
+	}
+	if depth > l.cfg.MaxDepth {
+		return &Error{Message: fmt.Sprintf("query depth %d exceeds the limit of %d", depth, l.cfg.MaxDepth), Code: "QUERY_TOO_DEEP"}
+	}
+	if complexity > l.cfg.MaxComplexity {
+		return &Error{Message: fmt.Sprintf("query complexity %d exceeds the limit of %d", complexity, l.cfg.MaxComplexity), Code: "QUERY_TOO_COMPLEX"}
+	}
+	return nil
+}
+
+// measure returns the complexity and depth of a selection set on a parent type
+func (l *limits) measure(parent graphql.Type, set *ast.SelectionSet) (complexity, depth int) {
+	obj, ok := parent.(*graphql.Object)
+	if !ok || set == nil {
+		return 0, 0
+	}
+
+	for _, selection := range set.Selections {
+		var c, d int
+		switch s := selection.(type) {
+		case *ast.Field:
+			c, d = l.measureField(obj, s)
+		case *ast.InlineFragment:
+			c, d = l.measure(obj, s.SelectionSet)
+		case *ast.FragmentSpread:
+			if fragment, ok := l.fragments[s.Name.Value]; ok {
+				c, d = l.measure(obj, fragment.SelectionSet)
+			}
+		}
+		complexity += c
+		depth = max(depth, d)
+	}
+	return complexity, depth
+}
+
+func (l *limits) measureField(parent *graphql.Object, field *ast.Field) (complexity, depth int) {
+	// introspection nests deeper than data queries, e.g. ofType chains, it has its own depth limit
+	switch field.Name.Value {
+	case graphql.SchemaMetaFieldDef.Name:
+		l.measureIntrospection(graphql.SchemaMetaFieldDef, field)
+		return 0, 0
+	case graphql.TypeMetaFieldDef.Name:
+		l.measureIntrospection(graphql.TypeMetaFieldDef, field)
+		return 0, 0
+	}
+	if strings.HasPrefix(field.Name.Value, "__") {
+		return 0, 0
+	}
+	def, ok := parent.Fields()[field.Name.Value]
+	if !ok {
+		return 1, 1
+	}
+
+	multiplier := 1
+	fieldType := unwrapNonNull(def.Type)
+	if list, ok := fieldType.(*graphql.List); ok {
+		multiplier = l.listSize(def, field)
+		fieldType = unwrapNonNull(list.OfType)
+	}
+
+	c, d := l.measure(fieldType, field.SelectionSet)
+	return 1 + multiplier*c, 1 + d
+}
+
+func (l *limits) measureIntrospection(def *graphql.FieldDefinition, field *ast.Field) {
+	_, depth := l.measure(unwrapNonNull(def.Type), field.SelectionSet)
+	l.introspectionDepth = max(l.introspectionDepth, 1+depth)
+}
+
+// listSize estimates the length of a list field from its arguments
+func (l *limits) listSize(def *graphql.FieldDefinition, field *ast.Field) int {
+	for _, arg := range field.Arguments {
+		switch arg.Name.Value {
+		case "first":
+			if n, ok := l.intValue(arg.Value); ok {
+				return max(n, 1)
+			}
+		case "ids":
+			if n, ok := l.listLength(arg.Value); ok {
+				return max(n, 1)
+			}
+		}
+	}
+	for _, arg := range def.Args {
+		if n, ok := arg.DefaultValue.(int); ok && arg.PrivateName == "first" {
+			return n
+		}
+	}
+	return l.cfg.ListSize
+}
+
+func (l *limits) intValue(value ast.Value) (int, bool) {
+	switch v := value.(type) {
+	case *ast.IntValue:
+		n, err := strconv.Atoi(v.Value)
+		return n, err == nil
+	case *ast.Variable:
+		switch n := l.variables[v.Name.Value].(type) {
+		case float64:
+			return int(n), true
+		case int:
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+func (l *limits) listLength(value ast.Value) (int, bool) {
+	switch v := value.(type) {
+	case *ast.ListValue:
+		return len(v.Values), true
+	case *ast.Variable:
+		if list, ok := l.variables[v.Name.Value].([]any); ok {
+			return len(list), true
+		}
+	}
+	return 0, false
+}
+
+func unwrapNonNull(t graphql.Type) graphql.Type {
+	if nonNull, ok := t.(*graphql.NonNull); ok {
+		return nonNull.OfType
+	}
+	return t
+}
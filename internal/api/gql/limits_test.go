@@ -0,0 +1,77 @@
+package gql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql/language/parser"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/config"
+)
+
+// introspectionQuery is the schema query of GraphiQL
+const introspectionQuery = `
+query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    types { ...FullType }
+    directives { name args { ...InputValue } }
+  }
+}
+fragment FullType on __Type {
+  kind name
+  fields(includeDeprecated: true) { name args { ...InputValue } type { ...TypeRef } }
+  inputFields { ...InputValue }
+  interfaces { ...TypeRef }
+  enumValues(includeDeprecated: true) { name }
+  possibleTypes { ...TypeRef }
+}
+fragment InputValue on __InputValue { name type { ...TypeRef } defaultValue }
+fragment TypeRef on __Type {
+  kind name
+  ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name
+    ofType { kind name ofType { kind name } } } } } } }
+}`
+
+func TestLimits(t *testing.T) {
+	schema, err := newSchema(&resolver{})
+	if err != nil {
+		t.Fatalf("newSchema: %v", err)
+	}
+	cfg := config.GraphQLConfig{MaxDepth: 3, MaxComplexity: 1000, MaxIntrospectionDepth: 15, ListSize: 10}
+
+	tests := []struct {
+		name     string
+		query    string
+		wantCode string
+	}{
+		{name: "graphiql introspection", query: introspectionQuery},
+		{
+			name:     "nested ofType beyond the introspection limit",
+			query:    `{ __type(name: "Subscription") { ` + strings.Repeat("ofType { ", 15) + "name" + strings.Repeat(" }", 15) + " } }",
+			wantCode: "QUERY_TOO_DEEP",
+		},
+		{
+			name:     "introspection does not hide a deep data query",
+			query:    `{ __typename users(ids: ["u"]) { subscriptions { user { id } } } }`,
+			wantCode: "QUERY_TOO_DEEP",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parser.Parse(parser.ParseParams{Source: tt.query})
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			l := limits{cfg: cfg, schema: &schema}
+			got := l.check(doc, "")
+			switch {
+			case tt.wantCode == "" && got != nil:
+				t.Fatalf("check() = %v, want nil", got.Message)
+			case tt.wantCode != "" && (got == nil || got.Code != tt.wantCode):
+				t.Fatalf("check() = %v, want %s", got, tt.wantCode)
+			}
+		})
+	}
+}
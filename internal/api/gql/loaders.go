@@ -0,0 +1,110 @@
+package gql
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/graph-gophers/dataloader"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/service"
+)
+
+type loadersKey struct{}
+
+// userKey is a dataloader key of a user
+type userKey uuid.UUID
+
+func (k userKey) String() string { return uuid.UUID(k).String() }
+
+func (k userKey) Raw() any { return uuid.UUID(k) }
+
+// loaders batch loading of nested user fields within one request,
+// so a list of N users costs one repository query per field instead of N
+type loaders struct {
+	service service.SubscriptionService
+	wait    time.Duration
+
+	subscriptions *dataloader.Loader
+
+	mu sync.Mutex
+	// costReports holds a loader per distinct report arguments, keyed by the JSON of the request
+	costReports map[string]*dataloader.Loader
+}
+
+func newLoaders(s service.SubscriptionService, wait time.Duration) *loaders {
+	l := &loaders{
+		service:     s,
+		wait:        wait,
+		costReports: make(map[string]*dataloader.Loader),
+	}
+	l.subscriptions = dataloader.NewBatchedLoader(l.loadSubscriptions, dataloader.WithWait(wait))
+	return l
+}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+func (l *loaders) costReport(req models.CostBreakdownRequest) *dataloader.Loader {
+	raw, _ := json.Marshal(req)
+	key := string(raw)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	loader, ok := l.costReports[key]
+	if !ok {
+		loader = dataloader.NewBatchedLoader(func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+			return l.loadCostReports(ctx, keys, req)
+		}, dataloader.WithWait(l.wait))
+		l.costReports[key] = loader
+	}
+	return loader
+}
+
+func (l *loaders) loadSubscriptions(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+	userIDs := keyIDs(keys)
+	subs, err := l.service.ListUsersSubscriptions(ctx, userIDs)
+	return results(userIDs, err, func(id uuid.UUID) any {
+		if list, ok := subs[id]; ok {
+			return list
+		}
+		return []models.SubscriptionResponse{}
+	})
+}
+
+func (l *loaders) loadCostReports(ctx context.Context, keys dataloader.Keys, req models.CostBreakdownRequest) []*dataloader.Result {
+	userIDs := keyIDs(keys)
+	reports, err := l.service.GetUsersCostBreakdown(ctx, userIDs, req)
+	return results(userIDs, err, func(id uuid.UUID) any {
+		return reports[id]
+	})
+}
+
+func keyIDs(keys dataloader.Keys) []uuid.UUID {
+	ids := make([]uuid.UUID, len(keys))
+	for i, key := range keys {
+		ids[i] = key.Raw().(uuid.UUID)
+	}
+	return ids
+}
+
+// results returns a result per user in the order of keys, a failed batch fails every key
+func results(userIDs []uuid.UUID, err error, data func(id uuid.UUID) any) []*dataloader.Result {
+	res := make([]*dataloader.Result, len(userIDs))
+	for i, id := range userIDs {
+		if err != nil {
+			res[i] = &dataloader.Result{Error: err}
+			continue
+		}
+		res[i] = &dataloader.Result{Data: data(id)}
+	}
+	return res
+}
@@ -0,0 +1,245 @@
+package gql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/auth"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/repository"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/service"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/validation"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/logger"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/monthyear"
+)
+
+const (
+	defaultFirst = 20
+	maxFirst     = 100
+)
+
+// resolver resolves query fields through the service layer, nested user fields are loaded in batches
+type resolver struct {
+	service   service.SubscriptionService
+	validator *validation.Validator
+}
+
+// Error is a GraphQL error with a machine-readable code in its extensions
+type Error struct {
+	Message string
+	Code    string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Extensions() map[string]any {
+	return map[string]any{"code": e.Code}
+}
+
+func badRequest(format string, args ...any) error {
+	return &Error{Message: fmt.Sprintf(format, args...), Code: "BAD_REQUEST"}
+}
+
+// toError maps service errors to GraphQL errors, unexpected errors are logged and hidden from the caller
+func toError(ctx context.Context, op string, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrSubscriptionNotFound):
+		return &Error{Message: repository.ErrSubscriptionNotFound.Error(), Code: "NOT_FOUND"}
+	case errors.Is(err, service.ErrInvalidDateRange):
+		return &Error{Message: service.ErrInvalidDateRange.Error(), Code: "BAD_REQUEST"}
+	case errors.Is(err, service.ErrUnknownService):
+		return &Error{Message: service.ErrUnknownService.Error(), Code: "BAD_REQUEST"}
+	case errors.Is(err, service.ErrUnknownPlan):
+		return &Error{Message: service.ErrUnknownPlan.Error(), Code: "BAD_REQUEST"}
+	case errors.Is(err, service.ErrForbidden):
+		return &Error{Message: service.ErrForbidden.Error(), Code: "FORBIDDEN"}
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return &Error{Message: err.Error(), Code: "CANCELED"}
+	}
+	logger.FromContext(ctx).ErrorContext(ctx, "service failed to "+op, "error", err)
+	return &Error{Message: "internal error", Code: "INTERNAL"}
+}
+
+func (r *resolver) me(p graphql.ResolveParams) (any, error) {
+	principal, ok := auth.FromContext(p.Context)
+	if !ok {
+		return nil, nil
+	}
+	return user{ID: principal.UserID}, nil
+}
+
+func (r *resolver) user(p graphql.ResolveParams) (any, error) {
+	id, err := parseID(p.Args["id"], "id")
+	if err != nil {
+		return nil, err
+	}
+	return user{ID: id}, nil
+}
+
+func (r *resolver) users(p graphql.ResolveParams) (any, error) {
+	raw, _ := p.Args["ids"].([]any)
+	users := make([]user, len(raw))
+	for i, v := range raw {
+		id, err := parseID(v, "ids")
+		if err != nil {
+			return nil, err
+		}
+		users[i] = user{ID: id}
+	}
+	return users, nil
+}
+
+func (r *resolver) subscription(p graphql.ResolveParams) (any, error) {
+	id, err := parseID(p.Args["id"], "id")
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := r.service.GetSubscriptionByID(p.Context, id)
+	if errors.Is(err, repository.ErrSubscriptionNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, toError(p.Context, "get subscription", err)
+	}
+	return sub, nil
+}
+
+func (r *resolver) subscriptions(p graphql.ResolveParams) (any, error) {
+	first, _ := p.Args["first"].(int)
+	if first < 1 || first > maxFirst {
+		return nil, badRequest("first must be between 1 and %d", maxFirst)
+	}
+	req := models.ListSubscriptionsRequest{Limit: first}
+
+	afterID, hasID := p.Args["afterId"]
+	afterStartDate, hasStartDate := p.Args["afterStartDate"].(monthyear.MonthYear)
+	if hasID != hasStartDate {
+		return nil, badRequest("afterId and afterStartDate must be set together")
+	}
+	if hasID {
+		id, err := parseID(afterID, "afterId")
+		if err != nil {
+			return nil, err
+		}
+		req.Cursor = &models.SubscriptionCursor{ID: id, StartDate: afterStartDate}
+	}
+	req.Tags = stringList(p.Args["tags"])
+	if err := r.validator.Struct(&req); err != nil {
+		return nil, badRequest("%s", err)
+	}
+
+	subs, err := r.service.ListSubscriptions(p.Context, req)
+	if err != nil {
+		return nil, toError(p.Context, "list subscriptions", err)
+	}
+	return subs, nil
+}
+
+func (r *resolver) costReport(p graphql.ResolveParams) (any, error) {
+	req, err := r.costRequest(p.Args)
+	if err != nil {
+		return nil, err
+	}
+	if raw, ok := p.Args["userId"]; ok {
+		userID, err := parseID(raw, "userId")
+		if err != nil {
+			return nil, err
+		}
+		req.UserID = &userID
+	}
+
+	resp, err := r.service.GetCostBreakdown(p.Context, req)
+	if err != nil {
+		return nil, toError(p.Context, "get cost breakdown", err)
+	}
+	return resp, nil
+}
+
+func (r *resolver) userSubscriptions(p graphql.ResolveParams) (any, error) {
+	thunk := loadersFrom(p.Context).subscriptions.Load(p.Context, userKey(p.Source.(user).ID))
+	return func() (any, error) {
+		subs, err := thunk()
+		if err != nil {
+			return nil, toError(p.Context, "list user subscriptions", err)
+		}
+		return subs, nil
+	}, nil
+}
+
+func (r *resolver) userCostReport(p graphql.ResolveParams) (any, error) {
+	req, err := r.costRequest(p.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	thunk := loadersFrom(p.Context).costReport(req).Load(p.Context, userKey(p.Source.(user).ID))
+	return func() (any, error) {
+		report, err := thunk()
+		if err != nil {
+			return nil, toError(p.Context, "get user cost breakdown", err)
+		}
+		return report, nil
+	}, nil
+}
+
+// costRequest converts and validates cost report arguments shared by all cost report fields
+func (r *resolver) costRequest(args map[string]any) (models.CostBreakdownRequest, error) {
+	req := models.CostBreakdownRequest{}
+	req.GroupBy, _ = args["groupBy"].(string)
+	if serviceName, ok := args["serviceName"].(string); ok {
+		req.ServiceName = &serviceName
+	}
+	if raw, ok := args["serviceId"]; ok {
+		serviceID, err := parseID(raw, "serviceId")
+		if err != nil {
+			return models.CostBreakdownRequest{}, err
+		}
+		req.ServiceID = &serviceID
+	}
+	if startDate, ok := args["startDate"].(monthyear.MonthYear); ok {
+		req.StartDate = &startDate
+	}
+	if endDate, ok := args["endDate"].(monthyear.MonthYear); ok {
+		req.EndDate = &endDate
+	}
+	req.Tags = stringList(args["tags"])
+	if category, ok := args["category"].(string); ok {
+		req.Category = &category
+	}
+
+	if err := r.validator.Struct(&req); err != nil {
+		return models.CostBreakdownRequest{}, badRequest("%s", err)
+	}
+	return req, nil
+}
+
+// stringList converts a list argument of strings, it returns nil if the argument is not set
+func stringList(raw any) []string {
+	values, ok := raw.([]any)
+	if !ok {
+		return nil
+	}
+	list := make([]string, 0, len(values))
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+func parseID(raw any, field string) (uuid.UUID, error) {
+	s, _ := raw.(string)
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return uuid.Nil, badRequest("invalid %s format", field)
+	}
+	return id, nil
+}
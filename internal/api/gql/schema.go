@@ -0,0 +1,357 @@
+package gql
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/monthyear"
+)
+
+// user is the source of the User type, users are not stored and only derived from subscriptions' user_id
+type user struct {
+	ID uuid.UUID
+}
+
+var monthType = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "Month",
+	Description: "Month in the MM-YYYY format",
+	Serialize: func(value any) any {
+		switch v := value.(type) {
+		case monthyear.MonthYear:
+			return time.Time(v).Format(monthyear.DateLayout)
+		case *monthyear.MonthYear:
+			if v == nil {
+				return nil
+			}
+			return time.Time(*v).Format(monthyear.DateLayout)
+		}
+		return nil
+	},
+	ParseValue: func(value any) any {
+		if s, ok := value.(string); ok {
+			return parseMonth(s)
+		}
+		return nil
+	},
+	ParseLiteral: func(value ast.Value) any {
+		if s, ok := value.(*ast.StringValue); ok {
+			return parseMonth(s.Value)
+		}
+		return nil
+	},
+})
+
+// parseMonth returns nil for invalid months, which GraphQL reports as an invalid argument
+func parseMonth(s string) any {
+	t, err := time.Parse(monthyear.DateLayout, s)
+	if err != nil {
+		return nil
+	}
+	return monthyear.MonthYear(t)
+}
+
+var costGroupType = graphql.NewEnum(graphql.EnumConfig{
+	Name: "CostGroup",
+	Values: graphql.EnumValueConfigMap{
+		"SERVICE":        {Value: "service", Description: "By service name"},
+		"USER":           {Value: "user", Description: "By user ID"},
+		"MONTH":          {Value: "month", Description: "By billed month, in chronological order"},
+		"CATEGORY":       {Value: "category", Description: "By category"},
+		"TAG":            {Value: "tag", Description: "By tag, a subscription is counted under each of its tags and untagged ones under an empty key"},
+		"PAYMENT_METHOD": {Value: "payment_method", Description: "By payment method name, members' shares of shared subscriptions and unlinked ones under an empty key"},
+	},
+})
+
+// costArgs are the arguments of cost report fields, query level reports also accept userId
+var costArgs = graphql.FieldConfigArgument{
+	"startDate":   {Type: monthType, Description: "Period start, inclusive (defaults to the subscription start)"},
+	"endDate":     {Type: monthType, Description: "Period end, inclusive (defaults to the current month)"},
+	"serviceName": {Type: graphql.String, Description: "Service name or catalog alias filter (partial match)"},
+	"serviceId":   {Type: graphql.ID, Description: "Catalog service filter"},
+	"tags":        {Type: graphql.NewList(graphql.NewNonNull(graphql.String)), Description: "Subscriptions having all of the tags"},
+	"category":    {Type: graphql.String, Description: "Category filter, ignoring case"},
+}
+
+func newSchema(r *resolver) (graphql.Schema, error) {
+	costReportItemType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "CostReportItem",
+		Fields: graphql.Fields{
+			"key": {
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "Service name, user ID, MM-YYYY month, category or tag",
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(models.CostBreakdownItem).Key, nil
+				},
+			},
+			"totalCost": {
+				Type: graphql.NewNonNull(graphql.Int),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(models.CostBreakdownItem).TotalCost, nil
+				},
+			},
+		},
+	})
+
+	costReportType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "CostReport",
+		Fields: graphql.Fields{
+			"groupBy": {
+				Type: graphql.NewNonNull(costGroupType),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(models.CostBreakdownResponse).GroupBy, nil
+				},
+			},
+			"totalCost": {
+				Type: graphql.NewNonNull(graphql.Int),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(models.CostBreakdownResponse).TotalCost, nil
+				},
+			},
+			"items": {
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(costReportItemType))),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(models.CostBreakdownResponse).Items, nil
+				},
+			},
+		},
+	})
+
+	// User and Subscription reference each other, so their fields are thunks
+	var subscriptionType *graphql.Object
+	userType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "User",
+		Description: "A user owning subscriptions",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id": {
+					Type: graphql.NewNonNull(graphql.ID),
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return p.Source.(user).ID.String(), nil
+					},
+				},
+				"subscriptions": {
+					Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(subscriptionType))),
+					Description: "All subscriptions of the user, ordered by start date",
+					Resolve:     r.userSubscriptions,
+				},
+				"costReport": {
+					Type:        graphql.NewNonNull(costReportType),
+					Description: "Cost of the user's subscriptions, by month unless groupBy is set",
+					Args: withArgs(costArgs, graphql.FieldConfigArgument{
+						"groupBy": {Type: costGroupType, DefaultValue: "month"},
+					}),
+					Resolve: r.userCostReport,
+				},
+			}
+		}),
+	})
+
+	subscriptionType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Subscription",
+		Fields: graphql.Fields{
+			"id": {
+				Type: graphql.NewNonNull(graphql.ID),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(models.SubscriptionResponse).ID.String(), nil
+				},
+			},
+			"serviceName": {
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(models.SubscriptionResponse).ServiceName, nil
+				},
+			},
+			"serviceId": {
+				Type:        graphql.ID,
+				Description: "Catalog service the name is matched to, null if none",
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					if id := p.Source.(models.SubscriptionResponse).ServiceID; id != nil {
+						return id.String(), nil
+					}
+					return nil, nil
+				},
+			},
+			"planId": {
+				Type:        graphql.ID,
+				Description: "Plan from the start date until the first plan change, null if none",
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					if id := p.Source.(models.SubscriptionResponse).PlanID; id != nil {
+						return id.String(), nil
+					}
+					return nil, nil
+				},
+			},
+			"price": {
+				Type:        graphql.NewNonNull(graphql.Int),
+				Description: "Monthly price in rubles from the start date until the first plan change",
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(models.SubscriptionResponse).Price, nil
+				},
+			},
+			"currentPrice": {
+				Type:        graphql.NewNonNull(graphql.Int),
+				Description: "Monthly price in rubles in the current month, after plan changes",
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(models.SubscriptionResponse).CurrentPrice, nil
+				},
+			},
+			"userId": {
+				Type: graphql.NewNonNull(graphql.ID),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(models.SubscriptionResponse).UserID.String(), nil
+				},
+			},
+			"user": {
+				Type: graphql.NewNonNull(userType),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return user{ID: p.Source.(models.SubscriptionResponse).UserID}, nil
+				},
+			},
+			"startDate": {
+				Type: graphql.NewNonNull(monthType),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(models.SubscriptionResponse).StartDate, nil
+				},
+			},
+			"endDate": {
+				Type:        monthType,
+				Description: "Last month of the subscription, null while it is active",
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(models.SubscriptionResponse).EndDate, nil
+				},
+			},
+			"billingDay": {
+				Type:        graphql.NewNonNull(graphql.Int),
+				Description: "Day of month charges happen on, the last day in shorter months",
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(models.SubscriptionResponse).BillingDay, nil
+				},
+			},
+			"trialMonths": {
+				Type:        graphql.NewNonNull(graphql.Int),
+				Description: "Trial months from the start date, 0 without a trial",
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(models.SubscriptionResponse).TrialMonths, nil
+				},
+			},
+			"trialPrice": {
+				Type:        graphql.NewNonNull(graphql.Int),
+				Description: "Monthly price in rubles during the trial",
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(models.SubscriptionResponse).TrialPrice, nil
+				},
+			},
+			"trialEndDate": {
+				Type:        monthType,
+				Description: "Last month of the trial, null without a trial",
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(models.SubscriptionResponse).TrialEndDate, nil
+				},
+			},
+			"state": {
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "State in the current month: active, paused or ended",
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(models.SubscriptionResponse).State, nil
+				},
+			},
+			"accountLabel": {
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "Account of the service, subscriptions of the same account do not overlap in time",
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(models.SubscriptionResponse).AccountLabel, nil
+				},
+			},
+			"category": {
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(models.SubscriptionResponse).Category, nil
+				},
+			},
+			"tags": {
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+				Description: "Lowercased tags in alphabetical order",
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(models.SubscriptionResponse).Tags, nil
+				},
+			},
+			"paymentMethodId": {
+				Type:        graphql.ID,
+				Description: "Payment method of the user the subscription is charged to, null if none",
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					if id := p.Source.(models.SubscriptionResponse).PaymentMethodID; id != nil {
+						return id.String(), nil
+					}
+					return nil, nil
+				},
+			},
+		},
+	})
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"me": {
+				Type:        userType,
+				Description: "The authenticated user, null when authentication is disabled",
+				Resolve:     r.me,
+			},
+			"user": {
+				Type: graphql.NewNonNull(userType),
+				Args: graphql.FieldConfigArgument{
+					"id": {Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: r.user,
+			},
+			"users": {
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(userType))),
+				Args: graphql.FieldConfigArgument{
+					"ids": {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.ID)))},
+				},
+				Resolve: r.users,
+			},
+			"subscription": {
+				Type: subscriptionType,
+				Args: graphql.FieldConfigArgument{
+					"id": {Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: r.subscription,
+			},
+			"subscriptions": {
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(subscriptionType))),
+				Description: "A page of subscriptions ordered by start date and ID, pass the last one as after* to get the next page",
+				Args: graphql.FieldConfigArgument{
+					"first":          {Type: graphql.Int, DefaultValue: defaultFirst},
+					"afterId":        {Type: graphql.ID},
+					"afterStartDate": {Type: monthType},
+					"tags":           {Type: graphql.NewList(graphql.NewNonNull(graphql.String)), Description: "Subscriptions having all of the tags"},
+				},
+				Resolve: r.subscriptions,
+			},
+			"costReport": {
+				Type: graphql.NewNonNull(costReportType),
+				Args: withArgs(costArgs, graphql.FieldConfigArgument{
+					"userId":  {Type: graphql.ID},
+					"groupBy": {Type: costGroupType, DefaultValue: "service"},
+				}),
+				Resolve: r.costReport,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+}
+
+func withArgs(args ...graphql.FieldConfigArgument) graphql.FieldConfigArgument {
+	merged := graphql.FieldConfigArgument{}
+	for _, a := range args {
+		for name, arg := range a {
+			merged[name] = arg
+		}
+	}
+	return merged
+}
@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/api/problem"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+)
+
+// Default periods of upcoming charges: a week for the list and a year for calendar feeds
+const (
+	defaultChargeDays   = 7
+	defaultCalendarDays = 365
+)
+
+func parseUpcomingChargesRequest(r *http.Request, defaultDays int) (models.UpcomingChargesRequest, error) {
+	req := models.UpcomingChargesRequest{Days: defaultDays}
+	query := r.URL.Query()
+
+	if rawDays := query.Get("days"); rawDays != "" {
+		days, err := strconv.Atoi(rawDays)
+		if err != nil {
+			return req, problem.InvalidParam("days", problem.ParamInteger)
+		}
+		req.Days = days
+	}
+	if rawUserID := query.Get("user_id"); rawUserID != "" {
+		userID, err := uuid.Parse(rawUserID)
+		if err != nil {
+			return req, problem.InvalidParam("user_id", problem.ParamUUID)
+		}
+		req.UserID = &userID
+	}
+	return req, nil
+}
+
+// ListUpcomingCharges godoc
+// @Summary List upcoming charges
+// @Description List charges from today for a number of days, each on the billing day of its subscription.
+// @Description Free trial months and paused months are not charged. Non-admins get only their own charges.
+// @Tags subscriptions
+// @Produce json
+// @Param days query int false "Number of days from today, 1 to 366 (defaults to 7)"
+// @Param user_id query string false "User ID" format(uuid)
+// @Param X-Tenant-ID header string false "Tenant ID (defaults to the tenant_id claim or APP_DEFAULT_TENANT)"
+// @Success 200 {array} models.ChargeResponse
+// @Failure 400 {object} models.Problem "Bad request"
+// @Failure 401 {object} models.Problem "Missing or invalid token"
+// @Failure 403 {object} models.Problem "Access to another user's subscriptions"
+// @Failure 429 {object} models.Problem "Rate limit exceeded"
+// @Failure 500 {object} models.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /subscriptions/upcoming-charges [get]
+func (h *Handler) ListUpcomingCharges(w http.ResponseWriter, r *http.Request) {
+	req, err := parseUpcomingChargesRequest(r, defaultChargeDays)
+	if err != nil {
+		problem.Error(w, r, "parse request", err)
+		return
+	}
+
+	if err := h.Validator.Struct(&req); err != nil {
+		problem.Error(w, r, "validate request", err)
+		return
+	}
+
+	resp, err := h.Service.ListUpcomingCharges(r.Context(), req)
+	if err != nil {
+		problem.Error(w, r, "list upcoming charges", err)
+		return
+	}
+
+	h.writeJSONResponse(w, resp, http.StatusOK)
+}
+
+// GetCalendar godoc
+// @Summary Calendar feed of upcoming charges
+// @Description An iCalendar feed with an all-day event on the day of each upcoming charge, for calendar apps.
+// @Description Takes the same parameters as /subscriptions/upcoming-charges, days defaults to 365.
+// @Tags subscriptions
+// @Produce text/calendar
+// @Param days query int false "Number of days from today, 1 to 366 (defaults to 365)"
+// @Param user_id query string false "User ID" format(uuid)
+// @Param X-Tenant-ID header string false "Tenant ID (defaults to the tenant_id claim or APP_DEFAULT_TENANT)"
+// @Success 200 {string} string "iCalendar feed"
+// @Failure 400 {object} models.Problem "Bad request"
+// @Failure 401 {object} models.Problem "Missing or invalid token"
+// @Failure 403 {object} models.Problem "Access to another user's subscriptions"
+// @Failure 429 {object} models.Problem "Rate limit exceeded"
+// @Failure 500 {object} models.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /subscriptions/calendar.ics [get]
+func (h *Handler) GetCalendar(w http.ResponseWriter, r *http.Request) {
+	req, err := parseUpcomingChargesRequest(r, defaultCalendarDays)
+	if err != nil {
+		problem.Error(w, r, "parse request", err)
+		return
+	}
+
+	if err := h.Validator.Struct(&req); err != nil {
+		problem.Error(w, r, "validate request", err)
+		return
+	}
+
+	charges, err := h.Service.ListUpcomingCharges(r.Context(), req)
+	if err != nil {
+		problem.Error(w, r, "list upcoming charges", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="subscriptions.ics"`)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(calendar(charges, time.Now())); err != nil {
+		slog.Error("failed to write calendar response", "error", err)
+	}
+}
+
+// calendar renders charges as an iCalendar (RFC 5545) feed. Event UIDs are stable per subscription and day,
+// so calendar apps update the events of a refreshed feed instead of duplicating them.
+func calendar(charges []models.ChargeResponse, now time.Time) []byte {
+	var b strings.Builder
+	line := func(format string, args ...any) {
+		b.WriteString(foldLine(fmt.Sprintf(format, args...)))
+		b.WriteString("\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//subscription-aggregator//charges//RU")
+	line("CALSCALE:GREGORIAN")
+	line("X-WR-CALNAME:%s", escapeText("Списания за подписки"))
+	stamp := now.UTC().Format("20060102T150405Z")
+	for _, charge := range charges {
+		date, err := time.Parse(time.DateOnly, charge.Date)
+		if err != nil {
+			continue
+		}
+		line("BEGIN:VEVENT")
+		line("UID:%s-%s@subscription-aggregator", charge.ID, date.Format("20060102"))
+		line("DTSTAMP:%s", stamp)
+		line("DTSTART;VALUE=DATE:%s", date.Format("20060102"))
+		line("DTEND;VALUE=DATE:%s", date.AddDate(0, 0, 1).Format("20060102"))
+		line("SUMMARY:%s", escapeText(fmt.Sprintf("%s: %d ₽", charge.ServiceName, charge.Amount)))
+		line("DESCRIPTION:%s", escapeText(fmt.Sprintf("Списание за подписку %s, %d ₽", charge.ServiceName, charge.Amount)))
+		line("TRANSP:TRANSPARENT")
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return []byte(b.String())
+}
+
+// escapeText escapes an iCalendar TEXT value
+func escapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// foldLine splits a content line longer than 75 octets into continuation lines starting with a space,
+// without splitting UTF-8 sequences
+func foldLine(s string) string {
+	const limit = 75
+	var b strings.Builder
+	width := 0
+	for _, r := range s {
+		size := utf8.RuneLen(r)
+		if width+size > limit {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
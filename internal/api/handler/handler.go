@@ -3,16 +3,18 @@ package handler
 import (
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"strconv"
 
 	"github.com/google/uuid"
 	"log/slog"
 
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/api/problem"
 	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
-	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/repository"
 	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/service"
 	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/validation"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/logger"
 	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/monthyear"
 )
 
@@ -30,41 +32,41 @@ func NewHandler(service service.SubscriptionService) Handler {
 
 // Create godoc
 // @Summary Create a new subscription
-// @Description Create a new subscription for a user
+// @Description Create a new subscription for a user. The service name is matched to the catalog by name or alias
+// @Description ignoring case and replaced with the canonical name, service_id selects a catalog service explicitly.
+// @Description plan_id selects a plan of a catalog service, the price defaults to the monthly price of the plan.
+// @Description The category defaults to the category of the catalog service, tags are stored lowercased.
+// @Description Subscriptions of a user to the same service and account_label must not overlap in time.
 // @Tags subscriptions
 // @Accept json
 // @Produce json
 // @Param subscription body models.CreateSubscriptionRequest true "Subscription data"
+// @Param X-Tenant-ID header string false "Tenant ID (defaults to the tenant_id claim or APP_DEFAULT_TENANT)"
 // @Success 201 {object} models.SubscriptionResponse
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 409 {object} map[string]string "Subscription already exists"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} models.Problem "Bad request"
+// @Failure 409 {object} models.Problem "Subscription of the same account overlaps in time"
+// @Failure 401 {object} models.Problem "Missing or invalid token"
+// @Failure 403 {object} models.Problem "Access to another user's subscriptions"
+// @Failure 429 {object} models.Problem "Rate limit exceeded"
+// @Failure 500 {object} models.Problem "Internal server error"
+// @Security BearerAuth
 // @Router /subscriptions [post]
 func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	var req models.CreateSubscriptionRequest
 	err := json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
-		http.Error(w, "Invalid JSON format", http.StatusBadRequest)
+		problem.Error(w, r, "decode request", problem.InvalidJSON(err))
 		return
 	}
 
 	if err := h.Validator.Struct(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		problem.Error(w, r, "validate request", err)
 		return
 	}
 
 	resp, err := h.Service.CreateSubscription(r.Context(), req)
 	if err != nil {
-		if errors.Is(err, repository.ErrSubscriptionAlreadyExists) {
-			http.Error(w, repository.ErrSubscriptionAlreadyExists.Error(), http.StatusConflict)
-			return
-		}
-		if errors.Is(err, service.ErrInvalidDateRange) {
-			http.Error(w, service.ErrInvalidDateRange.Error(), http.StatusBadRequest)
-			return
-		}
-		slog.Error("service failed to create subscription", "error", err)
-		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		problem.Error(w, r, "create subscription", err)
 		return
 	}
 
@@ -73,43 +75,62 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 
 // List godoc
 // @Summary List subscriptions
-// @Description List subscriptions from the system
+// @Description List subscriptions ordered by start date and ID. Pass the start date and ID of the last
+// @Description subscription of a page as previous_start_date and previous_id to get the next page.
 // @Tags subscriptions
 // @Produce json
+// @Param limit query int true "Page size" minimum(1)
+// @Param previous_id query string false "ID of the last subscription of the previous page" format(uuid)
+// @Param previous_start_date query string false "Start date of the last subscription of the previous page" format(MM-YYYY)
+// @Param tag query []string false "Tag, repeat for subscriptions having all of the tags" collectionFormat(multi)
+// @Param X-Tenant-ID header string false "Tenant ID (defaults to the tenant_id claim or APP_DEFAULT_TENANT)"
 // @Success 200 {array} models.SubscriptionResponse
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} models.Problem "Bad request"
+// @Failure 401 {object} models.Problem "Missing or invalid token"
+// @Failure 429 {object} models.Problem "Rate limit exceeded"
+// @Failure 500 {object} models.Problem "Internal server error"
+// @Security BearerAuth
 // @Router /subscriptions [get]
 func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
 	req := models.ListSubscriptionsRequest{}
+	query := r.URL.Query()
 
-	limit, err := strconv.Atoi(r.PathValue("limit"))
+	limit, err := strconv.Atoi(query.Get("limit"))
 	if err != nil {
-		http.Error(w, "limit required", http.StatusBadRequest)
+		problem.Error(w, r, "parse request", problem.InvalidParam("limit", problem.ParamInteger))
+		return
 	}
 	req.Limit = limit
 
-	rawID := r.PathValue("previous_id")
+	rawID := query.Get("previous_id")
 	if rawID != "" {
 		id, err := uuid.Parse(rawID)
 		if err != nil {
-			slog.Debug("invalid id provided", "id", rawID, "err", err)
-			http.Error(w, "invalid previous_id format", http.StatusBadRequest)
+			logger.FromContext(r.Context()).DebugContext(r.Context(), "invalid id provided", "id", rawID, "err", err)
+			problem.Error(w, r, "parse request", problem.InvalidParam("previous_id", problem.ParamUUID))
+			return
 		}
 
 		var startDate monthyear.MonthYear
-		if err := startDate.UnmarshalJSON([]byte(r.PathValue("previous_start_date"))); err != nil {
-			slog.Debug("invalid start date provided", "err", err)
-			http.Error(w, "invalid previous_start_date format", http.StatusBadRequest)
+		if err := startDate.UnmarshalJSON([]byte(`"` + query.Get("previous_start_date") + `"`)); err != nil {
+			logger.FromContext(r.Context()).DebugContext(r.Context(), "invalid start date provided", "err", err)
+			problem.Error(w, r, "parse request", problem.InvalidParam("previous_start_date", problem.ParamMonth))
+			return
 		}
 
 		req.Cursor = &models.SubscriptionCursor{ID: id, StartDate: startDate}
 	}
+	req.Tags = query["tag"]
+
+	if err := h.Validator.Struct(&req); err != nil {
+		problem.Error(w, r, "validate request", err)
+		return
+	}
 
 	resp, err := h.Service.ListSubscriptions(r.Context(), req)
 	if err != nil {
-		slog.Error("repo failed to get all subscriptions", "error", err)
-		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		problem.Error(w, r, "list subscriptions", err)
+		return
 	}
 
 	h.writeJSONResponse(w, resp, http.StatusOK)
@@ -117,30 +138,31 @@ func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
 
 // GetByID godoc
 // @Summary Get a subscription by ID
-// @Description Get a single subscription by its ID
+// @Description Get a single subscription by its ID, available to its owner and members of a shared subscription.
+// @Description Users other than admins get 403 rather than 404 for a missing subscription.
 // @Tags subscriptions
 // @Produce json
 // @Param id path string true "Subscription ID" format(uuid)
+// @Param X-Tenant-ID header string false "Tenant ID (defaults to the tenant_id claim or APP_DEFAULT_TENANT)"
 // @Success 200 {object} models.SubscriptionResponse
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 404 {object} map[string]string "Subscription not found"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} models.Problem "Bad request"
+// @Failure 404 {object} models.Problem "Subscription not found"
+// @Failure 401 {object} models.Problem "Missing or invalid token"
+// @Failure 403 {object} models.Problem "Access to another user's subscriptions"
+// @Failure 429 {object} models.Problem "Rate limit exceeded"
+// @Failure 500 {object} models.Problem "Internal server error"
+// @Security BearerAuth
 // @Router /subscriptions/{id} [get]
 func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
 	id, err := uuid.Parse(r.PathValue("id"))
 	if err != nil {
-		http.Error(w, "invalid subscription ID format", http.StatusBadRequest)
+		problem.Error(w, r, "parse request", problem.InvalidParam("id", problem.ParamUUID))
 		return
 	}
 
 	resp, err := h.Service.GetSubscriptionByID(r.Context(), id)
 	if err != nil {
-		if errors.Is(err, repository.ErrSubscriptionNotFound) {
-			http.Error(w, repository.ErrSubscriptionNotFound.Error(), http.StatusNotFound)
-			return
-		}
-		slog.Error("service failed to get subscription", "error", err)
-		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		problem.Error(w, r, "get subscription", err)
 		return
 	}
 
@@ -149,48 +171,46 @@ func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
 
 // Update godoc
 // @Summary Update a subscription
-// @Description Update an existing subscription
+// @Description Update an existing subscription. add_tags and remove_tags change tags without replacing the others,
+// @Description tags in both are removed first and added back.
 // @Tags subscriptions
 // @Accept json
 // @Produce json
 // @Param id path string true "Subscription ID" format(uuid)
 // @Param subscription body models.UpdateSubscriptionRequest true "Updated subscription data"
+// @Param X-Tenant-ID header string false "Tenant ID (defaults to the tenant_id claim or APP_DEFAULT_TENANT)"
 // @Success 200 {object} models.SubscriptionResponse
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 404 {object} map[string]string "Subscription not found"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} models.Problem "Bad request"
+// @Failure 404 {object} models.Problem "Subscription not found"
+// @Failure 401 {object} models.Problem "Missing or invalid token"
+// @Failure 403 {object} models.Problem "Access to another user's subscriptions"
+// @Failure 409 {object} models.Problem "Subscription of the same account overlaps in time"
+// @Failure 429 {object} models.Problem "Rate limit exceeded"
+// @Failure 500 {object} models.Problem "Internal server error"
+// @Security BearerAuth
 // @Router /subscriptions/{id} [patch]
 func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 	subscriptionID, err := uuid.Parse(r.PathValue("id"))
 	if err != nil {
-		http.Error(w, "invalid subscription ID format", http.StatusBadRequest)
+		problem.Error(w, r, "parse request", problem.InvalidParam("id", problem.ParamUUID))
 		return
 	}
 
 	var req models.UpdateSubscriptionRequest
 	err = json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
-		http.Error(w, "invalid JSON format", http.StatusBadRequest)
+		problem.Error(w, r, "decode request", problem.InvalidJSON(err))
 		return
 	}
 
 	if err := h.Validator.Struct(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		problem.Error(w, r, "validate request", err)
 		return
 	}
 
 	resp, err := h.Service.UpdateSubscription(r.Context(), subscriptionID, req)
 	if err != nil {
-		if errors.Is(err, repository.ErrSubscriptionNotFound) {
-			http.Error(w, err.Error(), http.StatusNotFound)
-			return
-		}
-		if errors.Is(err, service.ErrInvalidDateRange) {
-			http.Error(w, service.ErrInvalidDateRange.Error(), http.StatusBadRequest)
-			return
-		}
-		slog.Error("service failed to update subscription", "error", err)
-		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		problem.Error(w, r, "update subscription", err)
 		return
 	}
 
@@ -202,63 +222,253 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 // @Description Delete a subscription by ID
 // @Tags subscriptions
 // @Param id path string true "Subscription ID" format(uuid)
+// @Param X-Tenant-ID header string false "Tenant ID (defaults to the tenant_id claim or APP_DEFAULT_TENANT)"
 // @Success 204 "No content"
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 404 {object} map[string]string "Subscription not found"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} models.Problem "Bad request"
+// @Failure 404 {object} models.Problem "Subscription not found"
+// @Failure 401 {object} models.Problem "Missing or invalid token"
+// @Failure 403 {object} models.Problem "Access to another user's subscriptions"
+// @Failure 429 {object} models.Problem "Rate limit exceeded"
+// @Failure 500 {object} models.Problem "Internal server error"
+// @Security BearerAuth
 // @Router /subscriptions/{id} [delete]
 func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 	subscriptionID, err := uuid.Parse(r.PathValue("id"))
 	if err != nil {
-		http.Error(w, "invalid subscription ID format", http.StatusBadRequest)
+		problem.Error(w, r, "parse request", problem.InvalidParam("id", problem.ParamUUID))
 		return
 	}
 
 	err = h.Service.DeleteSubscription(r.Context(), subscriptionID)
 	if err != nil {
-		if errors.Is(err, repository.ErrSubscriptionNotFound) {
-			http.Error(w, repository.ErrSubscriptionNotFound.Error(), http.StatusNotFound)
-			return
-		}
-		slog.Error("service failed to delete subscription", "error", err)
-		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		problem.Error(w, r, "delete subscription", err)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// Pause godoc
+// @Summary Pause a subscription
+// @Description Pause a subscription from start_date (the current month by default) to end_date inclusive or until it is resumed.
+// @Description Paused months are not billed. The body is optional.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID" format(uuid)
+// @Param pause body models.PauseSubscriptionRequest false "Pause period"
+// @Param X-Tenant-ID header string false "Tenant ID (defaults to the tenant_id claim or APP_DEFAULT_TENANT)"
+// @Success 200 {object} models.SubscriptionResponse
+// @Failure 400 {object} models.Problem "Bad request"
+// @Failure 404 {object} models.Problem "Subscription not found"
+// @Failure 409 {object} models.Problem "Subscription is already paused in the period"
+// @Failure 401 {object} models.Problem "Missing or invalid token"
+// @Failure 403 {object} models.Problem "Access to another user's subscriptions"
+// @Failure 429 {object} models.Problem "Rate limit exceeded"
+// @Failure 500 {object} models.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /subscriptions/{id}/pause [post]
+func (h *Handler) Pause(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		problem.Error(w, r, "parse request", problem.InvalidParam("id", problem.ParamUUID))
+		return
+	}
+
+	var req models.PauseSubscriptionRequest
+	if err := decodeOptional(r, &req); err != nil {
+		problem.Error(w, r, "decode request", problem.InvalidJSON(err))
+		return
+	}
+
+	if err := h.Validator.Struct(&req); err != nil {
+		problem.Error(w, r, "validate request", err)
+		return
+	}
+
+	resp, err := h.Service.PauseSubscription(r.Context(), subscriptionID, req)
+	if err != nil {
+		problem.Error(w, r, "pause subscription", err)
+		return
+	}
+
+	h.writeJSONResponse(w, resp, http.StatusOK)
+}
+
+// Resume godoc
+// @Summary Resume a subscription
+// @Description End the pause in effect in month (the current month by default), the month is billed again. The body is optional.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID" format(uuid)
+// @Param resume body models.ResumeSubscriptionRequest false "Resume month"
+// @Param X-Tenant-ID header string false "Tenant ID (defaults to the tenant_id claim or APP_DEFAULT_TENANT)"
+// @Success 200 {object} models.SubscriptionResponse
+// @Failure 400 {object} models.Problem "Bad request"
+// @Failure 404 {object} models.Problem "Subscription not found"
+// @Failure 409 {object} models.Problem "Subscription is not paused in the month"
+// @Failure 401 {object} models.Problem "Missing or invalid token"
+// @Failure 403 {object} models.Problem "Access to another user's subscriptions"
+// @Failure 429 {object} models.Problem "Rate limit exceeded"
+// @Failure 500 {object} models.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /subscriptions/{id}/resume [post]
+func (h *Handler) Resume(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		problem.Error(w, r, "parse request", problem.InvalidParam("id", problem.ParamUUID))
+		return
+	}
+
+	var req models.ResumeSubscriptionRequest
+	if err := decodeOptional(r, &req); err != nil {
+		problem.Error(w, r, "decode request", problem.InvalidJSON(err))
+		return
+	}
+
+	resp, err := h.Service.ResumeSubscription(r.Context(), subscriptionID, req)
+	if err != nil {
+		problem.Error(w, r, "resume subscription", err)
+		return
+	}
+
+	h.writeJSONResponse(w, resp, http.StatusOK)
+}
+
 // GetTotalCost godoc
 // @Summary Get total cost of subscriptions
-// @Description Calculate total cost of subscriptions with optional filters
+// @Description Calculate total cost of subscriptions for the months of a period with optional filters.
+// @Description Every subscription active in a month of the period is charged for that month.
+// @Description Breaking change: start_date and end_date used to filter subscriptions by their own start and end
+// @Description dates and charge them for their whole term; they now bound the calculation period.
 // @Tags subscriptions
 // @Produce json
 // @Param user_id query string false "User ID" format(uuid)
-// @Param service_name query string false "Service name (partial match)"
-// @Param start_date query string false "Start date filter" format(MM-YYYY)
-// @Param end_date query string false "End date filter" format(MM-YYYY)
+// @Param service_name query string false "Service name or catalog alias (partial match)"
+// @Param service_id query string false "Catalog service ID" format(uuid)
+// @Param start_date query string false "Period start, inclusive (defaults to the start of each subscription)" format(MM-YYYY)
+// @Param end_date query string false "Period end, inclusive (defaults to the current month)" format(MM-YYYY)
+// @Param tag query []string false "Tag, repeat for subscriptions having all of the tags" collectionFormat(multi)
+// @Param category query string false "Category, ignoring case"
+// @Param X-Tenant-ID header string false "Tenant ID (defaults to the tenant_id claim or APP_DEFAULT_TENANT)"
 // @Success 200 {object} models.TotalCostResponse
-// @Failure 400 {object} map[string]string "Bad request"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} models.Problem "Bad request"
+// @Failure 401 {object} models.Problem "Missing or invalid token"
+// @Failure 403 {object} models.Problem "Access to another user's subscriptions"
+// @Failure 429 {object} models.Problem "Rate limit exceeded"
+// @Failure 500 {object} models.Problem "Internal server error"
+// @Security BearerAuth
 // @Router /subscriptions/total-cost [get]
 func (h *Handler) GetTotalCost(w http.ResponseWriter, r *http.Request) {
 	// Don't bother adding custom converters for uuid and monthyear to use tag parsing just in one place,
 	// so parse it manually
 	req, err := h.parseTotalCostRequest(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		problem.Error(w, r, "validate request", err)
 		return
 	}
 
 	if err := h.Validator.Struct(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		problem.Error(w, r, "validate request", err)
 		return
 	}
 
 	resp, err := h.Service.GetTotalCost(r.Context(), req)
 	if err != nil {
-		slog.Error("service failed to get total cost", "error", err)
-		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		problem.Error(w, r, "get total cost", err)
+		return
+	}
+
+	h.writeJSONResponse(w, resp, http.StatusOK)
+}
+
+// GetCostBreakdown godoc
+// @Summary Get cost breakdown of subscriptions
+// @Description Calculate cost of subscriptions for the months of a period grouped by service, user, month, category or tag.
+// @Description By tag a subscription is counted in the group of each of its tags and subscriptions without tags
+// @Description in the group with an empty key, so the groups may add up to more than total_cost.
+// @Tags subscriptions
+// @Produce json
+// @Param group_by query string false "Grouping" Enums(service, user, month, category, tag) default(service)
+// @Param user_id query string false "User ID" format(uuid)
+// @Param service_name query string false "Service name or catalog alias (partial match)"
+// @Param service_id query string false "Catalog service ID" format(uuid)
+// @Param start_date query string false "Period start, inclusive" format(MM-YYYY)
+// @Param end_date query string false "Period end, inclusive (defaults to the current month)" format(MM-YYYY)
+// @Param tag query []string false "Tag, repeat for subscriptions having all of the tags" collectionFormat(multi)
+// @Param category query string false "Category, ignoring case"
+// @Param X-Tenant-ID header string false "Tenant ID (defaults to the tenant_id claim or APP_DEFAULT_TENANT)"
+// @Success 200 {object} models.CostBreakdownResponse
+// @Failure 400 {object} models.Problem "Bad request"
+// @Failure 401 {object} models.Problem "Missing or invalid token"
+// @Failure 403 {object} models.Problem "Access to another user's subscriptions"
+// @Failure 429 {object} models.Problem "Rate limit exceeded"
+// @Failure 500 {object} models.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /subscriptions/cost-breakdown [get]
+func (h *Handler) GetCostBreakdown(w http.ResponseWriter, r *http.Request) {
+	filter, err := h.parseTotalCostRequest(r)
+	if err != nil {
+		problem.Error(w, r, "validate request", err)
+		return
+	}
+	req := models.CostBreakdownRequest{TotalCostRequest: filter, GroupBy: r.URL.Query().Get("group_by")}
+
+	if err := h.Validator.Struct(&req); err != nil {
+		problem.Error(w, r, "validate request", err)
+		return
+	}
+
+	resp, err := h.Service.GetCostBreakdown(r.Context(), req)
+	if err != nil {
+		problem.Error(w, r, "get cost breakdown", err)
+		return
+	}
+
+	h.writeJSONResponse(w, resp, http.StatusOK)
+}
+
+// ListTrialsEnding godoc
+// @Summary List subscriptions with ending trials
+// @Description List subscriptions whose trial period ends before a month, so the month is the first one billed at the regular price
+// @Tags subscriptions
+// @Produce json
+// @Param month query string false "First month at the regular price (defaults to the next month)" format(MM-YYYY)
+// @Param user_id query string false "User ID" format(uuid)
+// @Param X-Tenant-ID header string false "Tenant ID (defaults to the tenant_id claim or APP_DEFAULT_TENANT)"
+// @Success 200 {array} models.SubscriptionResponse
+// @Failure 400 {object} models.Problem "Bad request"
+// @Failure 401 {object} models.Problem "Missing or invalid token"
+// @Failure 403 {object} models.Problem "Access to another user's subscriptions"
+// @Failure 429 {object} models.Problem "Rate limit exceeded"
+// @Failure 500 {object} models.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /subscriptions/trial-ending [get]
+func (h *Handler) ListTrialsEnding(w http.ResponseWriter, r *http.Request) {
+	var req models.TrialEndingRequest
+	query := r.URL.Query()
+
+	if rawMonth := query.Get("month"); rawMonth != "" {
+		var month monthyear.MonthYear
+		if err := month.UnmarshalJSON([]byte(`"` + rawMonth + `"`)); err != nil {
+			problem.Error(w, r, "parse request", problem.InvalidParam("month", problem.ParamMonth))
+			return
+		}
+		req.Month = &month
+	}
+	if rawUserID := query.Get("user_id"); rawUserID != "" {
+		userID, err := uuid.Parse(rawUserID)
+		if err != nil {
+			problem.Error(w, r, "parse request", problem.InvalidParam("user_id", problem.ParamUUID))
+			return
+		}
+		req.UserID = &userID
+	}
+
+	resp, err := h.Service.ListTrialsEnding(r.Context(), req)
+	if err != nil {
+		problem.Error(w, r, "list ending trials", err)
 		return
 	}
 
@@ -272,7 +482,7 @@ func (h *Handler) parseTotalCostRequest(r *http.Request) (models.TotalCostReques
 	if userIDStr := r.URL.Query().Get("user_id"); userIDStr != "" {
 		userID, err := uuid.Parse(userIDStr)
 		if err != nil {
-			return req, errors.New("invalid user_id format")
+			return req, problem.InvalidParam("user_id", problem.ParamUUID)
 		}
 		req.UserID = &userID
 	}
@@ -282,11 +492,20 @@ func (h *Handler) parseTotalCostRequest(r *http.Request) (models.TotalCostReques
 		req.ServiceName = &serviceName
 	}
 
+	// Parse service_id
+	if serviceIDStr := r.URL.Query().Get("service_id"); serviceIDStr != "" {
+		serviceID, err := uuid.Parse(serviceIDStr)
+		if err != nil {
+			return req, problem.InvalidParam("service_id", problem.ParamUUID)
+		}
+		req.ServiceID = &serviceID
+	}
+
 	// Parse start_date
 	if startDateStr := r.URL.Query().Get("start_date"); startDateStr != "" {
 		var startDate monthyear.MonthYear
 		if err := startDate.UnmarshalJSON([]byte(`"` + startDateStr + `"`)); err != nil {
-			return req, errors.New("invalid start_date format, expected MM-YYYY")
+			return req, problem.InvalidParam("start_date", problem.ParamMonth)
 		}
 		req.StartDate = &startDate
 	}
@@ -295,14 +514,28 @@ func (h *Handler) parseTotalCostRequest(r *http.Request) (models.TotalCostReques
 	if endDateStr := r.URL.Query().Get("end_date"); endDateStr != "" {
 		var endDate monthyear.MonthYear
 		if err := endDate.UnmarshalJSON([]byte(`"` + endDateStr + `"`)); err != nil {
-			return req, errors.New("invalid end_date format, expected MM-YYYY")
+			return req, problem.InvalidParam("end_date", problem.ParamMonth)
 		}
 		req.EndDate = &endDate
 	}
 
+	req.Tags = r.URL.Query()["tag"]
+	if category := r.URL.Query().Get("category"); category != "" {
+		req.Category = &category
+	}
+
 	return req, nil
 }
 
+// decodeOptional decodes a JSON body into v, an empty body leaves v unchanged
+func decodeOptional(r *http.Request, v any) error {
+	err := json.NewDecoder(r.Body).Decode(v)
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	return err
+}
+
 func (h *Handler) writeJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
@@ -310,3 +543,82 @@ func (h *Handler) writeJSONResponse(w http.ResponseWriter, data interface{}, sta
 		slog.Error("failed to write JSON response", "error", err)
 	}
 }
+
+// ChangePlan godoc
+// @Summary Change the plan of a subscription
+// @Description Move a subscription to another plan of its catalog service from start_date (the current month by default).
+// @Description Months from start_date are billed at price, by default the monthly price of the plan, until the next change.
+// @Description A change in the same month replaces the previous one.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID" format(uuid)
+// @Param change body models.ChangePlanRequest true "New plan"
+// @Param X-Tenant-ID header string false "Tenant ID (defaults to the tenant_id claim or APP_DEFAULT_TENANT)"
+// @Success 200 {object} models.SubscriptionResponse
+// @Failure 400 {object} models.Problem "Bad request"
+// @Failure 404 {object} models.Problem "Subscription not found"
+// @Failure 401 {object} models.Problem "Missing or invalid token"
+// @Failure 403 {object} models.Problem "Access to another user's subscriptions"
+// @Failure 429 {object} models.Problem "Rate limit exceeded"
+// @Failure 500 {object} models.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /subscriptions/{id}/plan-changes [post]
+func (h *Handler) ChangePlan(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		problem.Error(w, r, "parse request", problem.InvalidParam("id", problem.ParamUUID))
+		return
+	}
+
+	var req models.ChangePlanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Error(w, r, "decode request", problem.InvalidJSON(err))
+		return
+	}
+
+	if err := h.Validator.Struct(&req); err != nil {
+		problem.Error(w, r, "validate request", err)
+		return
+	}
+
+	resp, err := h.Service.ChangeSubscriptionPlan(r.Context(), subscriptionID, req)
+	if err != nil {
+		problem.Error(w, r, "change subscription plan", err)
+		return
+	}
+
+	h.writeJSONResponse(w, resp, http.StatusOK)
+}
+
+// ListPlanChanges godoc
+// @Summary List plan changes of a subscription
+// @Description List plan changes in chronological order. The plan and price of the subscription apply before the first change.
+// @Tags subscriptions
+// @Produce json
+// @Param id path string true "Subscription ID" format(uuid)
+// @Param X-Tenant-ID header string false "Tenant ID (defaults to the tenant_id claim or APP_DEFAULT_TENANT)"
+// @Success 200 {array} models.PlanChangeResponse
+// @Failure 400 {object} models.Problem "Bad request"
+// @Failure 404 {object} models.Problem "Subscription not found"
+// @Failure 401 {object} models.Problem "Missing or invalid token"
+// @Failure 403 {object} models.Problem "Access to another user's subscriptions"
+// @Failure 429 {object} models.Problem "Rate limit exceeded"
+// @Failure 500 {object} models.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /subscriptions/{id}/plan-changes [get]
+func (h *Handler) ListPlanChanges(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		problem.Error(w, r, "parse request", problem.InvalidParam("id", problem.ParamUUID))
+		return
+	}
+
+	resp, err := h.Service.ListPlanChanges(r.Context(), subscriptionID)
+	if err != nil {
+		problem.Error(w, r, "list plan changes", err)
+		return
+	}
+
+	h.writeJSONResponse(w, resp, http.StatusOK)
+}
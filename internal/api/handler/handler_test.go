@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/service"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/monthyear"
+)
+
+// fakeService records list and total cost requests, other methods panic through the nil interface
+type fakeService struct {
+	service.SubscriptionService
+
+	list  *models.ListSubscriptionsRequest
+	total *models.TotalCostRequest
+}
+
+func (s *fakeService) ListSubscriptions(_ context.Context, req models.ListSubscriptionsRequest) ([]models.SubscriptionResponse, error) {
+	s.list = &req
+	return []models.SubscriptionResponse{}, nil
+}
+
+func (s *fakeService) GetTotalCost(_ context.Context, req models.TotalCostRequest) (models.TotalCostResponse, error) {
+	s.total = &req
+	return models.TotalCostResponse{}, nil
+}
+
+func TestListReadsQuery(t *testing.T) {
+	previousID := uuid.New()
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantCursor *models.SubscriptionCursor
+		wantTags   []string
+	}{
+		{name: "first page", query: "limit=2", wantStatus: http.StatusOK},
+		{
+			name:       "next page",
+			query:      "limit=2&previous_id=" + previousID.String() + "&previous_start_date=03-2025&tag=work&tag=video",
+			wantStatus: http.StatusOK,
+			wantCursor: &models.SubscriptionCursor{ID: previousID, StartDate: monthOf(2025, time.March)},
+			wantTags:   []string{"work", "video"},
+		},
+		{name: "missing limit", query: "", wantStatus: http.StatusBadRequest},
+		{name: "zero limit", query: "limit=0", wantStatus: http.StatusBadRequest},
+		{name: "invalid previous_id", query: "limit=2&previous_id=abc&previous_start_date=03-2025", wantStatus: http.StatusBadRequest},
+		{name: "previous_id without start date", query: "limit=2&previous_id=" + previousID.String(), wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &fakeService{}
+			h := NewHandler(svc)
+			w := httptest.NewRecorder()
+			h.List(w, httptest.NewRequest(http.MethodGet, "/subscriptions?"+tt.query, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				if svc.list != nil {
+					t.Error("service called after an invalid request")
+				}
+				return
+			}
+			if svc.list.Limit != 2 {
+				t.Errorf("limit = %d, want 2", svc.list.Limit)
+			}
+			if (svc.list.Cursor == nil) != (tt.wantCursor == nil) || svc.list.Cursor != nil && *svc.list.Cursor != *tt.wantCursor {
+				t.Errorf("cursor = %+v, want %+v", svc.list.Cursor, tt.wantCursor)
+			}
+			if !slices.Equal(svc.list.Tags, tt.wantTags) {
+				t.Errorf("tags = %v, want %v", svc.list.Tags, tt.wantTags)
+			}
+		})
+	}
+}
+
+func TestTotalCostPeriod(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantStart  *time.Time
+		wantEnd    *time.Time
+	}{
+		{name: "open period", query: "", wantStatus: http.StatusOK},
+		{name: "one month", query: "start_date=03-2025&end_date=03-2025", wantStatus: http.StatusOK,
+			wantStart: ptr(time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)), wantEnd: ptr(time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC))},
+		{name: "only end", query: "end_date=12-2024", wantStatus: http.StatusOK, wantEnd: ptr(time.Date(2024, time.December, 1, 0, 0, 0, 0, time.UTC))},
+		{name: "end before start", query: "start_date=03-2025&end_date=02-2025", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &fakeService{}
+			h := NewHandler(svc)
+			w := httptest.NewRecorder()
+			h.GetTotalCost(w, httptest.NewRequest(http.MethodGet, "/subscriptions/total-cost?"+tt.query, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if !equalMonth(svc.total.StartDate, tt.wantStart) || !equalMonth(svc.total.EndDate, tt.wantEnd) {
+				t.Errorf("period = %v - %v, want %v - %v", svc.total.StartDate, svc.total.EndDate, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func monthOf(year int, month time.Month) monthyear.MonthYear {
+	return monthyear.MonthYear(time.Date(year, month, 1, 0, 0, 0, 0, time.UTC))
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func equalMonth(got *monthyear.MonthYear, want *time.Time) bool {
+	if got == nil || want == nil {
+		return got == nil && want == nil
+	}
+	return time.Time(*got).Equal(*want)
+}
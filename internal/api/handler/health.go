@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/health"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/logger"
+)
+
+type HealthHandler struct {
+	Checker *health.Checker
+}
+
+func NewHealthHandler(checker *health.Checker) HealthHandler {
+	return HealthHandler{Checker: checker}
+}
+
+// Liveness godoc
+// @Summary Liveness probe
+// @Description Reports that the process is running
+// @Tags health
+// @Produce json
+// @Success 200 {object} models.HealthResponse
+// @Router /healthz [get]
+func (h *HealthHandler) Liveness(w http.ResponseWriter, r *http.Request) {
+	writeHealthResponse(w, r, models.HealthResponse{Status: "ok"}, http.StatusOK)
+}
+
+// Readiness godoc
+// @Summary Readiness probe
+// @Description Checks the database, the schema version and whether the server is shutting down
+// @Tags health
+// @Produce json
+// @Success 200 {object} models.ReadinessResponse
+// @Failure 503 {object} models.ReadinessResponse "Not ready"
+// @Router /readyz [get]
+func (h *HealthHandler) Readiness(w http.ResponseWriter, r *http.Request) {
+	ready, results := h.Checker.Ready(r.Context())
+
+	resp := models.ReadinessResponse{
+		Status: "ok",
+		Checks: make(map[string]models.CheckResult, len(results)),
+	}
+	for _, res := range results {
+		check := models.CheckResult{Status: "ok"}
+		if res.Err != nil {
+			check = models.CheckResult{Status: "fail", Error: res.Err.Error()}
+		}
+		resp.Checks[res.Name] = check
+	}
+
+	status := http.StatusOK
+	if !ready {
+		resp.Status = "unavailable"
+		status = http.StatusServiceUnavailable
+	}
+	writeHealthResponse(w, r, resp, status)
+}
+
+func writeHealthResponse(w http.ResponseWriter, r *http.Request, data any, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		logger.FromContext(r.Context()).ErrorContext(r.Context(), "failed to write JSON response", "error", err)
+	}
+}
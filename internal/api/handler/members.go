@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/api/problem"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+)
+
+// GetMembers godoc
+// @Summary Get members of a shared subscription
+// @Description Get the split rule and members of a subscription with their shares of the current price.
+// @Description The owner pays what is left after the members' shares. Available to the owner and the members.
+// @Tags subscriptions
+// @Produce json
+// @Param id path string true "Subscription ID" format(uuid)
+// @Param X-Tenant-ID header string false "Tenant ID (defaults to the tenant_id claim or APP_DEFAULT_TENANT)"
+// @Success 200 {object} models.MembersResponse
+// @Failure 400 {object} models.Problem "Bad request"
+// @Failure 404 {object} models.Problem "Subscription not found, only for admins"
+// @Failure 401 {object} models.Problem "Missing or invalid token"
+// @Failure 403 {object} models.Problem "Caller is neither the owner nor a member, or the subscription does not exist"
+// @Failure 429 {object} models.Problem "Rate limit exceeded"
+// @Failure 500 {object} models.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /subscriptions/{id}/members [get]
+func (h *Handler) GetMembers(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		problem.Error(w, r, "parse request", problem.InvalidParam("id", problem.ParamUUID))
+		return
+	}
+
+	resp, err := h.Service.GetMembers(r.Context(), id)
+	if err != nil {
+		problem.Error(w, r, "get members", err)
+		return
+	}
+
+	h.writeJSONResponse(w, resp, http.StatusOK)
+}
+
+// SetMembers godoc
+// @Summary Replace members of a shared subscription
+// @Description Replace the split rule and all members. With equal the price is split equally between the owner
+// @Description and the members, with percent each member pays share percent and with fixed share rubles a month.
+// @Description If fixed shares exceed the price of a month, the month is split between members in proportion to them.
+// @Description Cost reports filtered by user count the user's share. An empty member list stops sharing.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID" format(uuid)
+// @Param members body models.SetMembersRequest true "Split rule and members"
+// @Param X-Tenant-ID header string false "Tenant ID (defaults to the tenant_id claim or APP_DEFAULT_TENANT)"
+// @Success 200 {object} models.MembersResponse
+// @Failure 400 {object} models.Problem "Bad request"
+// @Failure 404 {object} models.Problem "Subscription not found"
+// @Failure 401 {object} models.Problem "Missing or invalid token"
+// @Failure 403 {object} models.Problem "Access to another user's subscriptions"
+// @Failure 429 {object} models.Problem "Rate limit exceeded"
+// @Failure 500 {object} models.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /subscriptions/{id}/members [put]
+func (h *Handler) SetMembers(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		problem.Error(w, r, "parse request", problem.InvalidParam("id", problem.ParamUUID))
+		return
+	}
+
+	var req models.SetMembersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Error(w, r, "decode request", problem.InvalidJSON(err))
+		return
+	}
+
+	if err := h.Validator.Struct(&req); err != nil {
+		problem.Error(w, r, "validate request", err)
+		return
+	}
+
+	resp, err := h.Service.SetMembers(r.Context(), id, req)
+	if err != nil {
+		problem.Error(w, r, "set members", err)
+		return
+	}
+
+	h.writeJSONResponse(w, resp, http.StatusOK)
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/api/problem"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+)
+
+// GetNotificationPreferences godoc
+// @Summary Get reminder preferences of a user
+// @Description Get when and through which channel the user is reminded of upcoming renewals and endings of subscriptions.
+// @Description Users who have not set preferences get the defaults: enabled, 3 days before, log channel.
+// @Tags notifications
+// @Produce json
+// @Param id path string true "User ID" format(uuid)
+// @Param X-Tenant-ID header string false "Tenant ID (defaults to the tenant_id claim or APP_DEFAULT_TENANT)"
+// @Success 200 {object} models.NotificationPreferencesResponse
+// @Failure 400 {object} models.Problem "Bad request"
+// @Failure 401 {object} models.Problem "Missing or invalid token"
+// @Failure 403 {object} models.Problem "Access to another user's preferences"
+// @Failure 429 {object} models.Problem "Rate limit exceeded"
+// @Failure 500 {object} models.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /users/{id}/notification-preferences [get]
+func (h *Handler) GetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		problem.Error(w, r, "parse request", problem.InvalidParam("id", problem.ParamUUID))
+		return
+	}
+
+	resp, err := h.Service.GetNotificationPreferences(r.Context(), id)
+	if err != nil {
+		problem.Error(w, r, "get notification preferences", err)
+		return
+	}
+
+	h.writeJSONResponse(w, resp, http.StatusOK)
+}
+
+// SetNotificationPreferences godoc
+// @Summary Replace reminder preferences of a user
+// @Description Replace when and through which channel the user is reminded. A renewal reminder is sent days_before
+// @Description the next billing day, an ending reminder days_before the month after end_date. Each reminder is sent once.
+// @Description The email channel needs email and the webhook channel needs webhook_url.
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID" format(uuid)
+// @Param preferences body models.NotificationPreferencesRequest true "Reminder preferences"
+// @Param X-Tenant-ID header string false "Tenant ID (defaults to the tenant_id claim or APP_DEFAULT_TENANT)"
+// @Success 200 {object} models.NotificationPreferencesResponse
+// @Failure 400 {object} models.Problem "Bad request"
+// @Failure 401 {object} models.Problem "Missing or invalid token"
+// @Failure 403 {object} models.Problem "Access to another user's preferences"
+// @Failure 429 {object} models.Problem "Rate limit exceeded"
+// @Failure 500 {object} models.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /users/{id}/notification-preferences [put]
+func (h *Handler) SetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		problem.Error(w, r, "parse request", problem.InvalidParam("id", problem.ParamUUID))
+		return
+	}
+
+	var req models.NotificationPreferencesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Error(w, r, "decode request", problem.InvalidJSON(err))
+		return
+	}
+
+	if err := h.Validator.Struct(&req); err != nil {
+		problem.Error(w, r, "validate request", err)
+		return
+	}
+
+	resp, err := h.Service.SetNotificationPreferences(r.Context(), id, req)
+	if err != nil {
+		problem.Error(w, r, "set notification preferences", err)
+		return
+	}
+
+	h.writeJSONResponse(w, resp, http.StatusOK)
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/api/problem"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+)
+
+// CreatePaymentMethod godoc
+// @Summary Add a payment method of a user
+// @Description Add a card by its last four digits, expiry month and optionally brand, or a bank account by its label.
+// @Description Only this metadata is stored: full card numbers, CVC codes and account details are never accepted.
+// @Description Subscriptions are linked to a payment method by payment_method_id.
+// @Tags payment-methods
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID" format(uuid)
+// @Param method body models.PaymentMethodRequest true "Payment method"
+// @Param X-Tenant-ID header string false "Tenant ID (defaults to the tenant_id claim or APP_DEFAULT_TENANT)"
+// @Success 201 {object} models.PaymentMethodResponse
+// @Failure 400 {object} models.Problem "Bad request"
+// @Failure 401 {object} models.Problem "Missing or invalid token"
+// @Failure 403 {object} models.Problem "Access to another user's payment methods"
+// @Failure 429 {object} models.Problem "Rate limit exceeded"
+// @Failure 500 {object} models.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /users/{id}/payment-methods [post]
+func (h *Handler) CreatePaymentMethod(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		problem.Error(w, r, "parse request", problem.InvalidParam("id", problem.ParamUUID))
+		return
+	}
+
+	var req models.PaymentMethodRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Error(w, r, "decode request", problem.InvalidJSON(err))
+		return
+	}
+
+	if err := h.Validator.Struct(&req); err != nil {
+		problem.Error(w, r, "validate request", err)
+		return
+	}
+
+	resp, err := h.Service.CreatePaymentMethod(r.Context(), userID, req)
+	if err != nil {
+		problem.Error(w, r, "create payment method", err)
+		return
+	}
+
+	h.writeJSONResponse(w, resp, http.StatusCreated)
+}
+
+// ListPaymentMethods godoc
+// @Summary List payment methods of a user
+// @Description List payment methods of a user in the order they were added.
+// @Tags payment-methods
+// @Produce json
+// @Param id path string true "User ID" format(uuid)
+// @Param X-Tenant-ID header string false "Tenant ID (defaults to the tenant_id claim or APP_DEFAULT_TENANT)"
+// @Success 200 {array} models.PaymentMethodResponse
+// @Failure 400 {object} models.Problem "Bad request"
+// @Failure 401 {object} models.Problem "Missing or invalid token"
+// @Failure 403 {object} models.Problem "Access to another user's payment methods"
+// @Failure 429 {object} models.Problem "Rate limit exceeded"
+// @Failure 500 {object} models.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /users/{id}/payment-methods [get]
+func (h *Handler) ListPaymentMethods(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		problem.Error(w, r, "parse request", problem.InvalidParam("id", problem.ParamUUID))
+		return
+	}
+
+	resp, err := h.Service.ListPaymentMethods(r.Context(), userID)
+	if err != nil {
+		problem.Error(w, r, "list payment methods", err)
+		return
+	}
+
+	h.writeJSONResponse(w, resp, http.StatusOK)
+}
+
+// UpdatePaymentMethod godoc
+// @Summary Replace a payment method of a user
+// @Description Replace the metadata of a payment method, e.g. the expiry month of a reissued card.
+// @Description Linked subscriptions stay linked.
+// @Tags payment-methods
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID" format(uuid)
+// @Param methodId path string true "Payment method ID" format(uuid)
+// @Param method body models.PaymentMethodRequest true "Payment method"
+// @Param X-Tenant-ID header string false "Tenant ID (defaults to the tenant_id claim or APP_DEFAULT_TENANT)"
+// @Success 200 {object} models.PaymentMethodResponse
+// @Failure 400 {object} models.Problem "Bad request"
+// @Failure 404 {object} models.Problem "Payment method not found"
+// @Failure 401 {object} models.Problem "Missing or invalid token"
+// @Failure 403 {object} models.Problem "Access to another user's payment methods"
+// @Failure 429 {object} models.Problem "Rate limit exceeded"
+// @Failure 500 {object} models.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /users/{id}/payment-methods/{methodId} [put]
+func (h *Handler) UpdatePaymentMethod(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		problem.Error(w, r, "parse request", problem.InvalidParam("id", problem.ParamUUID))
+		return
+	}
+	methodID, err := uuid.Parse(r.PathValue("methodId"))
+	if err != nil {
+		problem.Error(w, r, "parse request", problem.InvalidParam("methodId", problem.ParamUUID))
+		return
+	}
+
+	var req models.PaymentMethodRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Error(w, r, "decode request", problem.InvalidJSON(err))
+		return
+	}
+
+	if err := h.Validator.Struct(&req); err != nil {
+		problem.Error(w, r, "validate request", err)
+		return
+	}
+
+	resp, err := h.Service.UpdatePaymentMethod(r.Context(), userID, methodID, req)
+	if err != nil {
+		problem.Error(w, r, "update payment method", err)
+		return
+	}
+
+	h.writeJSONResponse(w, resp, http.StatusOK)
+}
+
+// DeletePaymentMethod godoc
+// @Summary Delete a payment method of a user
+// @Description Delete a payment method. Its subscriptions are moved to replacement_id, a payment method of the same user,
+// @Description or are left without a payment method.
+// @Tags payment-methods
+// @Param id path string true "User ID" format(uuid)
+// @Param methodId path string true "Payment method ID" format(uuid)
+// @Param replacement_id query string false "Payment method to move the subscriptions to" format(uuid)
+// @Param X-Tenant-ID header string false "Tenant ID (defaults to the tenant_id claim or APP_DEFAULT_TENANT)"
+// @Success 204 "No content"
+// @Failure 400 {object} models.Problem "Bad request"
+// @Failure 404 {object} models.Problem "Payment method not found"
+// @Failure 401 {object} models.Problem "Missing or invalid token"
+// @Failure 403 {object} models.Problem "Access to another user's payment methods"
+// @Failure 429 {object} models.Problem "Rate limit exceeded"
+// @Failure 500 {object} models.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /users/{id}/payment-methods/{methodId} [delete]
+func (h *Handler) DeletePaymentMethod(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		problem.Error(w, r, "parse request", problem.InvalidParam("id", problem.ParamUUID))
+		return
+	}
+	methodID, err := uuid.Parse(r.PathValue("methodId"))
+	if err != nil {
+		problem.Error(w, r, "parse request", problem.InvalidParam("methodId", problem.ParamUUID))
+		return
+	}
+
+	var req models.DeletePaymentMethodRequest
+	if rawReplacementID := r.URL.Query().Get("replacement_id"); rawReplacementID != "" {
+		replacementID, err := uuid.Parse(rawReplacementID)
+		if err != nil {
+			problem.Error(w, r, "parse request", problem.InvalidParam("replacement_id", problem.ParamUUID))
+			return
+		}
+		req.ReplacementID = &replacementID
+	}
+
+	if err := h.Service.DeletePaymentMethod(r.Context(), userID, methodID, req); err != nil {
+		problem.Error(w, r, "delete payment method", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
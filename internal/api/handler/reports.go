@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/api/problem"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/monthyear"
+)
+
+// FindDuplicates godoc
+// @Summary Find likely duplicate subscriptions
+// @Description Group subscriptions of each user billed in a month by reason: same_service for the same catalog service or
+// @Description names equal ignoring case, punctuation and plan words, similar_name for names containing one another or
+// @Description differing in a typo, same_price for identical prices charged on the same day. Potential savings assume only
+// @Description the most expensive subscription of a group is kept. Different services of one category are listed in
+// @Description category_overlaps without savings, since they do not replace each other.
+// @Tags reports
+// @Produce json
+// @Param month query string false "Month to compare subscriptions billed in (defaults to the current month)" format(MM-YYYY)
+// @Param user_id query string false "User ID" format(uuid)
+// @Param X-Tenant-ID header string false "Tenant ID (defaults to the tenant_id claim or APP_DEFAULT_TENANT)"
+// @Success 200 {object} models.DuplicatesResponse
+// @Failure 400 {object} models.Problem "Bad request"
+// @Failure 401 {object} models.Problem "Missing or invalid token"
+// @Failure 403 {object} models.Problem "Access to another user's subscriptions"
+// @Failure 429 {object} models.Problem "Rate limit exceeded"
+// @Failure 500 {object} models.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /reports/duplicates [get]
+func (h *Handler) FindDuplicates(w http.ResponseWriter, r *http.Request) {
+	var req models.DuplicatesRequest
+	query := r.URL.Query()
+
+	if rawMonth := query.Get("month"); rawMonth != "" {
+		var month monthyear.MonthYear
+		if err := month.UnmarshalJSON([]byte(`"` + rawMonth + `"`)); err != nil {
+			problem.Error(w, r, "parse request", problem.InvalidParam("month", problem.ParamMonth))
+			return
+		}
+		req.Month = &month
+	}
+	if rawUserID := query.Get("user_id"); rawUserID != "" {
+		userID, err := uuid.Parse(rawUserID)
+		if err != nil {
+			problem.Error(w, r, "parse request", problem.InvalidParam("user_id", problem.ParamUUID))
+			return
+		}
+		req.UserID = &userID
+	}
+
+	resp, err := h.Service.FindDuplicates(r.Context(), req)
+	if err != nil {
+		problem.Error(w, r, "find duplicates", err)
+		return
+	}
+
+	h.writeJSONResponse(w, resp, http.StatusOK)
+}
+
+// ListExpiringCards godoc
+// @Summary Find subscriptions charged after their card expires
+// @Description List subscriptions linked to a card whose next charge comes after the last day of the card's expiry month.
+// @Description The next charge is the first charged billing day from today in the current or the next month,
+// @Description free and paused months are skipped. Move the subscriptions to the new card before it is charged.
+// @Tags reports
+// @Produce json
+// @Param user_id query string false "User ID" format(uuid)
+// @Param X-Tenant-ID header string false "Tenant ID (defaults to the tenant_id claim or APP_DEFAULT_TENANT)"
+// @Success 200 {array} models.ExpiringCardResponse
+// @Failure 400 {object} models.Problem "Bad request"
+// @Failure 401 {object} models.Problem "Missing or invalid token"
+// @Failure 403 {object} models.Problem "Access to another user's subscriptions"
+// @Failure 429 {object} models.Problem "Rate limit exceeded"
+// @Failure 500 {object} models.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /reports/expiring-cards [get]
+func (h *Handler) ListExpiringCards(w http.ResponseWriter, r *http.Request) {
+	var req models.ExpiringCardsRequest
+	if rawUserID := r.URL.Query().Get("user_id"); rawUserID != "" {
+		userID, err := uuid.Parse(rawUserID)
+		if err != nil {
+			problem.Error(w, r, "parse request", problem.InvalidParam("user_id", problem.ParamUUID))
+			return
+		}
+		req.UserID = &userID
+	}
+
+	resp, err := h.Service.ListExpiringCards(r.Context(), req)
+	if err != nil {
+		problem.Error(w, r, "list expiring cards", err)
+		return
+	}
+
+	h.writeJSONResponse(w, resp, http.StatusOK)
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/api/problem"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+)
+
+// ScheduleChange godoc
+// @Summary Schedule a change of a subscription
+// @Description Schedule a change taking effect in a future month: cancel ends the subscription after month,
+// @Description price bills months from month at price. Pending changes are applied by the scheduler when their month comes
+// @Description and are included in cost, charge and reminder forecasts until then.
+// @Description Scheduling the same action for the same month replaces the pending change.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID" format(uuid)
+// @Param change body models.ScheduleChangeRequest true "Scheduled change"
+// @Param X-Tenant-ID header string false "Tenant ID (defaults to the tenant_id claim or APP_DEFAULT_TENANT)"
+// @Success 201 {object} models.ScheduledChangeResponse
+// @Failure 400 {object} models.Problem "Bad request"
+// @Failure 404 {object} models.Problem "Subscription not found"
+// @Failure 401 {object} models.Problem "Missing or invalid token"
+// @Failure 403 {object} models.Problem "Access to another user's subscriptions"
+// @Failure 429 {object} models.Problem "Rate limit exceeded"
+// @Failure 500 {object} models.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /subscriptions/{id}/scheduled-changes [post]
+func (h *Handler) ScheduleChange(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		problem.Error(w, r, "parse request", problem.InvalidParam("id", problem.ParamUUID))
+		return
+	}
+
+	var req models.ScheduleChangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Error(w, r, "decode request", problem.InvalidJSON(err))
+		return
+	}
+
+	if err := h.Validator.Struct(&req); err != nil {
+		problem.Error(w, r, "validate request", err)
+		return
+	}
+
+	resp, err := h.Service.ScheduleChange(r.Context(), subscriptionID, req)
+	if err != nil {
+		problem.Error(w, r, "schedule change", err)
+		return
+	}
+
+	h.writeJSONResponse(w, resp, http.StatusCreated)
+}
+
+// ListScheduledChanges godoc
+// @Summary List scheduled changes of a subscription
+// @Description List pending, applied and canceled scheduled changes by month.
+// @Tags subscriptions
+// @Produce json
+// @Param id path string true "Subscription ID" format(uuid)
+// @Param X-Tenant-ID header string false "Tenant ID (defaults to the tenant_id claim or APP_DEFAULT_TENANT)"
+// @Success 200 {array} models.ScheduledChangeResponse
+// @Failure 400 {object} models.Problem "Bad request"
+// @Failure 404 {object} models.Problem "Subscription not found"
+// @Failure 401 {object} models.Problem "Missing or invalid token"
+// @Failure 403 {object} models.Problem "Access to another user's subscriptions"
+// @Failure 429 {object} models.Problem "Rate limit exceeded"
+// @Failure 500 {object} models.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /subscriptions/{id}/scheduled-changes [get]
+func (h *Handler) ListScheduledChanges(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		problem.Error(w, r, "parse request", problem.InvalidParam("id", problem.ParamUUID))
+		return
+	}
+
+	resp, err := h.Service.ListScheduledChanges(r.Context(), subscriptionID)
+	if err != nil {
+		problem.Error(w, r, "list scheduled changes", err)
+		return
+	}
+
+	h.writeJSONResponse(w, resp, http.StatusOK)
+}
+
+// CancelScheduledChange godoc
+// @Summary Cancel a scheduled change
+// @Description Cancel a pending scheduled change of a subscription. Applied changes cannot be canceled.
+// @Tags subscriptions
+// @Param id path string true "Subscription ID" format(uuid)
+// @Param changeId path string true "Scheduled change ID" format(uuid)
+// @Param X-Tenant-ID header string false "Tenant ID (defaults to the tenant_id claim or APP_DEFAULT_TENANT)"
+// @Success 204 "No content"
+// @Failure 400 {object} models.Problem "Bad request"
+// @Failure 404 {object} models.Problem "Subscription or pending change not found"
+// @Failure 401 {object} models.Problem "Missing or invalid token"
+// @Failure 403 {object} models.Problem "Access to another user's subscriptions"
+// @Failure 429 {object} models.Problem "Rate limit exceeded"
+// @Failure 500 {object} models.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /subscriptions/{id}/scheduled-changes/{changeId} [delete]
+func (h *Handler) CancelScheduledChange(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		problem.Error(w, r, "parse request", problem.InvalidParam("id", problem.ParamUUID))
+		return
+	}
+	changeID, err := uuid.Parse(r.PathValue("changeId"))
+	if err != nil {
+		problem.Error(w, r, "parse request", problem.InvalidParam("changeId", problem.ParamUUID))
+		return
+	}
+
+	if err := h.Service.CancelScheduledChange(r.Context(), subscriptionID, changeID); err != nil {
+		problem.Error(w, r, "cancel scheduled change", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/api/problem"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+)
+
+// CreateService godoc
+// @Summary Add a service to the catalog
+// @Description Add a service with a canonical name and aliases. Subscriptions not yet matched to the catalog
+// @Description whose name equals the name or an alias ignoring case are matched to the new service. Admins only.
+// @Tags services
+// @Accept json
+// @Produce json
+// @Param service body models.ServiceRequest true "Service data"
+// @Param X-Tenant-ID header string false "Tenant ID (defaults to the tenant_id claim or APP_DEFAULT_TENANT)"
+// @Success 201 {object} models.ServiceResponse
+// @Failure 400 {object} models.Problem "Bad request"
+// @Failure 409 {object} models.Problem "Service with this name or alias already exists"
+// @Failure 401 {object} models.Problem "Missing or invalid token"
+// @Failure 403 {object} models.Problem "Caller is not an admin"
+// @Failure 429 {object} models.Problem "Rate limit exceeded"
+// @Failure 500 {object} models.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /services [post]
+func (h *Handler) CreateService(w http.ResponseWriter, r *http.Request) {
+	var req models.ServiceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Error(w, r, "decode request", problem.InvalidJSON(err))
+		return
+	}
+
+	if err := h.Validator.Struct(&req); err != nil {
+		problem.Error(w, r, "validate request", err)
+		return
+	}
+
+	resp, err := h.Service.CreateService(r.Context(), req)
+	if err != nil {
+		problem.Error(w, r, "create service", err)
+		return
+	}
+
+	h.writeJSONResponse(w, resp, http.StatusCreated)
+}
+
+// ListServices godoc
+// @Summary List catalog services
+// @Description List services of the catalog ordered by name
+// @Tags services
+// @Produce json
+// @Param q query string false "Name or alias (partial match)"
+// @Param X-Tenant-ID header string false "Tenant ID (defaults to the tenant_id claim or APP_DEFAULT_TENANT)"
+// @Success 200 {array} models.ServiceResponse
+// @Failure 401 {object} models.Problem "Missing or invalid token"
+// @Failure 429 {object} models.Problem "Rate limit exceeded"
+// @Failure 500 {object} models.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /services [get]
+func (h *Handler) ListServices(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.Service.ListServices(r.Context(), r.URL.Query().Get("q"))
+	if err != nil {
+		problem.Error(w, r, "list services", err)
+		return
+	}
+
+	h.writeJSONResponse(w, resp, http.StatusOK)
+}
+
+// GetService godoc
+// @Summary Get a catalog service by ID
+// @Tags services
+// @Produce json
+// @Param id path string true "Service ID" format(uuid)
+// @Param X-Tenant-ID header string false "Tenant ID (defaults to the tenant_id claim or APP_DEFAULT_TENANT)"
+// @Success 200 {object} models.ServiceResponse
+// @Failure 400 {object} models.Problem "Bad request"
+// @Failure 404 {object} models.Problem "Service not found"
+// @Failure 401 {object} models.Problem "Missing or invalid token"
+// @Failure 429 {object} models.Problem "Rate limit exceeded"
+// @Failure 500 {object} models.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /services/{id} [get]
+func (h *Handler) GetService(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		problem.Error(w, r, "parse request", problem.InvalidParam("id", problem.ParamUUID))
+		return
+	}
+
+	resp, err := h.Service.GetService(r.Context(), id)
+	if err != nil {
+		problem.Error(w, r, "get service", err)
+		return
+	}
+
+	h.writeJSONResponse(w, resp, http.StatusOK)
+}
+
+// UpdateService godoc
+// @Summary Replace a catalog service
+// @Description Replace all fields of a service. Like on creation, unmatched subscriptions are matched to it. Admins only.
+// @Tags services
+// @Accept json
+// @Produce json
+// @Param id path string true "Service ID" format(uuid)
+// @Param service body models.ServiceRequest true "Service data"
+// @Param X-Tenant-ID header string false "Tenant ID (defaults to the tenant_id claim or APP_DEFAULT_TENANT)"
+// @Success 200 {object} models.ServiceResponse
+// @Failure 400 {object} models.Problem "Bad request"
+// @Failure 404 {object} models.Problem "Service not found"
+// @Failure 409 {object} models.Problem "Service with this name or alias already exists"
+// @Failure 401 {object} models.Problem "Missing or invalid token"
+// @Failure 403 {object} models.Problem "Caller is not an admin"
+// @Failure 429 {object} models.Problem "Rate limit exceeded"
+// @Failure 500 {object} models.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /services/{id} [put]
+func (h *Handler) UpdateService(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		problem.Error(w, r, "parse request", problem.InvalidParam("id", problem.ParamUUID))
+		return
+	}
+
+	var req models.ServiceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.Error(w, r, "decode request", problem.InvalidJSON(err))
+		return
+	}
+
+	if err := h.Validator.Struct(&req); err != nil {
+		problem.Error(w, r, "validate request", err)
+		return
+	}
+
+	resp, err := h.Service.UpdateService(r.Context(), id, req)
+	if err != nil {
+		problem.Error(w, r, "update service", err)
+		return
+	}
+
+	h.writeJSONResponse(w, resp, http.StatusOK)
+}
+
+// DeleteService godoc
+// @Summary Delete a catalog service
+// @Description Delete a service from the catalog, subscriptions matched to it keep their names. Admins only.
+// @Tags services
+// @Param id path string true "Service ID" format(uuid)
+// @Param X-Tenant-ID header string false "Tenant ID (defaults to the tenant_id claim or APP_DEFAULT_TENANT)"
+// @Success 204 "No content"
+// @Failure 400 {object} models.Problem "Bad request"
+// @Failure 404 {object} models.Problem "Service not found"
+// @Failure 401 {object} models.Problem "Missing or invalid token"
+// @Failure 403 {object} models.Problem "Caller is not an admin"
+// @Failure 429 {object} models.Problem "Rate limit exceeded"
+// @Failure 500 {object} models.Problem "Internal server error"
+// @Security BearerAuth
+// @Router /services/{id} [delete]
+func (h *Handler) DeleteService(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		problem.Error(w, r, "parse request", problem.InvalidParam("id", problem.ParamUUID))
+		return
+	}
+
+	if err := h.Service.DeleteService(r.Context(), id); err != nil {
+		problem.Error(w, r, "delete service", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/logger"
+)
+
+const (
+	RequestIDHeader    = "X-Request-ID"
+	maxRequestIDLength = 128
+)
+
+// Logging assigns a request ID, or propagates a valid incoming X-Request-ID, stores a logger carrying it
+// in the request context and writes one access log line per request.
+// It must wrap the http.ServeMux (directly or through other middleware) to log the matched route pattern.
+func Logging() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			requestID := r.Header.Get(RequestIDHeader)
+			if !ValidRequestID(requestID) {
+				requestID = uuid.NewString()
+			}
+			w.Header().Set(RequestIDHeader, requestID)
+
+			lgr := logger.FromContext(r.Context()).With("request_id", requestID)
+			rec := newResponseRecorder(w)
+			req := r.WithContext(logger.WithContext(r.Context(), lgr))
+
+			next.ServeHTTP(rec, req)
+			// Expose the matched route to outer middleware, as http.ServeMux does
+			r.Pattern = req.Pattern
+
+			lgr.LogAttrs(req.Context(), slog.LevelInfo, "request completed",
+				slog.String("method", r.Method),
+				slog.String("route", req.Pattern),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", rec.status),
+				slog.Int("bytes", rec.bytes),
+				slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+			)
+		})
+	}
+}
+
+// ValidRequestID reports whether an incoming request ID is short printable ASCII and safe to propagate
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		if c < '!' || c > '~' {
+			return false
+		}
+	}
+	return true
+}
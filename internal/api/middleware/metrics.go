@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/metrics"
+)
+
+// Metrics records request count and latency per route pattern and status code.
+// It must wrap the http.ServeMux (directly or through other middleware),
+// which sets r.Pattern when routing the request.
+func Metrics(m *metrics.Metrics) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := newResponseRecorder(w)
+
+			next.ServeHTTP(rec, r)
+
+			m.ObserveRequest(r.Pattern, rec.status, time.Since(start))
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/api/problem"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/ratelimit"
+)
+
+// RateLimit rejects requests over the client's limit with 429 and reports the quota in RateLimit-* headers.
+// Clients are identified by a configured API key in keyHeader, the authenticated caller or their IP address
+// (see ratelimit.ClientKey). Must run after Authenticate.
+// A nil limiter disables rate limiting.
+func RateLimit(l *ratelimit.Limiter, keyHeader string, apiKeys []string, trustedProxies int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if l == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var apiKey string
+			if keyHeader != "" {
+				apiKey = r.Header.Get(keyHeader)
+			}
+			ip := ratelimit.ClientIP(r.RemoteAddr, r.Header.Values("X-Forwarded-For"), trustedProxies)
+			res := l.Allow(ratelimit.ClientKey(r.Context(), apiKey, apiKeys, ip))
+
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+
+			if !res.Allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(res.RetryAfter)))
+				problem.Write(w, r, problem.New(r, http.StatusTooManyRequests, problem.CodeRateLimited, problem.CodeRateLimited))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
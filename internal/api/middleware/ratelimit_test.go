@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/ratelimit"
+)
+
+func TestRateLimitIgnoresSpoofedForwardedFor(t *testing.T) {
+	handler := RateLimit(ratelimit.New(1, time.Hour, 100), "X-API-Key", nil, 1)(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) }))
+
+	// The client makes up a new leftmost entry per request, the proxy appends the real address
+	for i, want := range []int{http.StatusNoContent, http.StatusTooManyRequests, http.StatusTooManyRequests} {
+		r := httptest.NewRequest(http.MethodGet, "/subscriptions", nil)
+		r.RemoteAddr = "10.0.0.2:40000"
+		r.Header.Set("X-Forwarded-For", fmt.Sprintf("192.0.2.%d, 198.51.100.1", i+1))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != want {
+			t.Fatalf("request %d: status %d, want %d", i, w.Code, want)
+		}
+	}
+
+	// Another client behind the same proxy has its own bucket
+	r := httptest.NewRequest(http.MethodGet, "/subscriptions", nil)
+	r.RemoteAddr = "10.0.0.2:40000"
+	r.Header.Set("X-Forwarded-For", "198.51.100.2")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("other client: status %d, want %d", w.Code, http.StatusNoContent)
+	}
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/api/problem"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/tenant"
+)
+
+// Tenant resolves the tenant of a request and stores it in the request context.
+// The tenant_id claim of an authenticated caller takes precedence, a header naming another tenant is rejected.
+// Only admins may choose a tenant by header without a claim, other callers are limited to defaultTenant.
+// Requests without a claim or header fall back to defaultTenant, if it is set.
+// Must run after Authenticate.
+func Tenant(header, defaultTenant string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tenantID, err := tenant.Resolve(r.Context(), r.Header.Get(header), defaultTenant)
+			switch {
+			case errors.Is(err, tenant.ErrMismatch):
+				problem.Write(w, r, problem.New(r, http.StatusForbidden, problem.CodeTenantMismatch, problem.CodeTenantMismatch))
+				return
+			case errors.Is(err, tenant.ErrClaimRequired):
+				problem.Write(w, r, problem.New(r, http.StatusBadRequest, problem.CodeTenantRequired, "tenant_claim_required"))
+				return
+			case errors.Is(err, tenant.ErrRequired):
+				problem.Write(w, r, problem.New(r, http.StatusBadRequest, problem.CodeTenantRequired, problem.CodeTenantRequired, header))
+				return
+			case err != nil:
+				problem.Write(w, r, problem.New(r, http.StatusBadRequest, problem.CodeInvalidTenant, problem.CodeInvalidTenant))
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(tenant.WithID(r.Context(), tenantID)))
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/api"
+
+// Tracing starts a server span per request, continuing the trace from an incoming traceparent header.
+// It must wrap the http.ServeMux (directly or through other middleware):
+// the span is renamed after the matched route pattern once the request is served.
+func Tracing() func(http.Handler) http.Handler {
+	tracer := otel.Tracer(tracerName)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+			ctx, span := tracer.Start(ctx, r.Method,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.HTTPRequestMethodKey.String(r.Method),
+					semconv.URLPath(r.URL.Path),
+				),
+			)
+			defer span.End()
+
+			rec := newResponseRecorder(w)
+			req := r.WithContext(ctx)
+			next.ServeHTTP(rec, req)
+			// Expose the matched route to outer middleware, as http.ServeMux does
+			r.Pattern = req.Pattern
+
+			if req.Pattern != "" {
+				span.SetName(req.Pattern)
+				span.SetAttributes(semconv.HTTPRoute(req.Pattern))
+			}
+			span.SetAttributes(semconv.HTTPResponseStatusCode(rec.status))
+			if rec.status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(rec.status))
+			}
+		})
+	}
+}
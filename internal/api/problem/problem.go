@@ -0,0 +1,194 @@
+// Package problem writes API errors as RFC 9457 problem details (application/problem+json).
+// Every problem carries a stable code, clients should match on it rather than on the detail text.
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/i18n"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/repository"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/service"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/validation"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/logger"
+)
+
+const ContentType = "application/problem+json"
+
+// Error codes, part of the API contract
+const (
+	CodeInvalidJSON          = "invalid_json"
+	CodeInvalidParameter     = "invalid_parameter"
+	CodeValidationFailed     = "validation_failed"
+	CodeInvalidDateRange     = "invalid_date_range"
+	CodeNotFound             = "not_found"
+	CodeAlreadyExists        = "already_exists"
+	CodeAlreadyPaused        = "already_paused"
+	CodeNotPaused            = "not_paused"
+	CodeInvalidPause         = "invalid_pause"
+	CodeUnknownService       = "unknown_service"
+	CodeUnknownPlan          = "unknown_plan"
+	CodeInvalidPlanChange    = "invalid_plan_change"
+	CodeInvalidSplit         = "invalid_split"
+	CodeInvalidSchedule      = "invalid_schedule"
+	CodeUnknownPaymentMethod = "unknown_payment_method"
+	CodeForbidden            = "forbidden"
+	CodeUnauthorized         = "unauthorized"
+	CodeTenantMismatch       = "tenant_mismatch"
+	CodeTenantRequired       = "tenant_required"
+	CodeInvalidTenant        = "invalid_tenant"
+	CodeRateLimited          = "rate_limited"
+	CodeInternal             = "internal_error"
+)
+
+// ErrInvalidJSON is reported for request bodies that cannot be decoded
+var ErrInvalidJSON = errors.New("invalid JSON format")
+
+// InvalidJSON wraps an error of decoding a request body
+func InvalidJSON(err error) error {
+	return fmt.Errorf("%w: %w", ErrInvalidJSON, err)
+}
+
+// Kinds of malformed parameters for InvalidParam
+const (
+	ParamUUID    = "param_uuid"
+	ParamMonth   = "param_month"
+	ParamInteger = "param_integer"
+)
+
+// paramError is a malformed path or query parameter
+type paramError struct {
+	name string
+	kind string
+}
+
+func (e *paramError) Error() string {
+	return "invalid " + e.name + ": " + i18n.T(i18n.Translator(""), e.kind)
+}
+
+// InvalidParam returns an error for a path or query parameter that cannot be parsed as kind, e.g. ParamUUID
+func InvalidParam(name, kind string) error {
+	return &paramError{name: name, kind: kind}
+}
+
+// New returns a problem with the standard title of status.
+// The detail is the message key translated to the language of the request, params fill its placeholders.
+func New(r *http.Request, status int, code, key string, params ...string) models.Problem {
+	return models.Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   i18n.T(i18n.FromRequest(r), key, params...),
+		Instance: r.URL.Path,
+		Code:     code,
+	}
+}
+
+// Write writes p with its status code
+func Write(w http.ResponseWriter, r *http.Request, p models.Problem) {
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("Content-Language", i18n.FromRequest(r).Locale())
+	w.Header().Add("Vary", "Accept-Language")
+	w.WriteHeader(p.Status)
+	if err := json.NewEncoder(w).Encode(p); err != nil {
+		logger.FromContext(r.Context()).ErrorContext(r.Context(), "failed to write problem response", "error", err)
+	}
+}
+
+// Error writes the problem for a request, validation or service error.
+// Unexpected errors are logged as a failure to do op and reported without details.
+func Error(w http.ResponseWriter, r *http.Request, op string, err error) {
+	Write(w, r, From(r, op, err))
+}
+
+// From maps err to a problem, see Error
+func From(r *http.Request, op string, err error) models.Problem {
+	trans := i18n.FromRequest(r)
+	if fields := validation.InvalidFields(err, trans); fields != nil {
+		p := New(r, http.StatusBadRequest, CodeValidationFailed, CodeValidationFailed)
+		p.InvalidFields = fields
+		return p
+	}
+
+	var perr *paramError
+	switch {
+	case errors.As(err, &perr):
+		p := New(r, http.StatusBadRequest, CodeInvalidParameter, CodeInvalidParameter, perr.name)
+		p.InvalidFields = []models.InvalidField{{Name: perr.name, Rule: "format", Reason: i18n.T(trans, perr.kind)}}
+		return p
+	case errors.Is(err, ErrInvalidJSON):
+		p := New(r, http.StatusBadRequest, CodeInvalidJSON, CodeInvalidJSON)
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) && typeErr.Field != "" {
+			p.InvalidFields = []models.InvalidField{{Name: typeErr.Field, Rule: "type",
+				Reason: i18n.T(trans, "field_type", typeErr.Type.String(), typeErr.Value)}}
+		}
+		return p
+	case errors.Is(err, service.ErrInvalidDateRange):
+		p := New(r, http.StatusBadRequest, CodeInvalidDateRange, CodeInvalidDateRange)
+		p.InvalidFields = []models.InvalidField{{Name: "end_date", Rule: "afterstart",
+			Reason: i18n.T(trans, "afterstart", "end_date", "start_date")}}
+		return p
+	case errors.Is(err, service.ErrInvalidPause):
+		p := New(r, http.StatusBadRequest, CodeInvalidPause, CodeInvalidPause)
+		p.InvalidFields = []models.InvalidField{{Name: "start_date", Rule: "insubscription",
+			Reason: i18n.T(trans, CodeInvalidPause)}}
+		return p
+	case errors.Is(err, service.ErrUnknownService):
+		p := New(r, http.StatusBadRequest, CodeUnknownService, CodeUnknownService)
+		p.InvalidFields = []models.InvalidField{{Name: "service_id", Rule: "exists",
+			Reason: i18n.T(trans, CodeUnknownService)}}
+		return p
+	case errors.Is(err, service.ErrUnknownPlan):
+		p := New(r, http.StatusBadRequest, CodeUnknownPlan, CodeUnknownPlan)
+		p.InvalidFields = []models.InvalidField{{Name: "plan_id", Rule: "exists",
+			Reason: i18n.T(trans, CodeUnknownPlan)}}
+		return p
+	case errors.Is(err, service.ErrUnknownPaymentMethod):
+		p := New(r, http.StatusBadRequest, CodeUnknownPaymentMethod, CodeUnknownPaymentMethod)
+		p.InvalidFields = []models.InvalidField{{Name: "payment_method_id", Rule: "exists",
+			Reason: i18n.T(trans, CodeUnknownPaymentMethod)}}
+		return p
+	case errors.Is(err, service.ErrInvalidPlanChange):
+		p := New(r, http.StatusBadRequest, CodeInvalidPlanChange, CodeInvalidPlanChange)
+		p.InvalidFields = []models.InvalidField{{Name: "start_date", Rule: "insubscription",
+			Reason: i18n.T(trans, CodeInvalidPlanChange)}}
+		return p
+	case errors.Is(err, service.ErrInvalidSplit):
+		p := New(r, http.StatusBadRequest, CodeInvalidSplit, CodeInvalidSplit)
+		p.InvalidFields = []models.InvalidField{{Name: "members", Rule: "split",
+			Reason: i18n.T(trans, CodeInvalidSplit)}}
+		return p
+	case errors.Is(err, service.ErrInvalidSchedule):
+		p := New(r, http.StatusBadRequest, CodeInvalidSchedule, CodeInvalidSchedule)
+		p.InvalidFields = []models.InvalidField{{Name: "month", Rule: "insubscription",
+			Reason: i18n.T(trans, CodeInvalidSchedule)}}
+		return p
+	case errors.Is(err, repository.ErrSubscriptionNotFound):
+		return New(r, http.StatusNotFound, CodeNotFound, CodeNotFound)
+	case errors.Is(err, repository.ErrScheduledChangeNotFound):
+		return New(r, http.StatusNotFound, CodeNotFound, "change_not_found")
+	case errors.Is(err, repository.ErrPaymentMethodNotFound):
+		return New(r, http.StatusNotFound, CodeNotFound, "method_not_found")
+	case errors.Is(err, repository.ErrSubscriptionAlreadyExists):
+		return New(r, http.StatusConflict, CodeAlreadyExists, CodeAlreadyExists)
+	case errors.Is(err, repository.ErrServiceNotFound):
+		return New(r, http.StatusNotFound, CodeNotFound, "service_not_found")
+	case errors.Is(err, repository.ErrServiceAlreadyExists):
+		return New(r, http.StatusConflict, CodeAlreadyExists, "service_already_exists")
+	case errors.Is(err, service.ErrAdminRequired):
+		return New(r, http.StatusForbidden, CodeForbidden, "admin_required")
+	case errors.Is(err, repository.ErrSubscriptionPaused):
+		return New(r, http.StatusConflict, CodeAlreadyPaused, CodeAlreadyPaused)
+	case errors.Is(err, repository.ErrSubscriptionNotPaused):
+		return New(r, http.StatusConflict, CodeNotPaused, CodeNotPaused)
+	case errors.Is(err, service.ErrForbidden):
+		return New(r, http.StatusForbidden, CodeForbidden, CodeForbidden)
+	}
+
+	logger.FromContext(r.Context()).ErrorContext(r.Context(), "failed to "+op, "error", err)
+	return New(r, http.StatusInternalServerError, CodeInternal, CodeInternal)
+}
@@ -4,21 +4,67 @@ import (
 	httpSwagger "github.com/swaggo/http-swagger"
 	"net/http"
 
-	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/web/handler"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/api/gql"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/api/handler"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/api/middleware"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/auth"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/config"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/ratelimit"
 
 	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/service"
 )
 
-func NewRouter(s service.SubscriptionService) *http.ServeMux {
+// NewRouter registers API routes. Subscription and GraphQL routes require a bearer token unless verifier is nil,
+// are rate limited per client by the limiter of their pattern and are scoped to the tenant resolved from the token
+// or the tenant header.
+func NewRouter(s service.SubscriptionService, verifier *auth.Verifier, limiters *ratelimit.Limiters, cfg config.AppConfig,
+	gqlCfg config.GraphQLConfig) *http.ServeMux {
 	h := handler.NewHandler(s)
+	graphql := gql.NewHandler(s, gqlCfg)
 	mux := http.NewServeMux()
 
-	mux.HandleFunc("POST /subscriptions", h.Create)
-	mux.HandleFunc("GET /subscriptions", h.List)
-	mux.HandleFunc("GET /subscriptions/{id}", h.GetByID)
-	mux.HandleFunc("PATCH /subscriptions/{id}", h.Update)
-	mux.HandleFunc("DELETE /subscriptions/{id}", h.Delete)
-	mux.HandleFunc("GET /subscriptions/total-cost", h.GetTotalCost)
+	authn := middleware.Authenticate(verifier)
+	tenancy := middleware.Tenant(cfg.TenantHeader, cfg.DefaultTenant)
+	handle := func(pattern string, h http.HandlerFunc) {
+		limit := middleware.RateLimit(limiters.For(pattern), cfg.RateLimitKeyHeader, cfg.RateLimitAPIKeys,
+			cfg.RateLimitTrustedProxies)
+		mux.Handle(pattern, authn(limit(tenancy(h))))
+	}
+
+	handle("POST /subscriptions", h.Create)
+	handle("GET /subscriptions", h.List)
+	handle("GET /subscriptions/{id}", h.GetByID)
+	handle("PATCH /subscriptions/{id}", h.Update)
+	handle("DELETE /subscriptions/{id}", h.Delete)
+	handle("POST /subscriptions/{id}/pause", h.Pause)
+	handle("POST /subscriptions/{id}/resume", h.Resume)
+	handle("POST /subscriptions/{id}/plan-changes", h.ChangePlan)
+	handle("GET /subscriptions/{id}/plan-changes", h.ListPlanChanges)
+	handle("POST /subscriptions/{id}/scheduled-changes", h.ScheduleChange)
+	handle("GET /subscriptions/{id}/scheduled-changes", h.ListScheduledChanges)
+	handle("DELETE /subscriptions/{id}/scheduled-changes/{changeId}", h.CancelScheduledChange)
+	handle("GET /subscriptions/{id}/members", h.GetMembers)
+	handle("PUT /subscriptions/{id}/members", h.SetMembers)
+	handle("GET /subscriptions/total-cost", h.GetTotalCost)
+	handle("GET /subscriptions/cost-breakdown", h.GetCostBreakdown)
+	handle("GET /subscriptions/trial-ending", h.ListTrialsEnding)
+	handle("GET /subscriptions/upcoming-charges", h.ListUpcomingCharges)
+	handle("GET /subscriptions/calendar.ics", h.GetCalendar)
+	handle("GET /reports/duplicates", h.FindDuplicates)
+	handle("GET /reports/expiring-cards", h.ListExpiringCards)
+	handle("GET /users/{id}/notification-preferences", h.GetNotificationPreferences)
+	handle("PUT /users/{id}/notification-preferences", h.SetNotificationPreferences)
+	handle("POST /users/{id}/payment-methods", h.CreatePaymentMethod)
+	handle("GET /users/{id}/payment-methods", h.ListPaymentMethods)
+	handle("PUT /users/{id}/payment-methods/{methodId}", h.UpdatePaymentMethod)
+	handle("DELETE /users/{id}/payment-methods/{methodId}", h.DeletePaymentMethod)
+	handle("POST /services", h.CreateService)
+	handle("GET /services", h.ListServices)
+	handle("GET /services/{id}", h.GetService)
+	handle("PUT /services/{id}", h.UpdateService)
+	handle("DELETE /services/{id}", h.DeleteService)
+	handle("POST /graphql", graphql.ServeHTTP)
+	handle("GET /graphql", graphql.ServeHTTP)
 
 	mux.HandleFunc("GET /swagger/", httpSwagger.WrapHandler)
 
@@ -0,0 +1,399 @@
+package rpc
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+	subscriptionv1 "github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/api/subscription/v1"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/monthyear"
+)
+
+var eventTypes = map[string]subscriptionv1.SubscriptionEvent_Type{
+	models.EventCreated: subscriptionv1.SubscriptionEvent_TYPE_CREATED,
+	models.EventUpdated: subscriptionv1.SubscriptionEvent_TYPE_UPDATED,
+	models.EventDeleted: subscriptionv1.SubscriptionEvent_TYPE_DELETED,
+}
+
+var states = map[string]subscriptionv1.Subscription_State{
+	models.StateActive: subscriptionv1.Subscription_STATE_ACTIVE,
+	models.StatePaused: subscriptionv1.Subscription_STATE_PAUSED,
+	models.StateEnded:  subscriptionv1.Subscription_STATE_ENDED,
+}
+
+var splitRules = map[string]subscriptionv1.SplitRule{
+	models.SplitEqual:   subscriptionv1.SplitRule_SPLIT_RULE_EQUAL,
+	models.SplitPercent: subscriptionv1.SplitRule_SPLIT_RULE_PERCENT,
+	models.SplitFixed:   subscriptionv1.SplitRule_SPLIT_RULE_FIXED,
+}
+
+var scheduledActions = map[string]subscriptionv1.ScheduledAction{
+	models.ScheduledCancel: subscriptionv1.ScheduledAction_SCHEDULED_ACTION_CANCEL,
+	models.ScheduledPrice:  subscriptionv1.ScheduledAction_SCHEDULED_ACTION_PRICE,
+}
+
+var scheduledStatuses = map[string]subscriptionv1.ScheduledChange_Status{
+	models.ScheduledPending:  subscriptionv1.ScheduledChange_STATUS_PENDING,
+	models.ScheduledApplied:  subscriptionv1.ScheduledChange_STATUS_APPLIED,
+	models.ScheduledCanceled: subscriptionv1.ScheduledChange_STATUS_CANCELED,
+}
+
+func toProto(sub models.SubscriptionResponse) *subscriptionv1.Subscription {
+	resp := &subscriptionv1.Subscription{
+		Id:           sub.ID.String(),
+		UserId:       sub.UserID.String(),
+		ServiceName:  sub.ServiceName,
+		Price:        int64(sub.Price),
+		CurrentPrice: int64(sub.CurrentPrice),
+		StartDate:    monthToProto(sub.StartDate),
+		EndDate:      monthToProto(sub.EndDate),
+		TrialMonths:  int32(sub.TrialMonths),
+		TrialPrice:   int64(sub.TrialPrice),
+		TrialEndDate: monthToProto(sub.TrialEndDate),
+		State:        states[sub.State],
+		Category:     sub.Category,
+		Tags:         sub.Tags,
+		AccountLabel: sub.AccountLabel,
+		BillingDay:   int32(sub.BillingDay),
+	}
+	if sub.ServiceID != nil {
+		id := sub.ServiceID.String()
+		resp.ServiceId = &id
+	}
+	if sub.PlanID != nil {
+		id := sub.PlanID.String()
+		resp.PlanId = &id
+	}
+	if sub.PaymentMethodID != nil {
+		id := sub.PaymentMethodID.String()
+		resp.PaymentMethodId = &id
+	}
+	return resp
+}
+
+func eventToProto(event models.SubscriptionEvent) *subscriptionv1.SubscriptionEvent {
+	return &subscriptionv1.SubscriptionEvent{
+		Type:         eventTypes[event.Type],
+		Subscription: toProto(event.Subscription),
+		Time:         timestamppb.New(event.Time),
+	}
+}
+
+func monthToProto(my *monthyear.MonthYear) *subscriptionv1.Month {
+	if my == nil {
+		return nil
+	}
+	t := time.Time(*my)
+	return &subscriptionv1.Month{Year: int32(t.Year()), Month: int32(t.Month())}
+}
+
+// monthFromProto converts an optional month, field names the request field in errors
+func monthFromProto(m *subscriptionv1.Month, field string) (*monthyear.MonthYear, error) {
+	if m == nil {
+		return nil, nil
+	}
+	if m.Month < 1 || m.Month > 12 || m.Year < 1 || m.Year > 9999 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid %s: month must be 1-12 and year 1-9999", field)
+	}
+	my := monthyear.MonthYear(time.Date(int(m.Year), time.Month(m.Month), 1, 0, 0, 0, 0, time.UTC))
+	return &my, nil
+}
+
+func parseID(raw, field string) (uuid.UUID, error) {
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, status.Errorf(codes.InvalidArgument, "invalid %s format", field)
+	}
+	return id, nil
+}
+
+func parseOptionalID(raw *string, field string) (*uuid.UUID, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	id, err := parseID(*raw, field)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+func createFromProto(req *subscriptionv1.CreateSubscriptionRequest) (models.CreateSubscriptionRequest, error) {
+	userID, err := parseID(req.GetUserId(), "user_id")
+	if err != nil {
+		return models.CreateSubscriptionRequest{}, err
+	}
+	trialPrice, err := priceFromProto(req.GetTrialPrice(), "trial_price")
+	if err != nil {
+		return models.CreateSubscriptionRequest{}, err
+	}
+	serviceID, err := parseOptionalID(req.ServiceId, "service_id")
+	if err != nil {
+		return models.CreateSubscriptionRequest{}, err
+	}
+	planID, err := parseOptionalID(req.PlanId, "plan_id")
+	if err != nil {
+		return models.CreateSubscriptionRequest{}, err
+	}
+	paymentMethodID, err := parseOptionalID(req.PaymentMethodId, "payment_method_id")
+	if err != nil {
+		return models.CreateSubscriptionRequest{}, err
+	}
+	startDate, err := monthFromProto(req.GetStartDate(), "start_date")
+	if err != nil {
+		return models.CreateSubscriptionRequest{}, err
+	}
+	endDate, err := monthFromProto(req.EndDate, "end_date")
+	if err != nil {
+		return models.CreateSubscriptionRequest{}, err
+	}
+
+	create := models.CreateSubscriptionRequest{
+		ServiceName:     req.GetServiceName(),
+		ServiceID:       serviceID,
+		PlanID:          planID,
+		UserID:          userID,
+		StartDate:       startDate,
+		EndDate:         endDate,
+		TrialMonths:     int(req.GetTrialMonths()),
+		TrialPrice:      trialPrice,
+		Category:        req.GetCategory(),
+		Tags:            req.GetTags(),
+		AccountLabel:    req.GetAccountLabel(),
+		BillingDay:      int(req.GetBillingDay()),
+		PaymentMethodID: paymentMethodID,
+	}
+	if req.Price != nil {
+		price, err := priceFromProto(*req.Price, "price")
+		if err != nil {
+			return models.CreateSubscriptionRequest{}, err
+		}
+		create.Price = &price
+	}
+	return create, nil
+}
+
+func updateFromProto(req *subscriptionv1.UpdateSubscriptionRequest) (models.UpdateSubscriptionRequest, error) {
+	serviceID, err := parseOptionalID(req.ServiceId, "service_id")
+	if err != nil {
+		return models.UpdateSubscriptionRequest{}, err
+	}
+	planID, err := parseOptionalID(req.PlanId, "plan_id")
+	if err != nil {
+		return models.UpdateSubscriptionRequest{}, err
+	}
+	update := models.UpdateSubscriptionRequest{
+		ServiceName:  req.ServiceName,
+		ServiceID:    serviceID,
+		PlanID:       planID,
+		Category:     req.Category,
+		AccountLabel: req.AccountLabel,
+		AddTags:      req.GetAddTags(),
+		RemoveTags:   req.GetRemoveTags(),
+	}
+	if req.Price != nil {
+		price, err := priceFromProto(*req.Price, "price")
+		if err != nil {
+			return models.UpdateSubscriptionRequest{}, err
+		}
+		update.Price = &price
+	}
+	if req.TrialMonths != nil {
+		trialMonths := int(*req.TrialMonths)
+		update.TrialMonths = &trialMonths
+	}
+	if req.BillingDay != nil {
+		billingDay := int(*req.BillingDay)
+		update.BillingDay = &billingDay
+	}
+	if req.PaymentMethodId != nil {
+		// The nil UUID unlinks the subscription
+		paymentMethodID := uuid.Nil
+		if *req.PaymentMethodId != "" {
+			if paymentMethodID, err = parseID(*req.PaymentMethodId, "payment_method_id"); err != nil {
+				return models.UpdateSubscriptionRequest{}, err
+			}
+		}
+		update.PaymentMethodID = &paymentMethodID
+	}
+	if req.TrialPrice != nil {
+		trialPrice, err := priceFromProto(*req.TrialPrice, "trial_price")
+		if err != nil {
+			return models.UpdateSubscriptionRequest{}, err
+		}
+		update.TrialPrice = &trialPrice
+	}
+	endDate, err := monthFromProto(req.EndDate, "end_date")
+	if err != nil {
+		return models.UpdateSubscriptionRequest{}, err
+	}
+	update.EndDate = endDate
+	return update, nil
+}
+
+func pauseFromProto(req *subscriptionv1.PauseSubscriptionRequest) (models.PauseSubscriptionRequest, error) {
+	startDate, err := monthFromProto(req.StartDate, "start_date")
+	if err != nil {
+		return models.PauseSubscriptionRequest{}, err
+	}
+	endDate, err := monthFromProto(req.EndDate, "end_date")
+	if err != nil {
+		return models.PauseSubscriptionRequest{}, err
+	}
+	return models.PauseSubscriptionRequest{StartDate: startDate, EndDate: endDate}, nil
+}
+
+func changePlanFromProto(req *subscriptionv1.ChangePlanRequest) (models.ChangePlanRequest, error) {
+	planID, err := parseID(req.GetPlanId(), "plan_id")
+	if err != nil {
+		return models.ChangePlanRequest{}, err
+	}
+	startDate, err := monthFromProto(req.StartDate, "start_date")
+	if err != nil {
+		return models.ChangePlanRequest{}, err
+	}
+	change := models.ChangePlanRequest{PlanID: planID, StartDate: startDate}
+	if req.Price != nil {
+		price, err := priceFromProto(*req.Price, "price")
+		if err != nil {
+			return models.ChangePlanRequest{}, err
+		}
+		change.Price = &price
+	}
+	return change, nil
+}
+
+// setMembersFromProto leaves the split rule empty for SPLIT_RULE_UNSPECIFIED so validation rejects it.
+func setMembersFromProto(req *subscriptionv1.SetMembersRequest) (models.SetMembersRequest, error) {
+	set := models.SetMembersRequest{Members: make([]models.SubscriptionMemberRequest, len(req.GetMembers()))}
+	for rule, value := range splitRules {
+		if value == req.GetSplitRule() {
+			set.SplitRule = rule
+		}
+	}
+	for i, member := range req.GetMembers() {
+		userID, err := parseID(member.GetUserId(), "user_id")
+		if err != nil {
+			return models.SetMembersRequest{}, err
+		}
+		share, err := priceFromProto(member.GetShare(), "share")
+		if err != nil {
+			return models.SetMembersRequest{}, err
+		}
+		set.Members[i] = models.SubscriptionMemberRequest{UserID: userID, Share: share}
+	}
+	return set, nil
+}
+
+func membersToProto(members models.MembersResponse) *subscriptionv1.Members {
+	resp := &subscriptionv1.Members{
+		SplitRule:    splitRules[members.SplitRule],
+		OwnerId:      members.OwnerID.String(),
+		OwnerShare:   int64(members.OwnerShare),
+		Members:      make([]*subscriptionv1.Member, len(members.Members)),
+		CurrentPrice: int64(members.CurrentPrice),
+	}
+	for i, member := range members.Members {
+		resp.Members[i] = &subscriptionv1.Member{
+			UserId:       member.UserID.String(),
+			Share:        int64(member.Share),
+			CurrentShare: int64(member.CurrentShare),
+		}
+	}
+	return resp
+}
+
+func planChangeToProto(change models.PlanChangeResponse) *subscriptionv1.PlanChange {
+	resp := &subscriptionv1.PlanChange{
+		Id:        change.ID.String(),
+		Price:     int64(change.Price),
+		StartDate: monthToProto(change.StartDate),
+	}
+	if change.PlanID != nil {
+		id := change.PlanID.String()
+		resp.PlanId = &id
+	}
+	return resp
+}
+
+// scheduleChangeFromProto leaves the action empty for SCHEDULED_ACTION_UNSPECIFIED so validation rejects it.
+func scheduleChangeFromProto(req *subscriptionv1.ScheduleChangeRequest) (models.ScheduleChangeRequest, error) {
+	month, err := monthFromProto(req.Month, "month")
+	if err != nil {
+		return models.ScheduleChangeRequest{}, err
+	}
+	change := models.ScheduleChangeRequest{Month: month}
+	for action, value := range scheduledActions {
+		if value == req.GetAction() {
+			change.Action = action
+		}
+	}
+	if req.Price != nil {
+		price, err := priceFromProto(*req.Price, "price")
+		if err != nil {
+			return models.ScheduleChangeRequest{}, err
+		}
+		change.Price = &price
+	}
+	return change, nil
+}
+
+func scheduledChangeToProto(change models.ScheduledChangeResponse) *subscriptionv1.ScheduledChange {
+	resp := &subscriptionv1.ScheduledChange{
+		Id:             change.ID.String(),
+		SubscriptionId: change.SubscriptionID.String(),
+		Action:         scheduledActions[change.Action],
+		Month:          monthToProto(change.Month),
+		Status:         scheduledStatuses[change.Status],
+		CreatedAt:      timestamppb.New(change.CreatedAt),
+	}
+	if change.Price != nil {
+		price := int64(*change.Price)
+		resp.Price = &price
+	}
+	if change.AppliedAt != nil {
+		resp.AppliedAt = timestamppb.New(*change.AppliedAt)
+	}
+	return resp
+}
+
+func totalCostFromProto(req *subscriptionv1.GetTotalCostRequest) (models.TotalCostRequest, error) {
+	userID, err := parseOptionalID(req.UserId, "user_id")
+	if err != nil {
+		return models.TotalCostRequest{}, err
+	}
+	serviceID, err := parseOptionalID(req.ServiceId, "service_id")
+	if err != nil {
+		return models.TotalCostRequest{}, err
+	}
+	startDate, err := monthFromProto(req.StartDate, "start_date")
+	if err != nil {
+		return models.TotalCostRequest{}, err
+	}
+	endDate, err := monthFromProto(req.EndDate, "end_date")
+	if err != nil {
+		return models.TotalCostRequest{}, err
+	}
+
+	return models.TotalCostRequest{
+		UserID:      userID,
+		ServiceName: req.ServiceName,
+		ServiceID:   serviceID,
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Tags:        req.GetTags(),
+		Category:    req.Category,
+	}, nil
+}
+
+func priceFromProto(price int64, field string) (int, error) {
+	if price < 0 || price > 1<<31-1 {
+		return 0, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid %s %d", field, price))
+	}
+	return int(price), nil
+}
@@ -0,0 +1,238 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionv1alpha "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/api/middleware"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/auth"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/i18n"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/ratelimit"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/tenant"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/logger"
+)
+
+var requestIDKey = strings.ToLower(middleware.RequestIDHeader)
+
+// infrastructureServices are called by orchestrator probes and tools without tokens or tenants,
+// so they are not authenticated, rate limited or scoped to a tenant
+var infrastructureServices = []string{
+	healthpb.Health_ServiceDesc.ServiceName,
+	reflectionv1.ServerReflection_ServiceDesc.ServiceName,
+	reflectionv1alpha.ServerReflection_ServiceDesc.ServiceName,
+}
+
+func isInfrastructure(fullMethod string) bool {
+	service, _, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	return slices.Contains(infrastructureServices, service)
+}
+
+// contextFunc derives the context of a call to fullMethod or rejects the call with a status error
+type contextFunc func(ctx context.Context, fullMethod string) (context.Context, error)
+
+// unary applies fn to calls of the API, calls of infrastructure services pass through unchanged
+func unary(fn contextFunc) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if isInfrastructure(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		ctx, err := fn(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// stream applies fn to streams of the API, streams of infrastructure services pass through unchanged
+func stream(fn contextFunc) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if isInfrastructure(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		ctx, err := fn(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// serverStream overrides the context of a stream
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func authenticateUnary(v *auth.Verifier) grpc.UnaryServerInterceptor {
+	return unary(authenticate(v))
+}
+
+func authenticateStream(v *auth.Verifier) grpc.StreamServerInterceptor {
+	return stream(authenticate(v))
+}
+
+// authenticate requires a valid bearer token in the authorization metadata, a nil verifier disables it
+func authenticate(v *auth.Verifier) contextFunc {
+	return func(ctx context.Context, _ string) (context.Context, error) {
+		if v == nil {
+			return ctx, nil
+		}
+		scheme, token, found := strings.Cut(firstValue(ctx, "authorization"), " ")
+		if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+			return nil, status.Error(codes.Unauthenticated, auth.ErrMissingToken.Error())
+		}
+
+		principal, err := v.Verify(strings.TrimSpace(token))
+		if err != nil {
+			logger.FromContext(ctx).DebugContext(ctx, "token rejected", "error", err)
+			return nil, status.Error(codes.Unauthenticated, auth.ErrInvalidToken.Error())
+		}
+		return auth.WithPrincipal(ctx, principal), nil
+	}
+}
+
+func tenantUnary(key, defaultTenant string) grpc.UnaryServerInterceptor {
+	return unary(resolveTenant(key, defaultTenant))
+}
+
+func tenantStream(key, defaultTenant string) grpc.StreamServerInterceptor {
+	return stream(resolveTenant(key, defaultTenant))
+}
+
+// resolveTenant stores the tenant of the call in its context, must run after authenticate
+func resolveTenant(key, defaultTenant string) contextFunc {
+	return func(ctx context.Context, _ string) (context.Context, error) {
+		tenantID, err := tenant.Resolve(ctx, firstValue(ctx, key), defaultTenant)
+		switch {
+		case errors.Is(err, tenant.ErrMismatch):
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		case errors.Is(err, tenant.ErrRequired):
+			return nil, status.Error(codes.InvalidArgument, key+" metadata required")
+		case errors.Is(err, tenant.ErrClaimRequired):
+			return nil, status.Error(codes.InvalidArgument, i18n.T(i18n.Translator("en"), "tenant_claim_required"))
+		case err != nil:
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return tenant.WithID(ctx, tenantID), nil
+	}
+}
+
+func rateLimitUnary(limiters *ratelimit.Limiters, app rateLimitConfig) grpc.UnaryServerInterceptor {
+	return unary(rateLimit(limiters, app))
+}
+
+func rateLimitStream(limiters *ratelimit.Limiters, app rateLimitConfig) grpc.StreamServerInterceptor {
+	return stream(rateLimit(limiters, app))
+}
+
+// rateLimitConfig identifies clients the same way as the REST rate limit middleware
+type rateLimitConfig struct {
+	keyHeader      string
+	apiKeys        []string
+	trustedProxies int
+}
+
+// rateLimit rejects calls over the client's limit with RESOURCE_EXHAUSTED and a retry-after header.
+// Methods use the limiter of the equivalent REST route, so both APIs share a client's quota.
+// Must run after authenticate.
+func rateLimit(limiters *ratelimit.Limiters, cfg rateLimitConfig) contextFunc {
+	return func(ctx context.Context, fullMethod string) (context.Context, error) {
+		route, ok := methodRoutes[fullMethod]
+		if !ok {
+			route = fullMethod
+		}
+		l := limiters.For(route)
+		if l == nil {
+			return ctx, nil
+		}
+
+		var apiKey string
+		if cfg.keyHeader != "" {
+			apiKey = firstValue(ctx, strings.ToLower(cfg.keyHeader))
+		}
+		res := l.Allow(ratelimit.ClientKey(ctx, apiKey, cfg.apiKeys, peerIP(ctx, cfg.trustedProxies)))
+		if !res.Allowed {
+			retryAfter := strconv.Itoa(int(math.Ceil(res.RetryAfter.Seconds())))
+			_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", retryAfter))
+			return nil, status.Error(codes.ResourceExhausted, "rate limit exceeded, retry after "+retryAfter+"s")
+		}
+		return ctx, nil
+	}
+}
+
+// peerIP returns the IP address of the client of a call, see ratelimit.ClientIP
+func peerIP(ctx context.Context, trustedProxies int) string {
+	var remoteAddr string
+	if p, ok := peer.FromContext(ctx); ok {
+		remoteAddr = p.Addr.String()
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	return ratelimit.ClientIP(remoteAddr, md.Get("x-forwarded-for"), trustedProxies)
+}
+
+// loggingUnary stores a logger carrying the request ID in the call context and logs the completed call
+func loggingUnary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		ctx, lgr := withRequestLogger(ctx)
+		resp, err := handler(ctx, req)
+		logCall(ctx, lgr, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+func loggingStream() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx, lgr := withRequestLogger(ss.Context())
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		logCall(ctx, lgr, info.FullMethod, start, err)
+		return err
+	}
+}
+
+func withRequestLogger(ctx context.Context) (context.Context, *slog.Logger) {
+	requestID := firstValue(ctx, requestIDKey)
+	if !middleware.ValidRequestID(requestID) {
+		requestID = uuid.NewString()
+	}
+	lgr := logger.FromContext(ctx).With("request_id", requestID)
+	return logger.WithContext(ctx, lgr), lgr
+}
+
+func logCall(ctx context.Context, lgr *slog.Logger, method string, start time.Time, err error) {
+	lgr.LogAttrs(ctx, slog.LevelInfo, "rpc completed",
+		slog.String("method", method),
+		slog.String("code", status.Code(err).String()),
+		slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+	)
+}
+
+func firstValue(ctx context.Context, key string) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
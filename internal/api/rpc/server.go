@@ -0,0 +1,113 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/auth"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/config"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/ratelimit"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/service"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/validation"
+	subscriptionv1 "github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/api/subscription/v1"
+)
+
+// methodRoutes maps methods to the REST routes whose rate limits they share,
+// methods without an equivalent route are limited by their own name
+var methodRoutes = map[string]string{
+	subscriptionv1.SubscriptionService_CreateSubscription_FullMethodName:    "POST /subscriptions",
+	subscriptionv1.SubscriptionService_GetSubscription_FullMethodName:       "GET /subscriptions/{id}",
+	subscriptionv1.SubscriptionService_ListSubscriptions_FullMethodName:     "GET /subscriptions",
+	subscriptionv1.SubscriptionService_UpdateSubscription_FullMethodName:    "PATCH /subscriptions/{id}",
+	subscriptionv1.SubscriptionService_DeleteSubscription_FullMethodName:    "DELETE /subscriptions/{id}",
+	subscriptionv1.SubscriptionService_GetTotalCost_FullMethodName:          "GET /subscriptions/total-cost",
+	subscriptionv1.SubscriptionService_PauseSubscription_FullMethodName:     "POST /subscriptions/{id}/pause",
+	subscriptionv1.SubscriptionService_ResumeSubscription_FullMethodName:    "POST /subscriptions/{id}/resume",
+	subscriptionv1.SubscriptionService_ChangePlan_FullMethodName:            "POST /subscriptions/{id}/plan-changes",
+	subscriptionv1.SubscriptionService_ListPlanChanges_FullMethodName:       "GET /subscriptions/{id}/plan-changes",
+	subscriptionv1.SubscriptionService_ScheduleChange_FullMethodName:        "POST /subscriptions/{id}/scheduled-changes",
+	subscriptionv1.SubscriptionService_ListScheduledChanges_FullMethodName:  "GET /subscriptions/{id}/scheduled-changes",
+	subscriptionv1.SubscriptionService_CancelScheduledChange_FullMethodName: "DELETE /subscriptions/{id}/scheduled-changes/{changeId}",
+	subscriptionv1.SubscriptionService_GetMembers_FullMethodName:            "GET /subscriptions/{id}/members",
+	subscriptionv1.SubscriptionService_SetMembers_FullMethodName:            "PUT /subscriptions/{id}/members",
+}
+
+// Server serves the gRPC API with the standard health service.
+// Calls are logged, authenticated, rate limited and scoped to a tenant the same way as REST requests,
+// except for calls of the health and reflection services.
+type Server struct {
+	grpc   *grpc.Server
+	health *health.Server
+}
+
+func NewServer(s service.SubscriptionService, w service.SubscriptionWatcher, verifier *auth.Verifier,
+	limiters *ratelimit.Limiters, app config.AppConfig, cfg config.GRPCConfig) *Server {
+	tenantKey := strings.ToLower(app.TenantHeader)
+	limits := rateLimitConfig{
+		keyHeader:      app.RateLimitKeyHeader,
+		apiKeys:        app.RateLimitAPIKeys,
+		trustedProxies: app.RateLimitTrustedProxies,
+	}
+	srv := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(
+			loggingUnary(),
+			authenticateUnary(verifier),
+			rateLimitUnary(limiters, limits),
+			tenantUnary(tenantKey, app.DefaultTenant),
+		),
+		grpc.ChainStreamInterceptor(
+			loggingStream(),
+			authenticateStream(verifier),
+			rateLimitStream(limiters, limits),
+			tenantStream(tenantKey, app.DefaultTenant),
+		),
+	)
+
+	subscriptionv1.RegisterSubscriptionServiceServer(srv, &subscriptionServer{
+		service:   s,
+		watcher:   w,
+		validator: validation.New(),
+	})
+
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(srv, healthServer)
+	if cfg.Reflection {
+		reflection.Register(srv)
+	}
+
+	return &Server{grpc: srv, health: healthServer}
+}
+
+func (s *Server) Serve(lis net.Listener) error {
+	return s.grpc.Serve(lis)
+}
+
+// SetDraining reports NOT_SERVING to health checks, like /readyz during shutdown
+func (s *Server) SetDraining() {
+	s.health.Shutdown()
+}
+
+// Shutdown waits for running calls until ctx is done, then closes the remaining connections
+func (s *Server) Shutdown(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		s.grpc.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.grpc.Stop()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,144 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/auth"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/config"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/ratelimit"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/service"
+	subscriptionv1 "github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/api/subscription/v1"
+)
+
+const testSecret = "test-secret"
+
+// fakeService answers total cost calls, other methods panic through the nil interface
+type fakeService struct {
+	service.SubscriptionService
+}
+
+func (fakeService) GetTotalCost(context.Context, models.TotalCostRequest) (models.TotalCostResponse, error) {
+	return models.TotalCostResponse{TotalCost: 299}, nil
+}
+
+// newTestClient serves the API over an in-memory connection with authentication
+// and the given rate limiters
+func newTestClient(t *testing.T, limiters *ratelimit.Limiters) *grpc.ClientConn {
+	t.Helper()
+
+	verifier, err := auth.NewVerifier(config.AuthConfig{HMACSecret: testSecret})
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	app := config.AppConfig{TenantHeader: "X-Tenant-ID", DefaultTenant: "default", RateLimitKeyHeader: "X-API-Key"}
+	srv := NewServer(fakeService{}, nil, verifier, limiters, app, config.GRPCConfig{Reflection: true})
+
+	lis := bufconn.Listen(1 << 20)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(func() { srv.grpc.Stop() })
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func withToken(t *testing.T, ctx context.Context, userID uuid.UUID) context.Context {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   userID.String(),
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}).SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
+
+func TestInfrastructureServicesSkipAuthentication(t *testing.T) {
+	limiters := ratelimit.NewLimiters(config.RateLimit{Requests: 1, Period: time.Minute}, nil, 100)
+	conn := newTestClient(t, limiters)
+	health := healthpb.NewHealthClient(conn)
+
+	// More checks than the limit allows, without a token or tenant
+	for range 3 {
+		resp, err := health.Check(context.Background(), &healthpb.HealthCheckRequest{})
+		if err != nil {
+			t.Fatalf("Health/Check: %v", err)
+		}
+		if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+			t.Fatalf("Health/Check status = %v, want SERVING", resp.GetStatus())
+		}
+	}
+
+	_, err := subscriptionv1.NewSubscriptionServiceClient(conn).GetTotalCost(context.Background(),
+		&subscriptionv1.GetTotalCostRequest{})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("GetTotalCost without token: %v, want Unauthenticated", err)
+	}
+}
+
+func TestRateLimitSharedWithREST(t *testing.T) {
+	route := "GET /subscriptions/total-cost"
+	limiters := ratelimit.NewLimiters(config.RateLimit{Requests: 100, Period: time.Minute},
+		config.RouteRateLimits{route: {Requests: 2, Period: time.Minute}}, 100)
+	client := subscriptionv1.NewSubscriptionServiceClient(newTestClient(t, limiters))
+
+	userID := uuid.New()
+	ctx := withToken(t, context.Background(), userID)
+
+	// The REST route already used one request of the caller's quota
+	limiters.For(route).Allow("user:" + userID.String())
+
+	if _, err := client.GetTotalCost(ctx, &subscriptionv1.GetTotalCostRequest{}); err != nil {
+		t.Fatalf("first GetTotalCost: %v", err)
+	}
+	var header metadata.MD
+	_, err := client.GetTotalCost(ctx, &subscriptionv1.GetTotalCostRequest{}, grpc.Header(&header))
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("GetTotalCost over the limit: %v, want ResourceExhausted", err)
+	}
+	if len(header.Get("retry-after")) == 0 {
+		t.Error("retry-after header is missing")
+	}
+
+	// Other callers have their own quota
+	other := withToken(t, context.Background(), uuid.New())
+	if _, err := client.GetTotalCost(other, &subscriptionv1.GetTotalCostRequest{}); err != nil {
+		t.Fatalf("GetTotalCost of another user: %v", err)
+	}
+}
+
+func TestTenantClaimRequired(t *testing.T) {
+	limiters := ratelimit.NewLimiters(config.RateLimit{Requests: 100, Period: time.Minute}, nil, 100)
+	client := subscriptionv1.NewSubscriptionServiceClient(newTestClient(t, limiters))
+
+	// The token has no tenant_id claim, so only the default tenant is allowed
+	ctx := metadata.AppendToOutgoingContext(withToken(t, context.Background(), uuid.New()), "x-tenant-id", "acme")
+	_, err := client.GetTotalCost(ctx, &subscriptionv1.GetTotalCostRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("GetTotalCost: %v, want InvalidArgument", err)
+	}
+	if want := "token without a tenant_id claim can only access the default tenant"; status.Convert(err).Message() != want {
+		t.Errorf("message = %q, want %q", status.Convert(err).Message(), want)
+	}
+}
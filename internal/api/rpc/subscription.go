@@ -0,0 +1,383 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/repository"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/service"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/validation"
+	subscriptionv1 "github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/api/subscription/v1"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/logger"
+)
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+)
+
+// subscriptionServer implements subscriptionv1.SubscriptionServiceServer on top of the service layer
+type subscriptionServer struct {
+	subscriptionv1.UnimplementedSubscriptionServiceServer
+
+	service   service.SubscriptionService
+	watcher   service.SubscriptionWatcher
+	validator *validation.Validator
+}
+
+func (s *subscriptionServer) CreateSubscription(ctx context.Context, req *subscriptionv1.CreateSubscriptionRequest) (*subscriptionv1.Subscription, error) {
+	create, err := createFromProto(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validator.Struct(&create); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	sub, err := s.service.CreateSubscription(ctx, create)
+	if err != nil {
+		return nil, toStatus(ctx, "create subscription", err)
+	}
+	return toProto(sub), nil
+}
+
+func (s *subscriptionServer) GetSubscription(ctx context.Context, req *subscriptionv1.GetSubscriptionRequest) (*subscriptionv1.Subscription, error) {
+	id, err := parseID(req.GetId(), "id")
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := s.service.GetSubscriptionByID(ctx, id)
+	if err != nil {
+		return nil, toStatus(ctx, "get subscription", err)
+	}
+	return toProto(sub), nil
+}
+
+func (s *subscriptionServer) ListSubscriptions(req *subscriptionv1.ListSubscriptionsRequest, stream grpc.ServerStreamingServer[subscriptionv1.Subscription]) error {
+	ctx := stream.Context()
+
+	pageSize := int(req.GetPageSize())
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	pageSize = min(pageSize, maxPageSize)
+	if req.GetLimit() < 0 {
+		return status.Error(codes.InvalidArgument, "limit must not be negative")
+	}
+	remaining := int(req.GetLimit())
+
+	list := models.ListSubscriptionsRequest{Limit: pageSize, Tags: req.GetTags()}
+	if (req.AfterId == nil) != (req.AfterStartDate == nil) {
+		return status.Error(codes.InvalidArgument, "after_id and after_start_date must be set together")
+	}
+	if req.AfterId != nil {
+		id, err := parseID(req.GetAfterId(), "after_id")
+		if err != nil {
+			return err
+		}
+		startDate, err := monthFromProto(req.AfterStartDate, "after_start_date")
+		if err != nil {
+			return err
+		}
+		list.Cursor = &models.SubscriptionCursor{ID: id, StartDate: *startDate}
+	}
+
+	for {
+		if req.GetLimit() > 0 {
+			list.Limit = min(pageSize, remaining)
+		}
+		page, err := s.service.ListSubscriptions(ctx, list)
+		if err != nil {
+			return toStatus(ctx, "list subscriptions", err)
+		}
+		for _, sub := range page {
+			if err := stream.Send(toProto(sub)); err != nil {
+				return err
+			}
+		}
+
+		remaining -= len(page)
+		if len(page) < list.Limit || (req.GetLimit() > 0 && remaining == 0) {
+			return nil
+		}
+		last := page[len(page)-1]
+		list.Cursor = &models.SubscriptionCursor{ID: last.ID, StartDate: *last.StartDate}
+	}
+}
+
+func (s *subscriptionServer) UpdateSubscription(ctx context.Context, req *subscriptionv1.UpdateSubscriptionRequest) (*subscriptionv1.Subscription, error) {
+	id, err := parseID(req.GetId(), "id")
+	if err != nil {
+		return nil, err
+	}
+	update, err := updateFromProto(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validator.Struct(&update); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	sub, err := s.service.UpdateSubscription(ctx, id, update)
+	if err != nil {
+		return nil, toStatus(ctx, "update subscription", err)
+	}
+	return toProto(sub), nil
+}
+
+func (s *subscriptionServer) DeleteSubscription(ctx context.Context, req *subscriptionv1.DeleteSubscriptionRequest) (*subscriptionv1.DeleteSubscriptionResponse, error) {
+	id, err := parseID(req.GetId(), "id")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.service.DeleteSubscription(ctx, id); err != nil {
+		return nil, toStatus(ctx, "delete subscription", err)
+	}
+	return &subscriptionv1.DeleteSubscriptionResponse{}, nil
+}
+
+func (s *subscriptionServer) GetTotalCost(ctx context.Context, req *subscriptionv1.GetTotalCostRequest) (*subscriptionv1.GetTotalCostResponse, error) {
+	filter, err := totalCostFromProto(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validator.Struct(&filter); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	resp, err := s.service.GetTotalCost(ctx, filter)
+	if err != nil {
+		return nil, toStatus(ctx, "get total cost", err)
+	}
+	return &subscriptionv1.GetTotalCostResponse{TotalCost: int64(resp.TotalCost)}, nil
+}
+
+func (s *subscriptionServer) PauseSubscription(ctx context.Context, req *subscriptionv1.PauseSubscriptionRequest) (*subscriptionv1.Subscription, error) {
+	id, err := parseID(req.GetId(), "id")
+	if err != nil {
+		return nil, err
+	}
+	pause, err := pauseFromProto(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validator.Struct(&pause); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	sub, err := s.service.PauseSubscription(ctx, id, pause)
+	if err != nil {
+		return nil, toStatus(ctx, "pause subscription", err)
+	}
+	return toProto(sub), nil
+}
+
+func (s *subscriptionServer) ResumeSubscription(ctx context.Context, req *subscriptionv1.ResumeSubscriptionRequest) (*subscriptionv1.Subscription, error) {
+	id, err := parseID(req.GetId(), "id")
+	if err != nil {
+		return nil, err
+	}
+	month, err := monthFromProto(req.Month, "month")
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := s.service.ResumeSubscription(ctx, id, models.ResumeSubscriptionRequest{Month: month})
+	if err != nil {
+		return nil, toStatus(ctx, "resume subscription", err)
+	}
+	return toProto(sub), nil
+}
+
+func (s *subscriptionServer) ChangePlan(ctx context.Context, req *subscriptionv1.ChangePlanRequest) (*subscriptionv1.Subscription, error) {
+	id, err := parseID(req.GetId(), "id")
+	if err != nil {
+		return nil, err
+	}
+	change, err := changePlanFromProto(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validator.Struct(&change); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	sub, err := s.service.ChangeSubscriptionPlan(ctx, id, change)
+	if err != nil {
+		return nil, toStatus(ctx, "change subscription plan", err)
+	}
+	return toProto(sub), nil
+}
+
+func (s *subscriptionServer) ListPlanChanges(ctx context.Context, req *subscriptionv1.ListPlanChangesRequest) (*subscriptionv1.ListPlanChangesResponse, error) {
+	id, err := parseID(req.GetId(), "id")
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := s.service.ListPlanChanges(ctx, id)
+	if err != nil {
+		return nil, toStatus(ctx, "list plan changes", err)
+	}
+	resp := &subscriptionv1.ListPlanChangesResponse{Changes: make([]*subscriptionv1.PlanChange, len(changes))}
+	for i, change := range changes {
+		resp.Changes[i] = planChangeToProto(change)
+	}
+	return resp, nil
+}
+
+func (s *subscriptionServer) ScheduleChange(ctx context.Context, req *subscriptionv1.ScheduleChangeRequest) (*subscriptionv1.ScheduledChange, error) {
+	id, err := parseID(req.GetId(), "id")
+	if err != nil {
+		return nil, err
+	}
+	change, err := scheduleChangeFromProto(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validator.Struct(&change); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	scheduled, err := s.service.ScheduleChange(ctx, id, change)
+	if err != nil {
+		return nil, toStatus(ctx, "schedule change", err)
+	}
+	return scheduledChangeToProto(scheduled), nil
+}
+
+func (s *subscriptionServer) ListScheduledChanges(ctx context.Context, req *subscriptionv1.ListScheduledChangesRequest) (*subscriptionv1.ListScheduledChangesResponse, error) {
+	id, err := parseID(req.GetId(), "id")
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := s.service.ListScheduledChanges(ctx, id)
+	if err != nil {
+		return nil, toStatus(ctx, "list scheduled changes", err)
+	}
+	resp := &subscriptionv1.ListScheduledChangesResponse{Changes: make([]*subscriptionv1.ScheduledChange, len(changes))}
+	for i, change := range changes {
+		resp.Changes[i] = scheduledChangeToProto(change)
+	}
+	return resp, nil
+}
+
+func (s *subscriptionServer) CancelScheduledChange(ctx context.Context, req *subscriptionv1.CancelScheduledChangeRequest) (*subscriptionv1.CancelScheduledChangeResponse, error) {
+	id, err := parseID(req.GetId(), "id")
+	if err != nil {
+		return nil, err
+	}
+	changeID, err := parseID(req.GetChangeId(), "change_id")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.service.CancelScheduledChange(ctx, id, changeID); err != nil {
+		return nil, toStatus(ctx, "cancel scheduled change", err)
+	}
+	return &subscriptionv1.CancelScheduledChangeResponse{}, nil
+}
+
+func (s *subscriptionServer) GetMembers(ctx context.Context, req *subscriptionv1.GetMembersRequest) (*subscriptionv1.Members, error) {
+	id, err := parseID(req.GetId(), "id")
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.service.GetMembers(ctx, id)
+	if err != nil {
+		return nil, toStatus(ctx, "get members", err)
+	}
+	return membersToProto(members), nil
+}
+
+func (s *subscriptionServer) SetMembers(ctx context.Context, req *subscriptionv1.SetMembersRequest) (*subscriptionv1.Members, error) {
+	id, err := parseID(req.GetId(), "id")
+	if err != nil {
+		return nil, err
+	}
+	set, err := setMembersFromProto(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validator.Struct(&set); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	members, err := s.service.SetMembers(ctx, id, set)
+	if err != nil {
+		return nil, toStatus(ctx, "set members", err)
+	}
+	return membersToProto(members), nil
+}
+
+func (s *subscriptionServer) WatchSubscriptions(req *subscriptionv1.WatchSubscriptionsRequest, stream grpc.ServerStreamingServer[subscriptionv1.SubscriptionEvent]) error {
+	ctx := stream.Context()
+	userID, err := parseOptionalID(req.UserId, "user_id")
+	if err != nil {
+		return err
+	}
+
+	events, err := s.watcher.WatchSubscriptions(ctx, models.WatchSubscriptionsRequest{UserID: userID})
+	if err != nil {
+		return toStatus(ctx, "watch subscriptions", err)
+	}
+	for event := range events {
+		if err := stream.Send(eventToProto(event)); err != nil {
+			return err
+		}
+	}
+
+	if ctx.Err() != nil {
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	return status.Error(codes.Unavailable, "watch interrupted, changes may have been missed: list and watch again")
+}
+
+// toStatus maps service errors to gRPC status codes, unexpected errors are logged and hidden from the caller
+func toStatus(ctx context.Context, op string, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrSubscriptionNotFound):
+		return status.Error(codes.NotFound, repository.ErrSubscriptionNotFound.Error())
+	case errors.Is(err, repository.ErrSubscriptionAlreadyExists):
+		return status.Error(codes.AlreadyExists, repository.ErrSubscriptionAlreadyExists.Error())
+	case errors.Is(err, service.ErrInvalidDateRange):
+		return status.Error(codes.InvalidArgument, service.ErrInvalidDateRange.Error())
+	case errors.Is(err, service.ErrUnknownService):
+		return status.Error(codes.InvalidArgument, service.ErrUnknownService.Error())
+	case errors.Is(err, service.ErrUnknownPlan):
+		return status.Error(codes.InvalidArgument, service.ErrUnknownPlan.Error())
+	case errors.Is(err, service.ErrUnknownPaymentMethod):
+		return status.Error(codes.InvalidArgument, service.ErrUnknownPaymentMethod.Error())
+	case errors.Is(err, service.ErrInvalidPlanChange):
+		return status.Error(codes.InvalidArgument, service.ErrInvalidPlanChange.Error())
+	case errors.Is(err, service.ErrInvalidSplit):
+		return status.Error(codes.InvalidArgument, service.ErrInvalidSplit.Error())
+	case errors.Is(err, service.ErrInvalidPause):
+		return status.Error(codes.InvalidArgument, service.ErrInvalidPause.Error())
+	case errors.Is(err, service.ErrInvalidSchedule):
+		return status.Error(codes.InvalidArgument, service.ErrInvalidSchedule.Error())
+	case errors.Is(err, repository.ErrScheduledChangeNotFound):
+		return status.Error(codes.NotFound, repository.ErrScheduledChangeNotFound.Error())
+	case errors.Is(err, repository.ErrPaymentMethodNotFound):
+		return status.Error(codes.NotFound, repository.ErrPaymentMethodNotFound.Error())
+	case errors.Is(err, repository.ErrSubscriptionPaused):
+		return status.Error(codes.FailedPrecondition, repository.ErrSubscriptionPaused.Error())
+	case errors.Is(err, repository.ErrSubscriptionNotPaused):
+		return status.Error(codes.FailedPrecondition, repository.ErrSubscriptionNotPaused.Error())
+	case errors.Is(err, service.ErrForbidden):
+		return status.Error(codes.PermissionDenied, service.ErrForbidden.Error())
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	}
+	logger.FromContext(ctx).ErrorContext(ctx, "service failed to "+op, "error", err)
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,349 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/config"
+)
+
+const (
+	testSecret   = "test-hs256-secret"
+	testIssuer   = "https://issuer.test"
+	testAudience = "subscriptions"
+)
+
+var testUserID = uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
+
+type testKeys struct {
+	rsa      *rsa.PrivateKey
+	ed25519  ed25519.PrivateKey
+	jwksFile string
+}
+
+// newTestKeys generates an RSA and an Ed25519 key and writes their public halves to a JWKS file
+func newTestKeys(t *testing.T) testKeys {
+	t.Helper()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+	edPublic, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate ed25519 key: %v", err)
+	}
+
+	enc := base64.RawURLEncoding
+	set := jwks{Keys: []jwk{
+		{
+			Kty: "RSA", Kid: "rsa-1", Use: "sig",
+			N: enc.EncodeToString(rsaKey.N.Bytes()),
+			E: enc.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes()),
+		},
+		{Kty: "OKP", Kid: "ed-1", Crv: "Ed25519", X: enc.EncodeToString(edPublic)},
+		// Encryption keys are skipped
+		{Kty: "RSA", Kid: "enc-1", Use: "enc", N: "AQAB", E: "AQAB"},
+	}}
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatalf("marshal jwks: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write jwks: %v", err)
+	}
+
+	return testKeys{rsa: rsaKey, ed25519: edKey, jwksFile: path}
+}
+
+func validClaims() claims {
+	return claims{RegisteredClaims: jwt.RegisteredClaims{
+		Subject:   testUserID.String(),
+		Issuer:    testIssuer,
+		Audience:  jwt.ClaimStrings{testAudience},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}}
+}
+
+// mint signs c with key, kid is left out of the header when empty
+func mint(t *testing.T, method jwt.SigningMethod, kid string, key any, c claims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, c)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return signed
+}
+
+func TestVerify(t *testing.T) {
+	keys := newTestKeys(t)
+	verifier, err := NewVerifier(config.AuthConfig{
+		JWKSFile:   keys.jwksFile,
+		HMACSecret: testSecret,
+		Issuer:     testIssuer,
+		Audience:   testAudience,
+		AdminRole:  "admin",
+	})
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: mustMarshalPKIX(t, keys.rsa.Public())})
+
+	tests := []struct {
+		name    string
+		token   func() string
+		want    Principal
+		wantErr bool
+	}{
+		{
+			name:  "hs256 user",
+			token: func() string { return mint(t, jwt.SigningMethodHS256, "", []byte(testSecret), validClaims()) },
+			want:  Principal{UserID: testUserID},
+		},
+		{
+			name: "hs256 admin with tenant",
+			token: func() string {
+				c := validClaims()
+				c.Roles, c.TenantID = []string{"viewer", "admin"}, "acme"
+				return mint(t, jwt.SigningMethodHS256, "", []byte(testSecret), c)
+			},
+			want: Principal{UserID: testUserID, Admin: true, TenantID: "acme"},
+		},
+		{
+			name: "other roles are not admin",
+			token: func() string {
+				c := validClaims()
+				c.Roles = []string{"administrator"}
+				return mint(t, jwt.SigningMethodHS256, "", []byte(testSecret), c)
+			},
+			want: Principal{UserID: testUserID},
+		},
+		{
+			name:  "rs256 from jwks",
+			token: func() string { return mint(t, jwt.SigningMethodRS256, "rsa-1", keys.rsa, validClaims()) },
+			want:  Principal{UserID: testUserID},
+		},
+		{
+			name:  "eddsa from jwks",
+			token: func() string { return mint(t, jwt.SigningMethodEdDSA, "ed-1", keys.ed25519, validClaims()) },
+			want:  Principal{UserID: testUserID},
+		},
+		{
+			name:    "wrong hs256 secret",
+			token:   func() string { return mint(t, jwt.SigningMethodHS256, "", []byte("other"), validClaims()) },
+			wantErr: true,
+		},
+		{
+			name: "expired",
+			token: func() string {
+				c := validClaims()
+				c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+				return mint(t, jwt.SigningMethodHS256, "", []byte(testSecret), c)
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing expiry",
+			token: func() string {
+				c := validClaims()
+				c.ExpiresAt = nil
+				return mint(t, jwt.SigningMethodHS256, "", []byte(testSecret), c)
+			},
+			wantErr: true,
+		},
+		{
+			name: "wrong issuer",
+			token: func() string {
+				c := validClaims()
+				c.Issuer = "https://evil.test"
+				return mint(t, jwt.SigningMethodRS256, "rsa-1", keys.rsa, c)
+			},
+			wantErr: true,
+		},
+		{
+			name: "wrong audience",
+			token: func() string {
+				c := validClaims()
+				c.Audience = jwt.ClaimStrings{"billing"}
+				return mint(t, jwt.SigningMethodRS256, "rsa-1", keys.rsa, c)
+			},
+			wantErr: true,
+		},
+		{
+			name:    "unknown kid",
+			token:   func() string { return mint(t, jwt.SigningMethodRS256, "rsa-2", keys.rsa, validClaims()) },
+			wantErr: true,
+		},
+		{
+			name:    "no kid with several keys",
+			token:   func() string { return mint(t, jwt.SigningMethodRS256, "", keys.rsa, validClaims()) },
+			wantErr: true,
+		},
+		{
+			name:    "encryption key kid",
+			token:   func() string { return mint(t, jwt.SigningMethodRS256, "enc-1", keys.rsa, validClaims()) },
+			wantErr: true,
+		},
+		{
+			name: "alg none",
+			token: func() string {
+				return mint(t, jwt.SigningMethodNone, "", jwt.UnsafeAllowNoneSignatureType, validClaims())
+			},
+			wantErr: true,
+		},
+		{
+			name: "hs256 signed with the rsa public key",
+			token: func() string {
+				return mint(t, jwt.SigningMethodHS256, "rsa-1", publicPEM, validClaims())
+			},
+			wantErr: true,
+		},
+		{
+			name:    "rs256 header with ed25519 kid",
+			token:   func() string { return mint(t, jwt.SigningMethodRS256, "ed-1", keys.rsa, validClaims()) },
+			wantErr: true,
+		},
+		{
+			name:    "hs384 is not accepted",
+			token:   func() string { return mint(t, jwt.SigningMethodHS384, "", []byte(testSecret), validClaims()) },
+			wantErr: true,
+		},
+		{
+			name: "non-uuid sub",
+			token: func() string {
+				c := validClaims()
+				c.Subject = "alice"
+				return mint(t, jwt.SigningMethodHS256, "", []byte(testSecret), c)
+			},
+			wantErr: true,
+		},
+		{
+			name:    "malformed",
+			token:   func() string { return "not.a.token" },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := verifier.Verify(tt.token())
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Fatalf("Verify() error = %v, want ErrInvalidToken", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Verify() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyAlgorithmsOfConfiguredKeys(t *testing.T) {
+	keys := newTestKeys(t)
+	hmacOnly, err := NewVerifier(config.AuthConfig{HMACSecret: testSecret})
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	jwksOnly, err := NewVerifier(config.AuthConfig{JWKSFile: keys.jwksFile})
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		verifier *Verifier
+		token    string
+	}{
+		{
+			name:     "rs256 without jwks",
+			verifier: hmacOnly,
+			token:    mint(t, jwt.SigningMethodRS256, "rsa-1", keys.rsa, validClaims()),
+		},
+		{
+			name:     "hs256 without secret",
+			verifier: jwksOnly,
+			token:    mint(t, jwt.SigningMethodHS256, "", []byte(testSecret), validClaims()),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.verifier.Verify(tt.token); !errors.Is(err, ErrInvalidToken) {
+				t.Fatalf("Verify() error = %v, want ErrInvalidToken", err)
+			}
+		})
+	}
+}
+
+func TestVerifySingleKeyWithoutKid(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate ed25519 key: %v", err)
+	}
+	public := key.Public().(ed25519.PublicKey)
+	data, _ := json.Marshal(jwks{Keys: []jwk{
+		{Kty: "OKP", Kid: "only", Crv: "Ed25519", X: base64.RawURLEncoding.EncodeToString(public)},
+	}})
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write jwks: %v", err)
+	}
+
+	verifier, err := NewVerifier(config.AuthConfig{JWKSFile: path})
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	got, err := verifier.Verify(mint(t, jwt.SigningMethodEdDSA, "", key, validClaims()))
+	if err != nil {
+		t.Fatalf("Verify() unexpected error: %v", err)
+	}
+	if got.UserID != testUserID {
+		t.Errorf("Verify() user = %s, want %s", got.UserID, testUserID)
+	}
+}
+
+func TestNewVerifierRequiresKeys(t *testing.T) {
+	if _, err := NewVerifier(config.AuthConfig{}); err == nil {
+		t.Fatal("NewVerifier() without keys succeeded")
+	}
+	if _, err := NewVerifier(config.AuthConfig{JWKSFile: filepath.Join(t.TempDir(), "missing.json")}); err == nil {
+		t.Fatal("NewVerifier() with a missing jwks file succeeded")
+	}
+}
+
+func mustMarshalPKIX(t *testing.T, key crypto.PublicKey) []byte {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+	return der
+}
@@ -0,0 +1,416 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/monthyear"
+)
+
+// Client calls the subscription aggregator HTTP API
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	token      string
+	tenant     string
+}
+
+type Option func(*Client)
+
+// WithToken sends the bearer token with every request
+func WithToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// WithTenant sends the tenant header with every request
+func WithTenant(tenant string) Option {
+	return func(c *Client) { c.tenant = tenant }
+}
+
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) { c.httpClient = httpClient }
+}
+
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Error is returned for responses with a non-2xx status code.
+// Code and InvalidFields are set from problem+json responses.
+type Error struct {
+	StatusCode    int
+	Code          string
+	Message       string
+	InvalidFields []models.InvalidField
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("%d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+	for i, f := range e.InvalidFields {
+		sep := ", "
+		if i == 0 {
+			sep = ": "
+		}
+		msg += sep + f.Name + " " + f.Reason
+	}
+	return msg
+}
+
+// responseError reads the error of a non-2xx response
+func responseError(resp *http.Response) *Error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	apiErr := &Error{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(body))}
+
+	var p models.Problem
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/problem+json") && json.Unmarshal(body, &p) == nil {
+		apiErr.Code = p.Code
+		apiErr.Message = p.Detail
+		if apiErr.Message == "" {
+			apiErr.Message = p.Title
+		}
+		apiErr.InvalidFields = p.InvalidFields
+	}
+	return apiErr
+}
+
+func (c *Client) CreateSubscription(ctx context.Context, req models.CreateSubscriptionRequest) (models.SubscriptionResponse, error) {
+	var resp models.SubscriptionResponse
+	err := c.do(ctx, http.MethodPost, "/subscriptions", nil, req, &resp)
+	return resp, err
+}
+
+func (c *Client) GetSubscription(ctx context.Context, id uuid.UUID) (models.SubscriptionResponse, error) {
+	var resp models.SubscriptionResponse
+	err := c.do(ctx, http.MethodGet, "/subscriptions/"+id.String(), nil, nil, &resp)
+	return resp, err
+}
+
+func (c *Client) ListSubscriptions(ctx context.Context, req models.ListSubscriptionsRequest) ([]models.SubscriptionResponse, error) {
+	query := url.Values{"limit": {strconv.Itoa(req.Limit)}}
+	if req.Cursor != nil {
+		query.Set("previous_id", req.Cursor.ID.String())
+		query.Set("previous_start_date", formatMonth(req.Cursor.StartDate))
+	}
+	for _, tag := range req.Tags {
+		query.Add("tag", tag)
+	}
+
+	var resp []models.SubscriptionResponse
+	err := c.do(ctx, http.MethodGet, "/subscriptions", query, nil, &resp)
+	return resp, err
+}
+
+// ListAllSubscriptions follows the list cursor until the last page
+func (c *Client) ListAllSubscriptions(ctx context.Context, pageSize int, tags ...string) ([]models.SubscriptionResponse, error) {
+	var all []models.SubscriptionResponse
+	req := models.ListSubscriptionsRequest{Limit: pageSize, Tags: tags}
+	for {
+		page, err := c.ListSubscriptions(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		if len(page) < pageSize {
+			return all, nil
+		}
+		last := page[len(page)-1]
+		req.Cursor = &models.SubscriptionCursor{ID: last.ID, StartDate: *last.StartDate}
+	}
+}
+
+func (c *Client) UpdateSubscription(ctx context.Context, id uuid.UUID, req models.UpdateSubscriptionRequest) (models.SubscriptionResponse, error) {
+	var resp models.SubscriptionResponse
+	err := c.do(ctx, http.MethodPatch, "/subscriptions/"+id.String(), nil, req, &resp)
+	return resp, err
+}
+
+func (c *Client) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, http.MethodDelete, "/subscriptions/"+id.String(), nil, nil, nil)
+}
+
+func (c *Client) GetTotalCost(ctx context.Context, req models.TotalCostRequest) (models.TotalCostResponse, error) {
+	var resp models.TotalCostResponse
+	err := c.do(ctx, http.MethodGet, "/subscriptions/total-cost", costQuery(req), nil, &resp)
+	return resp, err
+}
+
+func (c *Client) GetCostBreakdown(ctx context.Context, req models.CostBreakdownRequest) (models.CostBreakdownResponse, error) {
+	query := costQuery(req.TotalCostRequest)
+	if req.GroupBy != "" {
+		query.Set("group_by", req.GroupBy)
+	}
+
+	var resp models.CostBreakdownResponse
+	err := c.do(ctx, http.MethodGet, "/subscriptions/cost-breakdown", query, nil, &resp)
+	return resp, err
+}
+
+func (c *Client) PauseSubscription(ctx context.Context, id uuid.UUID, req models.PauseSubscriptionRequest) (models.SubscriptionResponse, error) {
+	var resp models.SubscriptionResponse
+	err := c.do(ctx, http.MethodPost, "/subscriptions/"+id.String()+"/pause", nil, req, &resp)
+	return resp, err
+}
+
+func (c *Client) ResumeSubscription(ctx context.Context, id uuid.UUID, req models.ResumeSubscriptionRequest) (models.SubscriptionResponse, error) {
+	var resp models.SubscriptionResponse
+	err := c.do(ctx, http.MethodPost, "/subscriptions/"+id.String()+"/resume", nil, req, &resp)
+	return resp, err
+}
+
+func (c *Client) ChangeSubscriptionPlan(ctx context.Context, id uuid.UUID, req models.ChangePlanRequest) (models.SubscriptionResponse, error) {
+	var resp models.SubscriptionResponse
+	err := c.do(ctx, http.MethodPost, "/subscriptions/"+id.String()+"/plan-changes", nil, req, &resp)
+	return resp, err
+}
+
+func (c *Client) ListPlanChanges(ctx context.Context, id uuid.UUID) ([]models.PlanChangeResponse, error) {
+	var resp []models.PlanChangeResponse
+	err := c.do(ctx, http.MethodGet, "/subscriptions/"+id.String()+"/plan-changes", nil, nil, &resp)
+	return resp, err
+}
+
+// ScheduleChange schedules a cancellation or a price change of a subscription in a future month
+func (c *Client) ScheduleChange(ctx context.Context, id uuid.UUID, req models.ScheduleChangeRequest) (models.ScheduledChangeResponse, error) {
+	var resp models.ScheduledChangeResponse
+	err := c.do(ctx, http.MethodPost, "/subscriptions/"+id.String()+"/scheduled-changes", nil, req, &resp)
+	return resp, err
+}
+
+func (c *Client) ListScheduledChanges(ctx context.Context, id uuid.UUID) ([]models.ScheduledChangeResponse, error) {
+	var resp []models.ScheduledChangeResponse
+	err := c.do(ctx, http.MethodGet, "/subscriptions/"+id.String()+"/scheduled-changes", nil, nil, &resp)
+	return resp, err
+}
+
+func (c *Client) CancelScheduledChange(ctx context.Context, id, changeID uuid.UUID) error {
+	return c.do(ctx, http.MethodDelete, "/subscriptions/"+id.String()+"/scheduled-changes/"+changeID.String(), nil, nil, nil)
+}
+
+// GetMembers returns how the price of a shared subscription is split between its owner and members
+func (c *Client) GetMembers(ctx context.Context, id uuid.UUID) (models.MembersResponse, error) {
+	var resp models.MembersResponse
+	err := c.do(ctx, http.MethodGet, "/subscriptions/"+id.String()+"/members", nil, nil, &resp)
+	return resp, err
+}
+
+// SetMembers replaces the split rule and the members of a subscription
+func (c *Client) SetMembers(ctx context.Context, id uuid.UUID, req models.SetMembersRequest) (models.MembersResponse, error) {
+	var resp models.MembersResponse
+	err := c.do(ctx, http.MethodPut, "/subscriptions/"+id.String()+"/members", nil, req, &resp)
+	return resp, err
+}
+
+// ListTrialsEnding lists subscriptions billed at the regular price for the first time in req.Month
+func (c *Client) ListTrialsEnding(ctx context.Context, req models.TrialEndingRequest) ([]models.SubscriptionResponse, error) {
+	query := url.Values{}
+	if req.Month != nil {
+		query.Set("month", formatMonth(*req.Month))
+	}
+	if req.UserID != nil {
+		query.Set("user_id", req.UserID.String())
+	}
+
+	var resp []models.SubscriptionResponse
+	err := c.do(ctx, http.MethodGet, "/subscriptions/trial-ending", query, nil, &resp)
+	return resp, err
+}
+
+// ListUpcomingCharges lists charges from today for req.Days days, a week if req.Days is 0
+func (c *Client) ListUpcomingCharges(ctx context.Context, req models.UpcomingChargesRequest) ([]models.ChargeResponse, error) {
+	query := url.Values{}
+	if req.Days != 0 {
+		query.Set("days", strconv.Itoa(req.Days))
+	}
+	if req.UserID != nil {
+		query.Set("user_id", req.UserID.String())
+	}
+
+	var resp []models.ChargeResponse
+	err := c.do(ctx, http.MethodGet, "/subscriptions/upcoming-charges", query, nil, &resp)
+	return resp, err
+}
+
+// FindDuplicates returns groups of subscriptions billed in req.Month that likely duplicate each other
+func (c *Client) FindDuplicates(ctx context.Context, req models.DuplicatesRequest) (models.DuplicatesResponse, error) {
+	query := url.Values{}
+	if req.Month != nil {
+		query.Set("month", formatMonth(*req.Month))
+	}
+	if req.UserID != nil {
+		query.Set("user_id", req.UserID.String())
+	}
+
+	var resp models.DuplicatesResponse
+	err := c.do(ctx, http.MethodGet, "/reports/duplicates", query, nil, &resp)
+	return resp, err
+}
+
+// GetNotificationPreferences returns when and how a user is reminded of renewals and endings of subscriptions
+func (c *Client) GetNotificationPreferences(ctx context.Context, userID uuid.UUID) (models.NotificationPreferencesResponse, error) {
+	var resp models.NotificationPreferencesResponse
+	err := c.do(ctx, http.MethodGet, "/users/"+userID.String()+"/notification-preferences", nil, nil, &resp)
+	return resp, err
+}
+
+// SetNotificationPreferences replaces the reminder preferences of a user
+func (c *Client) SetNotificationPreferences(ctx context.Context, userID uuid.UUID, req models.NotificationPreferencesRequest) (models.NotificationPreferencesResponse, error) {
+	var resp models.NotificationPreferencesResponse
+	err := c.do(ctx, http.MethodPut, "/users/"+userID.String()+"/notification-preferences", nil, req, &resp)
+	return resp, err
+}
+
+// CreatePaymentMethod adds a card or a bank account of a user
+func (c *Client) CreatePaymentMethod(ctx context.Context, userID uuid.UUID, req models.PaymentMethodRequest) (models.PaymentMethodResponse, error) {
+	var resp models.PaymentMethodResponse
+	err := c.do(ctx, http.MethodPost, "/users/"+userID.String()+"/payment-methods", nil, req, &resp)
+	return resp, err
+}
+
+func (c *Client) ListPaymentMethods(ctx context.Context, userID uuid.UUID) ([]models.PaymentMethodResponse, error) {
+	var resp []models.PaymentMethodResponse
+	err := c.do(ctx, http.MethodGet, "/users/"+userID.String()+"/payment-methods", nil, nil, &resp)
+	return resp, err
+}
+
+// UpdatePaymentMethod replaces a payment method, linked subscriptions stay linked
+func (c *Client) UpdatePaymentMethod(ctx context.Context, userID, id uuid.UUID, req models.PaymentMethodRequest) (models.PaymentMethodResponse, error) {
+	var resp models.PaymentMethodResponse
+	err := c.do(ctx, http.MethodPut, "/users/"+userID.String()+"/payment-methods/"+id.String(), nil, req, &resp)
+	return resp, err
+}
+
+// DeletePaymentMethod deletes a payment method moving its subscriptions to req.ReplacementID, if set
+func (c *Client) DeletePaymentMethod(ctx context.Context, userID, id uuid.UUID, req models.DeletePaymentMethodRequest) error {
+	query := url.Values{}
+	if req.ReplacementID != nil {
+		query.Set("replacement_id", req.ReplacementID.String())
+	}
+	return c.do(ctx, http.MethodDelete, "/users/"+userID.String()+"/payment-methods/"+id.String(), query, nil, nil)
+}
+
+// ListExpiringCards lists subscriptions whose next charge comes after their card expires
+func (c *Client) ListExpiringCards(ctx context.Context, req models.ExpiringCardsRequest) ([]models.ExpiringCardResponse, error) {
+	query := url.Values{}
+	if req.UserID != nil {
+		query.Set("user_id", req.UserID.String())
+	}
+
+	var resp []models.ExpiringCardResponse
+	err := c.do(ctx, http.MethodGet, "/reports/expiring-cards", query, nil, &resp)
+	return resp, err
+}
+
+// ListServices lists catalog services whose name or alias contains q, all of them for an empty q
+func (c *Client) ListServices(ctx context.Context, q string) ([]models.ServiceResponse, error) {
+	query := url.Values{}
+	if q != "" {
+		query.Set("q", q)
+	}
+
+	var resp []models.ServiceResponse
+	err := c.do(ctx, http.MethodGet, "/services", query, nil, &resp)
+	return resp, err
+}
+
+func (c *Client) CreateService(ctx context.Context, req models.ServiceRequest) (models.ServiceResponse, error) {
+	var resp models.ServiceResponse
+	err := c.do(ctx, http.MethodPost, "/services", nil, req, &resp)
+	return resp, err
+}
+
+func costQuery(req models.TotalCostRequest) url.Values {
+	query := url.Values{}
+	if req.UserID != nil {
+		query.Set("user_id", req.UserID.String())
+	}
+	if req.ServiceName != nil {
+		query.Set("service_name", *req.ServiceName)
+	}
+	if req.ServiceID != nil {
+		query.Set("service_id", req.ServiceID.String())
+	}
+	if req.StartDate != nil {
+		query.Set("start_date", formatMonth(*req.StartDate))
+	}
+	if req.EndDate != nil {
+		query.Set("end_date", formatMonth(*req.EndDate))
+	}
+	for _, tag := range req.Tags {
+		query.Add("tag", tag)
+	}
+	if req.Category != nil {
+		query.Set("category", *req.Category)
+	}
+	return query
+}
+
+func formatMonth(my monthyear.MonthYear) string {
+	return time.Time(my).Format(monthyear.DateLayout)
+}
+
+// do sends a request with an optional JSON body and decodes a JSON response into out unless it is nil
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json, application/problem+json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.tenant != "" {
+		req.Header.Set("X-Tenant-ID", c.tenant)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return responseError(resp)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s %s response: %w", method, path, err)
+	}
+	return nil
+}
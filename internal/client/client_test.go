@@ -0,0 +1,187 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/monthyear"
+)
+
+func TestResponseError(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        Error
+		wantMessage string
+	}{
+		{
+			name:        "problem with invalid fields",
+			contentType: "application/problem+json",
+			body: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"request validation failed","code":"validation_failed",
+				"invalid_fields":[{"name":"price","rule":"min","reason":"must be at least 0"},{"name":"start_date","rule":"required","reason":"is required"}]}`,
+			want: Error{StatusCode: 400, Code: "validation_failed", Message: "request validation failed", InvalidFields: []models.InvalidField{
+				{Name: "price", Rule: "min", Reason: "must be at least 0"}, {Name: "start_date", Rule: "required", Reason: "is required"}}},
+			wantMessage: "400 Bad Request: request validation failed: price must be at least 0, start_date is required",
+		},
+		{
+			name:        "problem without detail",
+			contentType: "application/problem+json; charset=utf-8",
+			body:        `{"title":"Conflict","status":409,"code":"already_exists"}`,
+			want:        Error{StatusCode: 409, Code: "already_exists", Message: "Conflict"},
+			wantMessage: "409 Conflict: Conflict",
+		},
+		{
+			name:        "plain text",
+			contentType: "text/plain; charset=utf-8",
+			body:        "upstream unavailable\n",
+			want:        Error{StatusCode: 502, Message: "upstream unavailable"},
+			wantMessage: "502 Bad Gateway: upstream unavailable",
+		},
+		{
+			name:        "problem body without the problem content type",
+			contentType: "application/json",
+			body:        `{"code":"internal_error"}`,
+			want:        Error{StatusCode: 500, Message: `{"code":"internal_error"}`},
+			wantMessage: `500 Internal Server Error: {"code":"internal_error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.WriteHeader(tt.want.StatusCode)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			t.Cleanup(server.Close)
+
+			_, err := New(server.URL).GetSubscription(context.Background(), uuid.New())
+			var apiErr *Error
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("GetSubscription() error = %v, want *Error", err)
+			}
+			if apiErr.StatusCode != tt.want.StatusCode || apiErr.Code != tt.want.Code || apiErr.Message != tt.want.Message ||
+				!slices.Equal(apiErr.InvalidFields, tt.want.InvalidFields) {
+				t.Errorf("error = %+v, want %+v", *apiErr, tt.want)
+			}
+			if apiErr.Error() != tt.wantMessage {
+				t.Errorf("Error() = %q, want %q", apiErr.Error(), tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestRequestHeaders(t *testing.T) {
+	var got *http.Request
+	var body models.CreateSubscriptionRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"service_name":"Netflix"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	c := New(server.URL+"/", WithToken("secret"), WithTenant("acme"))
+	price := 799
+	sub, err := c.CreateSubscription(context.Background(), models.CreateSubscriptionRequest{ServiceName: "Netflix", Price: &price})
+	if err != nil {
+		t.Fatalf("CreateSubscription() error = %v", err)
+	}
+	if sub.ServiceName != "Netflix" {
+		t.Errorf("response = %+v", sub)
+	}
+
+	if got.Method != http.MethodPost || got.URL.Path != "/subscriptions" {
+		t.Errorf("request %s %s, want POST /subscriptions", got.Method, got.URL.Path)
+	}
+	for header, want := range map[string]string{
+		"Authorization": "Bearer secret",
+		"X-Tenant-ID":   "acme",
+		"Content-Type":  "application/json",
+		"Accept":        "application/json, application/problem+json",
+	} {
+		if got.Header.Get(header) != want {
+			t.Errorf("%s = %q, want %q", header, got.Header.Get(header), want)
+		}
+	}
+	if body.ServiceName != "Netflix" || body.Price == nil || *body.Price != 799 {
+		t.Errorf("request body = %+v", body)
+	}
+}
+
+func TestRequestWithoutTokenAndTenant(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+
+	if _, err := New(server.URL).GetSubscription(context.Background(), uuid.New()); err != nil {
+		t.Fatalf("GetSubscription() error = %v", err)
+	}
+	if _, ok := got["Authorization"]; ok {
+		t.Error("Authorization sent without a token")
+	}
+	if _, ok := got["X-Tenant-Id"]; ok {
+		t.Error("X-Tenant-ID sent without a tenant")
+	}
+}
+
+func TestListAllSubscriptions(t *testing.T) {
+	start := monthyear.MonthYear(time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC))
+
+	for _, total := range []int{0, 3, 4, 5} {
+		t.Run(strconv.Itoa(total), func(t *testing.T) {
+			subs := make([]models.SubscriptionResponse, total)
+			for i := range subs {
+				subs[i] = models.SubscriptionResponse{ID: uuid.New(), StartDate: &start}
+			}
+
+			var queries []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				query := r.URL.Query()
+				queries = append(queries, r.URL.RawQuery)
+				limit, _ := strconv.Atoi(query.Get("limit"))
+				from := 0
+				if previous := query.Get("previous_id"); previous != "" {
+					if query.Get("previous_start_date") != "03-2025" {
+						http.Error(w, "previous_start_date "+query.Get("previous_start_date"), http.StatusBadRequest)
+						return
+					}
+					from = slices.IndexFunc(subs, func(sub models.SubscriptionResponse) bool { return sub.ID.String() == previous }) + 1
+				}
+				if !slices.Equal(query["tag"], []string{"work", "video"}) {
+					http.Error(w, "tags "+r.URL.RawQuery, http.StatusBadRequest)
+					return
+				}
+				_ = json.NewEncoder(w).Encode(subs[from:min(from+limit, len(subs))])
+			}))
+			t.Cleanup(server.Close)
+
+			got, err := New(server.URL).ListAllSubscriptions(context.Background(), 2, "work", "video")
+			if err != nil {
+				t.Fatalf("ListAllSubscriptions() error = %v", err)
+			}
+			if !slices.EqualFunc(got, subs, func(a, b models.SubscriptionResponse) bool { return a.ID == b.ID }) {
+				t.Errorf("ListAllSubscriptions() = %d subscriptions, want %d", len(got), len(subs))
+			}
+			// A full last page needs one more request to see that nothing follows
+			if want := total/2 + 1; len(queries) != want {
+				t.Errorf("requests = %d, want %d: %v", len(queries), want, queries)
+			}
+		})
+	}
+}
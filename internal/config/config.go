@@ -8,14 +8,38 @@ import (
 )
 
 type Config struct {
-	App AppConfig
-	DB  DBConfig
+	App       AppConfig
+	DB        DBConfig
+	Auth      AuthConfig
+	Tracing   TracingConfig
+	GRPC      GRPCConfig
+	GraphQL   GraphQLConfig
+	Reminders ReminderConfig
+	SMTP      SMTPConfig
+	Scheduler SchedulerConfig
 }
 
 type AppConfig struct {
 	Address         string        `env:"APP_ADDRESS" envDefault:"0.0.0.0:8080"`
 	LogLevel        slog.Level    `env:"APP_LOG_LEVEL" envDefault:"INFO"`
 	ShutdownTimeout time.Duration `env:"APP_SHUTDOWN_TIMEOUT" envDefault:"10s"`
+	// DrainDelay is how long /readyz fails before the server stops accepting connections
+	DrainDelay    time.Duration `env:"APP_DRAIN_DELAY" envDefault:"5s"`
+	TenantHeader  string        `env:"APP_TENANT_HEADER" envDefault:"X-Tenant-ID"`
+	DefaultTenant string        `env:"APP_DEFAULT_TENANT" envDefault:"default"`
+	// MetricsAddress is the internal listener serving /metrics, kept off the public API port; empty disables it
+	MetricsAddress string `env:"APP_METRICS_ADDRESS" envDefault:"0.0.0.0:9100"`
+
+	RateLimit          RateLimit       `env:"APP_RATE_LIMIT" envDefault:"120/1m"`
+	RateLimitRoutes    RouteRateLimits `env:"APP_RATE_LIMIT_ROUTES" envDefault:"GET /subscriptions/total-cost=10/1m;GET /subscriptions/cost-breakdown=10/1m"`
+	RateLimitKeyHeader string          `env:"APP_RATE_LIMIT_KEY_HEADER" envDefault:"X-API-Key"`
+	// RateLimitTrustedProxies is the number of proxies in front of the service appending to X-Forwarded-For,
+	// 0 identifies clients by the connection address
+	RateLimitTrustedProxies int `env:"APP_RATE_LIMIT_TRUSTED_PROXIES" envDefault:"0"`
+	// RateLimitAPIKeys are the API keys accepted in RateLimitKeyHeader, other keys are ignored
+	RateLimitAPIKeys []string `env:"APP_RATE_LIMIT_API_KEYS" envSeparator:","`
+	// RateLimitMaxClients caps the number of clients tracked per route, least recently seen clients are dropped first
+	RateLimitMaxClients int `env:"APP_RATE_LIMIT_MAX_CLIENTS" envDefault:"10000"`
 }
 
 type DBConfig struct {
@@ -24,6 +48,74 @@ type DBConfig struct {
 	User string `env:"DB_USER"`
 	Pass string `env:"DB_PASSWORD"`
 	Name string `env:"DB_NAME"`
+	// AutoMigrate applies pending migrations on startup, disable it to run "server migrate up" separately
+	AutoMigrate bool `env:"DB_AUTO_MIGRATE" envDefault:"true"`
+}
+
+// AuthConfig configures JWT bearer authentication.
+// Authentication is disabled when neither JWKSFile nor HMACSecret is set.
+type AuthConfig struct {
+	JWKSFile   string `env:"AUTH_JWKS_FILE"`
+	HMACSecret string `env:"AUTH_HS256_SECRET"`
+	Issuer     string `env:"AUTH_ISSUER"`
+	Audience   string `env:"AUTH_AUDIENCE"`
+	AdminRole  string `env:"AUTH_ADMIN_ROLE" envDefault:"admin"`
+}
+
+// GRPCConfig configures the gRPC API, it is disabled when Address is empty
+type GRPCConfig struct {
+	Address    string `env:"GRPC_ADDRESS" envDefault:"0.0.0.0:9090"`
+	Reflection bool   `env:"GRPC_REFLECTION" envDefault:"true"`
+}
+
+// GraphQLConfig limits GraphQL queries. Complexity counts requested fields, multiplied by the expected size of lists.
+type GraphQLConfig struct {
+	MaxDepth      int `env:"GRAPHQL_MAX_DEPTH" envDefault:"8"`
+	MaxComplexity int `env:"GRAPHQL_MAX_COMPLEXITY" envDefault:"1000"`
+	// MaxIntrospectionDepth limits __schema and __type selections, which are not counted in MaxDepth and MaxComplexity
+	MaxIntrospectionDepth int `env:"GRAPHQL_MAX_INTROSPECTION_DEPTH" envDefault:"15"`
+	// ListSize is the expected size of lists without a "first" argument, e.g. subscriptions of a user
+	ListSize int `env:"GRAPHQL_LIST_SIZE" envDefault:"10"`
+	// BatchWait is how long loaders collect keys before querying the repository
+	BatchWait time.Duration `env:"GRAPHQL_BATCH_WAIT" envDefault:"5ms"`
+}
+
+// ReminderConfig configures reminders of upcoming renewals and endings of subscriptions
+type ReminderConfig struct {
+	Enabled bool `env:"REMINDER_ENABLED" envDefault:"true"`
+	// Interval is how often due reminders are checked, reminders are sent on the first check of their day
+	Interval time.Duration `env:"REMINDER_INTERVAL" envDefault:"1h"`
+	// WebhookSecret signs webhook bodies with HMAC-SHA256 in the X-Signature-256 header when set
+	WebhookSecret  string        `env:"REMINDER_WEBHOOK_SECRET"`
+	WebhookTimeout time.Duration `env:"REMINDER_WEBHOOK_TIMEOUT" envDefault:"10s"`
+}
+
+// SMTPConfig configures the email channel of reminders, it is disabled when Host is empty.
+// STARTTLS is used when the server offers it, authentication when User is set.
+type SMTPConfig struct {
+	Host     string        `env:"SMTP_HOST"`
+	Port     int           `env:"SMTP_PORT" envDefault:"25"`
+	User     string        `env:"SMTP_USER"`
+	Password string        `env:"SMTP_PASSWORD"`
+	From     string        `env:"SMTP_FROM" envDefault:"reminders@localhost"`
+	Timeout  time.Duration `env:"SMTP_TIMEOUT" envDefault:"10s"`
+}
+
+// SchedulerConfig configures applying scheduled changes of subscriptions
+type SchedulerConfig struct {
+	Enabled bool `env:"SCHEDULER_ENABLED" envDefault:"true"`
+	// Interval is how often due changes are checked, changes are applied on the first check of their month
+	Interval time.Duration `env:"SCHEDULER_INTERVAL" envDefault:"1h"`
+}
+
+// TracingConfig configures the OpenTelemetry span exporter: none, otlp (HTTP), stdout or file
+type TracingConfig struct {
+	Exporter     string  `env:"TRACING_EXPORTER" envDefault:"none"`
+	OTLPEndpoint string  `env:"TRACING_OTLP_ENDPOINT" envDefault:"localhost:4318"`
+	OTLPInsecure bool    `env:"TRACING_OTLP_INSECURE" envDefault:"true"`
+	File         string  `env:"TRACING_FILE" envDefault:"traces.json"`
+	SampleRatio  float64 `env:"TRACING_SAMPLE_RATIO" envDefault:"1"`
+	ServiceName  string  `env:"TRACING_SERVICE_NAME" envDefault:"subscription-aggregator"`
 }
 
 func Load() (Config, error) {
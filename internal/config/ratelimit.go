@@ -0,0 +1,63 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimit allows Requests requests per Period, written as "100/1m". Zero Requests disables the limit.
+type RateLimit struct {
+	Requests int
+	Period   time.Duration
+}
+
+func (rl *RateLimit) UnmarshalText(text []byte) error {
+	s := strings.TrimSpace(string(text))
+	if s == "" || s == "0" || s == "off" {
+		*rl = RateLimit{}
+		return nil
+	}
+
+	rawRequests, rawPeriod, found := strings.Cut(s, "/")
+	if !found {
+		return fmt.Errorf("invalid rate limit %q, expected <requests>/<period>", s)
+	}
+	requests, err := strconv.Atoi(rawRequests)
+	if err != nil || requests < 0 {
+		return fmt.Errorf("invalid rate limit requests %q", rawRequests)
+	}
+	period, err := time.ParseDuration(rawPeriod)
+	if err != nil || period <= 0 {
+		return fmt.Errorf("invalid rate limit period %q", rawPeriod)
+	}
+
+	*rl = RateLimit{Requests: requests, Period: period}
+	return nil
+}
+
+// RouteRateLimits overrides the default rate limit per route pattern,
+// written as "GET /subscriptions/total-cost=10/1m;GET /subscriptions/{id}=300/1m".
+type RouteRateLimits map[string]RateLimit
+
+func (rl *RouteRateLimits) UnmarshalText(text []byte) error {
+	limits := RouteRateLimits{}
+	for _, entry := range strings.Split(string(text), ";") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		route, rawLimit, found := strings.Cut(entry, "=")
+		if !found {
+			return fmt.Errorf("invalid route rate limit %q, expected <route>=<requests>/<period>", entry)
+		}
+		var limit RateLimit
+		if err := limit.UnmarshalText([]byte(rawLimit)); err != nil {
+			return err
+		}
+		limits[strings.TrimSpace(route)] = limit
+	}
+
+	*rl = limits
+	return nil
+}
@@ -0,0 +1,72 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// checkTimeout bounds a single dependency check
+const checkTimeout = 2 * time.Second
+
+var ErrDraining = errors.New("server is shutting down")
+
+// Check reports whether a dependency is usable
+type Check func(ctx context.Context) error
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Result is the outcome of a single readiness check, Err is nil when it passed
+type Result struct {
+	Name string
+	Err  error
+}
+
+// Checker tracks readiness of the service: its dependencies and whether it is draining
+type Checker struct {
+	checks   []namedCheck
+	draining atomic.Bool
+}
+
+func NewChecker() *Checker {
+	return &Checker{}
+}
+
+// Add registers a readiness check. Not safe for use once the server is running.
+func (c *Checker) Add(name string, check Check) {
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+// SetDraining marks the service as shutting down, which fails readiness
+func (c *Checker) SetDraining() {
+	c.draining.Store(true)
+}
+
+// Ready runs all checks and reports whether every one passed
+func (c *Checker) Ready(ctx context.Context) (bool, []Result) {
+	ready := true
+	results := make([]Result, 0, len(c.checks)+1)
+
+	var drainErr error
+	if c.draining.Load() {
+		drainErr = ErrDraining
+		ready = false
+	}
+	results = append(results, Result{Name: "shutdown", Err: drainErr})
+
+	for _, nc := range c.checks {
+		checkCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+		err := nc.check(checkCtx)
+		cancel()
+		if err != nil {
+			ready = false
+		}
+		results = append(results, Result{Name: nc.name, Err: err})
+	}
+
+	return ready, results
+}
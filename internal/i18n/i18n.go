@@ -0,0 +1,61 @@
+// Package i18n picks the language of API messages from the Accept-Language header.
+// English and Russian are supported, English is the fallback.
+package i18n
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/ru"
+	ut "github.com/go-playground/universal-translator"
+	"golang.org/x/text/language"
+)
+
+var (
+	uni     = ut.New(en.New(), en.New(), ru.New())
+	matcher = language.NewMatcher([]language.Tag{language.English, language.Russian})
+)
+
+func init() {
+	for locale, catalog := range messages {
+		trans, _ := uni.GetTranslator(locale)
+		for key, text := range catalog {
+			if err := trans.Add(key, text, false); err != nil {
+				panic(fmt.Sprintf("invalid %s message %q: %v", locale, key, err))
+			}
+		}
+	}
+}
+
+// Translator returns the translator of the best supported language of an Accept-Language header value
+func Translator(acceptLanguage string) ut.Translator {
+	tags, _, _ := language.ParseAcceptLanguage(acceptLanguage)
+	tag, _, _ := matcher.Match(tags...)
+	base, _ := tag.Base()
+	trans, _ := uni.FindTranslator(base.String())
+	return trans
+}
+
+// FromRequest returns the translator for the Accept-Language header of r
+func FromRequest(r *http.Request) ut.Translator {
+	return Translator(r.Header.Get("Accept-Language"))
+}
+
+// Translators returns the translators of all supported languages by locale
+func Translators() map[string]ut.Translator {
+	translators := make(map[string]ut.Translator, len(messages))
+	for locale := range messages {
+		translators[locale], _ = uni.GetTranslator(locale)
+	}
+	return translators
+}
+
+// T translates a message, params replace {0}, {1}... Unknown keys are returned as is.
+func T(trans ut.Translator, key string, params ...string) string {
+	msg, err := trans.T(key, params...)
+	if err != nil {
+		return key
+	}
+	return msg
+}
@@ -0,0 +1,77 @@
+package i18n
+
+// messages are API messages by locale, keys of problem details match their error codes
+var messages = map[string]map[string]string{
+	"en": {
+		"invalid_json":           "invalid JSON format",
+		"invalid_parameter":      "invalid parameter {0}",
+		"validation_failed":      "request validation failed",
+		"invalid_date_range":     "end date cannot be before start date",
+		"not_found":              "subscription not found",
+		"already_exists":         "subscription of the same service and account overlaps in time",
+		"already_paused":         "subscription is already paused in this period",
+		"not_paused":             "subscription is not paused in this month",
+		"invalid_pause":          "pause must start within the subscription period",
+		"unknown_service":        "no service with this ID in the catalog",
+		"unknown_plan":           "no plan with this ID for the subscription's service",
+		"unknown_payment_method": "the subscription's user has no payment method with this ID",
+		"invalid_plan_change":    "plan change must start after the first month of the subscription and not after its end",
+		"invalid_split":          "members must not include the owner and percent shares must not exceed 100 in total",
+		"invalid_schedule":       "cancellation must be at the end of the current or a later month before the subscription end, price change must start in a future month after the first month of the subscription and not after its end",
+		"change_not_found":       "pending scheduled change not found",
+		"method_not_found":       "payment method not found",
+		"service_not_found":      "service not found",
+		"service_already_exists": "service with this name or alias already exists",
+		"admin_required":         "only admins can manage the service catalog",
+		"forbidden":              "access to another user's subscriptions is forbidden",
+		"missing_token":          "missing bearer token",
+		"invalid_token":          "invalid token",
+		"tenant_mismatch":        "tenant does not match token",
+		"tenant_required":        "{0} header required",
+		"tenant_claim_required":  "token without a tenant_id claim can only access the default tenant",
+		"invalid_tenant":         "invalid tenant ID",
+		"rate_limited":           "rate limit exceeded, retry later",
+		"internal_error":         "internal error",
+
+		"param_uuid":    "must be a UUID",
+		"param_month":   "must be a month in the MM-YYYY format",
+		"param_integer": "must be an integer",
+		"field_type":    "expected {0}, got {1}",
+	},
+	"ru": {
+		"invalid_json":           "некорректный JSON",
+		"invalid_parameter":      "некорректный параметр {0}",
+		"validation_failed":      "запрос не прошёл проверку",
+		"invalid_date_range":     "дата окончания не может быть раньше даты начала",
+		"not_found":              "подписка не найдена",
+		"already_exists":         "подписка на тот же сервис и учётную запись пересекается по датам",
+		"already_paused":         "подписка уже приостановлена в этом периоде",
+		"not_paused":             "подписка не приостановлена в этом месяце",
+		"invalid_pause":          "пауза должна начинаться в период действия подписки",
+		"unknown_service":        "в каталоге нет сервиса с таким ID",
+		"unknown_plan":           "у сервиса подписки нет тарифа с таким ID",
+		"unknown_payment_method": "у пользователя подписки нет способа оплаты с таким ID",
+		"invalid_plan_change":    "смена тарифа должна начинаться после первого месяца подписки и не позже её окончания",
+		"invalid_split":          "владелец не может быть участником, а доли в процентах в сумме не могут превышать 100",
+		"invalid_schedule":       "отмена возможна в конце текущего или более позднего месяца до окончания подписки, смена цены — с будущего месяца после первого месяца подписки и не позже её окончания",
+		"change_not_found":       "ожидающее запланированное изменение не найдено",
+		"method_not_found":       "способ оплаты не найден",
+		"service_not_found":      "сервис не найден",
+		"service_already_exists": "сервис с таким названием или псевдонимом уже существует",
+		"admin_required":         "управлять каталогом сервисов могут только администраторы",
+		"forbidden":              "доступ к подпискам другого пользователя запрещён",
+		"missing_token":          "не передан bearer-токен",
+		"invalid_token":          "недействительный токен",
+		"tenant_mismatch":        "тенант не совпадает с тенантом токена",
+		"tenant_required":        "требуется заголовок {0}",
+		"tenant_claim_required":  "токен без claim tenant_id даёт доступ только к тенанту по умолчанию",
+		"invalid_tenant":         "некорректный ID тенанта",
+		"rate_limited":           "превышен лимит запросов, повторите позже",
+		"internal_error":         "внутренняя ошибка",
+
+		"param_uuid":    "должен быть UUID",
+		"param_month":   "должен быть месяцем в формате ММ-ГГГГ",
+		"param_integer": "должен быть целым числом",
+		"field_type":    "ожидается {0}, получено {1}",
+	},
+}
@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/repository"
+)
+
+// statsTimeout bounds the database query made on each scrape
+const statsTimeout = 5 * time.Second
+
+// RegisterPool exports connection pool statistics read from stat on each scrape
+func (m *Metrics) RegisterPool(stat func() *pgxpool.Stat) {
+	m.registry.MustRegister(&poolCollector{stat: stat})
+}
+
+var (
+	poolAcquiredConns = prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", "acquired_connections"),
+		"Connections currently in use.", nil, nil)
+	poolIdleConns = prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", "idle_connections"),
+		"Idle connections in the pool.", nil, nil)
+	poolTotalConns = prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", "total_connections"),
+		"Total connections in the pool.", nil, nil)
+	poolMaxConns = prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", "max_connections"),
+		"Maximum size of the pool.", nil, nil)
+	poolAcquires = prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", "acquires_total"),
+		"Number of successful connection acquires.", nil, nil)
+	poolEmptyAcquires = prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", "empty_acquires_total"),
+		"Number of acquires that had to wait for a connection.", nil, nil)
+	poolAcquireDuration = prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", "acquire_duration_seconds_total"),
+		"Total time spent acquiring connections.", nil, nil)
+	poolWaitDuration = prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", "empty_acquire_wait_seconds_total"),
+		"Total time spent waiting for a connection when the pool was empty.", nil, nil)
+)
+
+type poolCollector struct {
+	stat func() *pgxpool.Stat
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- poolAcquiredConns
+	ch <- poolIdleConns
+	ch <- poolTotalConns
+	ch <- poolMaxConns
+	ch <- poolAcquires
+	ch <- poolEmptyAcquires
+	ch <- poolAcquireDuration
+	ch <- poolWaitDuration
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.stat()
+	ch <- prometheus.MustNewConstMetric(poolAcquiredConns, prometheus.GaugeValue, float64(s.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(poolIdleConns, prometheus.GaugeValue, float64(s.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(poolTotalConns, prometheus.GaugeValue, float64(s.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(poolMaxConns, prometheus.GaugeValue, float64(s.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(poolAcquires, prometheus.CounterValue, float64(s.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolEmptyAcquires, prometheus.CounterValue, float64(s.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolAcquireDuration, prometheus.CounterValue, s.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(poolWaitDuration, prometheus.CounterValue, s.EmptyAcquireWaitTime().Seconds())
+}
+
+// StatsSource provides subscription figures aggregated over all tenants
+type StatsSource interface {
+	SubscriptionStats(ctx context.Context) (repository.SubscriptionStats, error)
+}
+
+// RegisterSubscriptionStats exports domain gauges queried from src on each scrape.
+// They are not labelled by tenant, so a scrape does not reveal the figures of individual tenants.
+func (m *Metrics) RegisterSubscriptionStats(src StatsSource) {
+	m.registry.MustRegister(&statsCollector{src: src})
+}
+
+var (
+	activeSubscriptions = prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "active_subscriptions"),
+		"Subscriptions active in the current month across all tenants.", nil, nil)
+	monthlyRecurringSpend = prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "monthly_recurring_spend_rubles"),
+		"Sum of monthly prices of active subscriptions across all tenants.", nil, nil)
+)
+
+type statsCollector struct {
+	src StatsSource
+}
+
+func (c *statsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- activeSubscriptions
+	ch <- monthlyRecurringSpend
+}
+
+func (c *statsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), statsTimeout)
+	defer cancel()
+
+	stats, err := c.src.SubscriptionStats(ctx)
+	if err != nil {
+		slog.Error("failed to collect subscription stats", "error", err)
+		ch <- prometheus.NewInvalidMetric(activeSubscriptions, err)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(activeSubscriptions, prometheus.GaugeValue, float64(stats.Active))
+	ch <- prometheus.MustNewConstMetric(monthlyRecurringSpend, prometheus.GaugeValue, float64(stats.MonthlySpend))
+}
@@ -0,0 +1,277 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/repository"
+)
+
+var _ repository.SubscriptionRepository = (*instrumentedRepository)(nil)
+
+// instrumentedRepository records call latency of the wrapped repository
+type instrumentedRepository struct {
+	next    repository.SubscriptionRepository
+	metrics *Metrics
+}
+
+// Repository decorates repo with per-method latency metrics
+func (m *Metrics) Repository(repo repository.SubscriptionRepository) repository.SubscriptionRepository {
+	return &instrumentedRepository{next: repo, metrics: m}
+}
+
+func (r *instrumentedRepository) observe(method string, start time.Time, err error) {
+	outcome := "success"
+	switch {
+	case err == nil:
+	case errors.Is(err, repository.ErrSubscriptionNotFound), errors.Is(err, repository.ErrServiceNotFound),
+		errors.Is(err, repository.ErrPlanNotFound):
+		outcome = "not_found"
+	case errors.Is(err, repository.ErrSubscriptionAlreadyExists), errors.Is(err, repository.ErrServiceAlreadyExists):
+		outcome = "conflict"
+	default:
+		outcome = "error"
+	}
+	r.metrics.repoDuration.WithLabelValues(method, outcome).Observe(time.Since(start).Seconds())
+}
+
+func (r *instrumentedRepository) CreateSubscription(ctx context.Context, sub repository.Subscription) (uuid.UUID, error) {
+	start := time.Now()
+	id, err := r.next.CreateSubscription(ctx, sub)
+	r.observe("CreateSubscription", start, err)
+	return id, err
+}
+
+func (r *instrumentedRepository) GetSubscriptionByID(ctx context.Context, id uuid.UUID) (repository.Subscription, error) {
+	start := time.Now()
+	sub, err := r.next.GetSubscriptionByID(ctx, id)
+	r.observe("GetSubscriptionByID", start, err)
+	return sub, err
+}
+
+func (r *instrumentedRepository) ListSubscriptions(ctx context.Context, pagination repository.SubscriptionPagination) ([]repository.Subscription, error) {
+	start := time.Now()
+	subs, err := r.next.ListSubscriptions(ctx, pagination)
+	r.observe("ListSubscriptions", start, err)
+	return subs, err
+}
+
+func (r *instrumentedRepository) UpdateSubscription(ctx context.Context, id uuid.UUID, fields repository.SubscriptionUpdate) (repository.Subscription, error) {
+	start := time.Now()
+	sub, err := r.next.UpdateSubscription(ctx, id, fields)
+	r.observe("UpdateSubscription", start, err)
+	return sub, err
+}
+
+func (r *instrumentedRepository) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	err := r.next.DeleteSubscription(ctx, id)
+	r.observe("DeleteSubscription", start, err)
+	return err
+}
+
+func (r *instrumentedRepository) GetTotalCostWithFilters(ctx context.Context, filter repository.SubscriptionFilter) (int, error) {
+	start := time.Now()
+	total, err := r.next.GetTotalCostWithFilters(ctx, filter)
+	r.observe("GetTotalCostWithFilters", start, err)
+	return total, err
+}
+
+func (r *instrumentedRepository) GetCostBreakdown(ctx context.Context, filter repository.SubscriptionFilter, group repository.CostGroup) ([]repository.CostBreakdownRow, error) {
+	start := time.Now()
+	rows, err := r.next.GetCostBreakdown(ctx, filter, group)
+	r.observe("GetCostBreakdown", start, err)
+	return rows, err
+}
+
+func (r *instrumentedRepository) ListSubscriptionsByUserIDs(ctx context.Context, userIDs []uuid.UUID) ([]repository.Subscription, error) {
+	start := time.Now()
+	subs, err := r.next.ListSubscriptionsByUserIDs(ctx, userIDs)
+	r.observe("ListSubscriptionsByUserIDs", start, err)
+	return subs, err
+}
+
+func (r *instrumentedRepository) GetCostBreakdownByUser(ctx context.Context, filter repository.SubscriptionFilter, group repository.CostGroup) ([]repository.UserCostBreakdownRow, error) {
+	start := time.Now()
+	rows, err := r.next.GetCostBreakdownByUser(ctx, filter, group)
+	r.observe("GetCostBreakdownByUser", start, err)
+	return rows, err
+}
+
+func (r *instrumentedRepository) ListTrialsEnding(ctx context.Context, filter repository.TrialEndingFilter) ([]repository.Subscription, error) {
+	start := time.Now()
+	subs, err := r.next.ListTrialsEnding(ctx, filter)
+	r.observe("ListTrialsEnding", start, err)
+	return subs, err
+}
+
+func (r *instrumentedRepository) ListBilledSubscriptions(ctx context.Context, filter repository.BilledFilter) ([]repository.BilledSubscription, error) {
+	start := time.Now()
+	subs, err := r.next.ListBilledSubscriptions(ctx, filter)
+	r.observe("ListBilledSubscriptions", start, err)
+	return subs, err
+}
+
+func (r *instrumentedRepository) PauseSubscription(ctx context.Context, pause repository.SubscriptionPause) (repository.SubscriptionPause, error) {
+	start := time.Now()
+	created, err := r.next.PauseSubscription(ctx, pause)
+	r.observe("PauseSubscription", start, err)
+	return created, err
+}
+
+func (r *instrumentedRepository) ResumeSubscription(ctx context.Context, subscriptionID uuid.UUID, month time.Time) error {
+	start := time.Now()
+	err := r.next.ResumeSubscription(ctx, subscriptionID, month)
+	r.observe("ResumeSubscription", start, err)
+	return err
+}
+
+func (r *instrumentedRepository) CreateService(ctx context.Context, svc repository.CatalogService) (repository.CatalogService, error) {
+	start := time.Now()
+	created, err := r.next.CreateService(ctx, svc)
+	r.observe("CreateService", start, err)
+	return created, err
+}
+
+func (r *instrumentedRepository) GetServiceByID(ctx context.Context, id uuid.UUID) (repository.CatalogService, error) {
+	start := time.Now()
+	svc, err := r.next.GetServiceByID(ctx, id)
+	r.observe("GetServiceByID", start, err)
+	return svc, err
+}
+
+func (r *instrumentedRepository) ListServices(ctx context.Context, query string) ([]repository.CatalogService, error) {
+	start := time.Now()
+	services, err := r.next.ListServices(ctx, query)
+	r.observe("ListServices", start, err)
+	return services, err
+}
+
+func (r *instrumentedRepository) UpdateService(ctx context.Context, svc repository.CatalogService) (repository.CatalogService, error) {
+	start := time.Now()
+	updated, err := r.next.UpdateService(ctx, svc)
+	r.observe("UpdateService", start, err)
+	return updated, err
+}
+
+func (r *instrumentedRepository) DeleteService(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	err := r.next.DeleteService(ctx, id)
+	r.observe("DeleteService", start, err)
+	return err
+}
+
+func (r *instrumentedRepository) MatchService(ctx context.Context, name string) (repository.CatalogService, error) {
+	start := time.Now()
+	svc, err := r.next.MatchService(ctx, name)
+	r.observe("MatchService", start, err)
+	return svc, err
+}
+
+func (r *instrumentedRepository) GetServicePlan(ctx context.Context, id uuid.UUID) (repository.ServicePlan, error) {
+	start := time.Now()
+	plan, err := r.next.GetServicePlan(ctx, id)
+	r.observe("GetServicePlan", start, err)
+	return plan, err
+}
+
+func (r *instrumentedRepository) ChangeSubscriptionPlan(ctx context.Context, change repository.PlanChange) (repository.PlanChange, error) {
+	start := time.Now()
+	created, err := r.next.ChangeSubscriptionPlan(ctx, change)
+	r.observe("ChangeSubscriptionPlan", start, err)
+	return created, err
+}
+
+func (r *instrumentedRepository) ListPlanChanges(ctx context.Context, subscriptionID uuid.UUID) ([]repository.PlanChange, error) {
+	start := time.Now()
+	changes, err := r.next.ListPlanChanges(ctx, subscriptionID)
+	r.observe("ListPlanChanges", start, err)
+	return changes, err
+}
+
+func (r *instrumentedRepository) ScheduleChange(ctx context.Context, change repository.ScheduledChange) (repository.ScheduledChange, error) {
+	start := time.Now()
+	scheduled, err := r.next.ScheduleChange(ctx, change)
+	r.observe("ScheduleChange", start, err)
+	return scheduled, err
+}
+
+func (r *instrumentedRepository) ListScheduledChanges(ctx context.Context, subscriptionID uuid.UUID) ([]repository.ScheduledChange, error) {
+	start := time.Now()
+	changes, err := r.next.ListScheduledChanges(ctx, subscriptionID)
+	r.observe("ListScheduledChanges", start, err)
+	return changes, err
+}
+
+func (r *instrumentedRepository) CancelScheduledChange(ctx context.Context, subscriptionID, changeID uuid.UUID) error {
+	start := time.Now()
+	err := r.next.CancelScheduledChange(ctx, subscriptionID, changeID)
+	r.observe("CancelScheduledChange", start, err)
+	return err
+}
+
+func (r *instrumentedRepository) GetSubscriptionSplit(ctx context.Context, subscriptionID uuid.UUID) (repository.SubscriptionSplit, error) {
+	start := time.Now()
+	split, err := r.next.GetSubscriptionSplit(ctx, subscriptionID)
+	r.observe("GetSubscriptionSplit", start, err)
+	return split, err
+}
+
+func (r *instrumentedRepository) SetSubscriptionSplit(ctx context.Context, split repository.SubscriptionSplit) (repository.SubscriptionSplit, error) {
+	start := time.Now()
+	split, err := r.next.SetSubscriptionSplit(ctx, split)
+	r.observe("SetSubscriptionSplit", start, err)
+	return split, err
+}
+
+func (r *instrumentedRepository) GetNotificationPreferences(ctx context.Context, userID uuid.UUID) (repository.NotificationPreferences, error) {
+	start := time.Now()
+	prefs, err := r.next.GetNotificationPreferences(ctx, userID)
+	r.observe("GetNotificationPreferences", start, err)
+	return prefs, err
+}
+
+func (r *instrumentedRepository) SetNotificationPreferences(ctx context.Context, prefs repository.NotificationPreferences) (repository.NotificationPreferences, error) {
+	start := time.Now()
+	prefs, err := r.next.SetNotificationPreferences(ctx, prefs)
+	r.observe("SetNotificationPreferences", start, err)
+	return prefs, err
+}
+
+func (r *instrumentedRepository) CreatePaymentMethod(ctx context.Context, method repository.PaymentMethod) (repository.PaymentMethod, error) {
+	start := time.Now()
+	method, err := r.next.CreatePaymentMethod(ctx, method)
+	r.observe("CreatePaymentMethod", start, err)
+	return method, err
+}
+
+func (r *instrumentedRepository) GetPaymentMethod(ctx context.Context, id uuid.UUID) (repository.PaymentMethod, error) {
+	start := time.Now()
+	method, err := r.next.GetPaymentMethod(ctx, id)
+	r.observe("GetPaymentMethod", start, err)
+	return method, err
+}
+
+func (r *instrumentedRepository) ListPaymentMethods(ctx context.Context, userID *uuid.UUID) ([]repository.PaymentMethod, error) {
+	start := time.Now()
+	methods, err := r.next.ListPaymentMethods(ctx, userID)
+	r.observe("ListPaymentMethods", start, err)
+	return methods, err
+}
+
+func (r *instrumentedRepository) UpdatePaymentMethod(ctx context.Context, method repository.PaymentMethod) (repository.PaymentMethod, error) {
+	start := time.Now()
+	method, err := r.next.UpdatePaymentMethod(ctx, method)
+	r.observe("UpdatePaymentMethod", start, err)
+	return method, err
+}
+
+func (r *instrumentedRepository) DeletePaymentMethod(ctx context.Context, id uuid.UUID, replacementID uuid.NullUUID) error {
+	start := time.Now()
+	err := r.next.DeletePaymentMethod(ctx, id, replacementID)
+	r.observe("DeletePaymentMethod", start, err)
+	return err
+}
@@ -0,0 +1,16 @@
+package models
+
+import "github.com/google/uuid"
+
+// UpcomingChargesRequest представляет параметры списка предстоящих списаний
+type UpcomingChargesRequest struct {
+	Days   int        `json:"days" validate:"min=1,max=366" example:"7" description:"Число дней начиная с сегодняшнего"`
+	UserID *uuid.UUID `json:"user_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000" description:"Фильтр по ID пользователя"`
+}
+
+// ChargeResponse представляет предстоящее списание за подписку
+type ChargeResponse struct {
+	SubscriptionResponse
+	Date   string `json:"date" example:"2024-03-15" description:"День списания, ГГГГ-ММ-ДД"`
+	Amount int    `json:"amount" example:"299" description:"Сумма списания в рублях с учётом пробного периода и смен тарифа"`
+}
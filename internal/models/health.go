@@ -0,0 +1,18 @@
+package models
+
+// HealthResponse представляет ответ liveness-проверки
+type HealthResponse struct {
+	Status string `json:"status" example:"ok" description:"Статус сервиса"`
+}
+
+// CheckResult представляет результат проверки одной зависимости
+type CheckResult struct {
+	Status string `json:"status" example:"ok" description:"ok или fail"`
+	Error  string `json:"error,omitempty" example:"failed to connect" description:"Причина неудачи"`
+}
+
+// ReadinessResponse представляет ответ readiness-проверки
+type ReadinessResponse struct {
+	Status string                 `json:"status" example:"ok" description:"ok или unavailable"`
+	Checks map[string]CheckResult `json:"checks" description:"Результаты проверок по зависимостям"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/monthyear"
+)
+
+// Виды способов оплаты
+const (
+	PaymentCard = "card"
+	PaymentBank = "bank"
+)
+
+// PaymentMethodRequest представляет запрос на создание или замену способа оплаты.
+// Номер карты целиком, CVC и реквизиты счёта не принимаются и не хранятся.
+type PaymentMethodRequest struct {
+	Kind    string               `json:"kind" validate:"required,oneof=card bank" example:"card" description:"Вид: card — банковская карта, bank — банковский счёт"`
+	Label   string               `json:"label,omitempty" validate:"required_if=Kind bank,max=100" example:"Зарплатная" description:"Название, для счёта обязательно"`
+	Brand   string               `json:"brand,omitempty" validate:"excluded_if=Kind bank,max=20" example:"Visa" description:"Платёжная система карты (необязательно)"`
+	Last4   string               `json:"last4,omitempty" validate:"required_if=Kind card,excluded_if=Kind bank,omitempty,len=4,numeric" example:"4242" description:"Последние четыре цифры карты"`
+	Expires *monthyear.MonthYear `json:"expires,omitempty" validate:"required_if=Kind card,excluded_if=Kind bank" example:"08-2026" description:"Месяц окончания срока действия карты в формате ММ-ГГГГ"`
+}
+
+// PaymentMethodResponse представляет способ оплаты пользователя
+type PaymentMethodResponse struct {
+	ID        uuid.UUID            `json:"id" example:"5d4c3b2a-1f0e-4d9c-8b7a-6f5e4d3c2b1a" description:"ID способа оплаты"`
+	UserID    uuid.UUID            `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174000" description:"ID пользователя"`
+	Kind      string               `json:"kind" example:"card" description:"Вид: card или bank"`
+	Name      string               `json:"name" example:"Visa *4242" description:"Название для отображения: label или платёжная система и последние цифры карты"`
+	Label     string               `json:"label,omitempty" example:"Зарплатная" description:"Название"`
+	Brand     string               `json:"brand,omitempty" example:"Visa" description:"Платёжная система карты"`
+	Last4     string               `json:"last4,omitempty" example:"4242" description:"Последние четыре цифры карты"`
+	Expires   *monthyear.MonthYear `json:"expires,omitempty" example:"08-2026" description:"Месяц окончания срока действия карты, она действует до его последнего дня"`
+	CreatedAt time.Time            `json:"created_at" example:"2025-05-14T10:00:00Z" description:"Время создания"`
+}
+
+// DeletePaymentMethodRequest представляет параметры удаления способа оплаты
+type DeletePaymentMethodRequest struct {
+	ReplacementID *uuid.UUID `json:"replacement_id,omitempty" example:"8e7d6c5b-4a3f-4e2d-9c1b-0a9f8e7d6c5b" description:"Способ оплаты того же пользователя, на который переносятся подписки (по умолчанию они отвязываются)"`
+}
+
+// ExpiringCardsRequest представляет параметры отчёта о картах, срок действия которых закончится до списания
+type ExpiringCardsRequest struct {
+	UserID *uuid.UUID `json:"user_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000" description:"Фильтр по ID пользователя"`
+}
+
+// ExpiringCardResponse представляет подписку, следующее списание которой придётся на день после окончания срока действия карты
+type ExpiringCardResponse struct {
+	PaymentMethod   PaymentMethodResponse `json:"payment_method" description:"Карта, к которой привязана подписка"`
+	Subscription    SubscriptionResponse  `json:"subscription" description:"Подписка"`
+	NextBillingDate string                `json:"next_billing_date" example:"2026-09-15" description:"День следующего списания, ГГГГ-ММ-ДД"`
+	Amount          int                   `json:"amount" example:"299" description:"Сумма следующего списания в рублях"`
+}
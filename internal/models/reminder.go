@@ -0,0 +1,48 @@
+package models
+
+import "github.com/google/uuid"
+
+// Каналы доставки напоминаний
+const (
+	ChannelLog     = "log"
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+)
+
+// NotificationPreferencesRequest представляет запрос на замену настроек напоминаний пользователя
+type NotificationPreferencesRequest struct {
+	Enabled    *bool  `json:"enabled" validate:"required" example:"true" description:"Отправлять ли напоминания"`
+	DaysBefore int    `json:"days_before" validate:"min=1,max=28" example:"3" description:"За сколько дней до списания или окончания подписки отправлять напоминание"`
+	Channel    string `json:"channel" validate:"required,oneof=log email webhook" example:"email" description:"Канал: log, email или webhook"`
+	Email      string `json:"email,omitempty" validate:"required_if=Channel email,omitempty,email" example:"user@example.com" description:"Адрес для канала email"`
+	WebhookURL string `json:"webhook_url,omitempty" validate:"required_if=Channel webhook,omitempty,https_url" example:"https://example.com/hooks/subscriptions" description:"Адрес https с публичным IP для канала webhook"`
+}
+
+// NotificationPreferencesResponse представляет настройки напоминаний пользователя
+type NotificationPreferencesResponse struct {
+	UserID     uuid.UUID `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174000" description:"ID пользователя"`
+	Enabled    bool      `json:"enabled" example:"true" description:"Отправлять ли напоминания"`
+	DaysBefore int       `json:"days_before" example:"3" description:"За сколько дней до списания или окончания подписки отправлять напоминание"`
+	Channel    string    `json:"channel" example:"email" description:"Канал: log, email или webhook"`
+	Email      string    `json:"email,omitempty" example:"user@example.com" description:"Адрес для канала email"`
+	WebhookURL string    `json:"webhook_url,omitempty" example:"https://example.com/hooks/subscriptions" description:"Адрес для канала webhook"`
+}
+
+// Виды напоминаний
+const (
+	ReminderRenewal = "renewal"
+	ReminderEnding  = "ending"
+)
+
+// Reminder представляет напоминание о списании или окончании подписки, в таком виде оно отправляется на webhook
+type Reminder struct {
+	Kind           string    `json:"kind" example:"renewal" description:"Вид: renewal — очередное списание, ending — окончание подписки"`
+	TenantID       string    `json:"tenant_id" example:"acme" description:"Тенант подписки"`
+	SubscriptionID uuid.UUID `json:"subscription_id" example:"123e4567-e89b-12d3-a456-426614174000" description:"ID подписки"`
+	UserID         uuid.UUID `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174000" description:"ID пользователя"`
+	ServiceName    string    `json:"service_name" example:"Netflix" description:"Название сервиса"`
+	Date           string    `json:"date" example:"2024-04-01" description:"День списания или первый день после окончания подписки, ГГГГ-ММ-ДД"`
+	Amount         int       `json:"amount" example:"299" description:"Сумма списания в рублях, для окончания 0"`
+	Email          string    `json:"-"`
+	WebhookURL     string    `json:"-"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/monthyear"
+)
+
+// Причины, по которым подписки попадают в группу возможных дублей
+const (
+	DuplicateSameService = "same_service" // Один сервис каталога или совпадающие названия
+	DuplicateSimilarName = "similar_name" // Похожие названия сервисов, хотя бы один из которых не из каталога
+	DuplicateSamePrice   = "same_price"   // Разные сервисы с одинаковой ценой и оплатой в один день
+)
+
+// DuplicatesRequest представляет параметры отчёта о возможных дублях
+type DuplicatesRequest struct {
+	Month  *monthyear.MonthYear `json:"month,omitempty" example:"03-2024" description:"Месяц, оплаченные в котором подписки сравниваются (по умолчанию текущий)"`
+	UserID *uuid.UUID           `json:"user_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000" description:"Фильтр по ID пользователя"`
+}
+
+// DuplicateSubscriptionResponse представляет подписку в группе возможных дублей
+type DuplicateSubscriptionResponse struct {
+	SubscriptionResponse
+	MonthCost int `json:"month_cost" example:"299" description:"Стоимость подписки в месяце отчёта в рублях"`
+}
+
+// DuplicateGroupResponse представляет группу подписок одного пользователя, которые могут дублировать друг друга
+type DuplicateGroupResponse struct {
+	Reason           string                          `json:"reason" example:"same_service" description:"Причина: same_service, similar_name или same_price"`
+	UserID           uuid.UUID                       `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174000" description:"ID пользователя"`
+	Subscriptions    []DuplicateSubscriptionResponse `json:"subscriptions" description:"Подписки группы в порядке убывания стоимости"`
+	MonthCost        int                             `json:"month_cost" example:"598" description:"Стоимость подписок группы в месяце отчёта в рублях"`
+	PotentialSavings int                             `json:"potential_savings" example:"299" description:"Экономия в месяц, если оставить только самую дорогую подписку группы"`
+}
+
+// CategoryOverlapResponse представляет разные сервисы одной категории, оплачиваемые пользователем в одном месяце.
+// Сервисы не заменяют друг друга, поэтому экономия не оценивается.
+type CategoryOverlapResponse struct {
+	Category      string                          `json:"category" example:"video" description:"Категория"`
+	UserID        uuid.UUID                       `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174000" description:"ID пользователя"`
+	Subscriptions []DuplicateSubscriptionResponse `json:"subscriptions" description:"Подписки категории в порядке убывания стоимости"`
+	MonthCost     int                             `json:"month_cost" example:"1198" description:"Стоимость подписок категории в месяце отчёта в рублях"`
+}
+
+// DuplicatesResponse представляет отчёт о возможных дублях
+type DuplicatesResponse struct {
+	Month            *monthyear.MonthYear      `json:"month" example:"03-2024" description:"Месяц отчёта"`
+	Groups           []DuplicateGroupResponse  `json:"groups" description:"Группы в порядке убывания экономии, подписка может входить в группу по названию и в группу по цене"`
+	CategoryOverlaps []CategoryOverlapResponse `json:"category_overlaps" description:"Разные сервисы одной категории в порядке убывания стоимости, без оценки экономии"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import "github.com/google/uuid"
+
+// ServiceRequest представляет сервис каталога в запросах создания и замены
+type ServiceRequest struct {
+	Name     string        `json:"name" validate:"required,max=255" example:"Netflix" description:"Каноническое название"`
+	Aliases  []string      `json:"aliases,omitempty" validate:"dive,required,max=255" example:"netflix,Нетфликс" description:"Другие написания названия, сопоставляются без учёта регистра"`
+	Category string        `json:"category,omitempty" validate:"max=255" example:"video" description:"Категория"`
+	Website  string        `json:"website,omitempty" validate:"omitempty,url" example:"https://www.netflix.com" description:"Сайт сервиса"`
+	Plans    []ServicePlan `json:"plans,omitempty" validate:"unique=Name,dive" description:"Тарифы; при замене сервиса тарифы с тем же названием сохраняют ID, отсутствующие удаляются"`
+}
+
+// ServicePlan представляет тариф сервиса в запросах
+type ServicePlan struct {
+	Name          string `json:"name" validate:"required,max=255" example:"Premium" description:"Название тарифа"`
+	Price         int    `json:"price" validate:"min=0" example:"899" description:"Стоимость в рублях за период оплаты"`
+	BillingPeriod string `json:"billing_period,omitempty" validate:"omitempty,oneof=month year" example:"month" description:"Период оплаты: month (по умолчанию) или year"`
+}
+
+// ServicePlanResponse представляет тариф сервиса в ответах API
+type ServicePlanResponse struct {
+	ID            uuid.UUID `json:"id" example:"0b7e9c4a-2f1d-4e8b-a3c5-6d7e8f9a0b1c" description:"ID тарифа"`
+	Name          string    `json:"name" example:"Premium" description:"Название тарифа"`
+	Price         int       `json:"price" example:"899" description:"Стоимость в рублях за период оплаты"`
+	BillingPeriod string    `json:"billing_period" example:"month" description:"Период оплаты: month или year"`
+	MonthlyPrice  int       `json:"monthly_price" example:"899" description:"Стоимость в рублях за месяц, для годовых тарифов — 1/12 цены с округлением"`
+}
+
+// ServiceResponse представляет сервис каталога в ответах API
+type ServiceResponse struct {
+	ID       uuid.UUID             `json:"id" example:"6f1c2a4e-8d3b-4c5a-9e7f-0a1b2c3d4e5f" description:"ID сервиса"`
+	Name     string                `json:"name" example:"Netflix" description:"Каноническое название"`
+	Aliases  []string              `json:"aliases" example:"netflix,Нетфликс" description:"Другие написания названия"`
+	Category string                `json:"category" example:"video" description:"Категория"`
+	Website  string                `json:"website" example:"https://www.netflix.com" description:"Сайт сервиса"`
+	Plans    []ServicePlanResponse `json:"plans" description:"Тарифы в порядке возрастания цены"`
+}
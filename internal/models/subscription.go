@@ -1,6 +1,8 @@
 package models
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 
 	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/monthyear"
@@ -8,50 +10,257 @@ import (
 
 // CreateSubscriptionRequest представляет запрос на создание новой подписки
 type CreateSubscriptionRequest struct {
-	ServiceName string               `json:"service_name" validate:"required" example:"Netflix" description:"Название сервиса"`
-	Price       int                  `json:"price" validate:"required,min=0" example:"299" description:"Стоимость в рублях за месяц"`
-	UserID      uuid.UUID            `json:"user_id" validate:"required" example:"123e4567-e89b-12d3-a456-426614174000" description:"ID пользователя"`
-	StartDate   *monthyear.MonthYear `json:"start_date" validate:"required" example:"01-2024" description:"Дата начала в формате ММ-ГГГГ"`
-	EndDate     *monthyear.MonthYear `json:"end_date,omitempty" example:"12-2024" description:"Дата окончания в формате ММ-ГГГГ (необязательно)"`
+	ServiceName  string               `json:"service_name" validate:"required_without_all=ServiceID PlanID" example:"Netflix" description:"Название сервиса, сопоставляется с каталогом (необязательно при service_id или plan_id)"`
+	ServiceID    *uuid.UUID           `json:"service_id,omitempty" example:"6f1c2a4e-8d3b-4c5a-9e7f-0a1b2c3d4e5f" description:"ID сервиса из каталога (необязательно)"`
+	AccountLabel string               `json:"account_label,omitempty" validate:"max=100" example:"family" description:"Учётная запись сервиса, если их у пользователя несколько (необязательно)"`
+	PlanID       *uuid.UUID           `json:"plan_id,omitempty" example:"0b7e9c4a-2f1d-4e8b-a3c5-6d7e8f9a0b1c" description:"ID тарифа сервиса (необязательно), по нему определяются сервис и цена по умолчанию"`
+	Price        *int                 `json:"price,omitempty" validate:"required_without=PlanID,omitempty,min=0" example:"299" description:"Стоимость в рублях за месяц, 0 — бесплатная (по умолчанию цена тарифа)"`
+	UserID       uuid.UUID            `json:"user_id" validate:"required" example:"123e4567-e89b-12d3-a456-426614174000" description:"ID пользователя"`
+	StartDate    *monthyear.MonthYear `json:"start_date" validate:"required" example:"01-2024" description:"Дата начала в формате ММ-ГГГГ"`
+	EndDate      *monthyear.MonthYear `json:"end_date,omitempty" example:"12-2024" description:"Дата окончания в формате ММ-ГГГГ (необязательно)"`
+	BillingDay   int                  `json:"billing_day,omitempty" validate:"omitempty,min=1,max=31" example:"15" description:"День месяца списания 1–31, в коротких месяцах — последний день (по умолчанию 1)"`
+	TrialMonths  int                  `json:"trial_months,omitempty" validate:"min=0" example:"1" description:"Длительность пробного периода в месяцах с даты начала (необязательно)"`
+	TrialPrice   int                  `json:"trial_price,omitempty" validate:"min=0" example:"0" description:"Стоимость месяца пробного периода в рублях, 0 — бесплатный"`
+	Category     string               `json:"category,omitempty" validate:"max=255" example:"entertainment" description:"Категория расходов (по умолчанию категория сервиса каталога)"`
+	Tags         []string             `json:"tags,omitempty" validate:"dive,required,max=100" example:"work,team:platform" description:"Теги, без учёта регистра"`
+	// Способ оплаты владельца, с которого списывается подписка
+	PaymentMethodID *uuid.UUID `json:"payment_method_id,omitempty" example:"5d4c3b2a-1f0e-4d9c-8b7a-6f5e4d3c2b1a" description:"ID способа оплаты пользователя (необязательно)"`
 }
 
 // UpdateSubscriptionRequest представляет запрос на обновление существующей подписки
 // Примечание: ID пользователя и дата начала не могут быть изменены
 type UpdateSubscriptionRequest struct {
-	ServiceName *string              `json:"service_name,omitempty" example:"Netflix Premium" description:"Обновлённое название сервиса"`
-	Price       *int                 `json:"price,omitempty" example:"599" description:"Обновлённая стоимость в рублях за месяц"`
-	EndDate     *monthyear.MonthYear `json:"end_date,omitempty" example:"12-2024" description:"Обновлённая дата окончания в формате ММ-ГГГГ"`
+	ServiceName  *string              `json:"service_name,omitempty" example:"Netflix Premium" description:"Обновлённое название сервиса"`
+	ServiceID    *uuid.UUID           `json:"service_id,omitempty" example:"6f1c2a4e-8d3b-4c5a-9e7f-0a1b2c3d4e5f" description:"ID сервиса из каталога"`
+	AccountLabel *string              `json:"account_label,omitempty" validate:"omitempty,max=100" example:"work" description:"Обновлённая учётная запись сервиса, пустая строка её сбрасывает"`
+	PlanID       *uuid.UUID           `json:"plan_id,omitempty" example:"0b7e9c4a-2f1d-4e8b-a3c5-6d7e8f9a0b1c" description:"Тариф с начала подписки, без price цена берётся из тарифа"`
+	Price        *int                 `json:"price,omitempty" example:"599" description:"Обновлённая стоимость в рублях за месяц с начала подписки"`
+	EndDate      *monthyear.MonthYear `json:"end_date,omitempty" example:"12-2024" description:"Обновлённая дата окончания в формате ММ-ГГГГ"`
+	BillingDay   *int                 `json:"billing_day,omitempty" validate:"omitempty,min=1,max=31" example:"28" description:"Обновлённый день месяца списания 1–31"`
+	TrialMonths  *int                 `json:"trial_months,omitempty" example:"2" description:"Обновлённая длительность пробного периода в месяцах"`
+	TrialPrice   *int                 `json:"trial_price,omitempty" example:"99" description:"Обновлённая стоимость месяца пробного периода в рублях"`
+	Category     *string              `json:"category,omitempty" validate:"omitempty,max=255" example:"work" description:"Обновлённая категория расходов, пустая строка её сбрасывает"`
+	AddTags      []string             `json:"add_tags,omitempty" validate:"dive,required,max=100" example:"team:platform" description:"Добавляемые теги"`
+	RemoveTags   []string             `json:"remove_tags,omitempty" validate:"dive,required,max=100" example:"entertainment" description:"Удаляемые теги"`
+	// Нулевой UUID отвязывает способ оплаты
+	PaymentMethodID *uuid.UUID `json:"payment_method_id,omitempty" example:"5d4c3b2a-1f0e-4d9c-8b7a-6f5e4d3c2b1a" description:"Новый способ оплаты, нулевой UUID отвязывает подписку от способа оплаты"`
 }
 
 type SubscriptionCursor struct {
-	StartDate monthyear.MonthYear `validate:"required" example:"01-2024" description:"Последняя дата начала в прошлом запросе"`
-	ID        uuid.UUID           `validate:"required" example:"123e4567-e89b-12d3-a456-426614174000" description:"Последний id в прошлом запросе"`
+	StartDate monthyear.MonthYear `json:"previous_start_date" validate:"required" example:"01-2024" description:"Последняя дата начала в прошлом запросе"`
+	ID        uuid.UUID           `json:"previous_id" validate:"required" example:"123e4567-e89b-12d3-a456-426614174000" description:"Последний id в прошлом запросе"`
 }
 
 type ListSubscriptionsRequest struct {
-	Limit  int `validate:"required,min=1" example:"30" description:"Ограничение количества подписок"`
-	Cursor *SubscriptionCursor
+	Limit  int                 `json:"limit" validate:"required,min=1" example:"30" description:"Ограничение количества подписок"`
+	Cursor *SubscriptionCursor `json:"cursor,omitempty"`
+	Tags   []string            `json:"tags,omitempty" validate:"dive,required,max=100" example:"work" description:"Подписки со всеми указанными тегами"`
 }
 
 // TotalCostRequest представляет параметры запроса для расчёта общей стоимости
 type TotalCostRequest struct {
-	UserID      *uuid.UUID           `json:"user_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000" description:"Фильтр по ID пользователя"`
-	ServiceName *string              `json:"service_name,omitempty" example:"Netflix" description:"Фильтр по названию сервиса (частичное совпадение)"`
-	StartDate   *monthyear.MonthYear `json:"start_date,omitempty" example:"01-2024" description:"Фильтр по дате начала (подписки, начинающиеся с этой даты)"`
-	EndDate     *monthyear.MonthYear `json:"end_date,omitempty" example:"12-2024" description:"Фильтр по дате окончания (подписки, заканчивающиеся до этой даты)"`
+	UserID      *uuid.UUID           `json:"user_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000" description:"Фильтр по ID пользователя, для общих подписок учитывается его доля"`
+	ServiceName *string              `json:"service_name,omitempty" example:"Netflix" description:"Фильтр по названию сервиса или его псевдониму в каталоге (частичное совпадение)"`
+	ServiceID   *uuid.UUID           `json:"service_id,omitempty" example:"6f1c2a4e-8d3b-4c5a-9e7f-0a1b2c3d4e5f" description:"Фильтр по ID сервиса из каталога"`
+	StartDate   *monthyear.MonthYear `json:"start_date,omitempty" example:"01-2024" description:"Начало периода расчёта, включительно (по умолчанию начало подписки)"`
+	EndDate     *monthyear.MonthYear `json:"end_date,omitempty" example:"12-2024" description:"Конец периода расчёта, включительно (по умолчанию текущий месяц)"`
+	Tags        []string             `json:"tags,omitempty" validate:"dive,required,max=100" example:"team:platform" description:"Фильтр по тегам, подписки со всеми указанными тегами"`
+	Category    *string              `json:"category,omitempty" example:"work" description:"Фильтр по категории без учёта регистра"`
+}
+
+// CostBreakdownRequest представляет параметры запроса для разбивки стоимости по группам
+type CostBreakdownRequest struct {
+	TotalCostRequest
+	GroupBy string `json:"group_by,omitempty" validate:"omitempty,oneof=service user month category tag payment_method" example:"service" description:"Группировка: service (по умолчанию), user, month, category, tag (подписка учитывается в группе каждого своего тега) или payment_method (доли участников общих подписок — в группе без способа оплаты)"`
 }
 
 // SubscriptionResponse представляет подписку в ответах API
 type SubscriptionResponse struct {
-	ID          uuid.UUID            `json:"id" example:"123e4567-e89b-12d3-a456-426614174000" description:"ID подписки"`
-	UserID      uuid.UUID            `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174000" description:"ID пользователя"`
-	ServiceName string               `json:"service_name" example:"Netflix" description:"Название сервиса"`
-	Price       int                  `json:"price" example:"299" description:"Стоимость в рублях за месяц"`
-	StartDate   *monthyear.MonthYear `json:"start_date" example:"01-2024" description:"Дата начала в формате ММ-ГГГГ"`
-	EndDate     *monthyear.MonthYear `json:"end_date,omitempty" example:"12-2024" description:"Дата окончания в формате ММ-ГГГГ (null если активна)"`
+	ID           uuid.UUID            `json:"id" example:"123e4567-e89b-12d3-a456-426614174000" description:"ID подписки"`
+	UserID       uuid.UUID            `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174000" description:"ID пользователя"`
+	ServiceName  string               `json:"service_name" example:"Netflix" description:"Название сервиса"`
+	ServiceID    *uuid.UUID           `json:"service_id,omitempty" example:"6f1c2a4e-8d3b-4c5a-9e7f-0a1b2c3d4e5f" description:"ID сервиса из каталога, если название с ним сопоставлено"`
+	AccountLabel string               `json:"account_label" example:"family" description:"Учётная запись сервиса, подписки одной учётной записи не пересекаются по датам"`
+	PlanID       *uuid.UUID           `json:"plan_id,omitempty" example:"0b7e9c4a-2f1d-4e8b-a3c5-6d7e8f9a0b1c" description:"Тариф с начала подписки до первой смены тарифа"`
+	Price        int                  `json:"price" example:"299" description:"Стоимость в рублях за месяц с начала подписки до первой смены тарифа"`
+	CurrentPrice int                  `json:"current_price" example:"899" description:"Стоимость в рублях за месяц с учётом смен тарифа в текущем месяце"`
+	StartDate    *monthyear.MonthYear `json:"start_date" example:"01-2024" description:"Дата начала в формате ММ-ГГГГ"`
+	EndDate      *monthyear.MonthYear `json:"end_date,omitempty" example:"12-2024" description:"Дата окончания в формате ММ-ГГГГ (null если активна)"`
+	BillingDay   int                  `json:"billing_day" example:"15" description:"День месяца списания, в коротких месяцах — последний день"`
+	TrialMonths  int                  `json:"trial_months" example:"1" description:"Длительность пробного периода в месяцах, 0 — без него"`
+	TrialPrice   int                  `json:"trial_price" example:"0" description:"Стоимость месяца пробного периода в рублях"`
+	TrialEndDate *monthyear.MonthYear `json:"trial_end_date,omitempty" example:"01-2024" description:"Последний месяц пробного периода в формате ММ-ГГГГ"`
+	State        string               `json:"state" example:"active" description:"Состояние в текущем месяце: active, paused или ended"`
+	Category     string               `json:"category" example:"entertainment" description:"Категория расходов"`
+	Tags         []string             `json:"tags" example:"work,team:platform" description:"Теги в нижнем регистре по алфавиту"`
+	// Способ оплаты, если подписка к нему привязана
+	PaymentMethodID *uuid.UUID `json:"payment_method_id,omitempty" example:"5d4c3b2a-1f0e-4d9c-8b7a-6f5e4d3c2b1a" description:"ID способа оплаты, с которого списывается подписка"`
+}
+
+// Состояния подписки в SubscriptionResponse
+const (
+	StateActive = "active"
+	StatePaused = "paused"
+	StateEnded  = "ended"
+)
+
+// PauseSubscriptionRequest представляет запрос на приостановку подписки
+type PauseSubscriptionRequest struct {
+	StartDate *monthyear.MonthYear `json:"start_date,omitempty" example:"03-2024" description:"Первый месяц паузы в формате ММ-ГГГГ (по умолчанию текущий месяц)"`
+	EndDate   *monthyear.MonthYear `json:"end_date,omitempty" example:"05-2024" description:"Последний месяц паузы в формате ММ-ГГГГ (по умолчанию до возобновления)"`
+}
+
+// ResumeSubscriptionRequest представляет запрос на возобновление подписки
+type ResumeSubscriptionRequest struct {
+	Month *monthyear.MonthYear `json:"month,omitempty" example:"04-2024" description:"Первый оплачиваемый месяц после паузы в формате ММ-ГГГГ (по умолчанию текущий месяц)"`
+}
+
+// ChangePlanRequest представляет запрос на смену тарифа подписки
+type ChangePlanRequest struct {
+	PlanID    uuid.UUID            `json:"plan_id" validate:"required" example:"0b7e9c4a-2f1d-4e8b-a3c5-6d7e8f9a0b1c" description:"ID нового тарифа сервиса подписки"`
+	Price     *int                 `json:"price,omitempty" validate:"omitempty,min=0" example:"899" description:"Стоимость в рублях за месяц (по умолчанию цена тарифа)"`
+	StartDate *monthyear.MonthYear `json:"start_date,omitempty" example:"03-2024" description:"Первый месяц по новому тарифу в формате ММ-ГГГГ (по умолчанию текущий месяц)"`
+}
+
+// PlanChangeResponse представляет смену тарифа подписки
+type PlanChangeResponse struct {
+	ID        uuid.UUID            `json:"id" example:"5d4c3b2a-1f0e-4d9c-8b7a-6f5e4d3c2b1a" description:"ID смены тарифа"`
+	PlanID    *uuid.UUID           `json:"plan_id,omitempty" example:"0b7e9c4a-2f1d-4e8b-a3c5-6d7e8f9a0b1c" description:"ID тарифа (null если тариф удалён из каталога)"`
+	Price     int                  `json:"price" example:"899" description:"Стоимость в рублях за месяц"`
+	StartDate *monthyear.MonthYear `json:"start_date" example:"03-2024" description:"Первый месяц по тарифу в формате ММ-ГГГГ"`
+}
+
+// Действия запланированных изменений подписки
+const (
+	ScheduledCancel = "cancel"
+	ScheduledPrice  = "price"
+)
+
+// Статусы запланированных изменений подписки
+const (
+	ScheduledPending  = "pending"
+	ScheduledApplied  = "applied"
+	ScheduledCanceled = "canceled"
+)
+
+// ScheduleChangeRequest представляет запрос на изменение подписки, которое вступит в силу в будущем месяце
+type ScheduleChangeRequest struct {
+	Action string               `json:"action" validate:"required,oneof=cancel price" example:"price" description:"Действие: cancel — отменить подписку после месяца month, price — сменить цену с месяца month"`
+	Month  *monthyear.MonthYear `json:"month" validate:"required" example:"09-2025" description:"Последний оплачиваемый месяц для cancel или первый месяц по новой цене для price в формате ММ-ГГГГ"`
+	Price  *int                 `json:"price,omitempty" validate:"required_if=Action price,omitempty,min=0" example:"399" description:"Новая стоимость в рублях за месяц, только для price"`
+}
+
+// ScheduledChangeResponse представляет запланированное изменение подписки
+type ScheduledChangeResponse struct {
+	ID             uuid.UUID            `json:"id" example:"7a6b5c4d-3e2f-4a1b-9c8d-7e6f5a4b3c2d" description:"ID запланированного изменения"`
+	SubscriptionID uuid.UUID            `json:"subscription_id" example:"123e4567-e89b-12d3-a456-426614174000" description:"ID подписки"`
+	Action         string               `json:"action" example:"price" description:"Действие: cancel или price"`
+	Month          *monthyear.MonthYear `json:"month" example:"09-2025" description:"Последний оплачиваемый месяц для cancel или первый месяц по новой цене для price"`
+	Price          *int                 `json:"price,omitempty" example:"399" description:"Новая стоимость в рублях за месяц, только для price"`
+	Status         string               `json:"status" example:"pending" description:"Статус: pending, applied или canceled"`
+	CreatedAt      time.Time            `json:"created_at" example:"2025-05-14T10:00:00Z" description:"Время создания"`
+	AppliedAt      *time.Time           `json:"applied_at,omitempty" example:"2025-09-01T00:00:00Z" description:"Время применения планировщиком"`
+}
+
+// Правила разделения стоимости общей подписки
+const (
+	SplitEqual   = "equal"
+	SplitPercent = "percent"
+	SplitFixed   = "fixed"
+)
+
+// SubscriptionMemberRequest представляет участника общей подписки
+type SubscriptionMemberRequest struct {
+	UserID uuid.UUID `json:"user_id" validate:"required" example:"9b2f6d1e-3c4a-4e8f-a1b2-c3d4e5f6a7b8" description:"ID пользователя-участника"`
+	Share  int       `json:"share,omitempty" validate:"min=0" example:"25" description:"Процент (percent) или сумма в рублях за месяц (fixed), для equal не используется"`
+}
+
+// SetMembersRequest представляет запрос на замену участников общей подписки
+type SetMembersRequest struct {
+	SplitRule string                      `json:"split_rule" validate:"required,oneof=equal percent fixed" example:"percent" description:"Правило разделения: equal, percent или fixed"`
+	Members   []SubscriptionMemberRequest `json:"members" validate:"unique=UserID,dive" description:"Участники кроме владельца, пустой список отменяет разделение"`
+}
+
+// SubscriptionMemberResponse представляет участника общей подписки и его долю
+type SubscriptionMemberResponse struct {
+	UserID       uuid.UUID `json:"user_id" example:"9b2f6d1e-3c4a-4e8f-a1b2-c3d4e5f6a7b8" description:"ID пользователя-участника"`
+	Share        int       `json:"share" example:"25" description:"Процент или сумма в рублях по правилу разделения"`
+	CurrentShare int       `json:"current_share" example:"225" description:"Доля текущей цены подписки в рублях за месяц"`
+}
+
+// MembersResponse представляет разделение стоимости подписки между владельцем и участниками
+type MembersResponse struct {
+	SplitRule    string                       `json:"split_rule" example:"percent" description:"Правило разделения"`
+	OwnerID      uuid.UUID                    `json:"owner_id" example:"123e4567-e89b-12d3-a456-426614174000" description:"ID владельца, он оплачивает остаток"`
+	OwnerShare   int                          `json:"owner_share" example:"675" description:"Доля владельца в текущей цене в рублях за месяц"`
+	Members      []SubscriptionMemberResponse `json:"members" description:"Участники в порядке user_id"`
+	CurrentPrice int                          `json:"current_price" example:"900" description:"Текущая цена подписки в рублях за месяц"`
+}
+
+// TrialEndingRequest представляет параметры запроса подписок, переходящих с пробного периода на обычную цену
+type TrialEndingRequest struct {
+	Month  *monthyear.MonthYear `json:"month,omitempty" example:"02-2024" description:"Первый месяц по обычной цене (по умолчанию следующий месяц)"`
+	UserID *uuid.UUID           `json:"user_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000" description:"Фильтр по ID пользователя"`
 }
 
 // TotalCostResponse представляет ответ с расчётом общей стоимости
 type TotalCostResponse struct {
 	TotalCost int `json:"total_cost" example:"1499" description:"Общая стоимость в рублях"`
 }
+
+// CostBreakdownItem представляет стоимость подписок одной группы
+type CostBreakdownItem struct {
+	Key       string `json:"key" example:"Netflix" description:"Название сервиса, ID пользователя, месяц в формате ММ-ГГГГ, категория, тег (пустой для подписок без тегов) или название способа оплаты (пустое для подписок без него)"`
+	TotalCost int    `json:"total_cost" example:"3588" description:"Стоимость группы в рублях"`
+}
+
+// CostBreakdownResponse представляет ответ с разбивкой стоимости по группам
+type CostBreakdownResponse struct {
+	GroupBy   string              `json:"group_by" example:"service" description:"Группировка"`
+	Items     []CostBreakdownItem `json:"items" description:"Группы в порядке убывания стоимости (по месяцам — в хронологическом порядке)"`
+	TotalCost int                 `json:"total_cost" example:"5376" description:"Общая стоимость в рублях"`
+}
+
+// Виды изменений подписки в SubscriptionEvent
+const (
+	EventCreated = "created"
+	EventUpdated = "updated"
+	EventDeleted = "deleted"
+)
+
+// WatchSubscriptionsRequest представляет параметры отслеживания изменений подписок
+type WatchSubscriptionsRequest struct {
+	UserID *uuid.UUID `json:"user_id,omitempty" description:"Только подписки этого пользователя"`
+}
+
+// SubscriptionEvent представляет изменение подписки
+type SubscriptionEvent struct {
+	Type         string               `json:"type" example:"created" description:"Вид изменения: created, updated или deleted"`
+	Subscription SubscriptionResponse `json:"subscription" description:"Подписка после изменения, для удаления — до него"`
+	Time         time.Time            `json:"time" description:"Время изменения"`
+}
+
+// GraphQLRequest представляет запрос к /graphql
+type GraphQLRequest struct {
+	Query         string         `json:"query" example:"{ me { subscriptions { serviceName price } costReport { totalCost items { key totalCost } } } }" description:"Текст запроса GraphQL"`
+	OperationName string         `json:"operationName,omitempty" description:"Выполняемая операция, если в запросе их несколько"`
+	Variables     map[string]any `json:"variables,omitempty" description:"Значения переменных запроса"`
+}
+
+// Problem представляет ошибку в формате RFC 9457 (application/problem+json)
+type Problem struct {
+	Type          string         `json:"type" example:"about:blank" description:"Тип проблемы, about:blank — смысл определяется статусом"`
+	Title         string         `json:"title" example:"Bad Request" description:"Краткое описание статуса"`
+	Status        int            `json:"status" example:"400" description:"HTTP статус"`
+	Detail        string         `json:"detail,omitempty" example:"request validation failed" description:"Описание ошибки"`
+	Instance      string         `json:"instance,omitempty" example:"/subscriptions" description:"Путь запроса"`
+	Code          string         `json:"code" example:"validation_failed" description:"Стабильный код ошибки"`
+	InvalidFields []InvalidField `json:"invalid_fields,omitempty" description:"Поля запроса с ошибками"`
+}
+
+// InvalidField представляет ошибку в одном поле запроса
+type InvalidField struct {
+	Name   string `json:"name" example:"price" description:"Имя поля в JSON или параметра запроса"`
+	Rule   string `json:"rule" example:"min" description:"Нарушенное правило"`
+	Reason string `json:"reason" example:"must be at least 0" description:"Описание ошибки"`
+}
@@ -0,0 +1,36 @@
+// Package notify delivers reminders of subscriptions through the channels of models.Channel*
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+)
+
+// Log writes reminders to the application log, it needs no configuration and is the default channel
+type Log struct{}
+
+func NewLog() Log {
+	return Log{}
+}
+
+func (Log) Notify(ctx context.Context, reminder models.Reminder) error {
+	slog.InfoContext(ctx, "subscription reminder", "kind", reminder.Kind, "tenant_id", reminder.TenantID,
+		"user_id", reminder.UserID, "subscription_id", reminder.SubscriptionID, "service_name", reminder.ServiceName,
+		"date", reminder.Date, "amount", reminder.Amount)
+	return nil
+}
+
+// text is the human readable form of a reminder used by the email channel
+func text(reminder models.Reminder) (subject, body string) {
+	if reminder.Kind == models.ReminderEnding {
+		subject = fmt.Sprintf("Подписка %s заканчивается", reminder.ServiceName)
+		body = fmt.Sprintf("Подписка %s заканчивается, с %s она больше не действует.\n", reminder.ServiceName, reminder.Date)
+		return subject, body
+	}
+	subject = fmt.Sprintf("Скоро списание за %s", reminder.ServiceName)
+	body = fmt.Sprintf("%s за подписку %s будет списано %d ₽.\n", reminder.Date, reminder.ServiceName, reminder.Amount)
+	return subject, body
+}
@@ -0,0 +1,229 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"mime"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/config"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+)
+
+// fakeSMTP is a minimal SMTP server accepting one message per connection.
+// Recipients in reject are refused with 550.
+type fakeSMTP struct {
+	addr   *net.TCPAddr
+	reject string
+
+	mu       sync.Mutex
+	auth     string
+	from     string
+	rcpt     string
+	data     string
+	commands []string
+}
+
+func newFakeSMTP(t *testing.T) *fakeSMTP {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = lis.Close() })
+
+	s := &fakeSMTP{addr: lis.Addr().(*net.TCPAddr)}
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeSMTP) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(lines ...string) {
+		for _, line := range lines {
+			_, _ = conn.Write([]byte(line + "\r\n"))
+		}
+	}
+
+	reply("220 localhost ESMTP fake")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb, arg, _ := strings.Cut(line, " ")
+
+		s.mu.Lock()
+		s.commands = append(s.commands, strings.ToUpper(verb))
+		s.mu.Unlock()
+
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			reply("250-localhost", "250 AUTH PLAIN")
+		case "AUTH":
+			_, credentials, _ := strings.Cut(arg, " ")
+			decoded, _ := base64.StdEncoding.DecodeString(credentials)
+			s.mu.Lock()
+			s.auth = string(decoded)
+			s.mu.Unlock()
+			reply("235 2.7.0 Authentication successful")
+		case "MAIL":
+			s.mu.Lock()
+			s.from = arg
+			s.mu.Unlock()
+			reply("250 OK")
+		case "RCPT":
+			if s.reject != "" && strings.Contains(arg, s.reject) {
+				reply("550 5.1.1 No such user")
+				continue
+			}
+			s.mu.Lock()
+			s.rcpt = arg
+			s.mu.Unlock()
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+			s.mu.Lock()
+			s.data = data.String()
+			s.mu.Unlock()
+			reply("250 OK queued")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+func (s *fakeSMTP) config() config.SMTPConfig {
+	return config.SMTPConfig{Host: "127.0.0.1", Port: s.addr.Port, From: "reminders@example.com", Timeout: 5 * time.Second}
+}
+
+var testReminder = models.Reminder{
+	Kind:        models.ReminderRenewal,
+	ServiceName: "Netflix",
+	Date:        "2024-04-01",
+	Amount:      299,
+	Email:       "user@example.com",
+}
+
+func TestSMTPNotify(t *testing.T) {
+	server := newFakeSMTP(t)
+
+	if err := NewSMTP(server.config()).Notify(context.Background(), testReminder); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if server.from != "FROM:<reminders@example.com>" {
+		t.Errorf("MAIL %s, want FROM:<reminders@example.com>", server.from)
+	}
+	if server.rcpt != "TO:<user@example.com>" {
+		t.Errorf("RCPT %s, want TO:<user@example.com>", server.rcpt)
+	}
+	if server.auth != "" {
+		t.Errorf("authenticated without a user: %q", server.auth)
+	}
+
+	headers, body, _ := strings.Cut(server.data, "\r\n\r\n")
+	subject, _ := text(testReminder)
+	for _, want := range []string{
+		"From: reminders@example.com",
+		"To: user@example.com",
+		"Subject: " + mime.QEncoding.Encode("utf-8", subject),
+		"Content-Type: text/plain; charset=utf-8",
+	} {
+		if !strings.Contains(headers, want) {
+			t.Errorf("headers %q do not contain %q", headers, want)
+		}
+	}
+	if !strings.Contains(body, "299 ₽") {
+		t.Errorf("body %q does not contain the amount", body)
+	}
+	if last := server.commands[len(server.commands)-1]; last != "QUIT" {
+		t.Errorf("last command %s, want QUIT", last)
+	}
+}
+
+func TestSMTPNotifyAuthenticates(t *testing.T) {
+	server := newFakeSMTP(t)
+	cfg := server.config()
+	cfg.User, cfg.Password = "mailer", "secret"
+
+	if err := NewSMTP(cfg).Notify(context.Background(), testReminder); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if server.auth != "\x00mailer\x00secret" {
+		t.Errorf("AUTH PLAIN credentials %q", server.auth)
+	}
+}
+
+func TestSMTPNotifyErrors(t *testing.T) {
+	server := newFakeSMTP(t)
+	server.reject = "unknown@example.com"
+
+	unknown := testReminder
+	unknown.Email = "unknown@example.com"
+	noEmail := testReminder
+	noEmail.Email = ""
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	unreachable := server.config()
+	unreachable.Port = closed.Addr().(*net.TCPAddr).Port
+	_ = closed.Close()
+
+	tests := []struct {
+		name     string
+		cfg      config.SMTPConfig
+		reminder models.Reminder
+		want     string
+	}{
+		{name: "rejected recipient", cfg: server.config(), reminder: unknown, want: "rejected recipient"},
+		{name: "no email", cfg: server.config(), reminder: noEmail, want: "no email address"},
+		{name: "server down", cfg: unreachable, reminder: testReminder, want: "failed to connect"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewSMTP(tt.cfg).Notify(context.Background(), tt.reminder)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Notify() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
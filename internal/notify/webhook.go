@@ -0,0 +1,121 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+)
+
+// ErrWebhookAddress is returned for webhook URLs that are not https or resolve to an internal address
+var ErrWebhookAddress = errors.New("webhook URL must be https and resolve to a public address")
+
+// Webhook posts reminders as JSON to the URL of the user's preferences.
+// When secret is set the body is signed with HMAC-SHA256 in the X-Signature-256 header as "sha256=<hex>".
+//
+// URLs are set by users, so requests go only over https to public addresses: the address is checked
+// after resolution, when connecting, and redirects are not followed. Proxies from the environment are not used.
+type Webhook struct {
+	client *http.Client
+	secret []byte
+}
+
+func NewWebhook(secret string, timeout time.Duration) Webhook {
+	dialer := &net.Dialer{Timeout: timeout, Control: publicAddressOnly}
+	transport := &http.Transport{
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: timeout,
+		MaxIdleConns:        10,
+		IdleConnTimeout:     90 * time.Second,
+	}
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return Webhook{client: client, secret: []byte(secret)}
+}
+
+// publicAddressOnly refuses connections to loopback, private, link-local, shared and unspecified addresses.
+// It runs for the resolved address, so a host name cannot point to an internal service.
+func publicAddressOnly(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrWebhookAddress, address)
+	}
+	if !isPublic(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrWebhookAddress, addrPort.Addr())
+	}
+	return nil
+}
+
+// internalPrefixes are internal ranges netip.Addr does not classify: "this network" and the carrier-grade NAT
+// range of RFC 6598
+var internalPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+}
+
+func isPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range internalPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+func (w Webhook) Notify(ctx context.Context, reminder models.Reminder) error {
+	if reminder.WebhookURL == "" {
+		return errors.New("reminder has no webhook URL")
+	}
+	// Preferences saved before https was required may still have other URLs
+	if u, err := url.Parse(reminder.WebhookURL); err != nil || u.Scheme != "https" {
+		return ErrWebhookAddress
+	}
+
+	body, err := json.Marshal(reminder)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reminder: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reminder.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(w.secret) > 0 {
+		mac := hmac.New(sha256.New, w.secret)
+		mac.Write(body)
+		req.Header.Set("X-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
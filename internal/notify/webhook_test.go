@@ -0,0 +1,138 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestIsPublic(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{addr: "93.184.216.34", want: true},
+		{addr: "2606:2800:220:1:248:1893:25c8:1946", want: true},
+		{addr: "127.0.0.1"},
+		{addr: "10.1.2.3"},
+		{addr: "172.16.0.1"},
+		{addr: "192.168.1.1"},
+		{addr: "169.254.169.254"},
+		{addr: "100.64.0.1"},
+		{addr: "0.0.0.0"},
+		{addr: "0.1.2.3"},
+		{addr: "255.255.255.255"},
+		{addr: "224.0.0.1"},
+		{addr: "::1"},
+		{addr: "::"},
+		{addr: "fe80::1"},
+		{addr: "fd00::1"},
+		{addr: "::ffff:127.0.0.1"},
+		{addr: "::ffff:10.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			if got := isPublic(netip.MustParseAddr(tt.addr)); got != tt.want {
+				t.Errorf("isPublic(%s) = %v, want %v", tt.addr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhookRejectsInternalAddresses(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { hits.Add(1) }))
+	t.Cleanup(server.Close)
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{name: "http", url: "http://example.com/hook"},
+		{name: "loopback", url: server.URL},
+		{name: "localhost", url: "https://localhost:" + port},
+		{name: "metadata", url: "https://169.254.169.254/latest/meta-data"},
+		{name: "private", url: "https://10.0.0.1:" + port},
+	}
+
+	webhook := NewWebhook("", time.Second)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reminder := testReminder
+			reminder.WebhookURL = tt.url
+			if err := webhook.Notify(context.Background(), reminder); !errors.Is(err, ErrWebhookAddress) {
+				t.Fatalf("Notify() error = %v, want ErrWebhookAddress", err)
+			}
+		})
+	}
+	if hits.Load() != 0 {
+		t.Errorf("internal server received %d requests", hits.Load())
+	}
+}
+
+// localWebhook returns a webhook allowed to reach server, which is on the loopback interface
+func localWebhook(server *httptest.Server, secret string) Webhook {
+	webhook := NewWebhook(secret, time.Second)
+	transport := webhook.client.Transport.(*http.Transport)
+	transport.DialContext = (&net.Dialer{}).DialContext
+	transport.TLSClientConfig = server.Client().Transport.(*http.Transport).TLSClientConfig
+	return webhook
+}
+
+func TestWebhookNotify(t *testing.T) {
+	var body, signature string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body, signature = string(data), r.Header.Get("X-Signature-256")
+	}))
+	t.Cleanup(server.Close)
+
+	reminder := testReminder
+	reminder.WebhookURL = server.URL
+	if err := localWebhook(server, "secret").Notify(context.Background(), reminder); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	if !strings.Contains(body, `"service_name":"Netflix"`) || strings.Contains(body, "user@example.com") {
+		t.Errorf("body = %s", body)
+	}
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte(body))
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); signature != want {
+		t.Errorf("X-Signature-256 = %s, want %s", signature, want)
+	}
+}
+
+func TestWebhookDoesNotFollowRedirects(t *testing.T) {
+	var redirected atomic.Bool
+	mux := http.NewServeMux()
+	mux.HandleFunc("/hook", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/internal", http.StatusTemporaryRedirect)
+	})
+	mux.HandleFunc("/internal", func(http.ResponseWriter, *http.Request) { redirected.Store(true) })
+	server := httptest.NewTLSServer(mux)
+	t.Cleanup(server.Close)
+
+	reminder := testReminder
+	reminder.WebhookURL = server.URL + "/hook"
+	err := localWebhook(server, "").Notify(context.Background(), reminder)
+	if err == nil || !strings.Contains(err.Error(), "status 307") {
+		t.Fatalf("Notify() error = %v, want status 307", err)
+	}
+	if redirected.Load() {
+		t.Error("redirect was followed")
+	}
+}
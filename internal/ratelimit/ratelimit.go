@@ -0,0 +1,199 @@
+package ratelimit
+
+import (
+	"container/list"
+	"context"
+	"crypto/subtle"
+	"math"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/auth"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/config"
+)
+
+// Result describes the state of a client's bucket after a request
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration // Time until the bucket is full again
+	RetryAfter time.Duration // Time until the next request is allowed, zero if Allowed
+}
+
+type bucket struct {
+	key    string
+	tokens float64
+	last   time.Time
+}
+
+// Limiter is an in-memory token bucket limiter keyed by client.
+// Each bucket holds up to limit tokens and refills completely over period.
+// At most maxBuckets buckets are kept: a new client over the cap replaces the least recently used one,
+// so clients flooding new keys cannot throttle other clients, they only shorten how long idle buckets are kept.
+type Limiter struct {
+	limit      int
+	period     time.Duration
+	rate       float64 // tokens per second
+	maxBuckets int
+
+	mu        sync.Mutex
+	buckets   map[string]*list.Element
+	recent    *list.List // buckets by last use, most recent first
+	lastSweep time.Time
+}
+
+func New(limit int, period time.Duration, maxBuckets int) *Limiter {
+	return &Limiter{
+		limit:      limit,
+		period:     period,
+		rate:       float64(limit) / period.Seconds(),
+		maxBuckets: max(maxBuckets, 1),
+		buckets:    make(map[string]*list.Element),
+		recent:     list.New(),
+	}
+}
+
+// Allow takes a token from the bucket of key if one is available
+func (l *Limiter) Allow(key string) Result {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.sweep(now)
+
+	var b *bucket
+	if e, ok := l.buckets[key]; ok {
+		l.recent.MoveToFront(e)
+		b = e.Value.(*bucket)
+	} else {
+		if len(l.buckets) >= l.maxBuckets {
+			oldest := l.recent.Back()
+			l.recent.Remove(oldest)
+			delete(l.buckets, oldest.Value.(*bucket).key)
+		}
+		b = &bucket{key: key, tokens: float64(l.limit), last: now}
+		l.buckets[key] = l.recent.PushFront(b)
+	}
+	b.tokens = math.Min(float64(l.limit), b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	res := Result{Limit: l.limit}
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = l.duration(1 - b.tokens)
+	}
+	res.Remaining = int(b.tokens)
+	res.Reset = l.duration(float64(l.limit) - b.tokens)
+
+	return res
+}
+
+// sweep forgets buckets that have refilled completely, at most once per period
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.period {
+		return
+	}
+	l.lastSweep = now
+
+	for e := l.recent.Back(); e != nil; e = l.recent.Back() {
+		b := e.Value.(*bucket)
+		if now.Sub(b.last) < l.period {
+			return
+		}
+		l.recent.Remove(e)
+		delete(l.buckets, b.key)
+	}
+}
+
+func (l *Limiter) duration(tokens float64) time.Duration {
+	return time.Duration(tokens / l.rate * float64(time.Second))
+}
+
+// Limiters holds one Limiter per route. They are shared by the HTTP and gRPC APIs,
+// so a client has a single quota for a route whichever API it calls the route through.
+type Limiters struct {
+	defaultLimit config.RateLimit
+	routes       config.RouteRateLimits
+	maxBuckets   int
+
+	mu       sync.Mutex
+	limiters map[string]*Limiter
+}
+
+func NewLimiters(defaultLimit config.RateLimit, routes config.RouteRateLimits, maxBuckets int) *Limiters {
+	return &Limiters{
+		defaultLimit: defaultLimit,
+		routes:       routes,
+		maxBuckets:   maxBuckets,
+		limiters:     make(map[string]*Limiter),
+	}
+}
+
+// For returns the limiter of route, nil if the route is not limited
+func (ls *Limiters) For(route string) *Limiter {
+	rl := ls.defaultLimit
+	if routeLimit, ok := ls.routes[route]; ok {
+		rl = routeLimit
+	}
+	if rl.Requests == 0 {
+		return nil
+	}
+
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	l, ok := ls.limiters[route]
+	if !ok {
+		l = New(rl.Requests, rl.Period, ls.maxBuckets)
+		ls.limiters[route] = l
+	}
+	return l
+}
+
+// ClientIP returns the IP address of a client connected from remoteAddr, a host or host:port.
+// forwardedFor are the X-Forwarded-For values of the request, trustedProxies the number of proxies
+// in front of the service. Each proxy appends the address it received the request from, so the client is
+// the entry added by the first trusted proxy, counting from the right; entries to its left are set
+// by the client and ignored. Without trusted proxies X-Forwarded-For is not used.
+func ClientIP(remoteAddr string, forwardedFor []string, trustedProxies int) string {
+	if trustedProxies > 0 {
+		var hops []string
+		for _, value := range forwardedFor {
+			for hop := range strings.SplitSeq(value, ",") {
+				if hop = strings.TrimSpace(hop); hop != "" {
+					hops = append(hops, hop)
+				}
+			}
+		}
+		if len(hops) > 0 {
+			return hops[max(len(hops)-trustedProxies, 0)]
+		}
+	}
+
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
+
+// ClientKey identifies the client of a request for a Limiter: by apiKey if it is one of apiKeys,
+// otherwise by the authenticated caller and, when authentication is disabled, by ip.
+// Unknown API keys are ignored, so clients cannot get fresh buckets by making keys up.
+func ClientKey(ctx context.Context, apiKey string, apiKeys []string, ip string) string {
+	if apiKey != "" {
+		for _, known := range apiKeys {
+			if subtle.ConstantTimeCompare([]byte(apiKey), []byte(known)) == 1 {
+				return "key:" + known
+			}
+		}
+	}
+	if p, ok := auth.FromContext(ctx); ok {
+		return "user:" + p.UserID.String()
+	}
+	return "ip:" + ip
+}
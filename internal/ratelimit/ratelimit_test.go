@@ -0,0 +1,108 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/auth"
+)
+
+func TestAllow(t *testing.T) {
+	l := New(2, time.Hour, 10)
+
+	for i, want := range []bool{true, true, false} {
+		if res := l.Allow("a"); res.Allowed != want {
+			t.Fatalf("request %d: Allowed = %v, want %v", i, res.Allowed, want)
+		}
+	}
+	if res := l.Allow("b"); !res.Allowed || res.Remaining != 1 {
+		t.Fatalf("other client: %+v, want allowed with 1 remaining", res)
+	}
+}
+
+func TestAllowCapsBuckets(t *testing.T) {
+	l := New(2, time.Hour, 3)
+
+	for i := range 100 {
+		l.Allow(fmt.Sprintf("client-%d", i))
+	}
+	if len(l.buckets) != 3 || l.recent.Len() != 3 {
+		t.Fatalf("buckets = %d, want 3", len(l.buckets))
+	}
+
+	// A client flooding new keys does not throttle other new clients
+	if res := l.Allow("new-client"); !res.Allowed {
+		t.Fatal("new client over the cap was throttled")
+	}
+
+	// An active client keeps its bucket, the least recently used one is evicted
+	l.Allow("active")
+	l.Allow("active")
+	l.Allow("idle")
+	l.Allow("other")
+	if res := l.Allow("active"); res.Allowed {
+		t.Fatal("active client got a fresh bucket")
+	}
+	if _, ok := l.buckets["new-client"]; ok {
+		t.Fatal("least recently used bucket was kept")
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name           string
+		remoteAddr     string
+		forwardedFor   []string
+		trustedProxies int
+		want           string
+	}{
+		{name: "connection", remoteAddr: "203.0.113.7:51234", want: "203.0.113.7"},
+		{name: "connection without port", remoteAddr: "203.0.113.7", want: "203.0.113.7"},
+		{name: "forwarded for ignored without proxies", remoteAddr: "203.0.113.7:1", forwardedFor: []string{"1.2.3.4"}, want: "203.0.113.7"},
+		{name: "one proxy", remoteAddr: "10.0.0.2:1", forwardedFor: []string{"198.51.100.1"}, trustedProxies: 1, want: "198.51.100.1"},
+		{name: "spoofed entry", remoteAddr: "10.0.0.2:1", forwardedFor: []string{"1.2.3.4, 198.51.100.1"}, trustedProxies: 1, want: "198.51.100.1"},
+		{name: "spoofed header", remoteAddr: "10.0.0.2:1", forwardedFor: []string{"1.2.3.4", "198.51.100.1"}, trustedProxies: 1, want: "198.51.100.1"},
+		{name: "two proxies", remoteAddr: "10.0.0.3:1", forwardedFor: []string{"1.2.3.4, 198.51.100.1, 10.0.0.2"}, trustedProxies: 2, want: "198.51.100.1"},
+		{name: "fewer entries than proxies", remoteAddr: "10.0.0.3:1", forwardedFor: []string{"198.51.100.1"}, trustedProxies: 2, want: "198.51.100.1"},
+		{name: "no header behind a proxy", remoteAddr: "10.0.0.2:1", trustedProxies: 1, want: "10.0.0.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClientIP(tt.remoteAddr, tt.forwardedFor, tt.trustedProxies); got != tt.want {
+				t.Errorf("ClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientKey(t *testing.T) {
+	userID := uuid.New()
+	authenticated := auth.WithPrincipal(context.Background(), auth.Principal{UserID: userID})
+	apiKeys := []string{"integration-key"}
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		apiKey string
+		want   string
+	}{
+		{name: "known api key", ctx: authenticated, apiKey: "integration-key", want: "key:integration-key"},
+		{name: "unknown api key with user", ctx: authenticated, apiKey: "made-up", want: "user:" + userID.String()},
+		{name: "unknown api key without authentication", ctx: context.Background(), apiKey: "made-up", want: "ip:10.0.0.1"},
+		{name: "user", ctx: authenticated, want: "user:" + userID.String()},
+		{name: "ip", ctx: context.Background(), want: "ip:10.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClientKey(tt.ctx, tt.apiKey, apiKeys, "10.0.0.1"); got != tt.want {
+				t.Errorf("ClientKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Ping checks that a pooled connection to the database can be used
+func (r *SubscriptionRepository) Ping(ctx context.Context) error {
+	return r.pool.Ping(ctx)
+}
+
+// SchemaVersion returns the migration version recorded by golang-migrate
+func (r *SubscriptionRepository) SchemaVersion(ctx context.Context) (version uint, dirty bool, err error) {
+	query := `-- name: SchemaVersion
+		SELECT version, dirty FROM schema_migrations LIMIT 1`
+	err = r.pool.QueryRow(ctx, query).Scan(&version, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
+
+// CheckSchema returns a readiness check that fails while the schema is dirty or older than expected.
+// A newer schema is ready: during a rolling deploy the new replicas migrate while old ones still serve
+func (r *SubscriptionRepository) CheckSchema(expected uint) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		version, dirty, err := r.SchemaVersion(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to read schema version: %w", err)
+		}
+		return checkVersion(version, dirty, expected)
+	}
+}
+
+func checkVersion(version uint, dirty bool, expected uint) error {
+	if dirty {
+		return fmt.Errorf("schema version %d is dirty", version)
+	}
+	if version < expected {
+		return fmt.Errorf("schema version %d, expected at least %d", version, expected)
+	}
+	return nil
+}
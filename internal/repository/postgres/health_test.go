@@ -0,0 +1,28 @@
+package postgres
+
+import "testing"
+
+func TestCheckVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		version  uint
+		dirty    bool
+		expected uint
+		wantErr  bool
+	}{
+		{name: "expected", version: 16, expected: 16},
+		{name: "newer during a rolling deploy", version: 17, expected: 16},
+		{name: "older", version: 15, expected: 16, wantErr: true},
+		{name: "not migrated", version: 0, expected: 16, wantErr: true},
+		{name: "dirty", version: 16, dirty: true, expected: 16, wantErr: true},
+		{name: "dirty newer", version: 17, dirty: true, expected: 16, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkVersion(tt.version, tt.dirty, tt.expected); (err != nil) != tt.wantErr {
+				t.Errorf("checkVersion(%d, %v, %d) = %v, want error %v", tt.version, tt.dirty, tt.expected, err, tt.wantErr)
+			}
+		})
+	}
+}
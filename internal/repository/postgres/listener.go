@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/repository"
+)
+
+var _ repository.ChangeListener = (*SubscriptionRepository)(nil)
+
+// changesChannel is notified by the subscriptions_notify_change trigger
+const changesChannel = "subscription_changes"
+
+// changePayload is the notification payload built by notify_subscription_change()
+type changePayload struct {
+	Op              string     `json:"op"`
+	TenantID        string     `json:"tenant_id"`
+	ID              uuid.UUID  `json:"id"`
+	UserID          uuid.UUID  `json:"user_id"`
+	ServiceName     string     `json:"service_name"`
+	ServiceID       *uuid.UUID `json:"service_id"`
+	PlanID          *uuid.UUID `json:"plan_id"`
+	Price           int        `json:"price"`
+	CurrentPrice    int        `json:"current_price"`
+	StartDate       string     `json:"start_date"`
+	EndDate         *string    `json:"end_date"`
+	BillingDay      int        `json:"billing_day"`
+	PaymentMethodID *uuid.UUID `json:"payment_method_id"`
+	TrialMonths     int        `json:"trial_months"`
+	TrialPrice      int        `json:"trial_price"`
+	Paused          bool       `json:"paused"`
+	Category        string     `json:"category"`
+	AccountLabel    string     `json:"account_label"`
+	Tags            []string   `json:"tags"`
+	ChangedAt       time.Time  `json:"changed_at"`
+}
+
+// ListenSubscriptionChanges holds a pool connection listening for changes of all tenants.
+// It returns when ctx is canceled or the connection fails; callers are expected to reconnect.
+func (r *SubscriptionRepository) ListenSubscriptionChanges(ctx context.Context, fn func(repository.SubscriptionChange)) error {
+	pooled, err := r.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire listener connection: %w", err)
+	}
+	// The connection keeps listening to the channel, so it must not return to the pool
+	conn := pooled.Hijack()
+	defer conn.Close(context.WithoutCancel(ctx))
+
+	if _, err := conn.Exec(ctx, "-- name: ListenSubscriptionChanges\nLISTEN "+changesChannel); err != nil {
+		return fmt.Errorf("failed to listen for subscription changes: %w", err)
+	}
+	slog.Info("listening for subscription changes")
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to wait for subscription changes: %w", err)
+		}
+
+		change, err := parseChange(notification.Payload)
+		if err != nil {
+			slog.Error("invalid subscription change notification", "payload", notification.Payload, "error", err)
+			continue
+		}
+		fn(change)
+	}
+}
+
+func parseChange(payload string) (repository.SubscriptionChange, error) {
+	var p changePayload
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return repository.SubscriptionChange{}, err
+	}
+
+	change := repository.SubscriptionChange{
+		TenantID:  p.TenantID,
+		ChangedAt: p.ChangedAt,
+		Subscription: repository.Subscription{
+			ID:           p.ID,
+			ServiceName:  p.ServiceName,
+			Price:        p.Price,
+			CurrentPrice: p.CurrentPrice,
+			UserID:       p.UserID,
+			BillingDay:   p.BillingDay,
+			TrialMonths:  p.TrialMonths,
+			TrialPrice:   p.TrialPrice,
+			Paused:       p.Paused,
+			Category:     p.Category,
+			AccountLabel: p.AccountLabel,
+			Tags:         p.Tags,
+		},
+	}
+	switch strings.ToUpper(p.Op) {
+	case "INSERT":
+		change.Op = repository.ChangeCreated
+	case "UPDATE":
+		change.Op = repository.ChangeUpdated
+	case "DELETE":
+		change.Op = repository.ChangeDeleted
+	default:
+		return repository.SubscriptionChange{}, fmt.Errorf("unknown operation %q", p.Op)
+	}
+
+	if p.ServiceID != nil {
+		change.Subscription.ServiceID = uuid.NullUUID{UUID: *p.ServiceID, Valid: true}
+	}
+	if p.PlanID != nil {
+		change.Subscription.PlanID = uuid.NullUUID{UUID: *p.PlanID, Valid: true}
+	}
+	if p.PaymentMethodID != nil {
+		change.Subscription.PaymentMethodID = uuid.NullUUID{UUID: *p.PaymentMethodID, Valid: true}
+	}
+
+	var err error
+	if change.Subscription.StartDate, err = time.Parse(time.DateOnly, p.StartDate); err != nil {
+		return repository.SubscriptionChange{}, fmt.Errorf("invalid start_date: %w", err)
+	}
+	if p.EndDate != nil {
+		if change.Subscription.EndDate.Time, err = time.Parse(time.DateOnly, *p.EndDate); err != nil {
+			return repository.SubscriptionChange{}, fmt.Errorf("invalid end_date: %w", err)
+		}
+		change.Subscription.EndDate.Valid = true
+	}
+
+	return change, nil
+}
@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/config"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/migrations"
+
+	_ "github.com/golang-migrate/migrate/v4/database/postgres" // PostgreSQL driver for golang-migrate
+)
+
+// migrationLockID is the advisory lock key held while migrating, so that only one replica migrates at a time
+const migrationLockID int64 = 0x73756273 // "subs"
+
+// Migrator applies the migrations embedded into the binary
+type Migrator struct {
+	dsn string
+	m   *migrate.Migrate
+}
+
+func NewMigrator(cfg config.DBConfig) (*Migrator, error) {
+	src, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open embedded migrations: %w", err)
+	}
+
+	dsn := connString(cfg)
+	m, err := migrate.NewWithSourceInstance("iofs", src, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migrate instance: %w", err)
+	}
+
+	return &Migrator{dsn: dsn, m: m}, nil
+}
+
+func (mg *Migrator) Close() error {
+	srcErr, dbErr := mg.m.Close()
+	return errors.Join(srcErr, dbErr)
+}
+
+// Up applies all pending migrations
+func (mg *Migrator) Up(ctx context.Context) error {
+	return mg.withLock(ctx, func() error {
+		err := mg.m.Up()
+		if errors.Is(err, migrate.ErrNoChange) {
+			slog.Info("no new database migrations to apply")
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to run migrations: %w", err)
+		}
+		slog.Info("database migrations applied successfully")
+		return nil
+	})
+}
+
+// Down rolls back the given number of migrations
+func (mg *Migrator) Down(ctx context.Context, steps int) error {
+	return mg.withLock(ctx, func() error {
+		err := mg.m.Steps(-steps)
+		if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+			return fmt.Errorf("failed to roll back migrations: %w", err)
+		}
+		return nil
+	})
+}
+
+// Force sets the schema version without running migrations, clearing the dirty flag
+func (mg *Migrator) Force(ctx context.Context, version int) error {
+	return mg.withLock(ctx, func() error {
+		if err := mg.m.Force(version); err != nil {
+			return fmt.Errorf("failed to force version %d: %w", version, err)
+		}
+		return nil
+	})
+}
+
+// Version returns the applied schema version, zero if no migration was applied
+func (mg *Migrator) Version() (version uint, dirty bool, err error) {
+	version, dirty, err = mg.m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
+
+// withLock runs fn while holding the migration advisory lock on a dedicated connection
+func (mg *Migrator) withLock(ctx context.Context, fn func() error) error {
+	conn, err := pgx.Connect(ctx, mg.dsn)
+	if err != nil {
+		return fmt.Errorf("failed to connect for migration lock: %w", err)
+	}
+	defer conn.Close(context.Background())
+
+	slog.Info("waiting for migration lock")
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", migrationLockID); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		if _, err := conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockID); err != nil {
+			slog.Error("failed to release migration lock", "error", err)
+		}
+	}()
+
+	return fn()
+}
+
+// LatestMigrationVersion returns the highest version among the embedded migrations
+func LatestMigrationVersion() (uint, error) {
+	src, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		return 0, fmt.Errorf("failed to open embedded migrations: %w", err)
+	}
+	defer src.Close()
+
+	return lastVersion(src)
+}
+
+func lastVersion(src source.Driver) (uint, error) {
+	version, err := src.First()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	for {
+		next, err := src.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read migrations: %w", err)
+		}
+		version = next
+	}
+}
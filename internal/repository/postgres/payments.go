@@ -0,0 +1,161 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/repository"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/logger"
+)
+
+// paymentMethodName is the display name of payment method pm: its label or the brand and last digits of a card
+const paymentMethodName = "COALESCE(NULLIF(pm.label, ''), concat_ws(' ', NULLIF(pm.brand, ''), '*' || pm.last4))"
+
+const paymentMethodColumns = "pm.id, pm.user_id, pm.kind, pm.label, pm.brand, COALESCE(pm.last4, ''), pm.expires, " +
+	paymentMethodName + ", pm.created_at"
+
+// paymentMethodFields returns scan destinations for paymentMethodColumns
+func paymentMethodFields(method *repository.PaymentMethod) []any {
+	return []any{&method.ID, &method.UserID, &method.Kind, &method.Label, &method.Brand, &method.Last4, &method.Expires,
+		&method.Name, &method.CreatedAt}
+}
+
+// nullLast4 stores missing last digits as NULL, so the table check accepts bank accounts
+func nullLast4(method repository.PaymentMethod) *string {
+	if method.Last4 == "" {
+		return nil
+	}
+	return &method.Last4
+}
+
+func (r *SubscriptionRepository) CreatePaymentMethod(ctx context.Context, method repository.PaymentMethod) (repository.PaymentMethod, error) {
+	query := `-- name: CreatePaymentMethod
+		WITH pm AS (
+			INSERT INTO payment_methods (user_id, kind, label, brand, last4, expires) VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING *
+		)
+		SELECT ` + paymentMethodColumns + ` FROM pm`
+
+	err := r.inTenantTx(ctx, func(tx pgx.Tx) error {
+		row := tx.QueryRow(ctx, query, method.UserID, method.Kind, method.Label, method.Brand, nullLast4(method),
+			method.Expires)
+		if err := row.Scan(paymentMethodFields(&method)...); err != nil {
+			return fmt.Errorf("failed to insert payment method: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return repository.PaymentMethod{}, err
+	}
+
+	logger.FromContext(ctx).DebugContext(ctx, "payment method created", "id", method.ID, "user_id", method.UserID)
+	return method, nil
+}
+
+func (r *SubscriptionRepository) GetPaymentMethod(ctx context.Context, id uuid.UUID) (repository.PaymentMethod, error) {
+	query := `-- name: GetPaymentMethod
+		SELECT ` + paymentMethodColumns + ` FROM payment_methods pm WHERE pm.id = $1`
+
+	var method repository.PaymentMethod
+	err := r.inTenantTx(ctx, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx, query, id).Scan(paymentMethodFields(&method)...)
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repository.PaymentMethod{}, repository.ErrPaymentMethodNotFound
+		}
+		return repository.PaymentMethod{}, err
+	}
+	return method, nil
+}
+
+func (r *SubscriptionRepository) ListPaymentMethods(ctx context.Context, userID *uuid.UUID) ([]repository.PaymentMethod, error) {
+	query := `-- name: ListPaymentMethods
+		SELECT ` + paymentMethodColumns + ` FROM payment_methods pm
+		WHERE $1::uuid IS NULL OR pm.user_id = $1
+		ORDER BY pm.user_id, pm.created_at`
+
+	var methods []repository.PaymentMethod
+	err := r.inTenantTx(ctx, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, query, userID)
+		if err != nil {
+			return fmt.Errorf("failed to query payment methods: %w", err)
+		}
+		methods, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (repository.PaymentMethod, error) {
+			var method repository.PaymentMethod
+			err := row.Scan(paymentMethodFields(&method)...)
+			return method, err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scan payment methods: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).DebugContext(ctx, "payment methods fetched", "user_id", userID, "methods", len(methods))
+	return methods, nil
+}
+
+func (r *SubscriptionRepository) UpdatePaymentMethod(ctx context.Context, method repository.PaymentMethod) (repository.PaymentMethod, error) {
+	query := `-- name: UpdatePaymentMethod
+		WITH pm AS (
+			UPDATE payment_methods SET kind = $2, label = $3, brand = $4, last4 = $5, expires = $6 WHERE id = $1
+			RETURNING *
+		)
+		SELECT ` + paymentMethodColumns + ` FROM pm`
+
+	err := r.inTenantTx(ctx, func(tx pgx.Tx) error {
+		row := tx.QueryRow(ctx, query, method.ID, method.Kind, method.Label, method.Brand, nullLast4(method),
+			method.Expires)
+		if err := row.Scan(paymentMethodFields(&method)...); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return repository.ErrPaymentMethodNotFound
+			}
+			return fmt.Errorf("failed to update payment method: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return repository.PaymentMethod{}, err
+	}
+
+	logger.FromContext(ctx).DebugContext(ctx, "payment method updated", "id", method.ID)
+	return method, nil
+}
+
+func (r *SubscriptionRepository) DeletePaymentMethod(ctx context.Context, id uuid.UUID, replacementID uuid.NullUUID) error {
+	moveQuery := `-- name: MovePaymentMethodSubscriptions
+		UPDATE subscriptions SET payment_method_id = $2 WHERE payment_method_id = $1`
+	// Subscriptions left on the method are unlinked by the foreign key
+	deleteQuery := `-- name: DeletePaymentMethod
+		DELETE FROM payment_methods WHERE id = $1`
+
+	err := r.inTenantTx(ctx, func(tx pgx.Tx) error {
+		if replacementID.Valid {
+			if _, err := tx.Exec(ctx, moveQuery, id, replacementID); err != nil {
+				return fmt.Errorf("failed to move subscriptions to replacement payment method: %w", err)
+			}
+		}
+		tag, err := tx.Exec(ctx, deleteQuery, id)
+		if err != nil {
+			return fmt.Errorf("failed to delete payment method: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return repository.ErrPaymentMethodNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx).DebugContext(ctx, "payment method deleted", "id", id, "replacement_id", replacementID)
+	return nil
+}
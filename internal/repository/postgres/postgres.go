@@ -4,36 +4,72 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"github.com/golang-migrate/migrate/v4"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"log/slog"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/config"
 	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/repository"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/tenant"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/logger"
 
 	"github.com/jackc/pgx/v5/pgxpool"
-
-	_ "github.com/golang-migrate/migrate/v4/database/postgres" // PostgreSQL driver for golang-migrate
-	_ "github.com/golang-migrate/migrate/v4/source/file"       // File source for golang-migrate
 )
 
 // Статическая проверка что SubscriptionRepository реализует repository.SubscriptionRepository
 var _ repository.SubscriptionRepository = (*SubscriptionRepository)(nil)
 
+// tenantRole подчиняется RLS-политикам, в отличие от суперпользователя и владельца таблицы
+const tenantRole = "subscription_tenant"
+
+// exclusionViolation is reported when a subscription overlaps another one of the same user, service and account
+const exclusionViolation = "23P01"
+
+// subscriptionColumns are the columns of repository.Subscription in the order scanned by scanSubscription,
+// they are selected from the subscriptions table without an alias
+const subscriptionColumns = "id, service_name, service_id, plan_id, price, " +
+	`COALESCE((SELECT c.price FROM subscription_plan_changes c
+		WHERE c.subscription_id = subscriptions.id AND c.start_date <= date_trunc('month', now())
+		ORDER BY c.start_date DESC LIMIT 1), price) AS current_price, ` +
+	"user_id, start_date, end_date, billing_day, trial_months, trial_price, " +
+	`EXISTS (SELECT 1 FROM subscription_pauses p
+		WHERE p.subscription_id = subscriptions.id
+		  AND p.start_date <= date_trunc('month', now())
+		  AND (p.end_date IS NULL OR p.end_date >= date_trunc('month', now()))) AS paused, category, account_label, ` +
+	`ARRAY(SELECT t.name FROM subscription_tags st JOIN tags t ON t.id = st.tag_id
+		WHERE st.subscription_id = subscriptions.id ORDER BY t.name) AS tags, payment_method_id`
+
+// scheduledEndDate returns the end date of a subscription selected from table, or the last month of its pending
+// cancellation if that is earlier, NULL while the subscription is open-ended. Forecasts end subscriptions there.
+func scheduledEndDate(table string) string {
+	return fmt.Sprintf(`LEAST(%[1]s.end_date, (SELECT min(sc.month) FROM subscription_scheduled_changes sc
+		WHERE sc.subscription_id = %[1]s.id AND sc.status = 'pending' AND sc.action = 'cancel'))`, table)
+}
+
+// hasTagsCondition returns a condition that the subscription with ID idColumn has all tags passed as the parameter argID
+func hasTagsCondition(idColumn string, argID int) string {
+	return fmt.Sprintf(`NOT EXISTS (SELECT 1 FROM unnest($%d::text[]) AS f(name)
+		WHERE NOT EXISTS (SELECT 1 FROM subscription_tags st JOIN tags t ON t.id = st.tag_id
+			WHERE st.subscription_id = %s AND t.name = f.name))`, argID, idColumn)
+}
+
 // SubscriptionRepository postgres реализация repository.SubscriptionRepository
 type SubscriptionRepository struct {
 	pool *pgxpool.Pool
 }
 
 func New(ctx context.Context, cfg config.DBConfig) (SubscriptionRepository, error) {
-	dsn := fmt.Sprintf(
-		"postgres://%s:%s@%s:%d/%s?sslmode=disable",
-		cfg.User, cfg.Pass, cfg.Host, cfg.Port, cfg.Name,
-	)
-	pool, err := pgxpool.New(ctx, dsn)
+	poolCfg, err := pgxpool.ParseConfig(connString(cfg))
+	if err != nil {
+		return SubscriptionRepository{}, fmt.Errorf("failed to parse database config: %w", err)
+	}
+	poolCfg.ConnConfig.Tracer = newQueryTracer()
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
 	if err != nil {
 		return SubscriptionRepository{}, fmt.Errorf("failed to connect to database: %w", err)
 	}
@@ -43,56 +79,169 @@ func New(ctx context.Context, cfg config.DBConfig) (SubscriptionRepository, erro
 	}
 	slog.Info("connected to postgres database")
 
-	migrationPath := "file://./migrations" // Path to your migration files (relative to where the app runs)
-	m, err := migrate.New(migrationPath, dsn)
-	if err != nil {
-		pool.Close() // Close the pool if migration instance creation fails
-		return SubscriptionRepository{}, fmt.Errorf("failed to create migrate instance: %w", err)
+	if !cfg.AutoMigrate {
+		slog.Info("automatic migrations disabled")
+		return SubscriptionRepository{pool: pool}, nil
 	}
 
-	slog.Info("Running database migrations...")
-	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
-		pool.Close() // Close the pool on migration error
-		return SubscriptionRepository{}, fmt.Errorf("failed to run migrations: %w", err)
+	migrator, err := NewMigrator(cfg)
+	if err != nil {
+		pool.Close()
+		return SubscriptionRepository{}, err
 	}
-	if errors.Is(err, migrate.ErrNoChange) {
-		slog.Info("No new database migrations to apply.")
-	} else {
-		slog.Info("Database migrations applied successfully.")
+	defer migrator.Close()
+
+	slog.Info("running database migrations")
+	if err := migrator.Up(ctx); err != nil {
+		pool.Close()
+		return SubscriptionRepository{}, err
 	}
 
 	return SubscriptionRepository{pool: pool}, nil
 }
 
+func connString(cfg config.DBConfig) string {
+	return fmt.Sprintf(
+		"postgres://%s:%s@%s:%d/%s?sslmode=disable",
+		cfg.User, cfg.Pass, cfg.Host, cfg.Port, cfg.Name,
+	)
+}
+
 func (r *SubscriptionRepository) Close() {
 	r.pool.Close()
 	slog.Info("disconnected from postgres database")
 }
 
+// Stat returns connection pool statistics
+func (r *SubscriptionRepository) Stat() *pgxpool.Stat {
+	return r.pool.Stat()
+}
+
+// SubscriptionStats aggregates subscriptions active in the current month over all tenants.
+// It runs as the connecting role, so it is not restricted to a single tenant; only totals leave it.
+func (r *SubscriptionRepository) SubscriptionStats(ctx context.Context) (repository.SubscriptionStats, error) {
+	query := `-- name: SubscriptionStats
+		SELECT COUNT(*), COALESCE(SUM(COALESCE((
+			SELECT c.price FROM subscription_plan_changes c
+			WHERE c.subscription_id = s.id AND c.start_date <= date_trunc('month', now())
+			ORDER BY c.start_date DESC LIMIT 1), s.price)), 0)
+		FROM subscriptions s
+		WHERE s.start_date <= date_trunc('month', now())
+		  AND (s.end_date IS NULL OR s.end_date >= date_trunc('month', now()))
+		  AND NOT EXISTS (
+			SELECT 1 FROM subscription_pauses p
+			WHERE p.subscription_id = s.id
+			  AND p.start_date <= date_trunc('month', now())
+			  AND (p.end_date IS NULL OR p.end_date >= date_trunc('month', now()))
+		  )`
+
+	var stats repository.SubscriptionStats
+	if err := r.pool.QueryRow(ctx, query).Scan(&stats.Active, &stats.MonthlySpend); err != nil {
+		return repository.SubscriptionStats{}, fmt.Errorf("failed to query subscription stats: %w", err)
+	}
+	return stats, nil
+}
+
+// inTenantTx runs fn in a transaction restricted by RLS to the tenant stored in ctx
+func (r *SubscriptionRepository) inTenantTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	tenantID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return repository.ErrTenantRequired
+	}
+
+	return pgx.BeginTxFunc(ctx, r.pool, pgx.TxOptions{}, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, "-- name: SetTenantRole\nSET LOCAL ROLE "+tenantRole); err != nil {
+			return fmt.Errorf("failed to set tenant role: %w", err)
+		}
+		// SET LOCAL does not accept parameters, set_config with is_local = true is equivalent
+		if _, err := tx.Exec(ctx, "-- name: SetTenant\nSELECT set_config('app.tenant_id', $1, true)", tenantID); err != nil {
+			return fmt.Errorf("failed to set tenant: %w", err)
+		}
+		return fn(tx)
+	})
+}
+
+// subscriptionFields returns scan destinations for subscriptionColumns
+func subscriptionFields(sub *repository.Subscription) []any {
+	return []any{&sub.ID, &sub.ServiceName, &sub.ServiceID, &sub.PlanID, &sub.Price, &sub.CurrentPrice, &sub.UserID,
+		&sub.StartDate, &sub.EndDate, &sub.BillingDay, &sub.TrialMonths, &sub.TrialPrice, &sub.Paused, &sub.Category, &sub.AccountLabel, &sub.Tags,
+		&sub.PaymentMethodID}
+}
+
+func scanSubscription(row pgx.Row, sub *repository.Subscription) error {
+	return row.Scan(subscriptionFields(sub)...)
+}
+
+// addTags creates missing tags of the tenant and attaches them to the subscription
+func addTags(ctx context.Context, tx pgx.Tx, subscriptionID uuid.UUID, tags []string) error {
+	createQuery := `-- name: CreateTags
+		INSERT INTO tags (name) SELECT unnest($1::text[]) ON CONFLICT (tenant_id, name) DO NOTHING`
+	attachQuery := `-- name: AttachTags
+		INSERT INTO subscription_tags (subscription_id, tag_id)
+		SELECT $1, id FROM tags WHERE name = ANY($2)
+		ON CONFLICT DO NOTHING`
+
+	if len(tags) == 0 {
+		return nil
+	}
+	if _, err := tx.Exec(ctx, createQuery, tags); err != nil {
+		return fmt.Errorf("failed to create tags: %w", err)
+	}
+	if _, err := tx.Exec(ctx, attachQuery, subscriptionID, tags); err != nil {
+		return fmt.Errorf("failed to attach tags: %w", err)
+	}
+	return nil
+}
+
+// removeTags detaches tags from the subscription, the tags themselves are kept
+func removeTags(ctx context.Context, tx pgx.Tx, subscriptionID uuid.UUID, tags []string) error {
+	query := `-- name: DetachTags
+		DELETE FROM subscription_tags st USING tags t
+		WHERE st.tag_id = t.id AND st.subscription_id = $1 AND t.name = ANY($2)`
+
+	if len(tags) == 0 {
+		return nil
+	}
+	if _, err := tx.Exec(ctx, query, subscriptionID, tags); err != nil {
+		return fmt.Errorf("failed to detach tags: %w", err)
+	}
+	return nil
+}
+
 func (r *SubscriptionRepository) CreateSubscription(ctx context.Context, sub repository.Subscription) (uuid.UUID, error) {
-	query := `INSERT INTO subscriptions (service_name, price, user_id, start_date, end_date)
-                  VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	query := `-- name: CreateSubscription
+		INSERT INTO subscriptions (service_name, service_id, plan_id, price, user_id, start_date, end_date, billing_day, trial_months, trial_price, category, account_label, payment_method_id)
+                  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id`
 	var id uuid.UUID
-	err := r.pool.QueryRow(ctx, query, sub.ServiceName, sub.Price, sub.UserID, sub.StartDate, sub.EndDate).Scan(&id)
+	err := r.inTenantTx(ctx, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, query, sub.ServiceName, sub.ServiceID, sub.PlanID, sub.Price, sub.UserID, sub.StartDate,
+			sub.EndDate, sub.BillingDay, sub.TrialMonths, sub.TrialPrice, sub.Category, sub.AccountLabel, sub.PaymentMethodID).Scan(&id)
+		if err != nil {
+			return err
+		}
+		return addTags(ctx, tx, id, sub.Tags)
+	})
 	if err != nil {
 		var pgxError *pgconn.PgError
 		if errors.As(err, &pgxError) {
-			// Unique constrain failed
-			if pgxError.Code == "23505" {
+			if pgxError.Code == exclusionViolation {
 				return id, repository.ErrSubscriptionAlreadyExists
 			}
 		}
 		return id, err
 	}
 
-	slog.Debug("subscription created", "id", id.String(), "user_id", sub.UserID)
+	logger.FromContext(ctx).DebugContext(ctx, "subscription created", "id", id.String(), "user_id", sub.UserID)
 	return id, nil
 }
 
 func (r *SubscriptionRepository) GetSubscriptionByID(ctx context.Context, id uuid.UUID) (repository.Subscription, error) {
-	query := `SELECT id, service_name, price, user_id, start_date, end_date FROM subscriptions WHERE id = $1`
+	query := `-- name: GetSubscriptionByID
+		SELECT ` + subscriptionColumns + ` FROM subscriptions WHERE id = $1`
 	sub := repository.Subscription{}
-	err := r.pool.QueryRow(ctx, query, id).Scan(&sub.ID, &sub.ServiceName, &sub.Price, &sub.UserID, &sub.StartDate, &sub.EndDate)
+	err := r.inTenantTx(ctx, func(tx pgx.Tx) error {
+		return scanSubscription(tx.QueryRow(ctx, query, id), &sub)
+	})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return repository.Subscription{}, repository.ErrSubscriptionNotFound
@@ -100,61 +249,89 @@ func (r *SubscriptionRepository) GetSubscriptionByID(ctx context.Context, id uui
 		return repository.Subscription{}, err
 	}
 
-	slog.Debug("subscription found", "subscription", sub)
+	logger.FromContext(ctx).DebugContext(ctx, "subscription found", "subscription", sub)
 	return sub, nil
 }
 
 func (r *SubscriptionRepository) ListSubscriptions(ctx context.Context, pagination repository.SubscriptionPagination) ([]repository.Subscription, error) {
 	var builder strings.Builder
 	args := make([]any, 0, 1)
+	argID := 1
 
-	builder.WriteString("SELECT id, service_name, price, user_id, start_date, end_date FROM subscriptions ")
+	builder.WriteString("-- name: ListSubscriptions\n")
+	builder.WriteString("SELECT " + subscriptionColumns + " FROM subscriptions WHERE TRUE ")
 
+	if pagination.UserID != nil {
+		builder.WriteString(fmt.Sprintf(`AND (user_id = $%[1]d OR EXISTS (SELECT 1 FROM subscription_members sm
+			WHERE sm.subscription_id = subscriptions.id AND sm.user_id = $%[1]d)) `, argID))
+		args = append(args, *pagination.UserID)
+		argID++
+	}
+	if pagination.Tags != nil {
+		builder.WriteString("AND " + hasTagsCondition("subscriptions.id", argID) + " ")
+		args = append(args, pagination.Tags)
+		argID++
+	}
 	if pagination.Cursor != nil {
-		builder.WriteString("WHERE (start_date, id) > ($2, $3) ")
+		builder.WriteString(fmt.Sprintf("AND (start_date, id) > ($%d, $%d) ", argID, argID+1))
 		args = append(args, pagination.Cursor.StartDate, pagination.Cursor.ID)
+		argID += 2
 	}
 
-	builder.WriteString("ORDER BY start_date, id LIMIT $1")
+	builder.WriteString(fmt.Sprintf("ORDER BY start_date, id LIMIT $%d", argID))
 	args = append(args, pagination.Limit)
 
-	rows, err := r.pool.Query(ctx, builder.String(), args...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query subscriptions: %w", err)
-	}
-
 	subs := make([]repository.Subscription, 0, pagination.Limit)
-	for rows.Next() {
-		var sub repository.Subscription
-		err := rows.Scan(&sub.ID, &sub.ServiceName, &sub.Price, &sub.UserID, &sub.StartDate, &sub.EndDate)
+	err := r.inTenantTx(ctx, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, builder.String(), args...)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan subscription: %w", err)
+			return fmt.Errorf("failed to query subscriptions: %w", err)
 		}
-		subs = append(subs, sub)
-	}
+		defer rows.Close()
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("failed to scan subscriptions: %w", err)
+		for rows.Next() {
+			var sub repository.Subscription
+			if err := scanSubscription(rows, &sub); err != nil {
+				return fmt.Errorf("failed to scan subscription: %w", err)
+			}
+			subs = append(subs, sub)
+		}
+
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to scan subscriptions: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	slog.Debug("subscriptions fetched")
+	logger.FromContext(ctx).DebugContext(ctx, "subscriptions fetched")
 	return subs, nil
 }
 
 func (r *SubscriptionRepository) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM subscriptions WHERE id = $1`
-	_, err := r.pool.Exec(ctx, query, id)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
+	query := `-- name: DeleteSubscription
+		DELETE FROM subscriptions WHERE id = $1`
+	err := r.inTenantTx(ctx, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, query, id)
+		if err != nil {
+			return fmt.Errorf("failed to delete subscription: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
 			return repository.ErrSubscriptionNotFound
 		}
-		return fmt.Errorf("failed to delete subscription: %w", err)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
-	slog.Debug("subscription deleted", "id", id)
+	logger.FromContext(ctx).DebugContext(ctx, "subscription deleted", "id", id)
 	return nil
 }
 func (r *SubscriptionRepository) UpdateSubscription(ctx context.Context, id uuid.UUID, fields repository.SubscriptionUpdate) (repository.Subscription, error) {
 	var builder strings.Builder
+	builder.WriteString("-- name: UpdateSubscription\n")
 	builder.WriteString("UPDATE subscriptions SET ")
 
 	args := make([]any, 0, 1)
@@ -165,6 +342,16 @@ func (r *SubscriptionRepository) UpdateSubscription(ctx context.Context, id uuid
 		args = append(args, *fields.ServiceName)
 		argCounter++
 	}
+	if fields.ServiceID != nil {
+		builder.WriteString(fmt.Sprintf("service_id = $%d, ", argCounter))
+		args = append(args, *fields.ServiceID)
+		argCounter++
+	}
+	if fields.PlanID != nil {
+		builder.WriteString(fmt.Sprintf("plan_id = $%d, ", argCounter))
+		args = append(args, *fields.PlanID)
+		argCounter++
+	}
 	if fields.Price != nil {
 		builder.WriteString(fmt.Sprintf("price = $%d, ", argCounter))
 		args = append(args, *fields.Price)
@@ -175,22 +362,62 @@ func (r *SubscriptionRepository) UpdateSubscription(ctx context.Context, id uuid
 		args = append(args, *fields.EndDate)
 		argCounter++
 	}
+	if fields.BillingDay != nil {
+		builder.WriteString(fmt.Sprintf("billing_day = $%d, ", argCounter))
+		args = append(args, *fields.BillingDay)
+		argCounter++
+	}
+	if fields.TrialMonths != nil {
+		builder.WriteString(fmt.Sprintf("trial_months = $%d, ", argCounter))
+		args = append(args, *fields.TrialMonths)
+		argCounter++
+	}
+	if fields.TrialPrice != nil {
+		builder.WriteString(fmt.Sprintf("trial_price = $%d, ", argCounter))
+		args = append(args, *fields.TrialPrice)
+		argCounter++
+	}
+	if fields.Category != nil {
+		builder.WriteString(fmt.Sprintf("category = $%d, ", argCounter))
+		args = append(args, *fields.Category)
+		argCounter++
+	}
+	if fields.AccountLabel != nil {
+		builder.WriteString(fmt.Sprintf("account_label = $%d, ", argCounter))
+		args = append(args, *fields.AccountLabel)
+		argCounter++
+	}
+	if fields.PaymentMethodID != nil {
+		builder.WriteString(fmt.Sprintf("payment_method_id = $%d, ", argCounter))
+		args = append(args, *fields.PaymentMethodID)
+		argCounter++
+	}
+	// The row is updated even if only tags change, so the change is notified and the result is returned
+	if len(args) == 0 {
+		builder.WriteString("id = id, ")
+	}
 
 	// Remove the trailing comma and space
 	sql := builder.String()[:builder.Len()-2]
 
-	sql += fmt.Sprintf(" WHERE id = $%d RETURNING id, service_name, price, user_id, start_date, end_date", argCounter)
+	sql += fmt.Sprintf(" WHERE id = $%d RETURNING %s", argCounter, subscriptionColumns)
 	args = append(args, id)
 
 	var updatedSub repository.Subscription
-	err := r.pool.QueryRow(ctx, sql, args...).Scan(
-		&updatedSub.ID,
-		&updatedSub.ServiceName,
-		&updatedSub.Price,
-		&updatedSub.UserID,
-		&updatedSub.StartDate,
-		&updatedSub.EndDate,
-	)
+	err := r.inTenantTx(ctx, func(tx pgx.Tx) error {
+		if fields.AddTags != nil || fields.RemoveTags != nil {
+			if err := lockSubscription(ctx, tx, id); err != nil {
+				return err
+			}
+			if err := removeTags(ctx, tx, id, fields.RemoveTags); err != nil {
+				return err
+			}
+			if err := addTags(ctx, tx, id, fields.AddTags); err != nil {
+				return err
+			}
+		}
+		return scanSubscription(tx.QueryRow(ctx, sql, args...), &updatedSub)
+	})
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -198,57 +425,547 @@ func (r *SubscriptionRepository) UpdateSubscription(ctx context.Context, id uuid
 		}
 		var pgxError *pgconn.PgError
 		if errors.As(err, &pgxError) {
-			if pgxError.Code == "23505" {
+			if pgxError.Code == exclusionViolation {
 				return repository.Subscription{}, repository.ErrSubscriptionAlreadyExists
 			}
 		}
 		return repository.Subscription{}, fmt.Errorf("failed to update subscription: %w", err)
 	}
 
-	slog.Debug("subscription updated", "subscription", updatedSub)
+	logger.FromContext(ctx).DebugContext(ctx, "subscription updated", "subscription", updatedSub)
 	return updatedSub, nil
 }
 
-func (r *SubscriptionRepository) GetTotalCostWithFilters(ctx context.Context, filter repository.SubscriptionFilter) (int, error) {
-	query := `
-		SELECT SUM(
-			price * (
-				EXTRACT(YEAR FROM age(LEAST(end_date, $1), GREATEST(start_date, $2))) * 12 +
-				EXTRACT(MONTH FROM age(LEAST(end_date, $1), GREATEST(start_date, $2)))
-			)
-		)
-		FROM subscriptions
-		WHERE TRUE`
-
-	args := make([]any, 0, 1)
-	argID := 1
-
-	if filter.UserID != nil {
-		query += fmt.Sprintf(" AND user_id = $%d", argID)
-		args = append(args, *filter.UserID)
+// memberShare is the amount a member pays of the month amount of "months" row b, sh is a row of "shares"
+const memberShare = `(CASE b.split_rule
+		WHEN 'percent' THEN b.amount::bigint * sh.share / 100
+		WHEN 'fixed' THEN CASE WHEN sh.total_share <= b.amount THEN sh.share
+			ELSE b.amount::bigint * sh.share / sh.total_share END
+		ELSE b.amount / (sh.members + 1)
+	END)::int`
+
+// billedMonthsQuery builds a "billed" CTE with a row per subscription, month it is billed for within the filter period
+// and user paying for it. Both period bounds are inclusive; open-ended subscriptions are billed up to the period end
+// or the current month, pending cancellations end them earlier. Months of the trial period are billed at the trial price,
+// other months at the price of the latest plan change or pending price change made by the month or at the subscription
+// price before any change. Paused months are not billed.
+// Members of a shared subscription pay their shares of a month and the owner pays the rest,
+// only the owner's rows carry the payment method of the subscription.
+// Subscriptions matched to the catalog are reported under the canonical service name.
+// A tags filter keeps subscriptions having all of the tags, user filters keep the rows of the users.
+func billedMonthsQuery(filter repository.SubscriptionFilter) (string, []any) {
+	var builder strings.Builder
+	builder.WriteString(`WITH months AS (
+		SELECT s.id, COALESCE(c.name, s.service_name) AS service_name, s.user_id, s.category, s.split_rule,
+			s.payment_method_id, m.month::date AS month,
+			CASE WHEN m.month < s.start_date + make_interval(months => s.trial_months)
+				THEN s.trial_price
+				ELSE COALESCE((SELECT c.price FROM subscription_prices c
+					WHERE c.subscription_id = s.id AND c.start_date <= m.month
+					ORDER BY c.start_date DESC LIMIT 1), s.price)
+			END AS amount
+		FROM subscriptions s
+		LEFT JOIN services c ON c.id = s.service_id
+		CROSS JOIN LATERAL generate_series(
+			GREATEST(s.start_date, $1::date),
+			LEAST(` + scheduledEndDate("s") + `, COALESCE($2::date, date_trunc('month', now())::date)),
+			interval '1 month'
+		) AS m(month)
+		WHERE NOT EXISTS (
+			SELECT 1 FROM subscription_pauses p
+			WHERE p.subscription_id = s.id
+			  AND m.month >= p.start_date
+			  AND (p.end_date IS NULL OR m.month <= p.end_date)
+		)`)
+
+	args := []any{filter.StartDate, filter.EndDate}
+	argID := 3
+
+	if filter.ServiceID != nil {
+		builder.WriteString(fmt.Sprintf(" AND s.service_id = $%d", argID))
+		args = append(args, *filter.ServiceID)
 		argID++
 	}
 	if filter.ServiceName != nil {
-		query += fmt.Sprintf(" AND service_name ILIKE $%d", argID)
+		builder.WriteString(fmt.Sprintf(` AND (s.service_name ILIKE $%[1]d OR c.name ILIKE $%[1]d
+			OR EXISTS (SELECT 1 FROM unnest(c.aliases) AS a WHERE a ILIKE $%[1]d))`, argID))
 		args = append(args, "%"+*filter.ServiceName+"%")
 		argID++
 	}
-	if filter.StartDate != nil {
-		query += fmt.Sprintf(" AND start_date >= $%d", argID)
-		args = append(args, *filter.StartDate)
+	if filter.Category != nil {
+		builder.WriteString(fmt.Sprintf(" AND lower(s.category) = lower($%d)", argID))
+		args = append(args, *filter.Category)
+		argID++
+	}
+	if filter.Tags != nil {
+		builder.WriteString(" AND " + hasTagsCondition("s.id", argID))
+		args = append(args, filter.Tags)
+		argID++
+	}
+
+	builder.WriteString(`
+	), shares AS (
+		SELECT sm.subscription_id, sm.user_id, sm.share,
+			COUNT(*) OVER w AS members, SUM(sm.share) OVER w AS total_share
+		FROM subscription_members sm
+		WHERE sm.subscription_id IN (SELECT id FROM months)
+		WINDOW w AS (PARTITION BY sm.subscription_id)
+	), billed AS (
+		SELECT b.id, b.service_name, p.user_id, b.category, b.month, p.amount,
+			CASE WHEN p.user_id = b.user_id THEN b.payment_method_id END AS payment_method_id
+		FROM months b
+		CROSS JOIN LATERAL (
+			SELECT sh.user_id, ` + memberShare + ` FROM shares sh WHERE sh.subscription_id = b.id
+			UNION ALL
+			SELECT b.user_id, b.amount - COALESCE((
+				SELECT SUM(` + memberShare + `) FROM shares sh WHERE sh.subscription_id = b.id), 0)::int
+		) AS p(user_id, amount)
+		WHERE TRUE`)
+
+	if filter.UserID != nil {
+		builder.WriteString(fmt.Sprintf(" AND p.user_id = $%d", argID))
+		args = append(args, *filter.UserID)
 		argID++
 	}
-	if filter.EndDate != nil {
-		query += fmt.Sprintf(" AND end_date <= $%d", argID)
-		args = append(args, *filter.EndDate)
+	if filter.UserIDs != nil {
+		builder.WriteString(fmt.Sprintf(" AND p.user_id = ANY($%d)", argID))
+		args = append(args, filter.UserIDs)
 	}
+	builder.WriteString(")\n")
+
+	return builder.String(), args
+}
+
+func (r *SubscriptionRepository) GetTotalCostWithFilters(ctx context.Context, filter repository.SubscriptionFilter) (int, error) {
+	billed, args := billedMonthsQuery(filter)
+	query := "-- name: GetTotalCostWithFilters\n" + billed + "SELECT COALESCE(SUM(amount), 0) FROM billed"
 
 	var totalCost int
-	err := r.pool.QueryRow(ctx, query, args...).Scan(&totalCost)
+	err := r.inTenantTx(ctx, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx, query, args...).Scan(&totalCost)
+	})
 	if err != nil {
 		return 0, err
 	}
 
-	slog.Debug("total cost with filters calculated", "total_cost", totalCost, "filter", filter)
+	logger.FromContext(ctx).DebugContext(ctx, "total cost with filters calculated", "total_cost", totalCost, "filter", filter)
 	return totalCost, nil
 }
+
+// costGroupKeys maps a cost group to its key expression, joins and result ordering over the "billed" CTE
+var costGroupKeys = map[repository.CostGroup]struct{ key, join, order string }{
+	repository.CostGroupService:  {key: "service_name", order: "SUM(amount) DESC, service_name"},
+	repository.CostGroupUser:     {key: "user_id::text", order: "SUM(amount) DESC, user_id::text"},
+	repository.CostGroupMonth:    {key: "to_char(month, 'MM-YYYY')", order: "min(month)"},
+	repository.CostGroupCategory: {key: "category", order: "SUM(amount) DESC, category"},
+	repository.CostGroupPaymentMethod: {
+		// Only the name columns are joined, so user_id stays the column of billed
+		key:   "COALESCE(" + paymentMethodName + ", '')",
+		join:  " LEFT JOIN (SELECT id, label, brand, last4 FROM payment_methods) pm ON pm.id = billed.payment_method_id",
+		order: "SUM(amount) DESC, COALESCE(" + paymentMethodName + ", '')",
+	},
+	repository.CostGroupTag: {
+		key: "COALESCE(t.name, '')",
+		join: " LEFT JOIN subscription_tags st ON st.subscription_id = billed.id" +
+			" LEFT JOIN tags t ON t.id = st.tag_id",
+		order: "SUM(amount) DESC, COALESCE(t.name, '')",
+	},
+}
+
+func (r *SubscriptionRepository) GetCostBreakdown(ctx context.Context, filter repository.SubscriptionFilter, group repository.CostGroup) ([]repository.CostBreakdownRow, error) {
+	keys, ok := costGroupKeys[group]
+	if !ok {
+		return nil, fmt.Errorf("unknown cost group %q", group)
+	}
+	billed, args := billedMonthsQuery(filter)
+	query := "-- name: GetCostBreakdown\n" + billed + fmt.Sprintf(
+		"SELECT %s, SUM(amount) FROM billed%s GROUP BY 1 ORDER BY %s", keys.key, keys.join, keys.order)
+
+	var rows []repository.CostBreakdownRow
+	err := r.inTenantTx(ctx, func(tx pgx.Tx) error {
+		result, err := tx.Query(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to query cost breakdown: %w", err)
+		}
+		rows, err = pgx.CollectRows(result, func(row pgx.CollectableRow) (repository.CostBreakdownRow, error) {
+			var item repository.CostBreakdownRow
+			err := row.Scan(&item.Key, &item.TotalCost)
+			return item, err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scan cost breakdown: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).DebugContext(ctx, "cost breakdown calculated", "group", group, "groups", len(rows), "filter", filter)
+	return rows, nil
+}
+
+func (r *SubscriptionRepository) GetCostBreakdownByUser(ctx context.Context, filter repository.SubscriptionFilter, group repository.CostGroup) ([]repository.UserCostBreakdownRow, error) {
+	keys, ok := costGroupKeys[group]
+	if !ok {
+		return nil, fmt.Errorf("unknown cost group %q", group)
+	}
+	billed, args := billedMonthsQuery(filter)
+	query := "-- name: GetCostBreakdownByUser\n" + billed + fmt.Sprintf(
+		"SELECT user_id, %s, SUM(amount) FROM billed%s GROUP BY 1, 2 ORDER BY 1, %s", keys.key, keys.join, keys.order)
+
+	var rows []repository.UserCostBreakdownRow
+	err := r.inTenantTx(ctx, func(tx pgx.Tx) error {
+		result, err := tx.Query(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to query cost breakdown by user: %w", err)
+		}
+		rows, err = pgx.CollectRows(result, func(row pgx.CollectableRow) (repository.UserCostBreakdownRow, error) {
+			var item repository.UserCostBreakdownRow
+			err := row.Scan(&item.UserID, &item.Key, &item.TotalCost)
+			return item, err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scan cost breakdown by user: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).DebugContext(ctx, "cost breakdown by user calculated", "group", group, "rows", len(rows), "filter", filter)
+	return rows, nil
+}
+
+func (r *SubscriptionRepository) ListSubscriptionsByUserIDs(ctx context.Context, userIDs []uuid.UUID) ([]repository.Subscription, error) {
+	query := `-- name: ListSubscriptionsByUserIDs
+		SELECT ` + subscriptionColumns + ` FROM subscriptions
+		WHERE user_id = ANY($1) ORDER BY user_id, start_date, id`
+
+	var subs []repository.Subscription
+	err := r.inTenantTx(ctx, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, query, userIDs)
+		if err != nil {
+			return fmt.Errorf("failed to query subscriptions by users: %w", err)
+		}
+		subs, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (repository.Subscription, error) {
+			var sub repository.Subscription
+			err := scanSubscription(row, &sub)
+			return sub, err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scan subscriptions: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).DebugContext(ctx, "subscriptions by users fetched", "users", len(userIDs), "subscriptions", len(subs))
+	return subs, nil
+}
+
+func (r *SubscriptionRepository) ListTrialsEnding(ctx context.Context, filter repository.TrialEndingFilter) ([]repository.Subscription, error) {
+	query := `-- name: ListTrialsEnding
+		SELECT ` + subscriptionColumns + ` FROM subscriptions
+		WHERE trial_months > 0
+		  AND start_date + make_interval(months => trial_months) = $1::date
+		  AND (end_date IS NULL OR end_date >= $1::date)
+		  AND ($2::uuid IS NULL OR user_id = $2)
+		ORDER BY start_date, id`
+
+	var subs []repository.Subscription
+	err := r.inTenantTx(ctx, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, query, filter.Month, filter.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to query ending trials: %w", err)
+		}
+		subs, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (repository.Subscription, error) {
+			var sub repository.Subscription
+			err := scanSubscription(row, &sub)
+			return sub, err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scan subscriptions: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).DebugContext(ctx, "ending trials fetched", "month", filter.Month, "subscriptions", len(subs))
+	return subs, nil
+}
+
+func (r *SubscriptionRepository) ListBilledSubscriptions(ctx context.Context, filter repository.BilledFilter) ([]repository.BilledSubscription, error) {
+	query := `-- name: ListBilledSubscriptions
+		SELECT ` + subscriptionColumns + `,
+			CASE WHEN $1::date < start_date + make_interval(months => trial_months)
+				THEN trial_price
+				ELSE COALESCE((SELECT c.price FROM subscription_prices c
+					WHERE c.subscription_id = subscriptions.id AND c.start_date <= $1::date
+					ORDER BY c.start_date DESC LIMIT 1), price)
+			END AS amount
+		FROM subscriptions
+		WHERE start_date <= $1::date
+		  AND COALESCE(` + scheduledEndDate("subscriptions") + `, $1::date) >= $1::date
+		  AND NOT EXISTS (
+			SELECT 1 FROM subscription_pauses p
+			WHERE p.subscription_id = subscriptions.id
+			  AND p.start_date <= $1::date
+			  AND (p.end_date IS NULL OR p.end_date >= $1::date)
+		  )
+		  AND ($2::uuid IS NULL OR user_id = $2)
+		ORDER BY user_id, start_date, id`
+
+	var subs []repository.BilledSubscription
+	err := r.inTenantTx(ctx, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, query, filter.Month, filter.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to query billed subscriptions: %w", err)
+		}
+		subs, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (repository.BilledSubscription, error) {
+			var sub repository.BilledSubscription
+			err := row.Scan(append(subscriptionFields(&sub.Subscription), &sub.Amount)...)
+			return sub, err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scan subscriptions: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).DebugContext(ctx, "billed subscriptions fetched", "month", filter.Month, "subscriptions", len(subs))
+	return subs, nil
+}
+
+// lockSubscription locks the subscription row until the end of tx, so concurrent changes of its pauses
+// and plan changes are serialized
+func lockSubscription(ctx context.Context, tx pgx.Tx, id uuid.UUID) error {
+	query := `-- name: LockSubscription
+		SELECT id FROM subscriptions WHERE id = $1 FOR UPDATE`
+	if err := tx.QueryRow(ctx, query, id).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repository.ErrSubscriptionNotFound
+		}
+		return fmt.Errorf("failed to lock subscription: %w", err)
+	}
+	return nil
+}
+
+func (r *SubscriptionRepository) PauseSubscription(ctx context.Context, pause repository.SubscriptionPause) (repository.SubscriptionPause, error) {
+	overlapQuery := `-- name: PauseOverlaps
+		SELECT EXISTS (
+			SELECT 1 FROM subscription_pauses
+			WHERE subscription_id = $1
+			  AND (end_date IS NULL OR end_date >= $2::date)
+			  AND ($3::date IS NULL OR start_date <= $3::date)
+		)`
+	insertQuery := `-- name: PauseSubscription
+		INSERT INTO subscription_pauses (subscription_id, start_date, end_date) VALUES ($1, $2, $3) RETURNING id`
+
+	err := r.inTenantTx(ctx, func(tx pgx.Tx) error {
+		if err := lockSubscription(ctx, tx, pause.SubscriptionID); err != nil {
+			return err
+		}
+
+		var overlaps bool
+		if err := tx.QueryRow(ctx, overlapQuery, pause.SubscriptionID, pause.StartDate, pause.EndDate).Scan(&overlaps); err != nil {
+			return fmt.Errorf("failed to check pause overlap: %w", err)
+		}
+		if overlaps {
+			return repository.ErrSubscriptionPaused
+		}
+
+		if err := tx.QueryRow(ctx, insertQuery, pause.SubscriptionID, pause.StartDate, pause.EndDate).Scan(&pause.ID); err != nil {
+			return fmt.Errorf("failed to insert pause: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return repository.SubscriptionPause{}, err
+	}
+
+	logger.FromContext(ctx).DebugContext(ctx, "subscription paused", "pause", pause)
+	return pause, nil
+}
+
+func (r *SubscriptionRepository) ResumeSubscription(ctx context.Context, subscriptionID uuid.UUID, month time.Time) error {
+	findQuery := `-- name: FindPause
+		SELECT id, start_date FROM subscription_pauses
+		WHERE subscription_id = $1
+		  AND start_date <= $2::date
+		  AND (end_date IS NULL OR end_date >= $2::date)`
+	endQuery := `-- name: EndPause
+		UPDATE subscription_pauses SET end_date = ($2::date - interval '1 month')::date WHERE id = $1`
+	deleteQuery := `-- name: DeletePause
+		DELETE FROM subscription_pauses WHERE id = $1`
+
+	err := r.inTenantTx(ctx, func(tx pgx.Tx) error {
+		if err := lockSubscription(ctx, tx, subscriptionID); err != nil {
+			return err
+		}
+
+		var (
+			pauseID   uuid.UUID
+			startDate time.Time
+		)
+		if err := tx.QueryRow(ctx, findQuery, subscriptionID, month).Scan(&pauseID, &startDate); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return repository.ErrSubscriptionNotPaused
+			}
+			return fmt.Errorf("failed to find pause: %w", err)
+		}
+
+		// A pause starting in the resumed month never takes effect
+		if !startDate.Before(month) {
+			if _, err := tx.Exec(ctx, deleteQuery, pauseID); err != nil {
+				return fmt.Errorf("failed to delete pause: %w", err)
+			}
+			return nil
+		}
+		if _, err := tx.Exec(ctx, endQuery, pauseID, month); err != nil {
+			return fmt.Errorf("failed to end pause: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx).DebugContext(ctx, "subscription resumed", "id", subscriptionID, "month", month)
+	return nil
+}
+
+func (r *SubscriptionRepository) ChangeSubscriptionPlan(ctx context.Context, change repository.PlanChange) (repository.PlanChange, error) {
+	query := `-- name: ChangeSubscriptionPlan
+		INSERT INTO subscription_plan_changes (subscription_id, plan_id, price, start_date) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (subscription_id, start_date) DO UPDATE SET plan_id = EXCLUDED.plan_id, price = EXCLUDED.price
+		RETURNING id`
+
+	err := r.inTenantTx(ctx, func(tx pgx.Tx) error {
+		if err := lockSubscription(ctx, tx, change.SubscriptionID); err != nil {
+			return err
+		}
+		err := tx.QueryRow(ctx, query, change.SubscriptionID, change.PlanID, change.Price, change.StartDate).Scan(&change.ID)
+		if err != nil {
+			return fmt.Errorf("failed to insert plan change: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return repository.PlanChange{}, err
+	}
+
+	logger.FromContext(ctx).DebugContext(ctx, "subscription plan changed", "change", change)
+	return change, nil
+}
+
+func (r *SubscriptionRepository) ListPlanChanges(ctx context.Context, subscriptionID uuid.UUID) ([]repository.PlanChange, error) {
+	query := `-- name: ListPlanChanges
+		SELECT id, subscription_id, plan_id, price, start_date FROM subscription_plan_changes
+		WHERE subscription_id = $1 ORDER BY start_date`
+
+	var changes []repository.PlanChange
+	err := r.inTenantTx(ctx, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, query, subscriptionID)
+		if err != nil {
+			return fmt.Errorf("failed to query plan changes: %w", err)
+		}
+		changes, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (repository.PlanChange, error) {
+			var change repository.PlanChange
+			err := row.Scan(&change.ID, &change.SubscriptionID, &change.PlanID, &change.Price, &change.StartDate)
+			return change, err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scan plan changes: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).DebugContext(ctx, "plan changes fetched", "subscription_id", subscriptionID, "changes", len(changes))
+	return changes, nil
+}
+
+func (r *SubscriptionRepository) GetSubscriptionSplit(ctx context.Context, subscriptionID uuid.UUID) (repository.SubscriptionSplit, error) {
+	ruleQuery := `-- name: GetSplitRule
+		SELECT split_rule FROM subscriptions WHERE id = $1`
+	membersQuery := `-- name: ListSubscriptionMembers
+		SELECT user_id, share FROM subscription_members WHERE subscription_id = $1 ORDER BY user_id`
+
+	split := repository.SubscriptionSplit{SubscriptionID: subscriptionID}
+	err := r.inTenantTx(ctx, func(tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx, ruleQuery, subscriptionID).Scan(&split.Rule); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return repository.ErrSubscriptionNotFound
+			}
+			return fmt.Errorf("failed to get split rule: %w", err)
+		}
+		rows, err := tx.Query(ctx, membersQuery, subscriptionID)
+		if err != nil {
+			return fmt.Errorf("failed to query subscription members: %w", err)
+		}
+		split.Members, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (repository.SubscriptionMember, error) {
+			var member repository.SubscriptionMember
+			err := row.Scan(&member.UserID, &member.Share)
+			return member, err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scan subscription members: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return repository.SubscriptionSplit{}, err
+	}
+
+	logger.FromContext(ctx).DebugContext(ctx, "subscription split fetched", "subscription_id", subscriptionID,
+		"rule", split.Rule, "members", len(split.Members))
+	return split, nil
+}
+
+func (r *SubscriptionRepository) SetSubscriptionSplit(ctx context.Context, split repository.SubscriptionSplit) (repository.SubscriptionSplit, error) {
+	ruleQuery := `-- name: SetSplitRule
+		UPDATE subscriptions SET split_rule = $2 WHERE id = $1`
+	deleteQuery := `-- name: DeleteSubscriptionMembers
+		DELETE FROM subscription_members WHERE subscription_id = $1`
+	insertQuery := `-- name: InsertSubscriptionMember
+		INSERT INTO subscription_members (subscription_id, user_id, share) VALUES ($1, $2, $3)`
+
+	err := r.inTenantTx(ctx, func(tx pgx.Tx) error {
+		if err := lockSubscription(ctx, tx, split.SubscriptionID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, ruleQuery, split.SubscriptionID, split.Rule); err != nil {
+			return fmt.Errorf("failed to set split rule: %w", err)
+		}
+		if _, err := tx.Exec(ctx, deleteQuery, split.SubscriptionID); err != nil {
+			return fmt.Errorf("failed to delete subscription members: %w", err)
+		}
+		for _, member := range split.Members {
+			if _, err := tx.Exec(ctx, insertQuery, split.SubscriptionID, member.UserID, member.Share); err != nil {
+				return fmt.Errorf("failed to insert subscription member: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return repository.SubscriptionSplit{}, err
+	}
+
+	slices.SortFunc(split.Members, func(a, b repository.SubscriptionMember) int {
+		return strings.Compare(a.UserID.String(), b.UserID.String())
+	})
+	logger.FromContext(ctx).DebugContext(ctx, "subscription split set", "subscription_id", split.SubscriptionID,
+		"rule", split.Rule, "members", len(split.Members))
+	return split, nil
+}
@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/repository"
+)
+
+func TestBilledMonthsQueryPeriod(t *testing.T) {
+	start := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC)
+
+	query, args := billedMonthsQuery(repository.SubscriptionFilter{StartDate: &start, EndDate: &end})
+
+	if len(args) < 2 || args[0] != &start || args[1] != &end {
+		t.Fatalf("billedMonthsQuery() args = %v, want the period bounds first", args)
+	}
+	// A row per month from the later of the subscription and period starts to the earlier of their ends,
+	// generate_series includes both bounds, an open period ends with the current month
+	for _, want := range []string{
+		"GREATEST(s.start_date, $1::date)",
+		"LEAST(" + scheduledEndDate("s") + ", COALESCE($2::date, date_trunc('month', now())::date))",
+		"interval '1 month'",
+	} {
+		if !strings.Contains(query, want) {
+			t.Errorf("billedMonthsQuery() query does not contain %q", want)
+		}
+	}
+}
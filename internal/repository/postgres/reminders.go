@@ -0,0 +1,153 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/repository"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/tenant"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/logger"
+)
+
+var _ repository.ReminderStore = (*SubscriptionRepository)(nil)
+
+func (r *SubscriptionRepository) GetNotificationPreferences(ctx context.Context, userID uuid.UUID) (repository.NotificationPreferences, error) {
+	query := `-- name: GetNotificationPreferences
+		SELECT user_id, enabled, days_before, channel, email, webhook_url FROM notification_preferences WHERE user_id = $1`
+
+	var prefs repository.NotificationPreferences
+	err := r.inTenantTx(ctx, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx, query, userID).Scan(&prefs.UserID, &prefs.Enabled, &prefs.DaysBefore, &prefs.Channel,
+			&prefs.Email, &prefs.WebhookURL)
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		prefs = repository.DefaultNotificationPreferences
+		prefs.UserID = userID
+		return prefs, nil
+	}
+	if err != nil {
+		return repository.NotificationPreferences{}, fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+	return prefs, nil
+}
+
+func (r *SubscriptionRepository) SetNotificationPreferences(ctx context.Context, prefs repository.NotificationPreferences) (repository.NotificationPreferences, error) {
+	query := `-- name: SetNotificationPreferences
+		INSERT INTO notification_preferences (user_id, enabled, days_before, channel, email, webhook_url)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (tenant_id, user_id) DO UPDATE
+		SET enabled = excluded.enabled, days_before = excluded.days_before, channel = excluded.channel,
+			email = excluded.email, webhook_url = excluded.webhook_url, updated_at = now()`
+
+	err := r.inTenantTx(ctx, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, query, prefs.UserID, prefs.Enabled, prefs.DaysBefore, prefs.Channel, prefs.Email, prefs.WebhookURL)
+		return err
+	})
+	if err != nil {
+		return repository.NotificationPreferences{}, fmt.Errorf("failed to set notification preferences: %w", err)
+	}
+
+	logger.FromContext(ctx).DebugContext(ctx, "notification preferences set", "user_id", prefs.UserID, "channel", prefs.Channel)
+	return prefs, nil
+}
+
+// ListDueReminders runs as the connecting role, so it is not restricted to a single tenant.
+// The next charge of a subscription is on its billing day of this month, or of the next month if that day has passed.
+// A subscription whose last month precedes the month of the next charge gets an ending reminder,
+// a subscription billed a positive amount in that month gets a renewal reminder unless the month is paused.
+// Pending cancellations and price changes are taken into account as if they were applied.
+func (r *SubscriptionRepository) ListDueReminders(ctx context.Context, today time.Time) ([]repository.DueReminder, error) {
+	query := `-- name: ListDueReminders
+		SELECT d.tenant_id, d.id, d.user_id, d.service_name, d.kind, d.date, d.amount,
+			COALESCE(np.enabled, $2), COALESCE(np.days_before, $3), COALESCE(np.channel, $4),
+			COALESCE(np.email, ''), COALESCE(np.webhook_url, '')
+		FROM (
+			SELECT s.tenant_id, s.id, s.user_id, s.service_name, next.month, billing_date(next.month, s.billing_day) AS date,
+				CASE WHEN e.end_date < next.month THEN 'ending' ELSE 'renewal' END AS kind,
+				CASE
+					WHEN e.end_date < next.month THEN 0
+					WHEN next.month < s.start_date + make_interval(months => s.trial_months) THEN s.trial_price
+					ELSE COALESCE((SELECT c.price FROM subscription_prices c
+						WHERE c.subscription_id = s.id AND c.start_date <= next.month
+						ORDER BY c.start_date DESC LIMIT 1), s.price)
+				END AS amount
+			FROM subscriptions s
+			CROSS JOIN LATERAL (SELECT CASE
+				WHEN billing_date($1::date, s.billing_day) >= $1::date THEN date_trunc('month', $1::date)::date
+				ELSE (date_trunc('month', $1::date) + interval '1 month')::date
+			END AS month) next
+			CROSS JOIN LATERAL (SELECT ` + scheduledEndDate("s") + ` AS end_date) e
+			WHERE s.start_date <= next.month
+			  AND (e.end_date IS NULL OR e.end_date >= next.month - interval '1 month')
+		) d
+		LEFT JOIN notification_preferences np ON np.tenant_id = d.tenant_id AND np.user_id = d.user_id
+		WHERE COALESCE(np.enabled, $2)
+		  AND d.date - COALESCE(np.days_before, $3) <= $1::date
+		  AND (d.kind = 'ending' OR (d.amount > 0 AND NOT EXISTS (
+			SELECT 1 FROM subscription_pauses p
+			WHERE p.subscription_id = d.id
+			  AND p.start_date <= d.month
+			  AND (p.end_date IS NULL OR p.end_date >= d.month)
+		  )))
+		  AND NOT EXISTS (
+			SELECT 1 FROM reminder_log l WHERE l.subscription_id = d.id AND l.kind = d.kind AND l.due_date = d.date
+		  )
+		ORDER BY d.tenant_id, d.user_id, d.id`
+
+	defaults := repository.DefaultNotificationPreferences
+	rows, err := r.pool.Query(ctx, query, today, defaults.Enabled, defaults.DaysBefore, defaults.Channel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query due reminders: %w", err)
+	}
+	reminders, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (repository.DueReminder, error) {
+		var d repository.DueReminder
+		err := row.Scan(&d.TenantID, &d.SubscriptionID, &d.UserID, &d.ServiceName, &d.Kind, &d.Date, &d.Amount,
+			&d.Preferences.Enabled, &d.Preferences.DaysBefore, &d.Preferences.Channel, &d.Preferences.Email,
+			&d.Preferences.WebhookURL)
+		d.Preferences.UserID = d.UserID
+		return d, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan due reminders: %w", err)
+	}
+
+	slog.DebugContext(ctx, "due reminders fetched", "today", today.Format(time.DateOnly), "reminders", len(reminders))
+	return reminders, nil
+}
+
+func (r *SubscriptionRepository) ClaimReminder(ctx context.Context, reminder repository.DueReminder) (bool, error) {
+	query := `-- name: ClaimReminder
+		INSERT INTO reminder_log (subscription_id, kind, due_date, channel) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (subscription_id, kind, due_date) DO NOTHING`
+
+	var claimed bool
+	err := r.inTenantTx(tenant.WithID(ctx, reminder.TenantID), func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, query, reminder.SubscriptionID, reminder.Kind, reminder.Date, reminder.Preferences.Channel)
+		claimed = tag.RowsAffected() == 1
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to claim reminder: %w", err)
+	}
+	return claimed, nil
+}
+
+func (r *SubscriptionRepository) ReleaseReminder(ctx context.Context, reminder repository.DueReminder) error {
+	query := `-- name: ReleaseReminder
+		DELETE FROM reminder_log WHERE subscription_id = $1 AND kind = $2 AND due_date = $3`
+
+	err := r.inTenantTx(tenant.WithID(ctx, reminder.TenantID), func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, query, reminder.SubscriptionID, reminder.Kind, reminder.Date)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to release reminder: %w", err)
+	}
+	return nil
+}
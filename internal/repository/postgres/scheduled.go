@@ -0,0 +1,194 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/repository"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/tenant"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/logger"
+)
+
+var _ repository.ScheduledChangeStore = (*SubscriptionRepository)(nil)
+
+const scheduledChangeColumns = "id, subscription_id, action, month, COALESCE(price, 0), status, created_at, applied_at"
+
+// scheduledChangeFields returns scan destinations for scheduledChangeColumns
+func scheduledChangeFields(change *repository.ScheduledChange) []any {
+	return []any{&change.ID, &change.SubscriptionID, &change.Action, &change.Month, &change.Price, &change.Status,
+		&change.CreatedAt, &change.AppliedAt}
+}
+
+func (r *SubscriptionRepository) ScheduleChange(ctx context.Context, change repository.ScheduledChange) (repository.ScheduledChange, error) {
+	query := `-- name: ScheduleChange
+		INSERT INTO subscription_scheduled_changes (subscription_id, action, month, price) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (subscription_id, action, month) WHERE status = 'pending' DO UPDATE SET price = EXCLUDED.price
+		RETURNING ` + scheduledChangeColumns
+
+	// Only price changes store a price
+	var price *int
+	if change.Action == repository.ScheduledPrice {
+		price = &change.Price
+	}
+
+	err := r.inTenantTx(ctx, func(tx pgx.Tx) error {
+		if err := lockSubscription(ctx, tx, change.SubscriptionID); err != nil {
+			return err
+		}
+		row := tx.QueryRow(ctx, query, change.SubscriptionID, change.Action, change.Month, price)
+		if err := row.Scan(scheduledChangeFields(&change)...); err != nil {
+			return fmt.Errorf("failed to insert scheduled change: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return repository.ScheduledChange{}, err
+	}
+
+	logger.FromContext(ctx).DebugContext(ctx, "subscription change scheduled", "change", change)
+	return change, nil
+}
+
+func (r *SubscriptionRepository) ListScheduledChanges(ctx context.Context, subscriptionID uuid.UUID) ([]repository.ScheduledChange, error) {
+	query := `-- name: ListScheduledChanges
+		SELECT ` + scheduledChangeColumns + ` FROM subscription_scheduled_changes
+		WHERE subscription_id = $1 ORDER BY month, created_at`
+
+	var changes []repository.ScheduledChange
+	err := r.inTenantTx(ctx, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, query, subscriptionID)
+		if err != nil {
+			return fmt.Errorf("failed to query scheduled changes: %w", err)
+		}
+		changes, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (repository.ScheduledChange, error) {
+			var change repository.ScheduledChange
+			err := row.Scan(scheduledChangeFields(&change)...)
+			return change, err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scan scheduled changes: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).DebugContext(ctx, "scheduled changes fetched", "subscription_id", subscriptionID, "changes", len(changes))
+	return changes, nil
+}
+
+func (r *SubscriptionRepository) CancelScheduledChange(ctx context.Context, subscriptionID, changeID uuid.UUID) error {
+	query := `-- name: CancelScheduledChange
+		UPDATE subscription_scheduled_changes SET status = 'canceled'
+		WHERE id = $1 AND subscription_id = $2 AND status = 'pending'`
+
+	err := r.inTenantTx(ctx, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, query, changeID, subscriptionID)
+		if err != nil {
+			return fmt.Errorf("failed to cancel scheduled change: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return repository.ErrScheduledChangeNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx).DebugContext(ctx, "scheduled change canceled", "subscription_id", subscriptionID, "id", changeID)
+	return nil
+}
+
+// ListDueScheduledChanges runs as the connecting role, so it is not restricted to a single tenant
+func (r *SubscriptionRepository) ListDueScheduledChanges(ctx context.Context, month time.Time) ([]repository.DueScheduledChange, error) {
+	query := `-- name: ListDueScheduledChanges
+		SELECT tenant_id, ` + scheduledChangeColumns + ` FROM subscription_scheduled_changes
+		WHERE status = 'pending'
+		  AND ((action = 'price' AND month <= $1::date) OR (action = 'cancel' AND month < $1::date))
+		ORDER BY tenant_id, month, created_at`
+
+	rows, err := r.pool.Query(ctx, query, month)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query due scheduled changes: %w", err)
+	}
+	changes, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (repository.DueScheduledChange, error) {
+		var change repository.DueScheduledChange
+		err := row.Scan(append([]any{&change.TenantID}, scheduledChangeFields(&change.ScheduledChange)...)...)
+		return change, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan due scheduled changes: %w", err)
+	}
+
+	slog.DebugContext(ctx, "due scheduled changes fetched", "month", month.Format(time.DateOnly), "changes", len(changes))
+	return changes, nil
+}
+
+// ApplyScheduledChange marks the change applied and changes the subscription in one transaction.
+// A cancellation sets the end date unless the subscription already ends earlier and drops pending changes after it,
+// a price change adds a plan change keeping the plan in effect in its month.
+func (r *SubscriptionRepository) ApplyScheduledChange(ctx context.Context, change repository.DueScheduledChange) (bool, error) {
+	claimQuery := `-- name: ClaimScheduledChange
+		UPDATE subscription_scheduled_changes SET status = 'applied', applied_at = now()
+		WHERE id = $1 AND status = 'pending'`
+	cancelQuery := `-- name: ApplyScheduledCancel
+		UPDATE subscriptions SET end_date = $2 WHERE id = $1 AND (end_date IS NULL OR end_date > $2)`
+	dropLaterQuery := `-- name: CancelLaterScheduledChanges
+		UPDATE subscription_scheduled_changes SET status = 'canceled'
+		WHERE subscription_id = $1 AND status = 'pending' AND month > $2`
+	priceQuery := `-- name: ApplyScheduledPrice
+		INSERT INTO subscription_plan_changes (subscription_id, plan_id, price, start_date)
+		SELECT s.id,
+			CASE WHEN EXISTS (SELECT 1 FROM subscription_plan_changes c WHERE c.subscription_id = s.id AND c.start_date <= $2)
+				THEN (SELECT c.plan_id FROM subscription_plan_changes c
+					WHERE c.subscription_id = s.id AND c.start_date <= $2
+					ORDER BY c.start_date DESC LIMIT 1)
+				ELSE s.plan_id
+			END,
+			$3, $2
+		FROM subscriptions s WHERE s.id = $1
+		ON CONFLICT (subscription_id, start_date) DO UPDATE SET price = EXCLUDED.price`
+
+	var applied bool
+	err := r.inTenantTx(tenant.WithID(ctx, change.TenantID), func(tx pgx.Tx) error {
+		if err := lockSubscription(ctx, tx, change.SubscriptionID); err != nil {
+			return err
+		}
+		tag, err := tx.Exec(ctx, claimQuery, change.ID)
+		if err != nil {
+			return fmt.Errorf("failed to claim scheduled change: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return nil
+		}
+		applied = true
+
+		switch change.Action {
+		case repository.ScheduledCancel:
+			if _, err := tx.Exec(ctx, cancelQuery, change.SubscriptionID, change.Month); err != nil {
+				return fmt.Errorf("failed to end subscription: %w", err)
+			}
+			if _, err := tx.Exec(ctx, dropLaterQuery, change.SubscriptionID, change.Month); err != nil {
+				return fmt.Errorf("failed to cancel later scheduled changes: %w", err)
+			}
+		case repository.ScheduledPrice:
+			if _, err := tx.Exec(ctx, priceQuery, change.SubscriptionID, change.Month, change.Price); err != nil {
+				return fmt.Errorf("failed to insert plan change: %w", err)
+			}
+		default:
+			return fmt.Errorf("unknown scheduled change action %q", change.Action)
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return applied, nil
+}
@@ -0,0 +1,261 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/repository"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/logger"
+)
+
+// serviceColumns are the columns of repository.CatalogService in the order scanned by scanService,
+// they are selected from the services table without an alias
+const serviceColumns = "id, name, aliases, category, website, " +
+	`COALESCE((SELECT json_agg(json_build_object('id', p.id, 'service_id', p.service_id, 'name', p.name,
+			'price', p.price, 'billing_period', p.billing_period) ORDER BY p.price, lower(p.name))
+		FROM service_plans p WHERE p.service_id = services.id), '[]') AS plans`
+
+func scanService(row pgx.Row, svc *repository.CatalogService) error {
+	return row.Scan(&svc.ID, &svc.Name, &svc.Aliases, &svc.Category, &svc.Website, &svc.Plans)
+}
+
+// serviceNames returns the lowercased name and aliases a service is matched by
+func serviceNames(svc repository.CatalogService) []string {
+	names := make([]string, 0, len(svc.Aliases)+1)
+	names = append(names, strings.ToLower(svc.Name))
+	for _, alias := range svc.Aliases {
+		names = append(names, strings.ToLower(alias))
+	}
+	return names
+}
+
+// saveService checks that no other service is matched by the same names, runs save, replaces the plans
+// and links unmatched subscriptions
+func saveService(ctx context.Context, tx pgx.Tx, svc *repository.CatalogService, save func() error) error {
+	takenQuery := `-- name: ServiceNameTaken
+		SELECT EXISTS (
+			SELECT 1 FROM services
+			WHERE id <> $1
+			  AND (lower(name) = ANY($2) OR EXISTS (SELECT 1 FROM unnest(aliases) AS a WHERE lower(a) = ANY($2)))
+		)`
+	linkQuery := `-- name: LinkServiceSubscriptions
+		UPDATE subscriptions SET service_id = $1 WHERE service_id IS NULL AND lower(service_name) = ANY($2)`
+
+	if svc.Aliases == nil {
+		svc.Aliases = []string{}
+	}
+	if svc.Plans == nil {
+		svc.Plans = []repository.ServicePlan{}
+	}
+	names := serviceNames(*svc)
+
+	var taken bool
+	if err := tx.QueryRow(ctx, takenQuery, svc.ID, names).Scan(&taken); err != nil {
+		return fmt.Errorf("failed to check service names: %w", err)
+	}
+	if taken {
+		return repository.ErrServiceAlreadyExists
+	}
+
+	if err := save(); err != nil {
+		return err
+	}
+	if err := savePlans(ctx, tx, svc); err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, linkQuery, svc.ID, names)
+	if err != nil {
+		return fmt.Errorf("failed to link subscriptions to service: %w", err)
+	}
+	logger.FromContext(ctx).DebugContext(ctx, "subscriptions linked to service", "id", svc.ID, "subscriptions", tag.RowsAffected())
+	return nil
+}
+
+// savePlans replaces the plans of svc, plans are matched by name ignoring case and keep their IDs
+func savePlans(ctx context.Context, tx pgx.Tx, svc *repository.CatalogService) error {
+	deleteQuery := `-- name: DeleteServicePlans
+		DELETE FROM service_plans WHERE service_id = $1 AND NOT (lower(name) = ANY($2))`
+	upsertQuery := `-- name: UpsertServicePlan
+		INSERT INTO service_plans (service_id, name, price, billing_period) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (service_id, lower(name)) DO UPDATE
+			SET name = EXCLUDED.name, price = EXCLUDED.price, billing_period = EXCLUDED.billing_period
+		RETURNING id`
+
+	names := make([]string, len(svc.Plans))
+	for i, plan := range svc.Plans {
+		names[i] = strings.ToLower(plan.Name)
+	}
+	if _, err := tx.Exec(ctx, deleteQuery, svc.ID, names); err != nil {
+		return fmt.Errorf("failed to delete service plans: %w", err)
+	}
+
+	for i := range svc.Plans {
+		plan := &svc.Plans[i]
+		plan.ServiceID = svc.ID
+		if err := tx.QueryRow(ctx, upsertQuery, svc.ID, plan.Name, plan.Price, plan.BillingPeriod).Scan(&plan.ID); err != nil {
+			return fmt.Errorf("failed to save service plan: %w", err)
+		}
+	}
+	return nil
+}
+
+func serviceError(err error) error {
+	var pgxError *pgconn.PgError
+	if errors.As(err, &pgxError) && pgxError.Code == "23505" {
+		return repository.ErrServiceAlreadyExists
+	}
+	return err
+}
+
+func (r *SubscriptionRepository) CreateService(ctx context.Context, svc repository.CatalogService) (repository.CatalogService, error) {
+	query := `-- name: CreateService
+		INSERT INTO services (name, aliases, category, website) VALUES ($1, $2, $3, $4) RETURNING id`
+
+	err := r.inTenantTx(ctx, func(tx pgx.Tx) error {
+		return saveService(ctx, tx, &svc, func() error {
+			return tx.QueryRow(ctx, query, svc.Name, svc.Aliases, svc.Category, svc.Website).Scan(&svc.ID)
+		})
+	})
+	if err != nil {
+		return repository.CatalogService{}, serviceError(err)
+	}
+
+	logger.FromContext(ctx).DebugContext(ctx, "service created", "id", svc.ID, "name", svc.Name)
+	return svc, nil
+}
+
+func (r *SubscriptionRepository) GetServiceByID(ctx context.Context, id uuid.UUID) (repository.CatalogService, error) {
+	query := `-- name: GetServiceByID
+		SELECT ` + serviceColumns + ` FROM services WHERE id = $1`
+
+	var svc repository.CatalogService
+	err := r.inTenantTx(ctx, func(tx pgx.Tx) error {
+		return scanService(tx.QueryRow(ctx, query, id), &svc)
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repository.CatalogService{}, repository.ErrServiceNotFound
+		}
+		return repository.CatalogService{}, err
+	}
+	return svc, nil
+}
+
+func (r *SubscriptionRepository) ListServices(ctx context.Context, query string) ([]repository.CatalogService, error) {
+	sql := `-- name: ListServices
+		SELECT ` + serviceColumns + ` FROM services
+		WHERE $1 = '' OR name ILIKE '%' || $1 || '%'
+		   OR EXISTS (SELECT 1 FROM unnest(aliases) AS a WHERE a ILIKE '%' || $1 || '%')
+		ORDER BY lower(name)`
+
+	var services []repository.CatalogService
+	err := r.inTenantTx(ctx, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, sql, query)
+		if err != nil {
+			return fmt.Errorf("failed to query services: %w", err)
+		}
+		services, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (repository.CatalogService, error) {
+			var svc repository.CatalogService
+			err := scanService(row, &svc)
+			return svc, err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scan services: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).DebugContext(ctx, "services fetched", "query", query, "services", len(services))
+	return services, nil
+}
+
+func (r *SubscriptionRepository) UpdateService(ctx context.Context, svc repository.CatalogService) (repository.CatalogService, error) {
+	query := `-- name: UpdateService
+		UPDATE services SET name = $2, aliases = $3, category = $4, website = $5 WHERE id = $1`
+
+	err := r.inTenantTx(ctx, func(tx pgx.Tx) error {
+		return saveService(ctx, tx, &svc, func() error {
+			tag, err := tx.Exec(ctx, query, svc.ID, svc.Name, svc.Aliases, svc.Category, svc.Website)
+			if err != nil {
+				return fmt.Errorf("failed to update service: %w", err)
+			}
+			if tag.RowsAffected() == 0 {
+				return repository.ErrServiceNotFound
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return repository.CatalogService{}, serviceError(err)
+	}
+
+	logger.FromContext(ctx).DebugContext(ctx, "service updated", "id", svc.ID, "name", svc.Name)
+	return svc, nil
+}
+
+func (r *SubscriptionRepository) DeleteService(ctx context.Context, id uuid.UUID) error {
+	query := `-- name: DeleteService
+		DELETE FROM services WHERE id = $1`
+	err := r.inTenantTx(ctx, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, query, id)
+		if err != nil {
+			return fmt.Errorf("failed to delete service: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return repository.ErrServiceNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx).DebugContext(ctx, "service deleted", "id", id)
+	return nil
+}
+
+func (r *SubscriptionRepository) MatchService(ctx context.Context, name string) (repository.CatalogService, error) {
+	query := `-- name: MatchService
+		SELECT ` + serviceColumns + ` FROM services
+		WHERE lower(name) = lower($1) OR EXISTS (SELECT 1 FROM unnest(aliases) AS a WHERE lower(a) = lower($1))
+		LIMIT 1`
+
+	var svc repository.CatalogService
+	err := r.inTenantTx(ctx, func(tx pgx.Tx) error {
+		return scanService(tx.QueryRow(ctx, query, strings.TrimSpace(name)), &svc)
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repository.CatalogService{}, repository.ErrServiceNotFound
+		}
+		return repository.CatalogService{}, err
+	}
+	return svc, nil
+}
+
+func (r *SubscriptionRepository) GetServicePlan(ctx context.Context, id uuid.UUID) (repository.ServicePlan, error) {
+	query := `-- name: GetServicePlan
+		SELECT id, service_id, name, price, billing_period FROM service_plans WHERE id = $1`
+
+	var plan repository.ServicePlan
+	err := r.inTenantTx(ctx, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx, query, id).Scan(&plan.ID, &plan.ServiceID, &plan.Name, &plan.Price, &plan.BillingPeriod)
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repository.ServicePlan{}, repository.ErrPlanNotFound
+		}
+		return repository.ServicePlan{}, err
+	}
+	return plan, nil
+}
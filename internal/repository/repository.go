@@ -10,19 +10,132 @@ import (
 
 // Subscription представляет подписку в базе данных
 type Subscription struct {
-	ID          uuid.UUID    `db:"id"`
-	ServiceName string       `db:"service_name"`
-	Price       int          `db:"price"`
-	UserID      uuid.UUID    `db:"user_id"`
-	StartDate   time.Time    `db:"start_date"`
-	EndDate     sql.NullTime `db:"end_date"`
+	ID           uuid.UUID     `db:"id"`
+	ServiceName  string        `db:"service_name"`
+	ServiceID    uuid.NullUUID `db:"service_id"`    // Сервис из каталога, если название с ним сопоставлено
+	AccountLabel string        `db:"account_label"` // Учётная запись сервиса; подписки одной учётной записи не пересекаются по датам
+	PlanID       uuid.NullUUID `db:"plan_id"`       // Тариф сервиса с начала подписки до первой смены тарифа
+	Price        int           `db:"price"`         // Стоимость месяца с начала подписки до первой смены тарифа
+	CurrentPrice int           `db:"current_price"` // Стоимость месяца с учётом смен тарифа в текущем месяце, только для чтения
+	UserID       uuid.UUID     `db:"user_id"`
+	StartDate    time.Time     `db:"start_date"`
+	EndDate      sql.NullTime  `db:"end_date"`
+	BillingDay   int           `db:"billing_day"`  // День месяца списания 1–31, в коротких месяцах — последний день
+	TrialMonths  int           `db:"trial_months"` // Длительность пробного периода с начала подписки, 0 — без него
+	TrialPrice   int           `db:"trial_price"`  // Стоимость месяца пробного периода, 0 — бесплатный
+	Paused       bool          `db:"paused"`       // Приостановлена в текущем месяце, только для чтения
+	Category     string        `db:"category"`     // Статья расходов, по умолчанию категория сервиса каталога
+	Tags         []string      `db:"tags"`         // Теги в нижнем регистре по алфавиту
+	// Способ оплаты владельца подписки, с которого она списывается
+	PaymentMethodID uuid.NullUUID `db:"payment_method_id"`
+}
+
+// Виды способов оплаты
+const (
+	PaymentCard = "card" // Банковская карта
+	PaymentBank = "bank" // Банковский счёт
+)
+
+// PaymentMethod способ оплаты пользователя. Хранятся только данные, по которым его можно узнать:
+// платёжная система, последние четыре цифры и месяц окончания срока действия карты или название счёта.
+type PaymentMethod struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Kind      string       // PaymentCard или PaymentBank
+	Label     string       // Название, для счёта обязательно
+	Brand     string       // Платёжная система карты
+	Last4     string       // Последние четыре цифры карты
+	Expires   sql.NullTime // Месяц окончания срока действия карты, она действует до его последнего дня
+	Name      string       // Название для отображения: Label или платёжная система и последние цифры, только для чтения
+	CreatedAt time.Time
+}
+
+// SubscriptionPause интервал приостановки подписки; за месяцы паузы подписка не оплачивается
+type SubscriptionPause struct {
+	ID             uuid.UUID
+	SubscriptionID uuid.UUID
+	StartDate      time.Time    // Первый месяц паузы
+	EndDate        sql.NullTime // Последний месяц паузы, включительно; пустой — до возобновления
+}
+
+// PlanChange смена тарифа подписки, действует с месяца StartDate до следующей смены
+type PlanChange struct {
+	ID             uuid.UUID
+	SubscriptionID uuid.UUID
+	PlanID         uuid.NullUUID // Пустой, если тариф удалён из каталога
+	Price          int           // Стоимость месяца по новому тарифу
+	StartDate      time.Time     // Первый месяц по новому тарифу
+}
+
+// Действия запланированных изменений подписки
+const (
+	ScheduledCancel = "cancel" // Подписка заканчивается месяцем Month
+	ScheduledPrice  = "price"  // С месяца Month действует цена Price
+)
+
+// Статусы запланированных изменений
+const (
+	ScheduledPending  = "pending"  // Ожидает применения
+	ScheduledApplied  = "applied"  // Применено планировщиком
+	ScheduledCanceled = "canceled" // Отменено пользователем
+)
+
+// ScheduledChange изменение подписки, которое вступает в силу в будущем месяце.
+// До применения оно учитывается в прогнозах стоимости, списаний и напоминаний.
+type ScheduledChange struct {
+	ID             uuid.UUID
+	SubscriptionID uuid.UUID
+	Action         string    // ScheduledCancel или ScheduledPrice
+	Month          time.Time // Последний оплачиваемый месяц для отмены, первый месяц по новой цене для смены цены
+	Price          int       // Новая цена, только для ScheduledPrice
+	Status         string
+	CreatedAt      time.Time
+	AppliedAt      sql.NullTime
+}
+
+// DueScheduledChange запланированное изменение, срок применения которого наступил
+type DueScheduledChange struct {
+	TenantID string
+	ScheduledChange
+}
+
+// Правила разделения стоимости подписки между участниками
+const (
+	SplitEqual   = "equal"   // Поровну между владельцем и участниками
+	SplitPercent = "percent" // Участник платит Share процентов
+	SplitFixed   = "fixed"   // Участник платит Share рублей в месяц
+)
+
+// SubscriptionMember участник общей подписки, оплачивающий её часть
+type SubscriptionMember struct {
+	UserID uuid.UUID
+	Share  int // Процент или сумма в рублях по правилу разделения, для SplitEqual не используется
+}
+
+// SubscriptionSplit разделение стоимости подписки; владелец оплачивает остаток после долей участников.
+// Если фиксированные доли в сумме превышают стоимость месяца, месяц делится между участниками пропорционально им.
+type SubscriptionSplit struct {
+	SubscriptionID uuid.UUID
+	Rule           string
+	Members        []SubscriptionMember // В порядке user_id
 }
 
 // At least one field must be provided
 type SubscriptionUpdate struct {
-	ServiceName *string
-	Price       *int
-	EndDate     *time.Time
+	ServiceName  *string
+	ServiceID    *uuid.NullUUID // Пустой Valid отвязывает подписку от каталога
+	PlanID       *uuid.NullUUID // Пустой Valid отвязывает подписку от тарифа
+	Price        *int
+	EndDate      *time.Time
+	BillingDay   *int
+	TrialMonths  *int
+	TrialPrice   *int
+	Category     *string
+	AccountLabel *string
+	AddTags      []string // Добавляемые теги в нижнем регистре
+	RemoveTags   []string // Удаляемые теги в нижнем регистре
+	// Пустой Valid отвязывает подписку от способа оплаты
+	PaymentMethodID *uuid.NullUUID
 }
 
 type SubscriptionCursor struct {
@@ -33,18 +146,165 @@ type SubscriptionCursor struct {
 type SubscriptionPagination struct {
 	Limit  int
 	Cursor *SubscriptionCursor
+	UserID *uuid.UUID // Ограничивает выборку подписками пользователя, включая общие, в которых он участник
+	Tags   []string   // Подписки со всеми указанными тегами
 }
 
+// SubscriptionFilter отбирает подписки для расчёта стоимости.
+// Фильтры по пользователю учитывают доли пользователя в общих подписках, а не их полную стоимость.
 type SubscriptionFilter struct {
-	ServiceName *string
+	ServiceName *string    // Частичное совпадение с названием подписки, сервиса каталога или его псевдонимом
+	ServiceID   *uuid.UUID // Подписки, сопоставленные с сервисом каталога
 	UserID      *uuid.UUID
-	StartDate   *time.Time
-	EndDate     *time.Time
+	UserIDs     []uuid.UUID // Подписки любого из пользователей, для пакетной загрузки
+	StartDate   *time.Time  // Начало периода расчёта, включительно
+	EndDate     *time.Time  // Конец периода расчёта, включительно; по умолчанию текущий месяц
+	Tags        []string    // Подписки со всеми указанными тегами
+	Category    *string     // Совпадение категории без учёта регистра
+}
+
+// CatalogService сервис из каталога тенанта с каноническим названием
+type CatalogService struct {
+	ID       uuid.UUID
+	Name     string
+	Aliases  []string // Другие написания названия, сопоставляются без учёта регистра
+	Category string
+	Website  string
+	Plans    []ServicePlan // Тарифы в порядке возрастания цены
+}
+
+// Периоды оплаты тарифа
+const (
+	BillingMonth = "month"
+	BillingYear  = "year"
+)
+
+// ServicePlan тариф сервиса каталога
+type ServicePlan struct {
+	ID            uuid.UUID `json:"id"`
+	ServiceID     uuid.UUID `json:"service_id"`
+	Name          string    `json:"name"`
+	Price         int       `json:"price"`          // Стоимость за период оплаты
+	BillingPeriod string    `json:"billing_period"` // BillingMonth или BillingYear
+}
+
+// TrialEndingFilter выбирает подписки, пробный период которых заканчивается перед месяцем Month
+type TrialEndingFilter struct {
+	Month  time.Time  // Первый месяц оплаты по обычной цене
+	UserID *uuid.UUID // Ограничивает выборку подписками одного пользователя
+}
+
+// BilledFilter выбирает подписки, оплачиваемые в месяце Month
+type BilledFilter struct {
+	Month  time.Time
+	UserID *uuid.UUID // Ограничивает выборку подписками одного пользователя
+}
+
+// BilledSubscription подписка и её стоимость в месяце
+type BilledSubscription struct {
+	Subscription
+	Amount int // Стоимость месяца с учётом пробного периода, смен тарифа и запланированных смен цены, без разделения с участниками
+}
+
+// CostGroup задаёт группировку при разбивке стоимости
+type CostGroup string
+
+const (
+	CostGroupService  CostGroup = "service"
+	CostGroupUser     CostGroup = "user"
+	CostGroupMonth    CostGroup = "month"
+	CostGroupCategory CostGroup = "category"
+	// CostGroupPaymentMethod относит к способу оплаты подписки долю владельца, доли участников — к группе без способа
+	CostGroupPaymentMethod CostGroup = "payment_method"
+	// CostGroupTag относит стоимость подписки к каждому её тегу, так что сумма групп может превышать итог.
+	// Подписки без тегов попадают в группу с пустым ключом.
+	CostGroupTag CostGroup = "tag"
+)
+
+// CostBreakdownRow стоимость подписок одной группы: сервиса, пользователя, месяца (ММ-ГГГГ), категории или тега
+type CostBreakdownRow struct {
+	Key       string
+	TotalCost int
+}
+
+// UserCostBreakdownRow стоимость подписок группы для одного пользователя
+type UserCostBreakdownRow struct {
+	UserID uuid.UUID
+	CostBreakdownRow
+}
+
+// SubscriptionStats агрегированные показатели активных подписок всех тенантов
+type SubscriptionStats struct {
+	Active       int
+	MonthlySpend int
+}
+
+// Каналы доставки напоминаний
+const (
+	ChannelLog     = "log"
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+)
+
+// NotificationPreferences настройки напоминаний пользователя
+type NotificationPreferences struct {
+	UserID     uuid.UUID
+	Enabled    bool
+	DaysBefore int    // За сколько дней до списания или окончания подписки отправляется напоминание
+	Channel    string // ChannelLog, ChannelEmail или ChannelWebhook
+	Email      string
+	WebhookURL string
+}
+
+// DefaultNotificationPreferences действуют для пользователей без сохранённых настроек
+var DefaultNotificationPreferences = NotificationPreferences{Enabled: true, DaysBefore: 3, Channel: ChannelLog}
+
+// Виды напоминаний
+const (
+	ReminderRenewal = "renewal" // Очередное списание
+	ReminderEnding  = "ending"  // Окончание подписки
+)
+
+// DueReminder напоминание, срок отправки которого наступил
+type DueReminder struct {
+	TenantID       string
+	SubscriptionID uuid.UUID
+	UserID         uuid.UUID
+	ServiceName    string
+	Kind           string
+	Date           time.Time // День списания или первый день после окончания подписки
+	Amount         int       // Сумма списания, для окончания 0
+	Preferences    NotificationPreferences
+}
+
+// ChangeOp вид изменения подписки
+type ChangeOp string
+
+const (
+	ChangeCreated ChangeOp = "created"
+	ChangeUpdated ChangeOp = "updated"
+	ChangeDeleted ChangeOp = "deleted"
+)
+
+// SubscriptionChange изменение подписки; для удаления Subscription содержит состояние до него
+type SubscriptionChange struct {
+	Op           ChangeOp
+	TenantID     string
+	Subscription Subscription
+	ChangedAt    time.Time
 }
 
 var (
 	ErrSubscriptionNotFound      = errors.New("subscription not found")
-	ErrSubscriptionAlreadyExists = errors.New("subscription already exists")
+	ErrSubscriptionAlreadyExists = errors.New("subscription of the same account overlaps in time")
+	ErrTenantRequired            = errors.New("tenant is not set in context")
+	ErrSubscriptionPaused        = errors.New("subscription is already paused")
+	ErrSubscriptionNotPaused     = errors.New("subscription is not paused")
+	ErrServiceNotFound           = errors.New("service not found")
+	ErrServiceAlreadyExists      = errors.New("service with this name or alias already exists")
+	ErrPlanNotFound              = errors.New("plan not found")
+	ErrScheduledChangeNotFound   = errors.New("pending scheduled change not found")
+	ErrPaymentMethodNotFound     = errors.New("payment method not found")
 )
 
 type SubscriptionRepository interface {
@@ -54,4 +314,89 @@ type SubscriptionRepository interface {
 	UpdateSubscription(ctx context.Context, id uuid.UUID, fields SubscriptionUpdate) (Subscription, error)
 	DeleteSubscription(ctx context.Context, id uuid.UUID) error
 	GetTotalCostWithFilters(ctx context.Context, filter SubscriptionFilter) (int, error)
+	GetCostBreakdown(ctx context.Context, filter SubscriptionFilter, group CostGroup) ([]CostBreakdownRow, error)
+	// ListSubscriptionsByUserIDs возвращает все подписки пользователей одним запросом
+	ListSubscriptionsByUserIDs(ctx context.Context, userIDs []uuid.UUID) ([]Subscription, error)
+	// GetCostBreakdownByUser разбивает стоимость по группам отдельно для каждого пользователя
+	GetCostBreakdownByUser(ctx context.Context, filter SubscriptionFilter, group CostGroup) ([]UserCostBreakdownRow, error)
+	// ListTrialsEnding возвращает подписки, которые переходят на обычную цену в месяце filter.Month
+	ListTrialsEnding(ctx context.Context, filter TrialEndingFilter) ([]Subscription, error)
+	// ListBilledSubscriptions возвращает подписки, оплачиваемые в месяце filter.Month, без приостановленных
+	// и отменённых запланированной отменой
+	ListBilledSubscriptions(ctx context.Context, filter BilledFilter) ([]BilledSubscription, error)
+	// PauseSubscription добавляет паузу, если она не пересекается с другими паузами подписки
+	PauseSubscription(ctx context.Context, pause SubscriptionPause) (SubscriptionPause, error)
+	// ResumeSubscription завершает паузу, действующую в месяце month, так что month снова оплачивается.
+	// Пауза, которая начинается в month, удаляется.
+	ResumeSubscription(ctx context.Context, subscriptionID uuid.UUID, month time.Time) error
+
+	// CreateService добавляет сервис в каталог и сопоставляет с ним подписки без сервиса по названию и псевдонимам
+	CreateService(ctx context.Context, svc CatalogService) (CatalogService, error)
+	GetServiceByID(ctx context.Context, id uuid.UUID) (CatalogService, error)
+	// ListServices возвращает сервисы каталога по названию; query — частичное совпадение с названием или псевдонимом
+	ListServices(ctx context.Context, query string) ([]CatalogService, error)
+	// UpdateService заменяет поля сервиса и, как CreateService, сопоставляет с ним подписки.
+	// Тарифы сопоставляются по названию без учёта регистра: совпавшие сохраняют ID, отсутствующие удаляются.
+	UpdateService(ctx context.Context, svc CatalogService) (CatalogService, error)
+	// DeleteService удаляет сервис, подписки сохраняют свои названия
+	DeleteService(ctx context.Context, id uuid.UUID) error
+	// MatchService находит сервис, название или псевдоним которого совпадает с name без учёта регистра
+	MatchService(ctx context.Context, name string) (CatalogService, error)
+	GetServicePlan(ctx context.Context, id uuid.UUID) (ServicePlan, error)
+
+	// ChangeSubscriptionPlan добавляет смену тарифа; смена в том же месяце заменяется
+	ChangeSubscriptionPlan(ctx context.Context, change PlanChange) (PlanChange, error)
+	// ListPlanChanges возвращает смены тарифа подписки в хронологическом порядке
+	ListPlanChanges(ctx context.Context, subscriptionID uuid.UUID) ([]PlanChange, error)
+
+	// ScheduleChange добавляет запланированное изменение; ожидающее изменение с тем же действием и месяцем заменяется
+	ScheduleChange(ctx context.Context, change ScheduledChange) (ScheduledChange, error)
+	// ListScheduledChanges возвращает запланированные изменения подписки всех статусов по месяцам
+	ListScheduledChanges(ctx context.Context, subscriptionID uuid.UUID) ([]ScheduledChange, error)
+	// CancelScheduledChange отменяет ожидающее изменение подписки
+	CancelScheduledChange(ctx context.Context, subscriptionID, changeID uuid.UUID) error
+
+	// GetSubscriptionSplit возвращает правило разделения и участников подписки
+	GetSubscriptionSplit(ctx context.Context, subscriptionID uuid.UUID) (SubscriptionSplit, error)
+	// SetSubscriptionSplit заменяет правило разделения и всех участников подписки
+	SetSubscriptionSplit(ctx context.Context, split SubscriptionSplit) (SubscriptionSplit, error)
+
+	CreatePaymentMethod(ctx context.Context, method PaymentMethod) (PaymentMethod, error)
+	GetPaymentMethod(ctx context.Context, id uuid.UUID) (PaymentMethod, error)
+	// ListPaymentMethods возвращает способы оплаты пользователя или, без userID, всех пользователей тенанта
+	ListPaymentMethods(ctx context.Context, userID *uuid.UUID) ([]PaymentMethod, error)
+	// UpdatePaymentMethod заменяет поля способа оплаты, подписки остаются привязаны к нему
+	UpdatePaymentMethod(ctx context.Context, method PaymentMethod) (PaymentMethod, error)
+	// DeletePaymentMethod удаляет способ оплаты и переносит его подписки на replacementID или отвязывает их
+	DeletePaymentMethod(ctx context.Context, id uuid.UUID, replacementID uuid.NullUUID) error
+
+	// GetNotificationPreferences возвращает настройки напоминаний пользователя или DefaultNotificationPreferences
+	GetNotificationPreferences(ctx context.Context, userID uuid.UUID) (NotificationPreferences, error)
+	SetNotificationPreferences(ctx context.Context, prefs NotificationPreferences) (NotificationPreferences, error)
+}
+
+// ReminderStore выбирает напоминания подписок всех тенантов и ведёт журнал их отправки
+type ReminderStore interface {
+	// ListDueReminders возвращает неотправленные напоминания о списаниях и окончаниях в начале месяца после today,
+	// срок которых по настройкам пользователей наступил к today
+	ListDueReminders(ctx context.Context, today time.Time) ([]DueReminder, error)
+	// ClaimReminder записывает напоминание в журнал отправки; false — оно уже записано
+	ClaimReminder(ctx context.Context, reminder DueReminder) (bool, error)
+	// ReleaseReminder удаляет напоминание из журнала после неудачной отправки, чтобы она повторилась
+	ReleaseReminder(ctx context.Context, reminder DueReminder) error
+}
+
+// ScheduledChangeStore выбирает запланированные изменения подписок всех тенантов и применяет их
+type ScheduledChangeStore interface {
+	// ListDueScheduledChanges возвращает ожидающие изменения, которые действуют в month: смены цены с month
+	// и отмены с последним месяцем до month
+	ListDueScheduledChanges(ctx context.Context, month time.Time) ([]DueScheduledChange, error)
+	// ApplyScheduledChange применяет изменение к подписке; false — оно уже применено или отменено
+	ApplyScheduledChange(ctx context.Context, change DueScheduledChange) (bool, error)
+}
+
+// ChangeListener доставляет изменения подписок всех тенантов
+type ChangeListener interface {
+	// ListenSubscriptionChanges вызывает fn для каждого изменения, пока не отменён ctx или не потеряно соединение
+	ListenSubscriptionChanges(ctx context.Context, fn func(SubscriptionChange)) error
 }
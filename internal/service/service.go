@@ -8,7 +8,18 @@ import (
 	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
 )
 
-var ErrInvalidDateRange = errors.New("end date cannot be before start date")
+var (
+	ErrInvalidDateRange     = errors.New("end date cannot be before start date")
+	ErrForbidden            = errors.New("access to another user's subscriptions is forbidden")
+	ErrInvalidPause         = errors.New("pause must start within the subscription period")
+	ErrAdminRequired        = errors.New("only admins can manage the service catalog")
+	ErrUnknownService       = errors.New("service_id does not reference a catalog service")
+	ErrUnknownPlan          = errors.New("plan_id does not reference a plan of the subscription's service")
+	ErrInvalidPlanChange    = errors.New("plan change must start after the subscription start and within its period")
+	ErrInvalidSplit         = errors.New("members must not include the owner and percent shares must not exceed 100 in total")
+	ErrInvalidSchedule      = errors.New("scheduled change must take effect in a future month within the subscription period")
+	ErrUnknownPaymentMethod = errors.New("payment_method_id does not reference a payment method of the subscription's user")
+)
 
 type SubscriptionService interface {
 	CreateSubscription(ctx context.Context, req models.CreateSubscriptionRequest) (models.SubscriptionResponse, error)
@@ -17,4 +28,56 @@ type SubscriptionService interface {
 	UpdateSubscription(ctx context.Context, id uuid.UUID, req models.UpdateSubscriptionRequest) (models.SubscriptionResponse, error)
 	DeleteSubscription(ctx context.Context, id uuid.UUID) error
 	GetTotalCost(ctx context.Context, filter models.TotalCostRequest) (models.TotalCostResponse, error)
+	GetCostBreakdown(ctx context.Context, req models.CostBreakdownRequest) (models.CostBreakdownResponse, error)
+	// ListUsersSubscriptions and GetUsersCostBreakdown load data of several users at once for batching loaders
+	ListUsersSubscriptions(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID][]models.SubscriptionResponse, error)
+	GetUsersCostBreakdown(ctx context.Context, userIDs []uuid.UUID, req models.CostBreakdownRequest) (map[uuid.UUID]models.CostBreakdownResponse, error)
+	// ListTrialsEnding lists subscriptions switching from the trial to the regular price in a month, next month by default
+	ListTrialsEnding(ctx context.Context, req models.TrialEndingRequest) ([]models.SubscriptionResponse, error)
+	PauseSubscription(ctx context.Context, id uuid.UUID, req models.PauseSubscriptionRequest) (models.SubscriptionResponse, error)
+	ResumeSubscription(ctx context.Context, id uuid.UUID, req models.ResumeSubscriptionRequest) (models.SubscriptionResponse, error)
+	// ChangeSubscriptionPlan moves a subscription to another plan of its service from a month, the current month by default
+	ChangeSubscriptionPlan(ctx context.Context, id uuid.UUID, req models.ChangePlanRequest) (models.SubscriptionResponse, error)
+	ListPlanChanges(ctx context.Context, id uuid.UUID) ([]models.PlanChangeResponse, error)
+	// ScheduleChange schedules a cancellation or a price change applied when its month comes
+	ScheduleChange(ctx context.Context, id uuid.UUID, req models.ScheduleChangeRequest) (models.ScheduledChangeResponse, error)
+	ListScheduledChanges(ctx context.Context, id uuid.UUID) ([]models.ScheduledChangeResponse, error)
+	// CancelScheduledChange drops a pending change, applied changes cannot be canceled
+	CancelScheduledChange(ctx context.Context, id, changeID uuid.UUID) error
+	// GetMembers is allowed to the owner and members of a subscription, SetMembers only to the owner
+	GetMembers(ctx context.Context, id uuid.UUID) (models.MembersResponse, error)
+	SetMembers(ctx context.Context, id uuid.UUID, req models.SetMembersRequest) (models.MembersResponse, error)
+	// FindDuplicates groups subscriptions of a user billed in a month that likely duplicate each other
+	FindDuplicates(ctx context.Context, req models.DuplicatesRequest) (models.DuplicatesResponse, error)
+	// ListUpcomingCharges lists charges on billing days from today for req.Days days
+	ListUpcomingCharges(ctx context.Context, req models.UpcomingChargesRequest) ([]models.ChargeResponse, error)
+	// GetNotificationPreferences returns the defaults for users who have not set preferences
+	GetNotificationPreferences(ctx context.Context, userID uuid.UUID) (models.NotificationPreferencesResponse, error)
+	SetNotificationPreferences(ctx context.Context, userID uuid.UUID, req models.NotificationPreferencesRequest) (models.NotificationPreferencesResponse, error)
+	// Payment methods store only what identifies them: a card's brand, last digits and expiry or an account label
+	CreatePaymentMethod(ctx context.Context, userID uuid.UUID, req models.PaymentMethodRequest) (models.PaymentMethodResponse, error)
+	ListPaymentMethods(ctx context.Context, userID uuid.UUID) ([]models.PaymentMethodResponse, error)
+	UpdatePaymentMethod(ctx context.Context, userID, id uuid.UUID, req models.PaymentMethodRequest) (models.PaymentMethodResponse, error)
+	// DeletePaymentMethod moves subscriptions of the method to the replacement or unlinks them
+	DeletePaymentMethod(ctx context.Context, userID, id uuid.UUID, req models.DeletePaymentMethodRequest) error
+	// ListExpiringCards lists subscriptions whose next charge comes after their card expires
+	ListExpiringCards(ctx context.Context, req models.ExpiringCardsRequest) ([]models.ExpiringCardResponse, error)
+
+	// Service catalog, only admins may change it
+	CreateService(ctx context.Context, req models.ServiceRequest) (models.ServiceResponse, error)
+	GetService(ctx context.Context, id uuid.UUID) (models.ServiceResponse, error)
+	ListServices(ctx context.Context, query string) ([]models.ServiceResponse, error)
+	UpdateService(ctx context.Context, id uuid.UUID, req models.ServiceRequest) (models.ServiceResponse, error)
+	DeleteService(ctx context.Context, id uuid.UUID) error
+}
+
+// Notifier delivers a reminder to its recipient through one channel
+type Notifier interface {
+	Notify(ctx context.Context, reminder models.Reminder) error
+}
+
+// SubscriptionWatcher streams changes of subscriptions in the caller's tenant.
+// The channel is closed when ctx is done or the watch is interrupted and has to be restarted.
+type SubscriptionWatcher interface {
+	WatchSubscriptions(ctx context.Context, req models.WatchSubscriptionsRequest) (<-chan models.SubscriptionEvent, error)
 }
@@ -0,0 +1,174 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/auth"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/repository"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/service"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/logger"
+)
+
+// requireAdmin checks that the caller may change the service catalog.
+// Unauthenticated requests (authentication disabled) are not restricted.
+func requireAdmin(ctx context.Context) error {
+	p, ok := auth.FromContext(ctx)
+	if !ok || p.Admin {
+		return nil
+	}
+	logger.FromContext(ctx).WarnContext(ctx, "service catalog change by non-admin denied", "caller_id", p.UserID)
+	return service.ErrAdminRequired
+}
+
+// resolveService matches a subscription to the catalog, by serviceID if it is set and by name otherwise.
+// If none matches it returns a service with name unchanged and a nil ID.
+func (s Service) resolveService(ctx context.Context, name string, serviceID *uuid.UUID) (repository.CatalogService, error) {
+	var (
+		svc repository.CatalogService
+		err error
+	)
+	if serviceID != nil {
+		svc, err = s.repo.GetServiceByID(ctx, *serviceID)
+		if errors.Is(err, repository.ErrServiceNotFound) {
+			return repository.CatalogService{}, service.ErrUnknownService
+		}
+	} else {
+		svc, err = s.repo.MatchService(ctx, name)
+		if errors.Is(err, repository.ErrServiceNotFound) {
+			return repository.CatalogService{Name: name}, nil
+		}
+	}
+	if err != nil {
+		return repository.CatalogService{}, fmt.Errorf("repo failed to match service: %w", err)
+	}
+	return svc, nil
+}
+
+// catalogID returns the ID of a service returned by resolveService, invalid if it is not in the catalog
+func catalogID(svc repository.CatalogService) uuid.NullUUID {
+	return uuid.NullUUID{UUID: svc.ID, Valid: svc.ID != uuid.Nil}
+}
+
+// getPlan fetches a plan and checks that it belongs to the service serviceID if it is valid
+func (s Service) getPlan(ctx context.Context, id uuid.UUID, serviceID uuid.NullUUID) (repository.ServicePlan, error) {
+	plan, err := s.repo.GetServicePlan(ctx, id)
+	if errors.Is(err, repository.ErrPlanNotFound) {
+		return repository.ServicePlan{}, service.ErrUnknownPlan
+	}
+	if err != nil {
+		return repository.ServicePlan{}, fmt.Errorf("repo failed to get service plan: %w", err)
+	}
+	if serviceID.Valid && plan.ServiceID != serviceID.UUID {
+		logger.FromContext(ctx).DebugContext(ctx, "plan of another service rejected",
+			"plan_id", id, "plan_service_id", plan.ServiceID, "service_id", serviceID.UUID)
+		return repository.ServicePlan{}, service.ErrUnknownPlan
+	}
+	return plan, nil
+}
+
+// monthlyPrice returns the price of a month of plan, a yearly price is spread evenly over its months
+func monthlyPrice(plan repository.ServicePlan) int {
+	if plan.BillingPeriod == repository.BillingYear {
+		return (plan.Price + 6) / 12
+	}
+	return plan.Price
+}
+
+func serviceToResponse(svc repository.CatalogService) models.ServiceResponse {
+	resp := models.ServiceResponse{
+		ID:       svc.ID,
+		Name:     svc.Name,
+		Aliases:  svc.Aliases,
+		Category: svc.Category,
+		Website:  svc.Website,
+		Plans:    make([]models.ServicePlanResponse, len(svc.Plans)),
+	}
+	if resp.Aliases == nil {
+		resp.Aliases = []string{}
+	}
+	for i, plan := range svc.Plans {
+		resp.Plans[i] = models.ServicePlanResponse{
+			ID:            plan.ID,
+			Name:          plan.Name,
+			Price:         plan.Price,
+			BillingPeriod: plan.BillingPeriod,
+			MonthlyPrice:  monthlyPrice(plan),
+		}
+	}
+	return resp
+}
+
+func serviceFromRequest(id uuid.UUID, req models.ServiceRequest) repository.CatalogService {
+	svc := repository.CatalogService{
+		ID:       id,
+		Name:     req.Name,
+		Aliases:  req.Aliases,
+		Category: req.Category,
+		Website:  req.Website,
+		Plans:    make([]repository.ServicePlan, len(req.Plans)),
+	}
+	for i, plan := range req.Plans {
+		svc.Plans[i] = repository.ServicePlan{Name: plan.Name, Price: plan.Price, BillingPeriod: plan.BillingPeriod}
+		if plan.BillingPeriod == "" {
+			svc.Plans[i].BillingPeriod = repository.BillingMonth
+		}
+	}
+	return svc
+}
+
+func (s Service) CreateService(ctx context.Context, req models.ServiceRequest) (models.ServiceResponse, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return models.ServiceResponse{}, err
+	}
+
+	svc, err := s.repo.CreateService(ctx, serviceFromRequest(uuid.Nil, req))
+	if err != nil {
+		return models.ServiceResponse{}, fmt.Errorf("repo failed to create service: %w", err)
+	}
+	return serviceToResponse(svc), nil
+}
+
+func (s Service) GetService(ctx context.Context, id uuid.UUID) (models.ServiceResponse, error) {
+	svc, err := s.repo.GetServiceByID(ctx, id)
+	if err != nil {
+		return models.ServiceResponse{}, fmt.Errorf("repo failed to get service by id: %w", err)
+	}
+	return serviceToResponse(svc), nil
+}
+
+func (s Service) ListServices(ctx context.Context, query string) ([]models.ServiceResponse, error) {
+	services, err := s.repo.ListServices(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("repo failed to list services: %w", err)
+	}
+
+	resp := make([]models.ServiceResponse, len(services))
+	for i, svc := range services {
+		resp[i] = serviceToResponse(svc)
+	}
+	return resp, nil
+}
+
+func (s Service) UpdateService(ctx context.Context, id uuid.UUID, req models.ServiceRequest) (models.ServiceResponse, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return models.ServiceResponse{}, err
+	}
+
+	svc, err := s.repo.UpdateService(ctx, serviceFromRequest(id, req))
+	if err != nil {
+		return models.ServiceResponse{}, fmt.Errorf("repo failed to update service: %w", err)
+	}
+	return serviceToResponse(svc), nil
+}
+
+func (s Service) DeleteService(ctx context.Context, id uuid.UUID) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
+	return s.repo.DeleteService(ctx, id)
+}
@@ -0,0 +1,70 @@
+package subscription
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/auth"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/repository"
+)
+
+// billingDate returns the day of month a subscription billed on billingDay is charged,
+// the last day of the month if the month is shorter
+func billingDate(month time.Time, billingDay int) time.Time {
+	lastDay := time.Date(month.Year(), month.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	return time.Date(month.Year(), month.Month(), min(billingDay, lastDay), 0, 0, 0, 0, time.UTC)
+}
+
+// ListUpcomingCharges lists charges from today for req.Days days in the order of their dates.
+// Free months and paused months are not charged.
+func (s Service) ListUpcomingCharges(ctx context.Context, req models.UpcomingChargesRequest) ([]models.ChargeResponse, error) {
+	userID := req.UserID
+	if req.UserID != nil {
+		if err := authorize(ctx, *req.UserID); err != nil {
+			return nil, err
+		}
+	} else if p, ok := auth.FromContext(ctx); ok && !p.Admin {
+		userID = &p.UserID
+	}
+
+	return s.upcomingCharges(ctx, userID, req.Days, time.Now())
+}
+
+// upcomingCharges lists charges of userID, of all users if nil, for days days starting on the day of now
+func (s Service) upcomingCharges(ctx context.Context, userID *uuid.UUID, days int, now time.Time) ([]models.ChargeResponse, error) {
+	now = now.UTC()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, days-1)
+
+	charges := []models.ChargeResponse{}
+	for month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC); !month.After(to); month = month.AddDate(0, 1, 0) {
+		subs, err := s.repo.ListBilledSubscriptions(ctx, repository.BilledFilter{Month: month, UserID: userID})
+		if err != nil {
+			return nil, fmt.Errorf("repo failed to list billed subscriptions: %w", err)
+		}
+		for _, sub := range subs {
+			date := billingDate(month, sub.BillingDay)
+			if sub.Amount == 0 || date.Before(from) || date.After(to) {
+				continue
+			}
+			charges = append(charges, models.ChargeResponse{
+				SubscriptionResponse: toResponse(sub.Subscription),
+				Date:                 date.Format(time.DateOnly),
+				Amount:               sub.Amount,
+			})
+		}
+	}
+
+	slices.SortFunc(charges, func(a, b models.ChargeResponse) int {
+		return cmp.Or(strings.Compare(a.Date, b.Date), strings.Compare(a.ServiceName, b.ServiceName),
+			strings.Compare(a.ID.String(), b.ID.String()))
+	})
+	return charges, nil
+}
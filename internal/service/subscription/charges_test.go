@@ -0,0 +1,149 @@
+package subscription
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/repository"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestBillingDate(t *testing.T) {
+	tests := []struct {
+		name       string
+		month      time.Time
+		billingDay int
+		want       time.Time
+	}{
+		{name: "day within the month", month: date(2025, time.March, 1), billingDay: 15, want: date(2025, time.March, 15)},
+		{name: "first day", month: date(2025, time.March, 1), billingDay: 1, want: date(2025, time.March, 1)},
+		{name: "31 in a 31-day month", month: date(2025, time.January, 1), billingDay: 31, want: date(2025, time.January, 31)},
+		{name: "31 in a 30-day month", month: date(2025, time.April, 1), billingDay: 31, want: date(2025, time.April, 30)},
+		{name: "31 in November", month: date(2025, time.November, 1), billingDay: 31, want: date(2025, time.November, 30)},
+		{name: "30 in February", month: date(2025, time.February, 1), billingDay: 30, want: date(2025, time.February, 28)},
+		{name: "29 in February of a non-leap year", month: date(2025, time.February, 1), billingDay: 29, want: date(2025, time.February, 28)},
+		{name: "29 in February of a leap year", month: date(2024, time.February, 1), billingDay: 29, want: date(2024, time.February, 29)},
+		{name: "31 in February of a leap year", month: date(2024, time.February, 1), billingDay: 31, want: date(2024, time.February, 29)},
+		{name: "31 in February of a century non-leap year", month: date(2100, time.February, 1), billingDay: 31, want: date(2100, time.February, 28)},
+		{name: "31 in December", month: date(2025, time.December, 1), billingDay: 31, want: date(2025, time.December, 31)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := billingDate(tt.month, tt.billingDay); !got.Equal(tt.want) {
+				t.Errorf("billingDate(%s, %d) = %s, want %s", tt.month.Format("2006-01"), tt.billingDay,
+					got.Format(time.DateOnly), tt.want.Format(time.DateOnly))
+			}
+		})
+	}
+}
+
+// billedRepo bills every subscription in every month at its current price
+type billedRepo struct {
+	*fakeRepo
+	months []time.Time
+}
+
+func (r *billedRepo) ListBilledSubscriptions(_ context.Context, filter repository.BilledFilter) ([]repository.BilledSubscription, error) {
+	r.months = append(r.months, filter.Month)
+	var billed []repository.BilledSubscription
+	for _, sub := range r.subs {
+		billed = append(billed, repository.BilledSubscription{Subscription: sub, Amount: sub.CurrentPrice})
+	}
+	return billed, nil
+}
+
+func TestUpcomingCharges(t *testing.T) {
+	subscription := func(name string, billingDay, price int) repository.Subscription {
+		return repository.Subscription{ID: uuid.New(), ServiceName: name, UserID: ownerID, BillingDay: billingDay, CurrentPrice: price}
+	}
+
+	tests := []struct {
+		name       string
+		now        time.Time
+		days       int
+		subs       []repository.Subscription
+		wantDates  []string
+		wantMonths int
+	}{
+		{
+			name:       "within a month",
+			now:        date(2025, time.March, 10),
+			days:       10,
+			subs:       []repository.Subscription{subscription("Netflix", 10, 799), subscription("Okko", 19, 399), subscription("IVI", 20, 299)},
+			wantDates:  []string{"2025-03-10", "2025-03-19"},
+			wantMonths: 1,
+		},
+		{
+			name:       "across a month boundary",
+			now:        date(2025, time.March, 25),
+			days:       14,
+			subs:       []repository.Subscription{subscription("Netflix", 5, 799), subscription("Okko", 20, 399), subscription("IVI", 28, 299)},
+			wantDates:  []string{"2025-03-28", "2025-04-05"},
+			wantMonths: 2,
+		},
+		{
+			name:       "clamped day at the end of a 30-day month",
+			now:        date(2025, time.April, 29),
+			days:       3,
+			subs:       []repository.Subscription{subscription("Netflix", 31, 799), subscription("Okko", 1, 399)},
+			wantDates:  []string{"2025-04-30", "2025-05-01"},
+			wantMonths: 2,
+		},
+		{
+			name:       "leap day",
+			now:        date(2024, time.February, 28),
+			days:       2,
+			subs:       []repository.Subscription{subscription("Netflix", 30, 799), subscription("Okko", 1, 399)},
+			wantDates:  []string{"2024-02-29"},
+			wantMonths: 1,
+		},
+		{
+			name:       "across a year boundary",
+			now:        date(2025, time.December, 31),
+			days:       2,
+			subs:       []repository.Subscription{subscription("Netflix", 31, 799), subscription("Okko", 1, 399)},
+			wantDates:  []string{"2025-12-31", "2026-01-01"},
+			wantMonths: 2,
+		},
+		{
+			name:       "free months are not charged",
+			now:        date(2025, time.March, 1),
+			days:       31,
+			subs:       []repository.Subscription{subscription("Netflix", 5, 0)},
+			wantDates:  []string{},
+			wantMonths: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &billedRepo{fakeRepo: &fakeRepo{subs: map[uuid.UUID]repository.Subscription{}}}
+			for _, sub := range tt.subs {
+				repo.subs[sub.ID] = sub
+			}
+
+			charges, err := NewService(repo).upcomingCharges(asUser(ownerID), &ownerID, tt.days, tt.now.Add(15*time.Hour))
+			if err != nil {
+				t.Fatalf("upcomingCharges() error = %v", err)
+			}
+			dates := []string{}
+			for _, charge := range charges {
+				dates = append(dates, charge.Date)
+			}
+			if !slices.Equal(dates, tt.wantDates) {
+				t.Errorf("charge dates = %v, want %v", dates, tt.wantDates)
+			}
+			if len(repo.months) != tt.wantMonths {
+				t.Errorf("billed months requested = %d, want %d", len(repo.months), tt.wantMonths)
+			}
+		})
+	}
+}
@@ -0,0 +1,277 @@
+package subscription
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/auth"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/repository"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/monthyear"
+)
+
+// planWords name editions of a service rather than the service, "Spotify Premium" and "Spotify Family" are one service
+var planWords = map[string]bool{
+	"basic": true, "standard": true, "premium": true, "plus": true, "pro": true, "family": true, "duo": true,
+	"individual": true, "student": true, "personal": true, "subscription": true, "the": true,
+}
+
+// normalizeServiceName lowercases a service name and keeps its letters and digits without plan words
+func normalizeServiceName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var b strings.Builder
+	for _, word := range words {
+		if !planWords[word] {
+			b.WriteString(word)
+		}
+	}
+	return b.String()
+}
+
+// sameService reports whether subscriptions are to the same service: they are matched to the same catalog
+// service or their names are equal ignoring case, punctuation and plan words
+func sameService(a, b repository.Subscription) bool {
+	if a.ServiceID.Valid && b.ServiceID.Valid {
+		return a.ServiceID == b.ServiceID
+	}
+	na := normalizeServiceName(a.ServiceName)
+	return na != "" && na == normalizeServiceName(b.ServiceName)
+}
+
+// levenshtein returns the number of single rune edits turning a into b
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// similarServices reports whether subscriptions are likely to the same service: they are to the same service,
+// or one of them is not matched to the catalog and their normalized names contain one another or differ
+// in a typo per four letters. Names shorter than four letters must be equal.
+func similarServices(a, b repository.Subscription) bool {
+	if sameService(a, b) {
+		return true
+	}
+	if a.ServiceID.Valid && b.ServiceID.Valid {
+		return false
+	}
+	na, nb := []rune(normalizeServiceName(a.ServiceName)), []rune(normalizeServiceName(b.ServiceName))
+	if len(na) > len(nb) {
+		na, nb = nb, na
+	}
+	if len(na) < 4 {
+		return false
+	}
+	return strings.Contains(string(nb), string(na)) || levenshtein(na, nb) <= len(nb)/4
+}
+
+// sortBilled orders subscriptions by cost, most expensive first
+func sortBilled(subs []repository.BilledSubscription) []repository.BilledSubscription {
+	subs = slices.Clone(subs)
+	slices.SortFunc(subs, func(a, b repository.BilledSubscription) int {
+		return cmp.Or(cmp.Compare(b.Amount, a.Amount), a.StartDate.Compare(b.StartDate), strings.Compare(a.ID.String(), b.ID.String()))
+	})
+	return subs
+}
+
+func toDuplicateSubscriptions(subs []repository.BilledSubscription) ([]models.DuplicateSubscriptionResponse, int) {
+	resp := make([]models.DuplicateSubscriptionResponse, len(subs))
+	monthCost := 0
+	for i, sub := range subs {
+		resp[i] = models.DuplicateSubscriptionResponse{SubscriptionResponse: toResponse(sub.Subscription), MonthCost: sub.Amount}
+		monthCost += sub.Amount
+	}
+	return resp, monthCost
+}
+
+// duplicateGroup builds a group of subscriptions sorted by cost, the savings assume the most expensive one is kept
+func duplicateGroup(reason string, subs []repository.BilledSubscription) models.DuplicateGroupResponse {
+	subs = sortBilled(subs)
+	group := models.DuplicateGroupResponse{Reason: reason, UserID: subs[0].UserID}
+	group.Subscriptions, group.MonthCost = toDuplicateSubscriptions(subs)
+	group.PotentialSavings = group.MonthCost - subs[0].Amount
+	return group
+}
+
+// findDuplicates groups subscriptions of each user billed in a month by the reasons of models.Duplicate*.
+// Groups without savings and groups repeating the subscriptions of an earlier group are left out.
+// Different services of one category are reported as overlaps: they are not interchangeable,
+// so no savings are estimated for them.
+func findDuplicates(subs []repository.BilledSubscription) ([]models.DuplicateGroupResponse, []models.CategoryOverlapResponse) {
+	byUser := make(map[uuid.UUID][]repository.BilledSubscription)
+	for _, sub := range subs {
+		byUser[sub.UserID] = append(byUser[sub.UserID], sub)
+	}
+
+	var (
+		groups   []models.DuplicateGroupResponse
+		overlaps []models.CategoryOverlapResponse
+	)
+	seen := make(map[string]bool)
+	add := func(reason string, subs []repository.BilledSubscription) {
+		ids := make([]string, len(subs))
+		for i, sub := range subs {
+			ids[i] = sub.ID.String()
+		}
+		slices.Sort(ids)
+		key := strings.Join(ids, ",")
+		group := duplicateGroup(reason, subs)
+		if seen[key] || group.PotentialSavings == 0 {
+			return
+		}
+		seen[key] = true
+		groups = append(groups, group)
+	}
+
+	for _, userSubs := range byUser {
+		// Similarity is not transitive, so similar pairs are joined into connected groups
+		parent := make([]int, len(userSubs))
+		for i := range parent {
+			parent[i] = i
+		}
+		var find func(int) int
+		find = func(i int) int {
+			if parent[i] != i {
+				parent[i] = find(parent[i])
+			}
+			return parent[i]
+		}
+		for i := range userSubs {
+			for j := i + 1; j < len(userSubs); j++ {
+				if similarServices(userSubs[i].Subscription, userSubs[j].Subscription) {
+					parent[find(j)] = find(i)
+				}
+			}
+		}
+
+		similar := make(map[int][]repository.BilledSubscription)
+		for i, sub := range userSubs {
+			similar[find(i)] = append(similar[find(i)], sub)
+		}
+		for _, subs := range similar {
+			if len(subs) < 2 {
+				continue
+			}
+			reason := models.DuplicateSameService
+			for _, sub := range subs[1:] {
+				if !sameService(subs[0].Subscription, sub.Subscription) {
+					reason = models.DuplicateSimilarName
+					break
+				}
+			}
+			add(reason, subs)
+		}
+
+		// Identical prices charged on the same day may be one service paid twice under different names
+		type priceKey struct{ amount, billingDay int }
+		samePrice := make(map[priceKey][]repository.BilledSubscription)
+		services := make(map[priceKey]map[int]bool)
+		for i, sub := range userSubs {
+			if sub.Amount == 0 {
+				continue
+			}
+			key := priceKey{sub.Amount, sub.BillingDay}
+			if services[key] == nil {
+				services[key] = make(map[int]bool)
+			}
+			samePrice[key] = append(samePrice[key], sub)
+			services[key][find(i)] = true
+		}
+		for key, subs := range samePrice {
+			if len(services[key]) > 1 {
+				add(models.DuplicateSamePrice, subs)
+			}
+		}
+
+		type categorySubs struct {
+			name     string
+			subs     []repository.BilledSubscription
+			services map[int]bool
+		}
+		byCategory := make(map[string]*categorySubs)
+		for i, sub := range userSubs {
+			name := strings.TrimSpace(sub.Category)
+			if name == "" {
+				continue
+			}
+			key := strings.ToLower(name)
+			if byCategory[key] == nil {
+				byCategory[key] = &categorySubs{name: name, services: make(map[int]bool)}
+			}
+			byCategory[key].subs = append(byCategory[key].subs, sub)
+			byCategory[key].services[find(i)] = true
+		}
+		for _, category := range byCategory {
+			if len(category.services) < 2 {
+				continue
+			}
+			subs := sortBilled(category.subs)
+			overlap := models.CategoryOverlapResponse{Category: category.name, UserID: subs[0].UserID}
+			overlap.Subscriptions, overlap.MonthCost = toDuplicateSubscriptions(subs)
+			overlaps = append(overlaps, overlap)
+		}
+	}
+
+	slices.SortFunc(groups, func(a, b models.DuplicateGroupResponse) int {
+		return cmp.Or(cmp.Compare(b.PotentialSavings, a.PotentialSavings), strings.Compare(a.UserID.String(), b.UserID.String()),
+			strings.Compare(a.Reason, b.Reason), strings.Compare(a.Subscriptions[0].ID.String(), b.Subscriptions[0].ID.String()))
+	})
+	slices.SortFunc(overlaps, func(a, b models.CategoryOverlapResponse) int {
+		return cmp.Or(cmp.Compare(b.MonthCost, a.MonthCost), strings.Compare(a.UserID.String(), b.UserID.String()),
+			strings.Compare(a.Category, b.Category))
+	})
+	return groups, overlaps
+}
+
+func (s Service) FindDuplicates(ctx context.Context, req models.DuplicatesRequest) (models.DuplicatesResponse, error) {
+	filter := repository.BilledFilter{UserID: req.UserID, Month: currentMonth()}
+	if req.UserID != nil {
+		if err := authorize(ctx, *req.UserID); err != nil {
+			return models.DuplicatesResponse{}, err
+		}
+	} else if p, ok := auth.FromContext(ctx); ok && !p.Admin {
+		filter.UserID = &p.UserID
+	}
+	if req.Month != nil {
+		filter.Month = time.Time(*req.Month)
+	}
+
+	subs, err := s.repo.ListBilledSubscriptions(ctx, filter)
+	if err != nil {
+		return models.DuplicatesResponse{}, fmt.Errorf("repo failed to list billed subscriptions: %w", err)
+	}
+
+	month := monthyear.MonthYear(filter.Month)
+	resp := models.DuplicatesResponse{Month: &month}
+	resp.Groups, resp.CategoryOverlaps = findDuplicates(subs)
+	if resp.Groups == nil {
+		resp.Groups = []models.DuplicateGroupResponse{}
+	}
+	if resp.CategoryOverlaps == nil {
+		resp.CategoryOverlaps = []models.CategoryOverlapResponse{}
+	}
+	return resp, nil
+}
@@ -0,0 +1,157 @@
+package subscription
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/repository"
+)
+
+func billed(name string, catalogID *uuid.UUID, category string, amount int) repository.BilledSubscription {
+	sub := repository.BilledSubscription{Amount: amount}
+	sub.ID, sub.UserID, sub.ServiceName, sub.Category = uuid.New(), ownerID, name, category
+	if catalogID != nil {
+		sub.ServiceID = uuid.NullUUID{UUID: *catalogID, Valid: true}
+	}
+	return sub
+}
+
+// onDay sets the billing day of sub
+func onDay(sub repository.BilledSubscription, day int) repository.BilledSubscription {
+	sub.BillingDay = day
+	return sub
+}
+
+func TestSimilarServices(t *testing.T) {
+	netflix, okko := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name string
+		a, b repository.BilledSubscription
+		want bool
+	}{
+		{name: "same catalog service", a: billed("Netflix", &netflix, "", 0), b: billed("Netflix Family", &netflix, "", 0), want: true},
+		{name: "plan words and case", a: billed("Spotify Premium", nil, "", 0), b: billed("spotify", nil, "", 0), want: true},
+		{name: "typo", a: billed("Netflix", nil, "", 0), b: billed("Netflx", nil, "", 0), want: true},
+		{name: "typo against the catalog", a: billed("Netflix", &netflix, "", 0), b: billed("Netflx", nil, "", 0), want: true},
+		{name: "one name contains the other", a: billed("YouTube", nil, "", 0), b: billed("YouTube Music", nil, "", 0), want: true},
+		{name: "different catalog services", a: billed("Okko", &okko, "", 0), b: billed("Okko Sport", &netflix, "", 0)},
+		{name: "short names must be equal", a: billed("Okko", nil, "", 0), b: billed("Oko", nil, "", 0)},
+		{name: "too many typos", a: billed("Kinopoisk", nil, "", 0), b: billed("Kinoteka", nil, "", 0)},
+		{name: "only plan words", a: billed("Premium", nil, "", 0), b: billed("Premium", nil, "", 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := similarServices(tt.a.Subscription, tt.b.Subscription); got != tt.want {
+				t.Errorf("similarServices(%q, %q) = %v, want %v", tt.a.ServiceName, tt.b.ServiceName, got, tt.want)
+			}
+			if got := similarServices(tt.b.Subscription, tt.a.Subscription); got != tt.want {
+				t.Errorf("similarServices(%q, %q) = %v, want %v", tt.b.ServiceName, tt.a.ServiceName, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	netflix, okko := uuid.New(), uuid.New()
+
+	type group struct {
+		reason  string
+		size    int
+		savings int
+	}
+	tests := []struct {
+		name         string
+		subs         []repository.BilledSubscription
+		wantGroups   []group
+		wantOverlaps []string
+	}{
+		{
+			name:       "same catalog service",
+			subs:       []repository.BilledSubscription{billed("Netflix", &netflix, "", 799), billed("Netflix Family", &netflix, "", 999)},
+			wantGroups: []group{{models.DuplicateSameService, 2, 799}},
+		},
+		{
+			name:       "same name ignoring plan words without catalog",
+			subs:       []repository.BilledSubscription{billed("Spotify Premium", nil, "", 299), billed("spotify", nil, "", 199)},
+			wantGroups: []group{{models.DuplicateSameService, 2, 199}},
+		},
+		{
+			name:       "similar names are joined transitively",
+			subs:       []repository.BilledSubscription{billed("Netflix", &netflix, "", 799), billed("Netflx", nil, "", 599), billed("Netflix Kids", nil, "", 299)},
+			wantGroups: []group{{models.DuplicateSimilarName, 3, 898}},
+		},
+		{
+			name: "similar names of different catalog services",
+			subs: []repository.BilledSubscription{billed("Okko", &okko, "", 399), billed("Okko Sport", &netflix, "", 299), billed("Oko", nil, "", 99)},
+		},
+		{
+			name:       "same price on the same day",
+			subs:       []repository.BilledSubscription{onDay(billed("Yandex Plus", nil, "", 299), 5), onDay(billed("IVI", nil, "", 299), 5)},
+			wantGroups: []group{{models.DuplicateSamePrice, 2, 299}},
+		},
+		{
+			name: "same price on different days",
+			subs: []repository.BilledSubscription{onDay(billed("Yandex Plus", nil, "", 299), 5), onDay(billed("IVI", nil, "", 299), 6)},
+		},
+		{
+			name:       "same price of one service is not repeated",
+			subs:       []repository.BilledSubscription{onDay(billed("Netflix", &netflix, "", 799), 5), onDay(billed("Netflix", &netflix, "", 799), 5)},
+			wantGroups: []group{{models.DuplicateSameService, 2, 799}},
+		},
+		{
+			name: "same price across a duplicate and another service",
+			subs: []repository.BilledSubscription{onDay(billed("Netflix", &netflix, "", 799), 5), onDay(billed("Netflix", &netflix, "", 799), 5),
+				onDay(billed("Okko", &okko, "", 799), 5)},
+			wantGroups: []group{{models.DuplicateSamePrice, 3, 1598}, {models.DuplicateSameService, 2, 799}},
+		},
+		{
+			name:         "category overlap has no savings",
+			subs:         []repository.BilledSubscription{billed("Netflix", &netflix, "Video", 799), billed("Okko", &okko, "video", 399)},
+			wantOverlaps: []string{"Video"},
+		},
+		{
+			name:       "one service in a category is not an overlap",
+			subs:       []repository.BilledSubscription{onDay(billed("Netflix", &netflix, "video", 799), 1), onDay(billed("Netflx", nil, "video", 599), 2)},
+			wantGroups: []group{{models.DuplicateSimilarName, 2, 599}},
+		},
+		{
+			name: "free duplicate",
+			subs: []repository.BilledSubscription{billed("Netflix", &netflix, "", 799), billed("Netflix", &netflix, "", 0)},
+		},
+		{
+			name: "subscriptions of different users",
+			subs: []repository.BilledSubscription{billed("Netflix", &netflix, "", 799), func() repository.BilledSubscription {
+				sub := billed("Netflix", &netflix, "", 799)
+				sub.UserID = otherID
+				return sub
+			}()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groups, overlaps := findDuplicates(tt.subs)
+			if len(groups) != len(tt.wantGroups) {
+				t.Fatalf("findDuplicates() groups = %d, want %d", len(groups), len(tt.wantGroups))
+			}
+			for i, g := range groups {
+				got := group{g.Reason, len(g.Subscriptions), g.PotentialSavings}
+				if got != tt.wantGroups[i] {
+					t.Errorf("group %d = %+v, want %+v", i, got, tt.wantGroups[i])
+				}
+			}
+			if len(overlaps) != len(tt.wantOverlaps) {
+				t.Fatalf("findDuplicates() overlaps = %d, want %d", len(overlaps), len(tt.wantOverlaps))
+			}
+			for i, overlap := range overlaps {
+				if overlap.Category != tt.wantOverlaps[i] {
+					t.Errorf("overlap %d category = %q, want %q", i, overlap.Category, tt.wantOverlaps[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/repository"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/service"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/logger"
+)
+
+// splitShares splits a monthly amount between members of split and the owner the same way cost reports do.
+// It returns the owner's share and the shares of members in the order of split.Members.
+func splitShares(amount int, split repository.SubscriptionSplit) (int, []int) {
+	total := 0
+	for _, member := range split.Members {
+		total += member.Share
+	}
+
+	shares := make([]int, len(split.Members))
+	owner := amount
+	for i, member := range split.Members {
+		switch split.Rule {
+		case repository.SplitPercent:
+			shares[i] = amount * member.Share / 100
+		case repository.SplitFixed:
+			shares[i] = member.Share
+			if total > amount {
+				shares[i] = amount * member.Share / total
+			}
+		default:
+			shares[i] = amount / (len(split.Members) + 1)
+		}
+		owner -= shares[i]
+	}
+	return owner, shares
+}
+
+func membersToResponse(sub repository.Subscription, split repository.SubscriptionSplit) models.MembersResponse {
+	owner, shares := splitShares(sub.CurrentPrice, split)
+	resp := models.MembersResponse{
+		SplitRule:    split.Rule,
+		OwnerID:      sub.UserID,
+		OwnerShare:   owner,
+		Members:      make([]models.SubscriptionMemberResponse, len(split.Members)),
+		CurrentPrice: sub.CurrentPrice,
+	}
+	for i, member := range split.Members {
+		resp.Members[i] = models.SubscriptionMemberResponse{UserID: member.UserID, Share: member.Share, CurrentShare: shares[i]}
+	}
+	return resp
+}
+
+func (s Service) GetMembers(ctx context.Context, id uuid.UUID) (models.MembersResponse, error) {
+	sub, split, err := s.getSharedSubscription(ctx, id)
+	if err != nil {
+		return models.MembersResponse{}, err
+	}
+	return membersToResponse(sub, split), nil
+}
+
+// isMember reports whether userID shares the subscription of split
+func isMember(split repository.SubscriptionSplit, userID uuid.UUID) bool {
+	return slices.ContainsFunc(split.Members, func(m repository.SubscriptionMember) bool { return m.UserID == userID })
+}
+
+func (s Service) SetMembers(ctx context.Context, id uuid.UUID, req models.SetMembersRequest) (models.MembersResponse, error) {
+	sub, err := s.getOwnSubscription(ctx, id)
+	if err != nil {
+		return models.MembersResponse{}, err
+	}
+
+	split := repository.SubscriptionSplit{
+		SubscriptionID: id,
+		Rule:           req.SplitRule,
+		Members:        make([]repository.SubscriptionMember, len(req.Members)),
+	}
+	percents := 0
+	for i, member := range req.Members {
+		if member.UserID == sub.UserID {
+			logger.FromContext(ctx).DebugContext(ctx, "owner as a member rejected", "subscription_id", id)
+			return models.MembersResponse{}, service.ErrInvalidSplit
+		}
+		split.Members[i] = repository.SubscriptionMember{UserID: member.UserID, Share: member.Share}
+		if req.SplitRule == repository.SplitEqual {
+			split.Members[i].Share = 0
+		}
+		percents += member.Share
+	}
+	if req.SplitRule == repository.SplitPercent && percents > 100 {
+		logger.FromContext(ctx).DebugContext(ctx, "percent shares over 100 rejected", "subscription_id", id, "percents", percents)
+		return models.MembersResponse{}, service.ErrInvalidSplit
+	}
+
+	split, err = s.repo.SetSubscriptionSplit(ctx, split)
+	if err != nil {
+		return models.MembersResponse{}, fmt.Errorf("repo failed to set subscription split: %w", err)
+	}
+
+	return membersToResponse(sub, split), nil
+}
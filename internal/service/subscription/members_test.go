@@ -0,0 +1,55 @@
+package subscription
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/repository"
+)
+
+func newSplit(rule string, shares ...int) repository.SubscriptionSplit {
+	s := repository.SubscriptionSplit{Rule: rule}
+	for _, share := range shares {
+		s.Members = append(s.Members, repository.SubscriptionMember{UserID: uuid.New(), Share: share})
+	}
+	return s
+}
+
+func TestSplitShares(t *testing.T) {
+	tests := []struct {
+		name       string
+		amount     int
+		split      repository.SubscriptionSplit
+		wantOwner  int
+		wantShares []int
+	}{
+		{name: "no members", amount: 799, split: newSplit(repository.SplitEqual), wantOwner: 799, wantShares: []int{}},
+		{name: "equal", amount: 900, split: newSplit(repository.SplitEqual, 0, 0), wantOwner: 300, wantShares: []int{300, 300}},
+		{name: "equal remainder goes to the owner", amount: 1000, split: newSplit(repository.SplitEqual, 0, 0), wantOwner: 334, wantShares: []int{333, 333}},
+		{name: "percent", amount: 1000, split: newSplit(repository.SplitPercent, 25, 25), wantOwner: 500, wantShares: []int{250, 250}},
+		{name: "percent rounds down", amount: 299, split: newSplit(repository.SplitPercent, 33, 33, 33), wantOwner: 5, wantShares: []int{98, 98, 98}},
+		{name: "fixed", amount: 799, split: newSplit(repository.SplitFixed, 200, 100), wantOwner: 499, wantShares: []int{200, 100}},
+		{name: "fixed equal to the amount", amount: 300, split: newSplit(repository.SplitFixed, 200, 100), wantOwner: 0, wantShares: []int{200, 100}},
+		{name: "fixed above the amount is scaled", amount: 300, split: newSplit(repository.SplitFixed, 400, 200), wantOwner: 0, wantShares: []int{200, 100}},
+		{name: "fixed scaling rounds down", amount: 100, split: newSplit(repository.SplitFixed, 100, 100, 100), wantOwner: 1, wantShares: []int{33, 33, 33}},
+		{name: "free", amount: 0, split: newSplit(repository.SplitFixed, 100), wantOwner: 0, wantShares: []int{0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			owner, shares := splitShares(tt.amount, tt.split)
+			if owner != tt.wantOwner || !slices.Equal(shares, tt.wantShares) {
+				t.Fatalf("splitShares(%d) = %d, %v, want %d, %v", tt.amount, owner, shares, tt.wantOwner, tt.wantShares)
+			}
+			sum := owner
+			for _, share := range shares {
+				sum += share
+			}
+			if sum != tt.amount {
+				t.Errorf("owner and members pay %d, want %d", sum, tt.amount)
+			}
+		})
+	}
+}
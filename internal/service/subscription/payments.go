@@ -0,0 +1,219 @@
+package subscription
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/auth"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/repository"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/service"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/logger"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/monthyear"
+)
+
+func paymentMethodToResponse(method repository.PaymentMethod) models.PaymentMethodResponse {
+	resp := models.PaymentMethodResponse{
+		ID:        method.ID,
+		UserID:    method.UserID,
+		Kind:      method.Kind,
+		Name:      method.Name,
+		Label:     method.Label,
+		Brand:     method.Brand,
+		Last4:     method.Last4,
+		CreatedAt: method.CreatedAt,
+	}
+	if method.Expires.Valid {
+		expires := monthyear.MonthYear(method.Expires.Time)
+		resp.Expires = &expires
+	}
+	return resp
+}
+
+// paymentMethodFromRequest keeps only the fields of the method's kind
+func paymentMethodFromRequest(req models.PaymentMethodRequest) repository.PaymentMethod {
+	method := repository.PaymentMethod{Kind: req.Kind, Label: strings.TrimSpace(req.Label)}
+	if req.Kind == repository.PaymentCard {
+		method.Brand = strings.TrimSpace(req.Brand)
+		method.Last4 = req.Last4
+		method.Expires = sql.NullTime{Time: time.Time(*req.Expires), Valid: true}
+	}
+	return method
+}
+
+// getPaymentMethod fetches a payment method of userID, methods of other users are reported as unknown
+func (s Service) getPaymentMethod(ctx context.Context, id, userID uuid.UUID) (repository.PaymentMethod, error) {
+	method, err := s.repo.GetPaymentMethod(ctx, id)
+	if errors.Is(err, repository.ErrPaymentMethodNotFound) {
+		return repository.PaymentMethod{}, service.ErrUnknownPaymentMethod
+	}
+	if err != nil {
+		return repository.PaymentMethod{}, fmt.Errorf("repo failed to get payment method: %w", err)
+	}
+	if method.UserID != userID {
+		logger.FromContext(ctx).DebugContext(ctx, "payment method of another user rejected",
+			"payment_method_id", id, "method_user_id", method.UserID, "user_id", userID)
+		return repository.PaymentMethod{}, service.ErrUnknownPaymentMethod
+	}
+	return method, nil
+}
+
+// authorizePaymentMethod checks that the caller may manage payment method id of userID,
+// a method of another user is not found
+func (s Service) authorizePaymentMethod(ctx context.Context, userID, id uuid.UUID) error {
+	if err := authorize(ctx, userID); err != nil {
+		return err
+	}
+	method, err := s.repo.GetPaymentMethod(ctx, id)
+	if err != nil {
+		return fmt.Errorf("repo failed to get payment method: %w", err)
+	}
+	if method.UserID != userID {
+		return repository.ErrPaymentMethodNotFound
+	}
+	return nil
+}
+
+func (s Service) CreatePaymentMethod(ctx context.Context, userID uuid.UUID, req models.PaymentMethodRequest) (models.PaymentMethodResponse, error) {
+	if err := authorize(ctx, userID); err != nil {
+		return models.PaymentMethodResponse{}, err
+	}
+
+	method := paymentMethodFromRequest(req)
+	method.UserID = userID
+	method, err := s.repo.CreatePaymentMethod(ctx, method)
+	if err != nil {
+		return models.PaymentMethodResponse{}, fmt.Errorf("repo failed to create payment method: %w", err)
+	}
+	return paymentMethodToResponse(method), nil
+}
+
+func (s Service) ListPaymentMethods(ctx context.Context, userID uuid.UUID) ([]models.PaymentMethodResponse, error) {
+	if err := authorize(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	methods, err := s.repo.ListPaymentMethods(ctx, &userID)
+	if err != nil {
+		return nil, fmt.Errorf("repo failed to list payment methods: %w", err)
+	}
+
+	resp := make([]models.PaymentMethodResponse, len(methods))
+	for i, method := range methods {
+		resp[i] = paymentMethodToResponse(method)
+	}
+	return resp, nil
+}
+
+func (s Service) UpdatePaymentMethod(ctx context.Context, userID, id uuid.UUID, req models.PaymentMethodRequest) (models.PaymentMethodResponse, error) {
+	if err := s.authorizePaymentMethod(ctx, userID, id); err != nil {
+		return models.PaymentMethodResponse{}, err
+	}
+
+	method := paymentMethodFromRequest(req)
+	method.ID, method.UserID = id, userID
+	method, err := s.repo.UpdatePaymentMethod(ctx, method)
+	if err != nil {
+		return models.PaymentMethodResponse{}, fmt.Errorf("repo failed to update payment method: %w", err)
+	}
+	return paymentMethodToResponse(method), nil
+}
+
+func (s Service) DeletePaymentMethod(ctx context.Context, userID, id uuid.UUID, req models.DeletePaymentMethodRequest) error {
+	if err := s.authorizePaymentMethod(ctx, userID, id); err != nil {
+		return err
+	}
+
+	var replacementID uuid.NullUUID
+	if req.ReplacementID != nil {
+		if *req.ReplacementID == id {
+			return service.ErrUnknownPaymentMethod
+		}
+		if _, err := s.getPaymentMethod(ctx, *req.ReplacementID, userID); err != nil {
+			return err
+		}
+		replacementID = uuid.NullUUID{UUID: *req.ReplacementID, Valid: true}
+	}
+
+	if err := s.repo.DeletePaymentMethod(ctx, id, replacementID); err != nil {
+		return fmt.Errorf("repo failed to delete payment method: %w", err)
+	}
+	return nil
+}
+
+// ListExpiringCards finds subscriptions whose next charge falls after the last day of their card's validity.
+// The next charge is the first charged billing day from today within the current and the next month.
+func (s Service) ListExpiringCards(ctx context.Context, req models.ExpiringCardsRequest) ([]models.ExpiringCardResponse, error) {
+	userID := req.UserID
+	if req.UserID != nil {
+		if err := authorize(ctx, *req.UserID); err != nil {
+			return nil, err
+		}
+	} else if p, ok := auth.FromContext(ctx); ok && !p.Admin {
+		userID = &p.UserID
+	}
+
+	methods, err := s.repo.ListPaymentMethods(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("repo failed to list payment methods: %w", err)
+	}
+	cards := make(map[uuid.UUID]repository.PaymentMethod)
+	for _, method := range methods {
+		if method.Expires.Valid {
+			cards[method.ID] = method
+		}
+	}
+
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	warnings := []models.ExpiringCardResponse{}
+	if len(cards) == 0 {
+		return warnings, nil
+	}
+	// Subscriptions already charged this month are next charged in the next one
+	seen := make(map[uuid.UUID]bool)
+	for month := currentMonth(); !month.After(currentMonth().AddDate(0, 1, 0)); month = month.AddDate(0, 1, 0) {
+		subs, err := s.repo.ListBilledSubscriptions(ctx, repository.BilledFilter{Month: month, UserID: userID})
+		if err != nil {
+			return nil, fmt.Errorf("repo failed to list billed subscriptions: %w", err)
+		}
+		for _, sub := range subs {
+			date := billingDate(month, sub.BillingDay)
+			if seen[sub.ID] || sub.Amount == 0 || date.Before(today) {
+				continue
+			}
+			seen[sub.ID] = true
+
+			card, ok := cards[sub.PaymentMethodID.UUID]
+			if !sub.PaymentMethodID.Valid || !ok {
+				continue
+			}
+			// A card is valid through the last day of its expiry month
+			if date.Before(card.Expires.Time.AddDate(0, 1, 0)) {
+				continue
+			}
+			warnings = append(warnings, models.ExpiringCardResponse{
+				PaymentMethod:   paymentMethodToResponse(card),
+				Subscription:    toResponse(sub.Subscription),
+				NextBillingDate: date.Format(time.DateOnly),
+				Amount:          sub.Amount,
+			})
+		}
+	}
+
+	slices.SortFunc(warnings, func(a, b models.ExpiringCardResponse) int {
+		return cmp.Or(strings.Compare(a.NextBillingDate, b.NextBillingDate),
+			strings.Compare(a.Subscription.ServiceName, b.Subscription.ServiceName),
+			strings.Compare(a.Subscription.ID.String(), b.Subscription.ID.String()))
+	})
+	return warnings, nil
+}
@@ -0,0 +1,140 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/repository"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/service"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/tenant"
+)
+
+func preferencesToResponse(prefs repository.NotificationPreferences) models.NotificationPreferencesResponse {
+	return models.NotificationPreferencesResponse{
+		UserID:     prefs.UserID,
+		Enabled:    prefs.Enabled,
+		DaysBefore: prefs.DaysBefore,
+		Channel:    prefs.Channel,
+		Email:      prefs.Email,
+		WebhookURL: prefs.WebhookURL,
+	}
+}
+
+func (s Service) GetNotificationPreferences(ctx context.Context, userID uuid.UUID) (models.NotificationPreferencesResponse, error) {
+	if err := authorize(ctx, userID); err != nil {
+		return models.NotificationPreferencesResponse{}, err
+	}
+
+	prefs, err := s.repo.GetNotificationPreferences(ctx, userID)
+	if err != nil {
+		return models.NotificationPreferencesResponse{}, fmt.Errorf("repo failed to get notification preferences: %w", err)
+	}
+	return preferencesToResponse(prefs), nil
+}
+
+func (s Service) SetNotificationPreferences(ctx context.Context, userID uuid.UUID, req models.NotificationPreferencesRequest) (models.NotificationPreferencesResponse, error) {
+	if err := authorize(ctx, userID); err != nil {
+		return models.NotificationPreferencesResponse{}, err
+	}
+
+	prefs := repository.NotificationPreferences{
+		UserID:     userID,
+		Enabled:    *req.Enabled,
+		DaysBefore: req.DaysBefore,
+		Channel:    req.Channel,
+		Email:      req.Email,
+		WebhookURL: req.WebhookURL,
+	}
+	prefs, err := s.repo.SetNotificationPreferences(ctx, prefs)
+	if err != nil {
+		return models.NotificationPreferencesResponse{}, fmt.Errorf("repo failed to set notification preferences: %w", err)
+	}
+	return preferencesToResponse(prefs), nil
+}
+
+// Reminders sends reminders of upcoming renewals and endings of subscriptions of all tenants
+// through the notifier of the channel each user prefers
+type Reminders struct {
+	store     repository.ReminderStore
+	notifiers map[string]service.Notifier
+	interval  time.Duration
+}
+
+// NewReminders creates a scheduler checking for due reminders every interval.
+// notifiers maps channels to notifiers, reminders of channels without a notifier are not sent.
+func NewReminders(store repository.ReminderStore, notifiers map[string]service.Notifier, interval time.Duration) *Reminders {
+	return &Reminders{store: store, notifiers: notifiers, interval: interval}
+}
+
+// Run sends due reminders right away and then every interval until ctx is canceled
+func (r *Reminders) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		r.SendDue(ctx, time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SendDue sends reminders due by the day of now. A reminder is logged before it is sent and the log entry
+// is removed if sending fails, so each reminder is sent at most once and failed ones are retried on the next run.
+func (r *Reminders) SendDue(ctx context.Context, now time.Time) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	due, err := r.store.ListDueReminders(ctx, today)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to list due reminders", "error", err)
+		return
+	}
+
+	for _, d := range due {
+		if ctx.Err() != nil {
+			return
+		}
+		ctx := tenant.WithID(ctx, d.TenantID)
+		log := slog.With("tenant_id", d.TenantID, "subscription_id", d.SubscriptionID, "kind", d.Kind,
+			"channel", d.Preferences.Channel)
+
+		notifier, ok := r.notifiers[d.Preferences.Channel]
+		if !ok {
+			log.WarnContext(ctx, "reminder channel is not configured")
+			continue
+		}
+		claimed, err := r.store.ClaimReminder(ctx, d)
+		if err != nil {
+			log.ErrorContext(ctx, "failed to claim reminder", "error", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		reminder := models.Reminder{
+			Kind:           d.Kind,
+			TenantID:       d.TenantID,
+			SubscriptionID: d.SubscriptionID,
+			UserID:         d.UserID,
+			ServiceName:    d.ServiceName,
+			Date:           d.Date.Format(time.DateOnly),
+			Amount:         d.Amount,
+			Email:          d.Preferences.Email,
+			WebhookURL:     d.Preferences.WebhookURL,
+		}
+		if err := notifier.Notify(ctx, reminder); err != nil {
+			log.ErrorContext(ctx, "failed to send reminder", "error", err)
+			if err := r.store.ReleaseReminder(context.WithoutCancel(ctx), d); err != nil {
+				log.ErrorContext(ctx, "failed to release reminder", "error", err)
+			}
+			continue
+		}
+		log.InfoContext(ctx, "reminder sent")
+	}
+}
@@ -0,0 +1,150 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/repository"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/service"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/tenant"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/logger"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/monthyear"
+)
+
+func scheduledChangeToResponse(change repository.ScheduledChange) models.ScheduledChangeResponse {
+	month := monthyear.MonthYear(change.Month)
+	resp := models.ScheduledChangeResponse{
+		ID:             change.ID,
+		SubscriptionID: change.SubscriptionID,
+		Action:         change.Action,
+		Month:          &month,
+		Status:         change.Status,
+		CreatedAt:      change.CreatedAt,
+	}
+	if change.Action == repository.ScheduledPrice {
+		resp.Price = &change.Price
+	}
+	if change.AppliedAt.Valid {
+		resp.AppliedAt = &change.AppliedAt.Time
+	}
+	return resp
+}
+
+func (s Service) ScheduleChange(ctx context.Context, id uuid.UUID, req models.ScheduleChangeRequest) (models.ScheduledChangeResponse, error) {
+	sub, err := s.getOwnSubscription(ctx, id)
+	if err != nil {
+		return models.ScheduledChangeResponse{}, err
+	}
+
+	change := repository.ScheduledChange{SubscriptionID: id, Action: req.Action, Month: time.Time(*req.Month)}
+	// A cancellation takes effect in the month after its last billed month, a price change in its month
+	effective := change.Month
+	valid := true
+	switch req.Action {
+	case repository.ScheduledCancel:
+		effective = change.Month.AddDate(0, 1, 0)
+		valid = !change.Month.Before(sub.StartDate) && (!sub.EndDate.Valid || change.Month.Before(sub.EndDate.Time))
+	case repository.ScheduledPrice:
+		change.Price = *req.Price
+		// The first month is billed by the price of the subscription itself
+		valid = change.Month.After(sub.StartDate) && (!sub.EndDate.Valid || !change.Month.After(sub.EndDate.Time))
+	}
+	if !valid || !effective.After(currentMonth()) {
+		logger.FromContext(ctx).DebugContext(ctx, "scheduled change outside subscription period rejected",
+			"subscription_id", id, "action", req.Action, "month", change.Month)
+		return models.ScheduledChangeResponse{}, service.ErrInvalidSchedule
+	}
+
+	change, err = s.repo.ScheduleChange(ctx, change)
+	if err != nil {
+		return models.ScheduledChangeResponse{}, fmt.Errorf("repo failed to schedule change: %w", err)
+	}
+	return scheduledChangeToResponse(change), nil
+}
+
+func (s Service) ListScheduledChanges(ctx context.Context, id uuid.UUID) ([]models.ScheduledChangeResponse, error) {
+	if _, err := s.getOwnSubscription(ctx, id); err != nil {
+		return nil, err
+	}
+
+	changes, err := s.repo.ListScheduledChanges(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("repo failed to list scheduled changes: %w", err)
+	}
+
+	resp := make([]models.ScheduledChangeResponse, len(changes))
+	for i, change := range changes {
+		resp[i] = scheduledChangeToResponse(change)
+	}
+	return resp, nil
+}
+
+func (s Service) CancelScheduledChange(ctx context.Context, id, changeID uuid.UUID) error {
+	if _, err := s.getOwnSubscription(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.repo.CancelScheduledChange(ctx, id, changeID); err != nil {
+		return fmt.Errorf("repo failed to cancel scheduled change: %w", err)
+	}
+	return nil
+}
+
+// Scheduler applies scheduled changes of subscriptions of all tenants when their months come
+type Scheduler struct {
+	store    repository.ScheduledChangeStore
+	interval time.Duration
+}
+
+// NewScheduler creates a scheduler checking for due changes every interval
+func NewScheduler(store repository.ScheduledChangeStore, interval time.Duration) *Scheduler {
+	return &Scheduler{store: store, interval: interval}
+}
+
+// Run applies due changes right away and then every interval until ctx is canceled
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		s.ApplyDue(ctx, time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ApplyDue applies changes taking effect by the month of now. Each change is applied in its own transaction,
+// failed ones stay pending and are retried on the next run.
+func (s *Scheduler) ApplyDue(ctx context.Context, now time.Time) {
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	due, err := s.store.ListDueScheduledChanges(ctx, month)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to list due scheduled changes", "error", err)
+		return
+	}
+
+	for _, change := range due {
+		if ctx.Err() != nil {
+			return
+		}
+		ctx := tenant.WithID(ctx, change.TenantID)
+		log := slog.With("tenant_id", change.TenantID, "subscription_id", change.SubscriptionID, "id", change.ID,
+			"action", change.Action)
+
+		applied, err := s.store.ApplyScheduledChange(ctx, change)
+		if err != nil {
+			log.ErrorContext(ctx, "failed to apply scheduled change", "error", err)
+			continue
+		}
+		if applied {
+			log.InfoContext(ctx, "scheduled change applied", "month", change.Month.Format(time.DateOnly))
+		}
+	}
+}
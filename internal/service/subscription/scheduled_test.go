@@ -0,0 +1,70 @@
+package subscription
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/repository"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/service"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/monthyear"
+)
+
+func TestScheduleChange(t *testing.T) {
+	current := currentMonth()
+	month := func(offset int) time.Time { return current.AddDate(0, offset, 0) }
+	period := func(start, end int) repository.Subscription {
+		sub := repository.Subscription{ID: subID, ServiceName: "Yandex Plus", UserID: ownerID, StartDate: month(start), BillingDay: 1}
+		sub.EndDate = sql.NullTime{Time: month(end), Valid: true}
+		return sub
+	}
+	open := repository.Subscription{ID: subID, ServiceName: "Yandex Plus", UserID: ownerID, StartDate: month(-2), BillingDay: 1}
+
+	tests := []struct {
+		name    string
+		sub     repository.Subscription
+		action  string
+		month   int // offset from the current month
+		wantErr error
+	}{
+		// Cancellations name the last billed month, which lies in [start, end)
+		{name: "cancel before start", sub: period(1, 4), action: repository.ScheduledCancel, month: 0, wantErr: service.ErrInvalidSchedule},
+		{name: "cancel at start", sub: period(1, 4), action: repository.ScheduledCancel, month: 1},
+		{name: "cancel before end", sub: period(1, 4), action: repository.ScheduledCancel, month: 3},
+		{name: "cancel at end", sub: period(1, 4), action: repository.ScheduledCancel, month: 4, wantErr: service.ErrInvalidSchedule},
+		{name: "cancel without end", sub: open, action: repository.ScheduledCancel, month: 12},
+		// Price changes name the first month at the new price, which lies in (start, end]
+		{name: "price at start", sub: period(1, 4), action: repository.ScheduledPrice, month: 1, wantErr: service.ErrInvalidSchedule},
+		{name: "price after start", sub: period(1, 4), action: repository.ScheduledPrice, month: 2},
+		{name: "price at end", sub: period(1, 4), action: repository.ScheduledPrice, month: 4},
+		{name: "price after end", sub: period(1, 4), action: repository.ScheduledPrice, month: 5, wantErr: service.ErrInvalidSchedule},
+		{name: "price without end", sub: open, action: repository.ScheduledPrice, month: 12},
+		// The change must take effect after the current month
+		{name: "cancel in the previous month", sub: open, action: repository.ScheduledCancel, month: -1, wantErr: service.ErrInvalidSchedule},
+		{name: "cancel in the current month", sub: open, action: repository.ScheduledCancel, month: 0},
+		{name: "price in the current month", sub: open, action: repository.ScheduledPrice, month: 0, wantErr: service.ErrInvalidSchedule},
+		{name: "price in the next month", sub: open, action: repository.ScheduledPrice, month: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepo()
+			repo.subs[subID] = tt.sub
+			changeMonth, price := monthyear.MonthYear(month(tt.month)), 499
+			req := models.ScheduleChangeRequest{Action: tt.action, Month: &changeMonth}
+			if tt.action == repository.ScheduledPrice {
+				req.Price = &price
+			}
+
+			_, err := NewService(repo).ScheduleChange(asUser(ownerID), subID, req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ScheduleChange() error = %v, want %v", err, tt.wantErr)
+			}
+			if scheduled := len(repo.scheduled) == 1; scheduled != (tt.wantErr == nil) {
+				t.Errorf("change stored = %v, want %v", scheduled, tt.wantErr == nil)
+			}
+		})
+	}
+}
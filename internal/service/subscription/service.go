@@ -1,15 +1,21 @@
 package subscription
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/auth"
 	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
 	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/repository"
 	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/service"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/logger"
 	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/monthyear"
 )
 
@@ -23,12 +29,195 @@ func NewService(repo repository.SubscriptionRepository) Service {
 	return Service{repo: repo}
 }
 
+// authorize checks that the caller may manage subscriptions of userID.
+// Admins and unauthenticated requests (authentication disabled) are not restricted.
+func authorize(ctx context.Context, userID uuid.UUID) error {
+	p, ok := auth.FromContext(ctx)
+	if !ok || p.Admin || p.UserID == userID {
+		return nil
+	}
+	logger.FromContext(ctx).WarnContext(ctx, "access to another user's subscriptions denied",
+		"caller_id", p.UserID, "owner_id", userID)
+	return service.ErrForbidden
+}
+
+// findSubscription fetches a subscription before access is checked. Callers limited to their own subscriptions
+// get ErrForbidden for a missing one too, so they cannot tell whether a subscription of another user exists.
+func (s Service) findSubscription(ctx context.Context, id uuid.UUID) (repository.Subscription, error) {
+	sub, err := s.repo.GetSubscriptionByID(ctx, id)
+	if errors.Is(err, repository.ErrSubscriptionNotFound) {
+		if p, ok := auth.FromContext(ctx); ok && !p.Admin {
+			logger.FromContext(ctx).DebugContext(ctx, "missing subscription reported as forbidden",
+				"caller_id", p.UserID, "subscription_id", id)
+			return repository.Subscription{}, service.ErrForbidden
+		}
+	}
+	if err != nil {
+		return repository.Subscription{}, fmt.Errorf("repo failed to get subcsciption by id: %w", err)
+	}
+	return sub, nil
+}
+
+// getOwnSubscription fetches a subscription and checks that the caller may manage it
+func (s Service) getOwnSubscription(ctx context.Context, id uuid.UUID) (repository.Subscription, error) {
+	sub, err := s.findSubscription(ctx, id)
+	if err != nil {
+		return repository.Subscription{}, err
+	}
+	if err := authorize(ctx, sub.UserID); err != nil {
+		return repository.Subscription{}, err
+	}
+	return sub, nil
+}
+
+// getSharedSubscription fetches a subscription with its split and checks that the caller may read it:
+// members of a shared subscription see it like its owner, but cannot change it
+func (s Service) getSharedSubscription(ctx context.Context, id uuid.UUID) (repository.Subscription, repository.SubscriptionSplit, error) {
+	sub, err := s.findSubscription(ctx, id)
+	if err != nil {
+		return repository.Subscription{}, repository.SubscriptionSplit{}, err
+	}
+	split, err := s.repo.GetSubscriptionSplit(ctx, id)
+	if err != nil {
+		return repository.Subscription{}, repository.SubscriptionSplit{}, fmt.Errorf("repo failed to get subscription split: %w", err)
+	}
+	if p, ok := auth.FromContext(ctx); ok && isMember(split, p.UserID) {
+		return sub, split, nil
+	}
+	if err := authorize(ctx, sub.UserID); err != nil {
+		return repository.Subscription{}, repository.SubscriptionSplit{}, err
+	}
+	return sub, split, nil
+}
+
+// currentMonth returns the first day of the current month, months are billed in UTC
+func currentMonth() time.Time {
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// normalizeTags lowercases and trims tags and removes duplicates, tags are compared ignoring case
+func normalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
+
+func toResponse(sub repository.Subscription) models.SubscriptionResponse {
+	resp := models.SubscriptionResponse{
+		ID:           sub.ID,
+		ServiceName:  sub.ServiceName,
+		AccountLabel: sub.AccountLabel,
+		Price:        sub.Price,
+		CurrentPrice: sub.CurrentPrice,
+		UserID:       sub.UserID,
+		BillingDay:   sub.BillingDay,
+		TrialMonths:  sub.TrialMonths,
+		TrialPrice:   sub.TrialPrice,
+		Category:     sub.Category,
+		Tags:         sub.Tags,
+	}
+	if resp.Tags == nil {
+		resp.Tags = []string{}
+	}
+	if sub.ServiceID.Valid {
+		resp.ServiceID = &sub.ServiceID.UUID
+	}
+	if sub.PlanID.Valid {
+		resp.PlanID = &sub.PlanID.UUID
+	}
+	if sub.PaymentMethodID.Valid {
+		resp.PaymentMethodID = &sub.PaymentMethodID.UUID
+	}
+	startDate := monthyear.MonthYear(sub.StartDate)
+	resp.StartDate = &startDate
+	if sub.EndDate.Valid {
+		endDate := monthyear.MonthYear(sub.EndDate.Time)
+		resp.EndDate = &endDate
+	}
+	if sub.TrialMonths > 0 {
+		trialEndDate := monthyear.MonthYear(sub.StartDate.AddDate(0, sub.TrialMonths-1, 0))
+		resp.TrialEndDate = &trialEndDate
+	}
+	switch {
+	case sub.EndDate.Valid && sub.EndDate.Time.Before(currentMonth()):
+		resp.State = models.StateEnded
+	case sub.Paused:
+		resp.State = models.StatePaused
+	default:
+		resp.State = models.StateActive
+	}
+	return resp
+}
+
 func (s Service) CreateSubscription(ctx context.Context, req models.CreateSubscriptionRequest) (models.SubscriptionResponse, error) {
+	if err := authorize(ctx, req.UserID); err != nil {
+		return models.SubscriptionResponse{}, err
+	}
+
+	svc, err := s.resolveService(ctx, req.ServiceName, req.ServiceID)
+	if err != nil {
+		return models.SubscriptionResponse{}, err
+	}
+	serviceName, serviceID := svc.Name, catalogID(svc)
+
+	var price int
+	if req.Price != nil {
+		price = *req.Price
+	}
+	var planID uuid.NullUUID
+	if req.PlanID != nil {
+		plan, err := s.getPlan(ctx, *req.PlanID, serviceID)
+		if err != nil {
+			return models.SubscriptionResponse{}, err
+		}
+		// Without a matched service the plan determines it
+		if !serviceID.Valid {
+			if svc, err = s.repo.GetServiceByID(ctx, plan.ServiceID); err != nil {
+				return models.SubscriptionResponse{}, fmt.Errorf("repo failed to get service of plan: %w", err)
+			}
+			serviceName, serviceID = svc.Name, catalogID(svc)
+		}
+		planID = uuid.NullUUID{UUID: plan.ID, Valid: true}
+		if req.Price == nil {
+			price = monthlyPrice(plan)
+		}
+	}
+
 	sub := repository.Subscription{
-		ServiceName: req.ServiceName,
-		Price:       req.Price,
-		UserID:      req.UserID,
-		StartDate:   time.Time(*req.StartDate),
+		ServiceName:  serviceName,
+		ServiceID:    serviceID,
+		AccountLabel: req.AccountLabel,
+		PlanID:       planID,
+		Price:        price,
+		CurrentPrice: price,
+		UserID:       req.UserID,
+		StartDate:    time.Time(*req.StartDate),
+		BillingDay:   cmp.Or(req.BillingDay, 1),
+		TrialMonths:  req.TrialMonths,
+		TrialPrice:   req.TrialPrice,
+		Category:     req.Category,
+		Tags:         normalizeTags(req.Tags),
+	}
+	if sub.Category == "" {
+		sub.Category = svc.Category
+	}
+	if req.PaymentMethodID != nil {
+		if _, err := s.getPaymentMethod(ctx, *req.PaymentMethodID, req.UserID); err != nil {
+			return models.SubscriptionResponse{}, err
+		}
+		sub.PaymentMethodID = uuid.NullUUID{UUID: *req.PaymentMethodID, Valid: true}
+	}
+	if sub.Tags != nil {
+		slices.Sort(sub.Tags)
 	}
 	if req.EndDate != nil {
 		endDate := time.Time(*req.EndDate)
@@ -43,46 +232,30 @@ func (s Service) CreateSubscription(ctx context.Context, req models.CreateSubscr
 		return models.SubscriptionResponse{}, fmt.Errorf("repo failed to create subcsciption: %w", err)
 	}
 
-	return models.SubscriptionResponse{
-		ID:          id,
-		ServiceName: req.ServiceName,
-		Price:       req.Price,
-		UserID:      req.UserID,
-		StartDate:   req.StartDate,
-		EndDate:     req.EndDate,
-	}, nil
+	sub.ID = id
+	return toResponse(sub), nil
 }
 
 func (s Service) GetSubscriptionByID(ctx context.Context, id uuid.UUID) (models.SubscriptionResponse, error) {
-	sub, err := s.repo.GetSubscriptionByID(ctx, id)
+	sub, _, err := s.getSharedSubscription(ctx, id)
 	if err != nil {
-		return models.SubscriptionResponse{}, fmt.Errorf("repo failed to get subcsciption by id: %w", err)
+		return models.SubscriptionResponse{}, err
 	}
 
-	resp := models.SubscriptionResponse{
-		ID:          sub.ID,
-		ServiceName: sub.ServiceName,
-		Price:       sub.Price,
-		UserID:      sub.UserID,
-	}
-	startDate := monthyear.MonthYear(sub.StartDate)
-	resp.StartDate = &startDate
-	if sub.EndDate.Valid {
-		endDate := monthyear.MonthYear(sub.EndDate.Time)
-		resp.EndDate = &endDate
-	}
-
-	return resp, nil
+	return toResponse(sub), nil
 }
 
 func (s Service) ListSubscriptions(ctx context.Context, req models.ListSubscriptionsRequest) ([]models.SubscriptionResponse, error) {
-	pagination := repository.SubscriptionPagination{Limit: req.Limit}
+	pagination := repository.SubscriptionPagination{Limit: req.Limit, Tags: normalizeTags(req.Tags)}
 	if req.Cursor != nil {
 		pagination.Cursor = &repository.SubscriptionCursor{
 			StartDate: time.Time(req.Cursor.StartDate),
 			ID:        req.Cursor.ID,
 		}
 	}
+	if p, ok := auth.FromContext(ctx); ok && !p.Admin {
+		pagination.UserID = &p.UserID
+	}
 
 	subs, err := s.repo.ListSubscriptions(ctx, pagination)
 	if err != nil {
@@ -91,18 +264,7 @@ func (s Service) ListSubscriptions(ctx context.Context, req models.ListSubscript
 
 	responds := make([]models.SubscriptionResponse, len(subs))
 	for i, sub := range subs {
-		responds[i] = models.SubscriptionResponse{
-			ID:          sub.ID,
-			ServiceName: sub.ServiceName,
-			Price:       sub.Price,
-			UserID:      sub.UserID,
-		}
-		startDate := monthyear.MonthYear(sub.StartDate)
-		responds[i].StartDate = &startDate
-		if sub.EndDate.Valid {
-			endDate := monthyear.MonthYear(sub.EndDate.Time)
-			responds[i].EndDate = &endDate
-		}
+		responds[i] = toResponse(sub)
 	}
 
 	return responds, nil
@@ -110,18 +272,75 @@ func (s Service) ListSubscriptions(ctx context.Context, req models.ListSubscript
 
 func (s Service) UpdateSubscription(ctx context.Context, id uuid.UUID, req models.UpdateSubscriptionRequest) (models.SubscriptionResponse, error) {
 	fields := repository.SubscriptionUpdate{
-		ServiceName: req.ServiceName,
-		Price:       req.Price,
+		ServiceName:  req.ServiceName,
+		Price:        req.Price,
+		BillingDay:   req.BillingDay,
+		TrialMonths:  req.TrialMonths,
+		TrialPrice:   req.TrialPrice,
+		Category:     req.Category,
+		AccountLabel: req.AccountLabel,
+		AddTags:      normalizeTags(req.AddTags),
+		RemoveTags:   normalizeTags(req.RemoveTags),
+	}
+	sub, err := s.getOwnSubscription(ctx, id)
+	if err != nil {
+		return models.SubscriptionResponse{}, err
 	}
-	if req.EndDate != nil {
-		endDate := time.Time(*req.EndDate)
 
-		sub, err := s.repo.GetSubscriptionByID(ctx, id)
+	if req.ServiceName != nil || req.ServiceID != nil {
+		var name string
+		if req.ServiceName != nil {
+			name = *req.ServiceName
+		}
+		svc, err := s.resolveService(ctx, name, req.ServiceID)
 		if err != nil {
-			return models.SubscriptionResponse{}, fmt.Errorf("repo failed to get subcsciption by id: %w", err)
+			return models.SubscriptionResponse{}, err
 		}
+		serviceName, serviceID := svc.Name, catalogID(svc)
+		fields.ServiceName = &serviceName
+		fields.ServiceID = &serviceID
+		// The plan belongs to the previous service
+		if req.PlanID == nil && sub.PlanID.Valid && serviceID != sub.ServiceID {
+			fields.PlanID = &uuid.NullUUID{}
+		}
+	}
+
+	if req.PlanID != nil {
+		serviceID := sub.ServiceID
+		if fields.ServiceID != nil {
+			serviceID = *fields.ServiceID
+		}
+		if !serviceID.Valid {
+			return models.SubscriptionResponse{}, service.ErrUnknownPlan
+		}
+		plan, err := s.getPlan(ctx, *req.PlanID, serviceID)
+		if err != nil {
+			return models.SubscriptionResponse{}, err
+		}
+		fields.PlanID = &uuid.NullUUID{UUID: plan.ID, Valid: true}
+		if req.Price == nil {
+			price := monthlyPrice(plan)
+			fields.Price = &price
+		}
+	}
+
+	if req.PaymentMethodID != nil {
+		// The nil UUID unlinks the subscription
+		fields.PaymentMethodID = &uuid.NullUUID{}
+		if *req.PaymentMethodID != uuid.Nil {
+			if _, err := s.getPaymentMethod(ctx, *req.PaymentMethodID, sub.UserID); err != nil {
+				return models.SubscriptionResponse{}, err
+			}
+			fields.PaymentMethodID = &uuid.NullUUID{UUID: *req.PaymentMethodID, Valid: true}
+		}
+	}
+
+	if req.EndDate != nil {
+		endDate := time.Time(*req.EndDate)
 
 		if endDate.Before(sub.StartDate) {
+			logger.FromContext(ctx).DebugContext(ctx, "end date before start date rejected",
+				"subscription_id", id, "start_date", sub.StartDate, "end_date", endDate)
 			return models.SubscriptionResponse{}, service.ErrInvalidDateRange
 		}
 
@@ -133,35 +352,34 @@ func (s Service) UpdateSubscription(ctx context.Context, id uuid.UUID, req model
 		return models.SubscriptionResponse{}, fmt.Errorf("repo failed to update subcsciption: %w", err)
 	}
 
-	resp := models.SubscriptionResponse{
-		ID:          updatedSub.ID,
-		ServiceName: updatedSub.ServiceName,
-		Price:       updatedSub.Price,
-		UserID:      updatedSub.UserID,
-	}
-	startDate := monthyear.MonthYear(updatedSub.StartDate)
-	resp.StartDate = &startDate
-	if updatedSub.EndDate.Valid {
-		endDate := monthyear.MonthYear(updatedSub.EndDate.Time)
-		resp.EndDate = &endDate
-	}
-
-	return resp, nil
+	return toResponse(updatedSub), nil
 }
 
 func (s Service) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.getOwnSubscription(ctx, id); err != nil {
+		return err
+	}
 	return s.repo.DeleteSubscription(ctx, id)
 }
 
-func (s Service) GetTotalCost(ctx context.Context, req models.TotalCostRequest) (models.TotalCostResponse, error) {
+// costFilter converts cost request filters, limiting non-admin callers to their own subscriptions
+func costFilter(ctx context.Context, req models.TotalCostRequest) (repository.SubscriptionFilter, error) {
 	filter := repository.SubscriptionFilter{}
 
 	if req.UserID != nil {
+		if err := authorize(ctx, *req.UserID); err != nil {
+			return filter, err
+		}
 		filter.UserID = req.UserID
+	} else if p, ok := auth.FromContext(ctx); ok && !p.Admin {
+		filter.UserID = &p.UserID
 	}
 	if req.ServiceName != nil {
 		filter.ServiceName = req.ServiceName
 	}
+	filter.ServiceID = req.ServiceID
+	filter.Tags = normalizeTags(req.Tags)
+	filter.Category = req.Category
 	if req.StartDate != nil {
 		startDate := time.Time(*req.StartDate)
 		filter.StartDate = &startDate
@@ -171,6 +389,15 @@ func (s Service) GetTotalCost(ctx context.Context, req models.TotalCostRequest)
 		filter.EndDate = &endDate
 	}
 
+	return filter, nil
+}
+
+func (s Service) GetTotalCost(ctx context.Context, req models.TotalCostRequest) (models.TotalCostResponse, error) {
+	filter, err := costFilter(ctx, req)
+	if err != nil {
+		return models.TotalCostResponse{}, err
+	}
+
 	totalCost, err := s.repo.GetTotalCostWithFilters(ctx, filter)
 	if err != nil {
 		return models.TotalCostResponse{}, fmt.Errorf("repo failed to get total cost: %w", err)
@@ -178,3 +405,235 @@ func (s Service) GetTotalCost(ctx context.Context, req models.TotalCostRequest)
 
 	return models.TotalCostResponse{TotalCost: totalCost}, nil
 }
+
+func (s Service) GetCostBreakdown(ctx context.Context, req models.CostBreakdownRequest) (models.CostBreakdownResponse, error) {
+	filter, err := costFilter(ctx, req.TotalCostRequest)
+	if err != nil {
+		return models.CostBreakdownResponse{}, err
+	}
+	group := repository.CostGroupService
+	if req.GroupBy != "" {
+		group = repository.CostGroup(req.GroupBy)
+	}
+
+	rows, err := s.repo.GetCostBreakdown(ctx, filter, group)
+	if err != nil {
+		return models.CostBreakdownResponse{}, fmt.Errorf("repo failed to get cost breakdown: %w", err)
+	}
+
+	resp := models.CostBreakdownResponse{
+		GroupBy: string(group),
+		Items:   make([]models.CostBreakdownItem, len(rows)),
+	}
+	for i, row := range rows {
+		resp.Items[i] = models.CostBreakdownItem{Key: row.Key, TotalCost: row.TotalCost}
+		resp.TotalCost += row.TotalCost
+	}
+	// A subscription is counted in the group of each of its tags, so the groups do not add up to the total
+	if group == repository.CostGroupTag {
+		if resp.TotalCost, err = s.repo.GetTotalCostWithFilters(ctx, filter); err != nil {
+			return models.CostBreakdownResponse{}, fmt.Errorf("repo failed to get total cost: %w", err)
+		}
+	}
+
+	return resp, nil
+}
+
+func (s Service) ListUsersSubscriptions(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID][]models.SubscriptionResponse, error) {
+	for _, id := range userIDs {
+		if err := authorize(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	subs, err := s.repo.ListSubscriptionsByUserIDs(ctx, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("repo failed to list subscriptions by users: %w", err)
+	}
+
+	resp := make(map[uuid.UUID][]models.SubscriptionResponse, len(userIDs))
+	for _, sub := range subs {
+		resp[sub.UserID] = append(resp[sub.UserID], toResponse(sub))
+	}
+	return resp, nil
+}
+
+// GetUsersCostBreakdown computes a breakdown per user, req.UserID is ignored
+func (s Service) GetUsersCostBreakdown(ctx context.Context, userIDs []uuid.UUID, req models.CostBreakdownRequest) (map[uuid.UUID]models.CostBreakdownResponse, error) {
+	for _, id := range userIDs {
+		if err := authorize(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	req.UserID = nil
+	filter, err := costFilter(ctx, req.TotalCostRequest)
+	if err != nil {
+		return nil, err
+	}
+	filter.UserID = nil
+	filter.UserIDs = userIDs
+	group := repository.CostGroupService
+	if req.GroupBy != "" {
+		group = repository.CostGroup(req.GroupBy)
+	}
+
+	rows, err := s.repo.GetCostBreakdownByUser(ctx, filter, group)
+	if err != nil {
+		return nil, fmt.Errorf("repo failed to get cost breakdown by user: %w", err)
+	}
+
+	resp := make(map[uuid.UUID]models.CostBreakdownResponse, len(userIDs))
+	for _, id := range userIDs {
+		resp[id] = models.CostBreakdownResponse{GroupBy: string(group), Items: []models.CostBreakdownItem{}}
+	}
+	for _, row := range rows {
+		breakdown := resp[row.UserID]
+		breakdown.Items = append(breakdown.Items, models.CostBreakdownItem{Key: row.Key, TotalCost: row.TotalCost})
+		if group != repository.CostGroupTag {
+			breakdown.TotalCost += row.TotalCost
+		}
+		resp[row.UserID] = breakdown
+	}
+	// Tag groups overlap, totals are summed over groups that do not
+	if group == repository.CostGroupTag {
+		totals, err := s.repo.GetCostBreakdownByUser(ctx, filter, repository.CostGroupUser)
+		if err != nil {
+			return nil, fmt.Errorf("repo failed to get cost by user: %w", err)
+		}
+		for _, row := range totals {
+			breakdown := resp[row.UserID]
+			breakdown.TotalCost += row.TotalCost
+			resp[row.UserID] = breakdown
+		}
+	}
+	return resp, nil
+}
+
+func (s Service) ListTrialsEnding(ctx context.Context, req models.TrialEndingRequest) ([]models.SubscriptionResponse, error) {
+	filter := repository.TrialEndingFilter{UserID: req.UserID}
+	if req.UserID != nil {
+		if err := authorize(ctx, *req.UserID); err != nil {
+			return nil, err
+		}
+	} else if p, ok := auth.FromContext(ctx); ok && !p.Admin {
+		filter.UserID = &p.UserID
+	}
+	if req.Month != nil {
+		filter.Month = time.Time(*req.Month)
+	} else {
+		filter.Month = currentMonth().AddDate(0, 1, 0)
+	}
+
+	subs, err := s.repo.ListTrialsEnding(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("repo failed to list ending trials: %w", err)
+	}
+
+	resp := make([]models.SubscriptionResponse, len(subs))
+	for i, sub := range subs {
+		resp[i] = toResponse(sub)
+	}
+	return resp, nil
+}
+
+func (s Service) PauseSubscription(ctx context.Context, id uuid.UUID, req models.PauseSubscriptionRequest) (models.SubscriptionResponse, error) {
+	sub, err := s.getOwnSubscription(ctx, id)
+	if err != nil {
+		return models.SubscriptionResponse{}, err
+	}
+
+	pause := repository.SubscriptionPause{SubscriptionID: id, StartDate: currentMonth()}
+	if req.StartDate != nil {
+		pause.StartDate = time.Time(*req.StartDate)
+	}
+	if req.EndDate != nil {
+		pause.EndDate = sql.NullTime{Time: time.Time(*req.EndDate), Valid: true}
+	}
+	if pause.EndDate.Valid && pause.EndDate.Time.Before(pause.StartDate) {
+		return models.SubscriptionResponse{}, service.ErrInvalidDateRange
+	}
+	if pause.StartDate.Before(sub.StartDate) || (sub.EndDate.Valid && pause.StartDate.After(sub.EndDate.Time)) {
+		logger.FromContext(ctx).DebugContext(ctx, "pause outside subscription period rejected",
+			"subscription_id", id, "pause_start", pause.StartDate)
+		return models.SubscriptionResponse{}, service.ErrInvalidPause
+	}
+
+	if _, err := s.repo.PauseSubscription(ctx, pause); err != nil {
+		return models.SubscriptionResponse{}, fmt.Errorf("repo failed to pause subscription: %w", err)
+	}
+	return s.GetSubscriptionByID(ctx, id)
+}
+
+func (s Service) ResumeSubscription(ctx context.Context, id uuid.UUID, req models.ResumeSubscriptionRequest) (models.SubscriptionResponse, error) {
+	if _, err := s.getOwnSubscription(ctx, id); err != nil {
+		return models.SubscriptionResponse{}, err
+	}
+
+	month := currentMonth()
+	if req.Month != nil {
+		month = time.Time(*req.Month)
+	}
+	if err := s.repo.ResumeSubscription(ctx, id, month); err != nil {
+		return models.SubscriptionResponse{}, fmt.Errorf("repo failed to resume subscription: %w", err)
+	}
+	return s.GetSubscriptionByID(ctx, id)
+}
+
+func (s Service) ChangeSubscriptionPlan(ctx context.Context, id uuid.UUID, req models.ChangePlanRequest) (models.SubscriptionResponse, error) {
+	sub, err := s.getOwnSubscription(ctx, id)
+	if err != nil {
+		return models.SubscriptionResponse{}, err
+	}
+	if !sub.ServiceID.Valid {
+		return models.SubscriptionResponse{}, service.ErrUnknownPlan
+	}
+	plan, err := s.getPlan(ctx, req.PlanID, sub.ServiceID)
+	if err != nil {
+		return models.SubscriptionResponse{}, err
+	}
+
+	change := repository.PlanChange{
+		SubscriptionID: id,
+		PlanID:         uuid.NullUUID{UUID: plan.ID, Valid: true},
+		Price:          monthlyPrice(plan),
+		StartDate:      currentMonth(),
+	}
+	if req.Price != nil {
+		change.Price = *req.Price
+	}
+	if req.StartDate != nil {
+		change.StartDate = time.Time(*req.StartDate)
+	}
+	// The first month is billed by the plan of the subscription itself
+	if !change.StartDate.After(sub.StartDate) || (sub.EndDate.Valid && change.StartDate.After(sub.EndDate.Time)) {
+		logger.FromContext(ctx).DebugContext(ctx, "plan change outside subscription period rejected",
+			"subscription_id", id, "change_start", change.StartDate)
+		return models.SubscriptionResponse{}, service.ErrInvalidPlanChange
+	}
+
+	if _, err := s.repo.ChangeSubscriptionPlan(ctx, change); err != nil {
+		return models.SubscriptionResponse{}, fmt.Errorf("repo failed to change subscription plan: %w", err)
+	}
+	return s.GetSubscriptionByID(ctx, id)
+}
+
+func (s Service) ListPlanChanges(ctx context.Context, id uuid.UUID) ([]models.PlanChangeResponse, error) {
+	if _, err := s.getOwnSubscription(ctx, id); err != nil {
+		return nil, err
+	}
+
+	changes, err := s.repo.ListPlanChanges(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("repo failed to list plan changes: %w", err)
+	}
+
+	resp := make([]models.PlanChangeResponse, len(changes))
+	for i, change := range changes {
+		startDate := monthyear.MonthYear(change.StartDate)
+		resp[i] = models.PlanChangeResponse{ID: change.ID, Price: change.Price, StartDate: &startDate}
+		if change.PlanID.Valid {
+			resp[i].PlanID = &change.PlanID.UUID
+		}
+	}
+	return resp, nil
+}
@@ -0,0 +1,376 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/auth"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/repository"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/service"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/monthyear"
+)
+
+var (
+	ownerID   = uuid.MustParse("11111111-1111-4111-8111-111111111111")
+	otherID   = uuid.MustParse("22222222-2222-4222-8222-222222222222")
+	memberID  = uuid.MustParse("66666666-6666-4666-8666-666666666666")
+	subID     = uuid.MustParse("33333333-3333-4333-8333-333333333333")
+	serviceID = uuid.MustParse("44444444-4444-4444-8444-444444444444")
+	planID    = uuid.MustParse("55555555-5555-4555-8555-555555555555")
+)
+
+// fakeRepo keeps subscriptions in memory, methods the tests do not use panic through the nil interface
+type fakeRepo struct {
+	repository.SubscriptionRepository
+
+	subs       map[uuid.UUID]repository.Subscription
+	splits     map[uuid.UUID]repository.SubscriptionSplit
+	created    []repository.Subscription
+	deleted    []uuid.UUID
+	pagination repository.SubscriptionPagination
+	filter     repository.SubscriptionFilter
+	scheduled  []repository.ScheduledChange
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{
+		subs: map[uuid.UUID]repository.Subscription{
+			subID: {
+				ID:           subID,
+				ServiceName:  "Yandex Plus",
+				Price:        400,
+				CurrentPrice: 400,
+				UserID:       ownerID,
+				StartDate:    time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+				BillingDay:   1,
+			},
+		},
+		splits: map[uuid.UUID]repository.SubscriptionSplit{},
+	}
+}
+
+func (r *fakeRepo) GetSubscriptionByID(_ context.Context, id uuid.UUID) (repository.Subscription, error) {
+	sub, ok := r.subs[id]
+	if !ok {
+		return repository.Subscription{}, repository.ErrSubscriptionNotFound
+	}
+	return sub, nil
+}
+
+func (r *fakeRepo) GetSubscriptionSplit(_ context.Context, id uuid.UUID) (repository.SubscriptionSplit, error) {
+	split, ok := r.splits[id]
+	if !ok {
+		return repository.SubscriptionSplit{SubscriptionID: id, Rule: repository.SplitEqual}, nil
+	}
+	return split, nil
+}
+
+func (r *fakeRepo) CreateSubscription(_ context.Context, sub repository.Subscription) (uuid.UUID, error) {
+	r.created = append(r.created, sub)
+	return uuid.New(), nil
+}
+
+func (r *fakeRepo) UpdateSubscription(_ context.Context, id uuid.UUID, _ repository.SubscriptionUpdate) (repository.Subscription, error) {
+	return r.subs[id], nil
+}
+
+func (r *fakeRepo) DeleteSubscription(_ context.Context, id uuid.UUID) error {
+	r.deleted = append(r.deleted, id)
+	return nil
+}
+
+func (r *fakeRepo) ListSubscriptions(_ context.Context, pagination repository.SubscriptionPagination) ([]repository.Subscription, error) {
+	r.pagination = pagination
+	return nil, nil
+}
+
+func (r *fakeRepo) GetTotalCostWithFilters(_ context.Context, filter repository.SubscriptionFilter) (int, error) {
+	r.filter = filter
+	return 0, nil
+}
+
+func (r *fakeRepo) ScheduleChange(_ context.Context, change repository.ScheduledChange) (repository.ScheduledChange, error) {
+	change.ID, change.Status = uuid.New(), repository.ScheduledPending
+	r.scheduled = append(r.scheduled, change)
+	return change, nil
+}
+
+func (r *fakeRepo) MatchService(context.Context, string) (repository.CatalogService, error) {
+	return repository.CatalogService{}, repository.ErrServiceNotFound
+}
+
+func (r *fakeRepo) GetServiceByID(_ context.Context, id uuid.UUID) (repository.CatalogService, error) {
+	if id != serviceID {
+		return repository.CatalogService{}, repository.ErrServiceNotFound
+	}
+	return repository.CatalogService{ID: serviceID, Name: "Yandex Plus", Category: "entertainment"}, nil
+}
+
+func (r *fakeRepo) GetServicePlan(_ context.Context, id uuid.UUID) (repository.ServicePlan, error) {
+	if id != planID {
+		return repository.ServicePlan{}, repository.ErrPlanNotFound
+	}
+	return repository.ServicePlan{ID: planID, ServiceID: serviceID, Name: "Basic", Price: 399, BillingPeriod: repository.BillingMonth}, nil
+}
+
+func asUser(id uuid.UUID) context.Context {
+	return auth.WithPrincipal(context.Background(), auth.Principal{UserID: id})
+}
+
+func asAdmin() context.Context {
+	return auth.WithPrincipal(context.Background(), auth.Principal{UserID: otherID, Admin: true})
+}
+
+func TestSubscriptionAccess(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		wantErr error
+	}{
+		{name: "owner", ctx: asUser(ownerID)},
+		{name: "admin", ctx: asAdmin()},
+		{name: "authentication disabled", ctx: context.Background()},
+		{name: "another user", ctx: asUser(otherID), wantErr: service.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepo()
+			svc := NewService(repo)
+
+			_, err := svc.GetSubscriptionByID(tt.ctx, subID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetSubscriptionByID() error = %v, want %v", err, tt.wantErr)
+			}
+
+			err = svc.DeleteSubscription(tt.ctx, subID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("DeleteSubscription() error = %v, want %v", err, tt.wantErr)
+			}
+			if deleted := len(repo.deleted) == 1; deleted != (tt.wantErr == nil) {
+				t.Errorf("DeleteSubscription() deleted = %v", deleted)
+			}
+
+			_, err = svc.UpdateSubscription(tt.ctx, subID, models.UpdateSubscriptionRequest{})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("UpdateSubscription() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSharedSubscriptionAccess(t *testing.T) {
+	repo := newFakeRepo()
+	repo.splits[subID] = repository.SubscriptionSplit{
+		SubscriptionID: subID,
+		Rule:           repository.SplitEqual,
+		Members:        []repository.SubscriptionMember{{UserID: memberID}},
+	}
+	svc := NewService(repo)
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		wantRead  error
+		wantWrite error
+	}{
+		{name: "owner", ctx: asUser(ownerID)},
+		{name: "member", ctx: asUser(memberID), wantWrite: service.ErrForbidden},
+		{name: "another user", ctx: asUser(otherID), wantRead: service.ErrForbidden, wantWrite: service.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.GetSubscriptionByID(tt.ctx, subID); !errors.Is(err, tt.wantRead) {
+				t.Errorf("GetSubscriptionByID() error = %v, want %v", err, tt.wantRead)
+			}
+			if _, err := svc.GetMembers(tt.ctx, subID); !errors.Is(err, tt.wantRead) {
+				t.Errorf("GetMembers() error = %v, want %v", err, tt.wantRead)
+			}
+			if _, err := svc.UpdateSubscription(tt.ctx, subID, models.UpdateSubscriptionRequest{}); !errors.Is(err, tt.wantWrite) {
+				t.Errorf("UpdateSubscription() error = %v, want %v", err, tt.wantWrite)
+			}
+		})
+	}
+}
+
+func TestMissingSubscription(t *testing.T) {
+	missing := uuid.New()
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		wantErr error
+	}{
+		// Users get the same error as for a subscription of another user
+		{name: "user", ctx: asUser(otherID), wantErr: service.ErrForbidden},
+		{name: "admin", ctx: asAdmin(), wantErr: repository.ErrSubscriptionNotFound},
+		{name: "authentication disabled", ctx: context.Background(), wantErr: repository.ErrSubscriptionNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewService(newFakeRepo())
+			if _, err := svc.GetSubscriptionByID(tt.ctx, missing); !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetSubscriptionByID() error = %v, want %v", err, tt.wantErr)
+			}
+			if _, err := svc.GetMembers(tt.ctx, missing); !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetMembers() error = %v, want %v", err, tt.wantErr)
+			}
+			if err := svc.DeleteSubscription(tt.ctx, missing); !errors.Is(err, tt.wantErr) {
+				t.Errorf("DeleteSubscription() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreateSubscriptionForAnotherUser(t *testing.T) {
+	start := monthyear.MonthYear(time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC))
+	price := 300
+	req := models.CreateSubscriptionRequest{ServiceName: "Kinopoisk", Price: &price, UserID: ownerID, StartDate: &start}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		wantErr error
+	}{
+		{name: "owner", ctx: asUser(ownerID)},
+		{name: "admin", ctx: asAdmin()},
+		{name: "another user", ctx: asUser(otherID), wantErr: service.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepo()
+			_, err := NewService(repo).CreateSubscription(tt.ctx, req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateSubscription() error = %v, want %v", err, tt.wantErr)
+			}
+			if created := len(repo.created) == 1; created != (tt.wantErr == nil) {
+				t.Errorf("CreateSubscription() created = %v", created)
+			}
+		})
+	}
+}
+
+func TestCreateSubscriptionPrice(t *testing.T) {
+	start := monthyear.MonthYear(time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC))
+	free, override := 0, 199
+
+	tests := []struct {
+		name      string
+		price     *int
+		wantPrice int
+	}{
+		{name: "plan price by default", wantPrice: 399},
+		{name: "free", price: &free, wantPrice: 0},
+		{name: "override", price: &override, wantPrice: 199},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepo()
+			req := models.CreateSubscriptionRequest{PlanID: &planID, Price: tt.price, UserID: ownerID, StartDate: &start}
+			resp, err := NewService(repo).CreateSubscription(asUser(ownerID), req)
+			if err != nil {
+				t.Fatalf("CreateSubscription() error = %v", err)
+			}
+			if resp.Price != tt.wantPrice || repo.created[0].Price != tt.wantPrice {
+				t.Errorf("CreateSubscription() price = %d, stored %d, want %d", resp.Price, repo.created[0].Price, tt.wantPrice)
+			}
+		})
+	}
+}
+
+func TestListAndCostAreScopedToCaller(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		userID   *uuid.UUID
+		wantUser *uuid.UUID
+		wantErr  error
+	}{
+		{name: "user without filter", ctx: asUser(otherID), wantUser: &otherID},
+		{name: "user filtering by self", ctx: asUser(ownerID), userID: &ownerID, wantUser: &ownerID},
+		{name: "user filtering by another user", ctx: asUser(otherID), userID: &ownerID, wantErr: service.ErrForbidden},
+		{name: "admin without filter", ctx: asAdmin()},
+		{name: "admin filtering by user", ctx: asAdmin(), userID: &ownerID, wantUser: &ownerID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepo()
+			svc := NewService(repo)
+
+			_, err := svc.GetTotalCost(tt.ctx, models.TotalCostRequest{UserID: tt.userID})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetTotalCost() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if !equalUserID(repo.filter.UserID, tt.wantUser) {
+				t.Errorf("GetTotalCost() filter user = %v, want %v", repo.filter.UserID, tt.wantUser)
+			}
+
+			if tt.userID != nil {
+				return
+			}
+			if _, err := svc.ListSubscriptions(tt.ctx, models.ListSubscriptionsRequest{}); err != nil {
+				t.Fatalf("ListSubscriptions() error = %v", err)
+			}
+			if !equalUserID(repo.pagination.UserID, tt.wantUser) {
+				t.Errorf("ListSubscriptions() user = %v, want %v", repo.pagination.UserID, tt.wantUser)
+			}
+		})
+	}
+}
+
+func TestTotalCostPeriodIsInclusive(t *testing.T) {
+	march := monthyear.MonthYear(time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC))
+	may := monthyear.MonthYear(time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name      string
+		req       models.TotalCostRequest
+		wantStart *monthyear.MonthYear
+		wantEnd   *monthyear.MonthYear
+	}{
+		{name: "open period"},
+		{name: "both months", req: models.TotalCostRequest{StartDate: &march, EndDate: &may}, wantStart: &march, wantEnd: &may},
+		{name: "one month", req: models.TotalCostRequest{StartDate: &march, EndDate: &march}, wantStart: &march, wantEnd: &march},
+		{name: "only start", req: models.TotalCostRequest{StartDate: &march}, wantStart: &march},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepo()
+			if _, err := NewService(repo).GetTotalCost(asAdmin(), tt.req); err != nil {
+				t.Fatalf("GetTotalCost() error = %v", err)
+			}
+			// The repository bills both bounding months, so the months are passed on without shifting the end
+			if !equalMonth(repo.filter.StartDate, tt.wantStart) || !equalMonth(repo.filter.EndDate, tt.wantEnd) {
+				t.Errorf("GetTotalCost() filter period = %v - %v, want %v - %v",
+					repo.filter.StartDate, repo.filter.EndDate, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func equalMonth(got *time.Time, want *monthyear.MonthYear) bool {
+	if got == nil || want == nil {
+		return got == nil && want == nil
+	}
+	return got.Equal(time.Time(*want))
+}
+
+func equalUserID(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
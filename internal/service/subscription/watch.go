@@ -0,0 +1,146 @@
+package subscription
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/auth"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/repository"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/service"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/tenant"
+)
+
+var _ service.SubscriptionWatcher = (*Watcher)(nil)
+
+const (
+	// watchBuffer is the number of events a watcher may lag behind before it is dropped
+	watchBuffer = 64
+
+	minReconnectDelay = time.Second
+	maxReconnectDelay = 30 * time.Second
+)
+
+var eventTypes = map[repository.ChangeOp]string{
+	repository.ChangeCreated: models.EventCreated,
+	repository.ChangeUpdated: models.EventUpdated,
+	repository.ChangeDeleted: models.EventDeleted,
+}
+
+// Watcher fans out subscription changes from a single repository listener to watchers of the same tenant
+type Watcher struct {
+	listener repository.ChangeListener
+
+	mu       sync.Mutex
+	watchers map[*watcher]struct{}
+}
+
+type watcher struct {
+	tenantID string
+	userID   *uuid.UUID
+	events   chan models.SubscriptionEvent
+}
+
+func NewWatcher(listener repository.ChangeListener) *Watcher {
+	return &Watcher{
+		listener: listener,
+		watchers: make(map[*watcher]struct{}),
+	}
+}
+
+// Run listens for changes until ctx is canceled, reconnecting with backoff after failures.
+// Changes made while reconnecting are lost, so watchers are closed and have to resync.
+func (w *Watcher) Run(ctx context.Context) {
+	delay := minReconnectDelay
+	for {
+		started := time.Now()
+		err := w.listener.ListenSubscriptionChanges(ctx, w.publish)
+		w.closeAll()
+		if ctx.Err() != nil {
+			return
+		}
+
+		if time.Since(started) > maxReconnectDelay {
+			delay = minReconnectDelay
+		}
+		slog.Error("subscription change listener failed", "error", err, "retry_in", delay)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxReconnectDelay)
+	}
+}
+
+func (w *Watcher) WatchSubscriptions(ctx context.Context, req models.WatchSubscriptionsRequest) (<-chan models.SubscriptionEvent, error) {
+	tenantID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return nil, repository.ErrTenantRequired
+	}
+
+	wt := &watcher{tenantID: tenantID, userID: req.UserID, events: make(chan models.SubscriptionEvent, watchBuffer)}
+	if req.UserID != nil {
+		if err := authorize(ctx, *req.UserID); err != nil {
+			return nil, err
+		}
+	} else if p, ok := auth.FromContext(ctx); ok && !p.Admin {
+		wt.userID = &p.UserID
+	}
+
+	w.mu.Lock()
+	w.watchers[wt] = struct{}{}
+	w.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		w.mu.Lock()
+		w.remove(wt)
+		w.mu.Unlock()
+	}()
+
+	return wt.events, nil
+}
+
+func (w *Watcher) publish(change repository.SubscriptionChange) {
+	event := models.SubscriptionEvent{
+		Type:         eventTypes[change.Op],
+		Subscription: toResponse(change.Subscription),
+		Time:         change.ChangedAt,
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for wt := range w.watchers {
+		if wt.tenantID != change.TenantID || (wt.userID != nil && *wt.userID != change.Subscription.UserID) {
+			continue
+		}
+		select {
+		case wt.events <- event:
+		default:
+			slog.Warn("dropping subscription watcher that fell behind", "tenant_id", wt.tenantID)
+			w.remove(wt)
+		}
+	}
+}
+
+func (w *Watcher) closeAll() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for wt := range w.watchers {
+		w.remove(wt)
+	}
+}
+
+// remove closes the watcher events unless it is already removed, w.mu must be held
+func (w *Watcher) remove(wt *watcher) {
+	if _, ok := w.watchers[wt]; !ok {
+		return
+	}
+	delete(w.watchers, wt)
+	close(wt.events)
+}
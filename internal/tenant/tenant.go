@@ -0,0 +1,64 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/auth"
+)
+
+type tenantKey struct{}
+
+// WithID returns a copy of ctx carrying the tenant ID
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, id)
+}
+
+// FromContext returns the tenant ID stored in ctx
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(tenantKey{}).(string)
+	return id, ok && id != ""
+}
+
+const maxIDLength = 64
+
+var (
+	ErrMismatch = errors.New("tenant does not match token")
+	ErrRequired = errors.New("tenant required")
+	ErrInvalid  = errors.New("invalid tenant ID")
+	// ErrClaimRequired rejects a non-admin caller without a tenant_id claim outside the default tenant
+	ErrClaimRequired = errors.New("tenant_id claim required")
+)
+
+// Resolve picks the tenant of a call from the tenant requested by the client, if any.
+// The tenant_id claim of an authenticated caller takes precedence, a request naming another tenant is rejected.
+// Only admins and unauthenticated calls (authentication disabled) may request a tenant without a claim,
+// other callers without a claim are limited to defaultTenant.
+// Calls without a claim or requested tenant fall back to defaultTenant, if it is set.
+func Resolve(ctx context.Context, requested, defaultTenant string) (string, error) {
+	id := requested
+	p, ok := auth.FromContext(ctx)
+	switch {
+	case ok && p.TenantID != "":
+		if id != "" && id != p.TenantID {
+			return "", ErrMismatch
+		}
+		id = p.TenantID
+	case ok && !p.Admin:
+		if defaultTenant == "" || (id != "" && id != defaultTenant) {
+			return "", ErrClaimRequired
+		}
+		id = defaultTenant
+	}
+	if id == "" {
+		id = defaultTenant
+	}
+
+	if id == "" {
+		return "", ErrRequired
+	}
+	if len(id) > maxIDLength {
+		return "", ErrInvalid
+	}
+	return id, nil
+}
@@ -0,0 +1,56 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/auth"
+)
+
+func TestResolve(t *testing.T) {
+	user := auth.Principal{UserID: uuid.New()}
+	admin := auth.Principal{UserID: uuid.New(), Admin: true}
+	claimed := auth.Principal{UserID: uuid.New(), TenantID: "acme"}
+
+	tests := []struct {
+		name          string
+		principal     *auth.Principal
+		requested     string
+		defaultTenant string
+		want          string
+		wantErr       error
+	}{
+		{name: "authentication disabled, header", requested: "acme", defaultTenant: "default", want: "acme"},
+		{name: "authentication disabled, default", defaultTenant: "default", want: "default"},
+		{name: "authentication disabled, no default", wantErr: ErrRequired},
+		{name: "claim", principal: &claimed, defaultTenant: "default", want: "acme"},
+		{name: "claim and same header", principal: &claimed, requested: "acme", want: "acme"},
+		{name: "claim and another header", principal: &claimed, requested: "other", wantErr: ErrMismatch},
+		{name: "admin without claim, header", principal: &admin, requested: "acme", defaultTenant: "default", want: "acme"},
+		{name: "admin without claim, default", principal: &admin, defaultTenant: "default", want: "default"},
+		{name: "user without claim, default", principal: &user, defaultTenant: "default", want: "default"},
+		{name: "user without claim, default header", principal: &user, requested: "default", defaultTenant: "default", want: "default"},
+		{name: "user without claim, another header", principal: &user, requested: "acme", defaultTenant: "default", wantErr: ErrClaimRequired},
+		{name: "user without claim, no default", principal: &user, requested: "acme", wantErr: ErrClaimRequired},
+		{name: "too long", requested: string(make([]byte, maxIDLength+1)), wantErr: ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.principal != nil {
+				ctx = auth.WithPrincipal(ctx, *tt.principal)
+			}
+			got, err := Resolve(ctx, tt.requested, tt.defaultTenant)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Resolve() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Resolve() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
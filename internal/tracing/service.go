@@ -0,0 +1,280 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/service"
+)
+
+const serviceTracerName = "github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/service/subscription"
+
+var _ service.SubscriptionService = (*tracedService)(nil)
+
+// tracedService starts a span for every call of the wrapped service
+type tracedService struct {
+	next   service.SubscriptionService
+	tracer trace.Tracer
+}
+
+// Service decorates s with a span per method
+func Service(s service.SubscriptionService) service.SubscriptionService {
+	return &tracedService{next: s, tracer: otel.Tracer(serviceTracerName)}
+}
+
+func (s *tracedService) start(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return s.tracer.Start(ctx, "subscription.Service/"+method, trace.WithAttributes(attrs...))
+}
+
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (s *tracedService) CreateSubscription(ctx context.Context, req models.CreateSubscriptionRequest) (models.SubscriptionResponse, error) {
+	ctx, span := s.start(ctx, "CreateSubscription", attribute.String("user_id", req.UserID.String()))
+	resp, err := s.next.CreateSubscription(ctx, req)
+	end(span, err)
+	return resp, err
+}
+
+func (s *tracedService) GetSubscriptionByID(ctx context.Context, id uuid.UUID) (models.SubscriptionResponse, error) {
+	ctx, span := s.start(ctx, "GetSubscriptionByID", attribute.String("subscription_id", id.String()))
+	resp, err := s.next.GetSubscriptionByID(ctx, id)
+	end(span, err)
+	return resp, err
+}
+
+func (s *tracedService) ListSubscriptions(ctx context.Context, req models.ListSubscriptionsRequest) ([]models.SubscriptionResponse, error) {
+	ctx, span := s.start(ctx, "ListSubscriptions", attribute.Int("limit", req.Limit))
+	resp, err := s.next.ListSubscriptions(ctx, req)
+	end(span, err)
+	return resp, err
+}
+
+func (s *tracedService) UpdateSubscription(ctx context.Context, id uuid.UUID, req models.UpdateSubscriptionRequest) (models.SubscriptionResponse, error) {
+	ctx, span := s.start(ctx, "UpdateSubscription", attribute.String("subscription_id", id.String()))
+	resp, err := s.next.UpdateSubscription(ctx, id, req)
+	end(span, err)
+	return resp, err
+}
+
+func (s *tracedService) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	ctx, span := s.start(ctx, "DeleteSubscription", attribute.String("subscription_id", id.String()))
+	err := s.next.DeleteSubscription(ctx, id)
+	end(span, err)
+	return err
+}
+
+func (s *tracedService) GetTotalCost(ctx context.Context, req models.TotalCostRequest) (models.TotalCostResponse, error) {
+	ctx, span := s.start(ctx, "GetTotalCost")
+	resp, err := s.next.GetTotalCost(ctx, req)
+	end(span, err)
+	return resp, err
+}
+
+func (s *tracedService) GetCostBreakdown(ctx context.Context, req models.CostBreakdownRequest) (models.CostBreakdownResponse, error) {
+	ctx, span := s.start(ctx, "GetCostBreakdown", attribute.String("group_by", req.GroupBy))
+	resp, err := s.next.GetCostBreakdown(ctx, req)
+	end(span, err)
+	return resp, err
+}
+
+func (s *tracedService) ListUsersSubscriptions(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID][]models.SubscriptionResponse, error) {
+	ctx, span := s.start(ctx, "ListUsersSubscriptions", attribute.Int("users", len(userIDs)))
+	resp, err := s.next.ListUsersSubscriptions(ctx, userIDs)
+	end(span, err)
+	return resp, err
+}
+
+func (s *tracedService) GetUsersCostBreakdown(ctx context.Context, userIDs []uuid.UUID, req models.CostBreakdownRequest) (map[uuid.UUID]models.CostBreakdownResponse, error) {
+	ctx, span := s.start(ctx, "GetUsersCostBreakdown",
+		attribute.Int("users", len(userIDs)), attribute.String("group_by", req.GroupBy))
+	resp, err := s.next.GetUsersCostBreakdown(ctx, userIDs, req)
+	end(span, err)
+	return resp, err
+}
+
+func (s *tracedService) ListTrialsEnding(ctx context.Context, req models.TrialEndingRequest) ([]models.SubscriptionResponse, error) {
+	ctx, span := s.start(ctx, "ListTrialsEnding")
+	resp, err := s.next.ListTrialsEnding(ctx, req)
+	end(span, err)
+	return resp, err
+}
+
+func (s *tracedService) ListUpcomingCharges(ctx context.Context, req models.UpcomingChargesRequest) ([]models.ChargeResponse, error) {
+	ctx, span := s.start(ctx, "ListUpcomingCharges", attribute.Int("days", req.Days))
+	resp, err := s.next.ListUpcomingCharges(ctx, req)
+	end(span, err)
+	return resp, err
+}
+
+func (s *tracedService) FindDuplicates(ctx context.Context, req models.DuplicatesRequest) (models.DuplicatesResponse, error) {
+	ctx, span := s.start(ctx, "FindDuplicates")
+	resp, err := s.next.FindDuplicates(ctx, req)
+	end(span, err)
+	return resp, err
+}
+
+func (s *tracedService) GetNotificationPreferences(ctx context.Context, userID uuid.UUID) (models.NotificationPreferencesResponse, error) {
+	ctx, span := s.start(ctx, "GetNotificationPreferences", attribute.String("user_id", userID.String()))
+	resp, err := s.next.GetNotificationPreferences(ctx, userID)
+	end(span, err)
+	return resp, err
+}
+
+func (s *tracedService) SetNotificationPreferences(ctx context.Context, userID uuid.UUID, req models.NotificationPreferencesRequest) (models.NotificationPreferencesResponse, error) {
+	ctx, span := s.start(ctx, "SetNotificationPreferences", attribute.String("user_id", userID.String()))
+	resp, err := s.next.SetNotificationPreferences(ctx, userID, req)
+	end(span, err)
+	return resp, err
+}
+
+func (s *tracedService) CreatePaymentMethod(ctx context.Context, userID uuid.UUID, req models.PaymentMethodRequest) (models.PaymentMethodResponse, error) {
+	ctx, span := s.start(ctx, "CreatePaymentMethod", attribute.String("user_id", userID.String()),
+		attribute.String("kind", req.Kind))
+	resp, err := s.next.CreatePaymentMethod(ctx, userID, req)
+	end(span, err)
+	return resp, err
+}
+
+func (s *tracedService) ListPaymentMethods(ctx context.Context, userID uuid.UUID) ([]models.PaymentMethodResponse, error) {
+	ctx, span := s.start(ctx, "ListPaymentMethods", attribute.String("user_id", userID.String()))
+	resp, err := s.next.ListPaymentMethods(ctx, userID)
+	end(span, err)
+	return resp, err
+}
+
+func (s *tracedService) UpdatePaymentMethod(ctx context.Context, userID, id uuid.UUID, req models.PaymentMethodRequest) (models.PaymentMethodResponse, error) {
+	ctx, span := s.start(ctx, "UpdatePaymentMethod", attribute.String("user_id", userID.String()),
+		attribute.String("payment_method_id", id.String()))
+	resp, err := s.next.UpdatePaymentMethod(ctx, userID, id, req)
+	end(span, err)
+	return resp, err
+}
+
+func (s *tracedService) DeletePaymentMethod(ctx context.Context, userID, id uuid.UUID, req models.DeletePaymentMethodRequest) error {
+	ctx, span := s.start(ctx, "DeletePaymentMethod", attribute.String("user_id", userID.String()),
+		attribute.String("payment_method_id", id.String()))
+	err := s.next.DeletePaymentMethod(ctx, userID, id, req)
+	end(span, err)
+	return err
+}
+
+func (s *tracedService) ListExpiringCards(ctx context.Context, req models.ExpiringCardsRequest) ([]models.ExpiringCardResponse, error) {
+	ctx, span := s.start(ctx, "ListExpiringCards")
+	resp, err := s.next.ListExpiringCards(ctx, req)
+	end(span, err)
+	return resp, err
+}
+
+func (s *tracedService) PauseSubscription(ctx context.Context, id uuid.UUID, req models.PauseSubscriptionRequest) (models.SubscriptionResponse, error) {
+	ctx, span := s.start(ctx, "PauseSubscription", attribute.String("subscription_id", id.String()))
+	resp, err := s.next.PauseSubscription(ctx, id, req)
+	end(span, err)
+	return resp, err
+}
+
+func (s *tracedService) ResumeSubscription(ctx context.Context, id uuid.UUID, req models.ResumeSubscriptionRequest) (models.SubscriptionResponse, error) {
+	ctx, span := s.start(ctx, "ResumeSubscription", attribute.String("subscription_id", id.String()))
+	resp, err := s.next.ResumeSubscription(ctx, id, req)
+	end(span, err)
+	return resp, err
+}
+
+func (s *tracedService) ChangeSubscriptionPlan(ctx context.Context, id uuid.UUID, req models.ChangePlanRequest) (models.SubscriptionResponse, error) {
+	ctx, span := s.start(ctx, "ChangeSubscriptionPlan",
+		attribute.String("subscription_id", id.String()), attribute.String("plan_id", req.PlanID.String()))
+	resp, err := s.next.ChangeSubscriptionPlan(ctx, id, req)
+	end(span, err)
+	return resp, err
+}
+
+func (s *tracedService) ListPlanChanges(ctx context.Context, id uuid.UUID) ([]models.PlanChangeResponse, error) {
+	ctx, span := s.start(ctx, "ListPlanChanges", attribute.String("subscription_id", id.String()))
+	resp, err := s.next.ListPlanChanges(ctx, id)
+	end(span, err)
+	return resp, err
+}
+
+func (s *tracedService) ScheduleChange(ctx context.Context, id uuid.UUID, req models.ScheduleChangeRequest) (models.ScheduledChangeResponse, error) {
+	ctx, span := s.start(ctx, "ScheduleChange",
+		attribute.String("subscription_id", id.String()), attribute.String("action", req.Action))
+	resp, err := s.next.ScheduleChange(ctx, id, req)
+	end(span, err)
+	return resp, err
+}
+
+func (s *tracedService) ListScheduledChanges(ctx context.Context, id uuid.UUID) ([]models.ScheduledChangeResponse, error) {
+	ctx, span := s.start(ctx, "ListScheduledChanges", attribute.String("subscription_id", id.String()))
+	resp, err := s.next.ListScheduledChanges(ctx, id)
+	end(span, err)
+	return resp, err
+}
+
+func (s *tracedService) CancelScheduledChange(ctx context.Context, id, changeID uuid.UUID) error {
+	ctx, span := s.start(ctx, "CancelScheduledChange",
+		attribute.String("subscription_id", id.String()), attribute.String("change_id", changeID.String()))
+	err := s.next.CancelScheduledChange(ctx, id, changeID)
+	end(span, err)
+	return err
+}
+
+func (s *tracedService) CreateService(ctx context.Context, req models.ServiceRequest) (models.ServiceResponse, error) {
+	ctx, span := s.start(ctx, "CreateService", attribute.String("service_name", req.Name))
+	resp, err := s.next.CreateService(ctx, req)
+	end(span, err)
+	return resp, err
+}
+
+func (s *tracedService) GetService(ctx context.Context, id uuid.UUID) (models.ServiceResponse, error) {
+	ctx, span := s.start(ctx, "GetService", attribute.String("service_id", id.String()))
+	resp, err := s.next.GetService(ctx, id)
+	end(span, err)
+	return resp, err
+}
+
+func (s *tracedService) ListServices(ctx context.Context, query string) ([]models.ServiceResponse, error) {
+	ctx, span := s.start(ctx, "ListServices")
+	resp, err := s.next.ListServices(ctx, query)
+	end(span, err)
+	return resp, err
+}
+
+func (s *tracedService) UpdateService(ctx context.Context, id uuid.UUID, req models.ServiceRequest) (models.ServiceResponse, error) {
+	ctx, span := s.start(ctx, "UpdateService", attribute.String("service_id", id.String()))
+	resp, err := s.next.UpdateService(ctx, id, req)
+	end(span, err)
+	return resp, err
+}
+
+func (s *tracedService) DeleteService(ctx context.Context, id uuid.UUID) error {
+	ctx, span := s.start(ctx, "DeleteService", attribute.String("service_id", id.String()))
+	err := s.next.DeleteService(ctx, id)
+	end(span, err)
+	return err
+}
+
+func (s *tracedService) GetMembers(ctx context.Context, id uuid.UUID) (models.MembersResponse, error) {
+	ctx, span := s.start(ctx, "GetMembers", attribute.String("subscription_id", id.String()))
+	resp, err := s.next.GetMembers(ctx, id)
+	end(span, err)
+	return resp, err
+}
+
+func (s *tracedService) SetMembers(ctx context.Context, id uuid.UUID, req models.SetMembersRequest) (models.MembersResponse, error) {
+	ctx, span := s.start(ctx, "SetMembers", attribute.String("subscription_id", id.String()),
+		attribute.String("split_rule", req.SplitRule), attribute.Int("members", len(req.Members)))
+	resp, err := s.next.SetMembers(ctx, id, req)
+	end(span, err)
+	return resp, err
+}
@@ -1,10 +1,20 @@
 package validation
 
 import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
 	"time"
 
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
+	en "github.com/go-playground/validator/v10/translations/en"
+	ru "github.com/go-playground/validator/v10/translations/ru"
 
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/i18n"
 	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
 )
 
@@ -12,33 +22,126 @@ type Validator struct {
 	validator *validator.Validate
 }
 
+// customTranslations are messages of the rules reported by struct level validations
+// and of the rules missing from the default translations, by locale
+var customTranslations = map[string]map[string]string{
+	"en": {
+		"afterstart":            "{0} must not be before {1}",
+		"at_least_one_required": "at least one field must be provided",
+		"https_url":             "{0} must be an https URL",
+	},
+	"ru": {
+		"afterstart":            "{0} не может быть раньше {1}",
+		"at_least_one_required": "нужно указать хотя бы одно поле",
+		"https_url":             "{0} должен быть URL с https",
+		"required_if":           "{0} обязательное поле",
+	},
+}
+
+var shared = sync.OnceValue(newValidator)
+
+// New returns the validator shared by the process, it is safe for concurrent use.
+// Translations can be registered only once, so there is a single instance.
 func New() *Validator {
+	return shared()
+}
+
+func newValidator() *Validator {
 	v := &Validator{
 		validator: validator.New(validator.WithRequiredStructEnabled()),
 	}
 
+	// Report fields by their JSON names, which clients send
+	v.validator.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			return field.Name
+		}
+		return name
+	})
+
+	// Webhooks are sent only over TLS, see notify.Webhook
+	if err := v.validator.RegisterValidation("https_url", isHTTPSURL); err != nil {
+		panic(fmt.Sprintf("failed to register https_url validation: %v", err))
+	}
+
 	v.validator.RegisterStructValidation(v.createSubscriptionRequest, models.CreateSubscriptionRequest{})
 	v.validator.RegisterStructValidation(v.updateSubscriptionRequest, models.UpdateSubscriptionRequest{})
 	v.validator.RegisterStructValidation(v.totalCostRequest, models.TotalCostRequest{})
+	v.validator.RegisterStructValidation(v.pauseSubscriptionRequest, models.PauseSubscriptionRequest{})
+	v.validator.RegisterStructValidation(v.paymentMethodRequest, models.PaymentMethodRequest{})
+
+	if err := v.registerTranslations(); err != nil {
+		panic(fmt.Sprintf("failed to register validation translations: %v", err))
+	}
 
 	return v
 }
 
+func (v *Validator) registerTranslations() error {
+	defaults := map[string]func(*validator.Validate, ut.Translator) error{
+		"en": en.RegisterDefaultTranslations,
+		"ru": ru.RegisterDefaultTranslations,
+	}
+	for locale, trans := range i18n.Translators() {
+		if err := defaults[locale](v.validator, trans); err != nil {
+			return fmt.Errorf("%s: %w", locale, err)
+		}
+		for tag, text := range customTranslations[locale] {
+			register := func(trans ut.Translator) error {
+				return trans.Add(tag, text, false)
+			}
+			if err := v.validator.RegisterTranslation(tag, trans, register, translateCustom); err != nil {
+				return fmt.Errorf("%s %s: %w", locale, tag, err)
+			}
+		}
+	}
+	return nil
+}
+
+func translateCustom(trans ut.Translator, fe validator.FieldError) string {
+	msg, err := trans.T(fe.Tag(), fe.Field(), fe.Param())
+	if err != nil {
+		return fe.Error()
+	}
+	return msg
+}
+
+// isHTTPSURL accepts absolute https URLs with a host
+func isHTTPSURL(fl validator.FieldLevel) bool {
+	u, err := url.Parse(fl.Field().String())
+	return err == nil && u.Scheme == "https" && u.Host != ""
+}
+
 // Struct validates a struct and returns validation errors
 func (v *Validator) Struct(s any) error {
 	return v.validator.Struct(s)
 }
 
+// InvalidFields converts errors returned by Struct to invalid fields with reasons in the language of trans,
+// it returns nil for other errors
+func InvalidFields(err error, trans ut.Translator) []models.InvalidField {
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return nil
+	}
+	fields := make([]models.InvalidField, len(verrs))
+	for i, fe := range verrs {
+		fields[i] = models.InvalidField{Name: fe.Field(), Rule: fe.Tag(), Reason: fe.Translate(trans)}
+	}
+	return fields
+}
+
 func (v *Validator) createSubscriptionRequest(sl validator.StructLevel) {
 	req := sl.Current().Interface().(models.CreateSubscriptionRequest)
 
 	// Validate that end_date is after start_date if provided
-	if nil != req.EndDate {
+	if req.StartDate != nil && req.EndDate != nil {
 		startTime := time.Time(*req.StartDate)
 		endTime := time.Time(*req.EndDate)
 
 		if endTime.Before(startTime) {
-			sl.ReportError(req.EndDate, "end_date", "EndDate", "afterstart", "end date must be after start date")
+			sl.ReportError(req.EndDate, "end_date", "EndDate", "afterstart", "start_date")
 		}
 	}
 }
@@ -48,16 +151,25 @@ func (v *Validator) updateSubscriptionRequest(sl validator.StructLevel) {
 
 	// Validate price if provided (must be non-negative)
 	if req.Price != nil && *req.Price < 0 {
-		sl.ReportError(req.Price, "price", "Price", "min", "price must be non-negative")
+		sl.ReportError(req.Price, "price", "Price", "min", "0")
 	}
 
 	// Validate service name if provided (must not be empty)
 	if req.ServiceName != nil && *req.ServiceName == "" {
-		sl.ReportError(req.ServiceName, "service_name", "ServiceName", "required", "service name cannot be empty")
+		sl.ReportError(req.ServiceName, "service_name", "ServiceName", "required", "")
 	}
 
-	if req.ServiceName == nil && req.Price == nil && req.EndDate == nil {
-		sl.ReportError(req, "request_body", "RequestBody", "at_least_one_required", "at least one field must be provided")
+	if req.TrialMonths != nil && *req.TrialMonths < 0 {
+		sl.ReportError(req.TrialMonths, "trial_months", "TrialMonths", "min", "0")
+	}
+	if req.TrialPrice != nil && *req.TrialPrice < 0 {
+		sl.ReportError(req.TrialPrice, "trial_price", "TrialPrice", "min", "0")
+	}
+
+	if req.ServiceName == nil && req.ServiceID == nil && req.PlanID == nil && req.Price == nil && req.EndDate == nil && req.BillingDay == nil &&
+		req.TrialMonths == nil && req.TrialPrice == nil && req.Category == nil && req.AccountLabel == nil && req.AddTags == nil &&
+		req.RemoveTags == nil && req.PaymentMethodID == nil {
+		sl.ReportError(req, "request_body", "RequestBody", "at_least_one_required", "")
 	}
 }
 
@@ -70,12 +182,29 @@ func (v *Validator) totalCostRequest(sl validator.StructLevel) {
 		endTime := time.Time(*req.EndDate)
 
 		if endTime.Before(startTime) {
-			sl.ReportError(req.EndDate, "end_date", "EndDate", "afterstart", "end date must be after start date")
+			sl.ReportError(req.EndDate, "end_date", "EndDate", "afterstart", "start_date")
 		}
 	}
 
 	// Validate service name if provided (must not be empty)
 	if req.ServiceName != nil && *req.ServiceName == "" {
-		sl.ReportError(req.ServiceName, "service_name", "ServiceName", "required", "service name cannot be empty")
+		sl.ReportError(req.ServiceName, "service_name", "ServiceName", "required", "")
+	}
+}
+
+func (v *Validator) paymentMethodRequest(sl validator.StructLevel) {
+	req := sl.Current().Interface().(models.PaymentMethodRequest)
+
+	// The label is stored trimmed, a blank one is as missing as an empty one, which required_if reports
+	if req.Kind == "bank" && req.Label != "" && strings.TrimSpace(req.Label) == "" {
+		sl.ReportError(req.Label, "label", "Label", "required_if", "Kind bank")
+	}
+}
+
+func (v *Validator) pauseSubscriptionRequest(sl validator.StructLevel) {
+	req := sl.Current().Interface().(models.PauseSubscriptionRequest)
+
+	if req.StartDate != nil && req.EndDate != nil && time.Time(*req.EndDate).Before(time.Time(*req.StartDate)) {
+		sl.ReportError(req.EndDate, "end_date", "EndDate", "afterstart", "start_date")
 	}
 }
@@ -0,0 +1,78 @@
+package validation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/i18n"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/internal/models"
+	"github.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/monthyear"
+)
+
+func TestPaymentMethodRequest(t *testing.T) {
+	expires := monthyear.MonthYear(time.Date(2030, 8, 1, 0, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name      string
+		req       models.PaymentMethodRequest
+		wantField string
+	}{
+		{name: "bank", req: models.PaymentMethodRequest{Kind: "bank", Label: " Зарплатная "}},
+		{name: "bank without label", req: models.PaymentMethodRequest{Kind: "bank"}, wantField: "label"},
+		{name: "bank with blank label", req: models.PaymentMethodRequest{Kind: "bank", Label: " \t "}, wantField: "label"},
+		{name: "card with blank label", req: models.PaymentMethodRequest{Kind: "card", Label: "  ", Last4: "4242", Expires: &expires}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := InvalidFields(New().Struct(tt.req), i18n.Translator("en"))
+			if tt.wantField == "" {
+				if len(fields) != 0 {
+					t.Fatalf("Struct() invalid fields = %v, want none", fields)
+				}
+				return
+			}
+			if len(fields) != 1 || fields[0].Name != tt.wantField {
+				t.Fatalf("Struct() invalid fields = %v, want %s", fields, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestCreateSubscriptionRequestPeriod(t *testing.T) {
+	month := func(m time.Month) *monthyear.MonthYear {
+		my := monthyear.MonthYear(time.Date(2025, m, 1, 0, 0, 0, 0, time.UTC))
+		return &my
+	}
+	price := 299
+
+	tests := []struct {
+		name      string
+		start     *monthyear.MonthYear
+		end       *monthyear.MonthYear
+		wantField string
+	}{
+		{name: "open", start: month(time.March)},
+		{name: "one month", start: month(time.March), end: month(time.March)},
+		{name: "end before start", start: month(time.March), end: month(time.February), wantField: "end_date"},
+		{name: "end without start", end: month(time.March), wantField: "start_date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := models.CreateSubscriptionRequest{ServiceName: "Netflix", Price: &price, UserID: uuid.New(), StartDate: tt.start, EndDate: tt.end}
+			fields := InvalidFields(New().Struct(req), i18n.Translator("en"))
+			if tt.wantField == "" {
+				if len(fields) != 0 {
+					t.Fatalf("Struct() invalid fields = %v, want none", fields)
+				}
+				return
+			}
+			if len(fields) != 1 || fields[0].Name != tt.wantField {
+				t.Fatalf("Struct() invalid fields = %v, want %s", fields, tt.wantField)
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+DROP POLICY IF EXISTS tenant_isolation ON subscriptions;
+ALTER TABLE subscriptions DISABLE ROW LEVEL SECURITY;
+
+REVOKE ALL ON subscriptions FROM subscription_tenant;
+DROP ROLE IF EXISTS subscription_tenant;
+
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_tenant_service_name_user_id_key;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE subscriptions
+    ADD CONSTRAINT subscriptions_service_name_user_id_key UNIQUE (service_name, user_id);
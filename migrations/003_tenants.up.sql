@@ -0,0 +1,26 @@
+-- Existing rows belong to the default tenant
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default' CHECK (tenant_id <> '');
+ALTER TABLE subscriptions
+    ALTER COLUMN tenant_id SET DEFAULT current_setting('app.tenant_id', true);
+
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_service_name_user_id_key;
+ALTER TABLE subscriptions
+    ADD CONSTRAINT subscriptions_tenant_service_name_user_id_key UNIQUE (tenant_id, service_name, user_id);
+
+-- Superusers and table owners bypass RLS, so the application switches to this role inside each transaction
+DO
+$$
+    BEGIN
+        IF NOT EXISTS (SELECT FROM pg_roles WHERE rolname = 'subscription_tenant') THEN
+            CREATE ROLE subscription_tenant NOLOGIN;
+        END IF;
+    END
+$$;
+GRANT subscription_tenant TO CURRENT_USER;
+GRANT SELECT, INSERT, UPDATE, DELETE ON subscriptions TO subscription_tenant;
+
+ALTER TABLE subscriptions ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON subscriptions
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS subscriptions_notify_change ON subscriptions;
+DROP FUNCTION IF EXISTS notify_subscription_change();
@@ -0,0 +1,32 @@
+-- Changes are published to the subscription_changes channel for watchers on every replica
+CREATE OR REPLACE FUNCTION notify_subscription_change() RETURNS trigger AS
+$$
+DECLARE
+    rec subscriptions;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        rec := OLD;
+    ELSE
+        rec := NEW;
+    END IF;
+
+    PERFORM pg_notify('subscription_changes', json_build_object(
+            'op', TG_OP,
+            'tenant_id', rec.tenant_id,
+            'id', rec.id,
+            'user_id', rec.user_id,
+            'service_name', rec.service_name,
+            'price', rec.price,
+            'start_date', rec.start_date,
+            'end_date', rec.end_date,
+            'changed_at', now()
+        )::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER subscriptions_notify_change
+    AFTER INSERT OR UPDATE OR DELETE
+    ON subscriptions
+    FOR EACH ROW
+EXECUTE FUNCTION notify_subscription_change();
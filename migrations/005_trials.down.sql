@@ -0,0 +1,29 @@
+CREATE OR REPLACE FUNCTION notify_subscription_change() RETURNS trigger AS
+$$
+DECLARE
+    rec subscriptions;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        rec := OLD;
+    ELSE
+        rec := NEW;
+    END IF;
+
+    PERFORM pg_notify('subscription_changes', json_build_object(
+            'op', TG_OP,
+            'tenant_id', rec.tenant_id,
+            'id', rec.id,
+            'user_id', rec.user_id,
+            'service_name', rec.service_name,
+            'price', rec.price,
+            'start_date', rec.start_date,
+            'end_date', rec.end_date,
+            'changed_at', now()
+        )::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS trial_months,
+    DROP COLUMN IF EXISTS trial_price;
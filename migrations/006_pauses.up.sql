@@ -0,0 +1,51 @@
+-- A pause covers months start_date to end_date inclusive, an open pause lasts until the subscription is resumed
+CREATE TABLE IF NOT EXISTS subscription_pauses
+(
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id       TEXT NOT NULL DEFAULT current_setting('app.tenant_id', true) CHECK (tenant_id <> ''),
+    subscription_id UUID NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+    start_date      DATE NOT NULL,
+    end_date        DATE,
+    CHECK (end_date IS NULL OR end_date >= start_date)
+);
+CREATE INDEX IF NOT EXISTS idx_subscription_pauses_subscription_id ON subscription_pauses (subscription_id);
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON subscription_pauses TO subscription_tenant;
+
+ALTER TABLE subscription_pauses ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON subscription_pauses
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
+
+CREATE OR REPLACE FUNCTION notify_subscription_change() RETURNS trigger AS
+$$
+DECLARE
+    rec subscriptions;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        rec := OLD;
+    ELSE
+        rec := NEW;
+    END IF;
+
+    PERFORM pg_notify('subscription_changes', json_build_object(
+            'op', TG_OP,
+            'tenant_id', rec.tenant_id,
+            'id', rec.id,
+            'user_id', rec.user_id,
+            'service_name', rec.service_name,
+            'price', rec.price,
+            'start_date', rec.start_date,
+            'end_date', rec.end_date,
+            'trial_months', rec.trial_months,
+            'trial_price', rec.trial_price,
+            'paused', EXISTS (SELECT 1
+                              FROM subscription_pauses p
+                              WHERE p.subscription_id = rec.id
+                                AND p.start_date <= date_trunc('month', now())
+                                AND (p.end_date IS NULL OR p.end_date >= date_trunc('month', now()))),
+            'changed_at', now()
+        )::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
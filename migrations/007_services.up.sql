@@ -0,0 +1,57 @@
+-- Catalog of services per tenant, subscriptions are matched to it by name or alias ignoring case
+CREATE TABLE IF NOT EXISTS services
+(
+    id        UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id TEXT         NOT NULL DEFAULT current_setting('app.tenant_id', true) CHECK (tenant_id <> ''),
+    name      VARCHAR(255) NOT NULL CHECK (name <> ''),
+    aliases   TEXT[]       NOT NULL DEFAULT '{}',
+    category  VARCHAR(255) NOT NULL DEFAULT '',
+    website   TEXT         NOT NULL DEFAULT '',
+    plans     JSONB        NOT NULL DEFAULT '[]' -- default plans: [{"name": "...", "price": 299}]
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_services_tenant_name ON services (tenant_id, lower(name));
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON services TO subscription_tenant;
+
+ALTER TABLE services ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON services
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
+
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS service_id UUID REFERENCES services (id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_subscriptions_service_id ON subscriptions (service_id);
+
+CREATE OR REPLACE FUNCTION notify_subscription_change() RETURNS trigger AS
+$$
+DECLARE
+    rec subscriptions;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        rec := OLD;
+    ELSE
+        rec := NEW;
+    END IF;
+
+    PERFORM pg_notify('subscription_changes', json_build_object(
+            'op', TG_OP,
+            'tenant_id', rec.tenant_id,
+            'id', rec.id,
+            'user_id', rec.user_id,
+            'service_name', rec.service_name,
+            'service_id', rec.service_id,
+            'price', rec.price,
+            'start_date', rec.start_date,
+            'end_date', rec.end_date,
+            'trial_months', rec.trial_months,
+            'trial_price', rec.trial_price,
+            'paused', EXISTS (SELECT 1
+                              FROM subscription_pauses p
+                              WHERE p.subscription_id = rec.id
+                                AND p.start_date <= date_trunc('month', now())
+                                AND (p.end_date IS NULL OR p.end_date >= date_trunc('month', now()))),
+            'changed_at', now()
+        )::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,45 @@
+CREATE OR REPLACE FUNCTION notify_subscription_change() RETURNS trigger AS
+$$
+DECLARE
+    rec subscriptions;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        rec := OLD;
+    ELSE
+        rec := NEW;
+    END IF;
+
+    PERFORM pg_notify('subscription_changes', json_build_object(
+            'op', TG_OP,
+            'tenant_id', rec.tenant_id,
+            'id', rec.id,
+            'user_id', rec.user_id,
+            'service_name', rec.service_name,
+            'service_id', rec.service_id,
+            'price', rec.price,
+            'start_date', rec.start_date,
+            'end_date', rec.end_date,
+            'trial_months', rec.trial_months,
+            'trial_price', rec.trial_price,
+            'paused', EXISTS (SELECT 1
+                              FROM subscription_pauses p
+                              WHERE p.subscription_id = rec.id
+                                AND p.start_date <= date_trunc('month', now())
+                                AND (p.end_date IS NULL OR p.end_date >= date_trunc('month', now()))),
+            'changed_at', now()
+        )::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TABLE IF EXISTS subscription_plan_changes;
+
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS plan_id;
+
+ALTER TABLE services ADD COLUMN IF NOT EXISTS plans JSONB NOT NULL DEFAULT '[]';
+UPDATE services s
+SET plans = (SELECT COALESCE(jsonb_agg(jsonb_build_object('name', p.name, 'price', p.price) ORDER BY p.price), '[]')
+             FROM service_plans p
+             WHERE p.service_id = s.id);
+
+DROP TABLE IF EXISTS service_plans;
@@ -0,0 +1,91 @@
+-- Plans of catalog services, the price is per billing period: a month or a year
+CREATE TABLE IF NOT EXISTS service_plans
+(
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id      TEXT         NOT NULL DEFAULT current_setting('app.tenant_id', true) CHECK (tenant_id <> ''),
+    service_id     UUID         NOT NULL REFERENCES services (id) ON DELETE CASCADE,
+    name           VARCHAR(255) NOT NULL CHECK (name <> ''),
+    price          INT          NOT NULL CHECK (price >= 0),
+    billing_period VARCHAR(5)   NOT NULL DEFAULT 'month' CHECK (billing_period IN ('month', 'year'))
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_service_plans_service_name ON service_plans (service_id, lower(name));
+
+INSERT INTO service_plans (tenant_id, service_id, name, price)
+SELECT s.tenant_id, s.id, p ->> 'name', (p ->> 'price')::int
+FROM services s
+         CROSS JOIN LATERAL jsonb_array_elements(s.plans) AS p
+ON CONFLICT DO NOTHING;
+ALTER TABLE services
+    DROP COLUMN IF EXISTS plans;
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON service_plans TO subscription_tenant;
+
+ALTER TABLE service_plans ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON service_plans
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
+
+-- The plan and price of a subscription apply from its start date until the first plan change
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS plan_id UUID REFERENCES service_plans (id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_subscriptions_plan_id ON subscriptions (plan_id);
+
+-- A plan change applies from start_date until the next change
+CREATE TABLE IF NOT EXISTS subscription_plan_changes
+(
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id       TEXT NOT NULL DEFAULT current_setting('app.tenant_id', true) CHECK (tenant_id <> ''),
+    subscription_id UUID NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+    plan_id         UUID REFERENCES service_plans (id) ON DELETE SET NULL,
+    price           INT  NOT NULL CHECK (price >= 0),
+    start_date      DATE NOT NULL,
+    UNIQUE (subscription_id, start_date)
+);
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON subscription_plan_changes TO subscription_tenant;
+
+ALTER TABLE subscription_plan_changes ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON subscription_plan_changes
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
+
+CREATE OR REPLACE FUNCTION notify_subscription_change() RETURNS trigger AS
+$$
+DECLARE
+    rec subscriptions;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        rec := OLD;
+    ELSE
+        rec := NEW;
+    END IF;
+
+    PERFORM pg_notify('subscription_changes', json_build_object(
+            'op', TG_OP,
+            'tenant_id', rec.tenant_id,
+            'id', rec.id,
+            'user_id', rec.user_id,
+            'service_name', rec.service_name,
+            'service_id', rec.service_id,
+            'plan_id', rec.plan_id,
+            'price', rec.price,
+            'current_price', COALESCE((SELECT c.price
+                                       FROM subscription_plan_changes c
+                                       WHERE c.subscription_id = rec.id
+                                         AND c.start_date <= date_trunc('month', now())
+                                       ORDER BY c.start_date DESC
+                                       LIMIT 1), rec.price),
+            'start_date', rec.start_date,
+            'end_date', rec.end_date,
+            'trial_months', rec.trial_months,
+            'trial_price', rec.trial_price,
+            'paused', EXISTS (SELECT 1
+                              FROM subscription_pauses p
+                              WHERE p.subscription_id = rec.id
+                                AND p.start_date <= date_trunc('month', now())
+                                AND (p.end_date IS NULL OR p.end_date >= date_trunc('month', now()))),
+            'changed_at', now()
+        )::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
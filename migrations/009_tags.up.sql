@@ -0,0 +1,79 @@
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS category VARCHAR(255) NOT NULL DEFAULT '';
+
+-- Tags are stored lowercased, a tag is shared by all subscriptions of the tenant with it
+CREATE TABLE IF NOT EXISTS tags
+(
+    id        UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id TEXT         NOT NULL DEFAULT current_setting('app.tenant_id', true) CHECK (tenant_id <> ''),
+    name      VARCHAR(100) NOT NULL CHECK (name <> '' AND name = lower(name)),
+    UNIQUE (tenant_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS subscription_tags
+(
+    subscription_id UUID NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+    tag_id          UUID NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    tenant_id       TEXT NOT NULL DEFAULT current_setting('app.tenant_id', true) CHECK (tenant_id <> ''),
+    PRIMARY KEY (subscription_id, tag_id)
+);
+CREATE INDEX IF NOT EXISTS idx_subscription_tags_tag_id ON subscription_tags (tag_id);
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON tags, subscription_tags TO subscription_tenant;
+
+ALTER TABLE tags ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON tags
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
+
+ALTER TABLE subscription_tags ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON subscription_tags
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
+
+CREATE OR REPLACE FUNCTION notify_subscription_change() RETURNS trigger AS
+$$
+DECLARE
+    rec subscriptions;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        rec := OLD;
+    ELSE
+        rec := NEW;
+    END IF;
+
+    PERFORM pg_notify('subscription_changes', json_build_object(
+            'op', TG_OP,
+            'tenant_id', rec.tenant_id,
+            'id', rec.id,
+            'user_id', rec.user_id,
+            'service_name', rec.service_name,
+            'service_id', rec.service_id,
+            'category', rec.category,
+            'tags', ARRAY(SELECT t.name
+                          FROM subscription_tags st
+                                   JOIN tags t ON t.id = st.tag_id
+                          WHERE st.subscription_id = rec.id
+                          ORDER BY t.name),
+            'plan_id', rec.plan_id,
+            'price', rec.price,
+            'current_price', COALESCE((SELECT c.price
+                                       FROM subscription_plan_changes c
+                                       WHERE c.subscription_id = rec.id
+                                         AND c.start_date <= date_trunc('month', now())
+                                       ORDER BY c.start_date DESC
+                                       LIMIT 1), rec.price),
+            'start_date', rec.start_date,
+            'end_date', rec.end_date,
+            'trial_months', rec.trial_months,
+            'trial_price', rec.trial_price,
+            'paused', EXISTS (SELECT 1
+                              FROM subscription_pauses p
+                              WHERE p.subscription_id = rec.id
+                                AND p.start_date <= date_trunc('month', now())
+                                AND (p.end_date IS NULL OR p.end_date >= date_trunc('month', now()))),
+            'changed_at', now()
+        )::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
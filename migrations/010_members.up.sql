@@ -0,0 +1,21 @@
+-- Members share the cost of a subscription with its owner by the split rule of the subscription:
+-- equally, by percent or by a fixed monthly amount in share. The owner pays the rest.
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS split_rule TEXT NOT NULL DEFAULT 'equal' CHECK (split_rule IN ('equal', 'percent', 'fixed'));
+
+CREATE TABLE IF NOT EXISTS subscription_members
+(
+    subscription_id UUID    NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+    user_id         UUID    NOT NULL,
+    tenant_id       TEXT    NOT NULL DEFAULT current_setting('app.tenant_id', true) CHECK (tenant_id <> ''),
+    share           INTEGER NOT NULL DEFAULT 0 CHECK (share >= 0),
+    PRIMARY KEY (subscription_id, user_id)
+);
+CREATE INDEX IF NOT EXISTS idx_subscription_members_user_id ON subscription_members (user_id);
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON subscription_members TO subscription_tenant;
+
+ALTER TABLE subscription_members ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON subscription_members
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
@@ -0,0 +1,53 @@
+-- Fails if a user has several subscriptions of the same service
+CREATE OR REPLACE FUNCTION notify_subscription_change() RETURNS trigger AS
+$$
+DECLARE
+    rec subscriptions;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        rec := OLD;
+    ELSE
+        rec := NEW;
+    END IF;
+
+    PERFORM pg_notify('subscription_changes', json_build_object(
+            'op', TG_OP,
+            'tenant_id', rec.tenant_id,
+            'id', rec.id,
+            'user_id', rec.user_id,
+            'service_name', rec.service_name,
+            'service_id', rec.service_id,
+            'category', rec.category,
+            'tags', ARRAY(SELECT t.name
+                          FROM subscription_tags st
+                                   JOIN tags t ON t.id = st.tag_id
+                          WHERE st.subscription_id = rec.id
+                          ORDER BY t.name),
+            'plan_id', rec.plan_id,
+            'price', rec.price,
+            'current_price', COALESCE((SELECT c.price
+                                       FROM subscription_plan_changes c
+                                       WHERE c.subscription_id = rec.id
+                                         AND c.start_date <= date_trunc('month', now())
+                                       ORDER BY c.start_date DESC
+                                       LIMIT 1), rec.price),
+            'start_date', rec.start_date,
+            'end_date', rec.end_date,
+            'trial_months', rec.trial_months,
+            'trial_price', rec.trial_price,
+            'paused', EXISTS (SELECT 1
+                              FROM subscription_pauses p
+                              WHERE p.subscription_id = rec.id
+                                AND p.start_date <= date_trunc('month', now())
+                                AND (p.end_date IS NULL OR p.end_date >= date_trunc('month', now()))),
+            'changed_at', now()
+        )::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_no_overlap;
+ALTER TABLE subscriptions
+    ADD CONSTRAINT subscriptions_tenant_service_name_user_id_key UNIQUE (tenant_id, service_name, user_id);
+
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS account_label;
@@ -0,0 +1,38 @@
+-- Users without preferences get reminders with the defaults of the application
+CREATE TABLE IF NOT EXISTS notification_preferences
+(
+    tenant_id   TEXT        NOT NULL DEFAULT current_setting('app.tenant_id', true) CHECK (tenant_id <> ''),
+    user_id     UUID        NOT NULL,
+    enabled     BOOLEAN     NOT NULL,
+    days_before INTEGER     NOT NULL CHECK (days_before BETWEEN 1 AND 28),
+    channel     TEXT        NOT NULL CHECK (channel IN ('log', 'email', 'webhook')),
+    email       TEXT        NOT NULL DEFAULT '',
+    webhook_url TEXT        NOT NULL DEFAULT '',
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (tenant_id, user_id)
+);
+
+-- A reminder is logged before it is sent, so it goes out once even with several replicas
+CREATE TABLE IF NOT EXISTS reminder_log
+(
+    id              UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    tenant_id       TEXT        NOT NULL DEFAULT current_setting('app.tenant_id', true) CHECK (tenant_id <> ''),
+    subscription_id UUID        NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+    kind            TEXT        NOT NULL CHECK (kind IN ('renewal', 'ending')),
+    due_date        DATE        NOT NULL,
+    channel         TEXT        NOT NULL,
+    sent_at         TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (subscription_id, kind, due_date)
+);
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON notification_preferences, reminder_log TO subscription_tenant;
+
+ALTER TABLE notification_preferences ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON notification_preferences
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
+
+ALTER TABLE reminder_log ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON reminder_log
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
@@ -0,0 +1,60 @@
+-- start_date is a month, billing_day is the day of the month charges happen on.
+-- Months shorter than billing_day are charged on their last day.
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS billing_day SMALLINT NOT NULL DEFAULT 1 CHECK (billing_day BETWEEN 1 AND 31);
+
+-- billing_date returns the day of month a subscription billed on billing_day is charged
+CREATE OR REPLACE FUNCTION billing_date(month DATE, billing_day SMALLINT) RETURNS DATE AS
+$$
+SELECT (date_trunc('month', month)::date +
+        LEAST(billing_day, EXTRACT(DAY FROM date_trunc('month', month) + INTERVAL '1 month - 1 day')::int) - 1)::date
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION notify_subscription_change() RETURNS trigger AS
+$$
+DECLARE
+    rec subscriptions;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        rec := OLD;
+    ELSE
+        rec := NEW;
+    END IF;
+
+    PERFORM pg_notify('subscription_changes', json_build_object(
+            'op', TG_OP,
+            'tenant_id', rec.tenant_id,
+            'id', rec.id,
+            'user_id', rec.user_id,
+            'service_name', rec.service_name,
+            'service_id', rec.service_id,
+            'account_label', rec.account_label,
+            'category', rec.category,
+            'tags', ARRAY(SELECT t.name
+                          FROM subscription_tags st
+                                   JOIN tags t ON t.id = st.tag_id
+                          WHERE st.subscription_id = rec.id
+                          ORDER BY t.name),
+            'plan_id', rec.plan_id,
+            'price', rec.price,
+            'current_price', COALESCE((SELECT c.price
+                                       FROM subscription_plan_changes c
+                                       WHERE c.subscription_id = rec.id
+                                         AND c.start_date <= date_trunc('month', now())
+                                       ORDER BY c.start_date DESC
+                                       LIMIT 1), rec.price),
+            'start_date', rec.start_date,
+            'billing_day', rec.billing_day,
+            'end_date', rec.end_date,
+            'trial_months', rec.trial_months,
+            'trial_price', rec.trial_price,
+            'paused', EXISTS (SELECT 1
+                              FROM subscription_pauses p
+                              WHERE p.subscription_id = rec.id
+                                AND p.start_date <= date_trunc('month', now())
+                                AND (p.end_date IS NULL OR p.end_date >= date_trunc('month', now()))),
+            'changed_at', now()
+        )::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,2 @@
+DROP VIEW IF EXISTS subscription_prices;
+DROP TABLE IF EXISTS subscription_scheduled_changes;
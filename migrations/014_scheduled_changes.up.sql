@@ -0,0 +1,44 @@
+-- Changes of subscriptions taking effect in a future month, applied by the scheduler when the month comes.
+-- cancel ends the subscription with month, price sets the price from month.
+CREATE TABLE IF NOT EXISTS subscription_scheduled_changes
+(
+    id              UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    tenant_id       TEXT        NOT NULL DEFAULT current_setting('app.tenant_id', true) CHECK (tenant_id <> ''),
+    subscription_id UUID        NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+    action          VARCHAR(10) NOT NULL CHECK (action IN ('cancel', 'price')),
+    month           DATE        NOT NULL,
+    price           INT CHECK (price >= 0),
+    status          VARCHAR(10) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'applied', 'canceled')),
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    applied_at      TIMESTAMPTZ,
+    CHECK ((action = 'price') = (price IS NOT NULL))
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_scheduled_changes_pending
+    ON subscription_scheduled_changes (subscription_id, action, month) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_scheduled_changes_due ON subscription_scheduled_changes (month) WHERE status = 'pending';
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON subscription_scheduled_changes TO subscription_tenant;
+
+ALTER TABLE subscription_scheduled_changes ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON subscription_scheduled_changes
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
+
+-- Prices of subscriptions from plan changes and pending price changes, a pending change replaces a plan change
+-- of the same month. Forecasts read prices from here, so they include changes not yet applied.
+CREATE OR REPLACE VIEW subscription_prices WITH (security_invoker = true) AS
+SELECT sc.subscription_id, sc.month AS start_date, sc.price
+FROM subscription_scheduled_changes sc
+WHERE sc.status = 'pending'
+  AND sc.action = 'price'
+UNION ALL
+SELECT c.subscription_id, c.start_date, c.price
+FROM subscription_plan_changes c
+WHERE NOT EXISTS (SELECT 1
+                  FROM subscription_scheduled_changes sc
+                  WHERE sc.subscription_id = c.subscription_id
+                    AND sc.status = 'pending'
+                    AND sc.action = 'price'
+                    AND sc.month = c.start_date);
+
+GRANT SELECT ON subscription_prices TO subscription_tenant;
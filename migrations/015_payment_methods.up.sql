@@ -0,0 +1,80 @@
+-- Payment methods keep only what lets users tell them apart: a card's brand, last four digits and expiry month,
+-- or a bank account label. Card numbers and other sensitive data are never stored.
+CREATE TABLE IF NOT EXISTS payment_methods
+(
+    id         UUID PRIMARY KEY      DEFAULT gen_random_uuid(),
+    tenant_id  TEXT         NOT NULL DEFAULT current_setting('app.tenant_id', true) CHECK (tenant_id <> ''),
+    user_id    UUID         NOT NULL,
+    kind       VARCHAR(10)  NOT NULL CHECK (kind IN ('card', 'bank')),
+    label      VARCHAR(100) NOT NULL DEFAULT '',
+    brand      VARCHAR(20)  NOT NULL DEFAULT '',
+    last4      CHAR(4)      CHECK (last4 ~ '^[0-9]{4}$'),
+    -- The card is valid through the last day of this month
+    expires    DATE,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    CHECK (kind <> 'card' OR (last4 IS NOT NULL AND expires IS NOT NULL)),
+    CHECK (kind <> 'bank' OR (label <> '' AND last4 IS NULL AND expires IS NULL))
+);
+CREATE INDEX IF NOT EXISTS idx_payment_methods_user ON payment_methods (user_id);
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON payment_methods TO subscription_tenant;
+
+ALTER TABLE payment_methods ENABLE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON payment_methods
+    USING (tenant_id = current_setting('app.tenant_id', true))
+    WITH CHECK (tenant_id = current_setting('app.tenant_id', true));
+
+-- The payment method a subscription is charged to, it must belong to the owner of the subscription
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS payment_method_id UUID REFERENCES payment_methods (id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_subscriptions_payment_method ON subscriptions (payment_method_id);
+
+CREATE OR REPLACE FUNCTION notify_subscription_change() RETURNS trigger AS
+$$
+DECLARE
+    rec subscriptions;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        rec := OLD;
+    ELSE
+        rec := NEW;
+    END IF;
+
+    PERFORM pg_notify('subscription_changes', json_build_object(
+            'op', TG_OP,
+            'tenant_id', rec.tenant_id,
+            'id', rec.id,
+            'user_id', rec.user_id,
+            'service_name', rec.service_name,
+            'service_id', rec.service_id,
+            'account_label', rec.account_label,
+            'category', rec.category,
+            'tags', ARRAY(SELECT t.name
+                          FROM subscription_tags st
+                                   JOIN tags t ON t.id = st.tag_id
+                          WHERE st.subscription_id = rec.id
+                          ORDER BY t.name),
+            'plan_id', rec.plan_id,
+            'price', rec.price,
+            'current_price', COALESCE((SELECT c.price
+                                       FROM subscription_plan_changes c
+                                       WHERE c.subscription_id = rec.id
+                                         AND c.start_date <= date_trunc('month', now())
+                                       ORDER BY c.start_date DESC
+                                       LIMIT 1), rec.price),
+            'start_date', rec.start_date,
+            'billing_day', rec.billing_day,
+            'payment_method_id', rec.payment_method_id,
+            'end_date', rec.end_date,
+            'trial_months', rec.trial_months,
+            'trial_price', rec.trial_price,
+            'paused', EXISTS (SELECT 1
+                              FROM subscription_pauses p
+                              WHERE p.subscription_id = rec.id
+                                AND p.start_date <= date_trunc('month', now())
+                                AND (p.end_date IS NULL OR p.end_date >= date_trunc('month', now()))),
+            'changed_at', now()
+        )::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,4 @@
+-- Free subscriptions are kept, the restored check applies only to new rows
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_price_check;
+ALTER TABLE subscriptions
+    ADD CONSTRAINT subscriptions_price_check CHECK (price > 0) NOT VALID;
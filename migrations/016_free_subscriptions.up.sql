@@ -0,0 +1,4 @@
+-- Free plans and explicit price overrides of 0 are valid subscriptions, like free plans in the catalog
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_price_check;
+ALTER TABLE subscriptions
+    ADD CONSTRAINT subscriptions_price_check CHECK (price >= 0);
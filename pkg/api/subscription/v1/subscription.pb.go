@@ -0,0 +1,2651 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: subscription/v1/subscription.proto
+
+package subscriptionv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ScheduledAction int32
+
+const (
+	ScheduledAction_SCHEDULED_ACTION_UNSPECIFIED ScheduledAction = 0
+	// Ends the subscription after month.
+	ScheduledAction_SCHEDULED_ACTION_CANCEL ScheduledAction = 1
+	// Bills months from month at price.
+	ScheduledAction_SCHEDULED_ACTION_PRICE ScheduledAction = 2
+)
+
+// Enum value maps for ScheduledAction.
+var (
+	ScheduledAction_name = map[int32]string{
+		0: "SCHEDULED_ACTION_UNSPECIFIED",
+		1: "SCHEDULED_ACTION_CANCEL",
+		2: "SCHEDULED_ACTION_PRICE",
+	}
+	ScheduledAction_value = map[string]int32{
+		"SCHEDULED_ACTION_UNSPECIFIED": 0,
+		"SCHEDULED_ACTION_CANCEL":      1,
+		"SCHEDULED_ACTION_PRICE":       2,
+	}
+)
+
+func (x ScheduledAction) Enum() *ScheduledAction {
+	p := new(ScheduledAction)
+	*p = x
+	return p
+}
+
+func (x ScheduledAction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ScheduledAction) Descriptor() protoreflect.EnumDescriptor {
+	return file_subscription_v1_subscription_proto_enumTypes[0].Descriptor()
+}
+
+func (ScheduledAction) Type() protoreflect.EnumType {
+	return &file_subscription_v1_subscription_proto_enumTypes[0]
+}
+
+func (x ScheduledAction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ScheduledAction.Descriptor instead.
+func (ScheduledAction) EnumDescriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{0}
+}
+
+// SplitRule sets how members share the price, the owner pays the rest.
+type SplitRule int32
+
+const (
+	SplitRule_SPLIT_RULE_UNSPECIFIED SplitRule = 0
+	// Equally between the owner and the members.
+	SplitRule_SPLIT_RULE_EQUAL SplitRule = 1
+	// Each member pays share percent.
+	SplitRule_SPLIT_RULE_PERCENT SplitRule = 2
+	// Each member pays share rubles a month, fixed shares exceeding the price of a month split it in proportion.
+	SplitRule_SPLIT_RULE_FIXED SplitRule = 3
+)
+
+// Enum value maps for SplitRule.
+var (
+	SplitRule_name = map[int32]string{
+		0: "SPLIT_RULE_UNSPECIFIED",
+		1: "SPLIT_RULE_EQUAL",
+		2: "SPLIT_RULE_PERCENT",
+		3: "SPLIT_RULE_FIXED",
+	}
+	SplitRule_value = map[string]int32{
+		"SPLIT_RULE_UNSPECIFIED": 0,
+		"SPLIT_RULE_EQUAL":       1,
+		"SPLIT_RULE_PERCENT":     2,
+		"SPLIT_RULE_FIXED":       3,
+	}
+)
+
+func (x SplitRule) Enum() *SplitRule {
+	p := new(SplitRule)
+	*p = x
+	return p
+}
+
+func (x SplitRule) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SplitRule) Descriptor() protoreflect.EnumDescriptor {
+	return file_subscription_v1_subscription_proto_enumTypes[1].Descriptor()
+}
+
+func (SplitRule) Type() protoreflect.EnumType {
+	return &file_subscription_v1_subscription_proto_enumTypes[1]
+}
+
+func (x SplitRule) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SplitRule.Descriptor instead.
+func (SplitRule) EnumDescriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{1}
+}
+
+type Subscription_State int32
+
+const (
+	Subscription_STATE_UNSPECIFIED Subscription_State = 0
+	Subscription_STATE_ACTIVE      Subscription_State = 1
+	Subscription_STATE_PAUSED      Subscription_State = 2
+	Subscription_STATE_ENDED       Subscription_State = 3
+)
+
+// Enum value maps for Subscription_State.
+var (
+	Subscription_State_name = map[int32]string{
+		0: "STATE_UNSPECIFIED",
+		1: "STATE_ACTIVE",
+		2: "STATE_PAUSED",
+		3: "STATE_ENDED",
+	}
+	Subscription_State_value = map[string]int32{
+		"STATE_UNSPECIFIED": 0,
+		"STATE_ACTIVE":      1,
+		"STATE_PAUSED":      2,
+		"STATE_ENDED":       3,
+	}
+)
+
+func (x Subscription_State) Enum() *Subscription_State {
+	p := new(Subscription_State)
+	*p = x
+	return p
+}
+
+func (x Subscription_State) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Subscription_State) Descriptor() protoreflect.EnumDescriptor {
+	return file_subscription_v1_subscription_proto_enumTypes[2].Descriptor()
+}
+
+func (Subscription_State) Type() protoreflect.EnumType {
+	return &file_subscription_v1_subscription_proto_enumTypes[2]
+}
+
+func (x Subscription_State) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Subscription_State.Descriptor instead.
+func (Subscription_State) EnumDescriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{1, 0}
+}
+
+type ScheduledChange_Status int32
+
+const (
+	ScheduledChange_STATUS_UNSPECIFIED ScheduledChange_Status = 0
+	ScheduledChange_STATUS_PENDING     ScheduledChange_Status = 1
+	ScheduledChange_STATUS_APPLIED     ScheduledChange_Status = 2
+	ScheduledChange_STATUS_CANCELED    ScheduledChange_Status = 3
+)
+
+// Enum value maps for ScheduledChange_Status.
+var (
+	ScheduledChange_Status_name = map[int32]string{
+		0: "STATUS_UNSPECIFIED",
+		1: "STATUS_PENDING",
+		2: "STATUS_APPLIED",
+		3: "STATUS_CANCELED",
+	}
+	ScheduledChange_Status_value = map[string]int32{
+		"STATUS_UNSPECIFIED": 0,
+		"STATUS_PENDING":     1,
+		"STATUS_APPLIED":     2,
+		"STATUS_CANCELED":    3,
+	}
+)
+
+func (x ScheduledChange_Status) Enum() *ScheduledChange_Status {
+	p := new(ScheduledChange_Status)
+	*p = x
+	return p
+}
+
+func (x ScheduledChange_Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ScheduledChange_Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_subscription_v1_subscription_proto_enumTypes[3].Descriptor()
+}
+
+func (ScheduledChange_Status) Type() protoreflect.EnumType {
+	return &file_subscription_v1_subscription_proto_enumTypes[3]
+}
+
+func (x ScheduledChange_Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ScheduledChange_Status.Descriptor instead.
+func (ScheduledChange_Status) EnumDescriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{13, 0}
+}
+
+type SubscriptionEvent_Type int32
+
+const (
+	SubscriptionEvent_TYPE_UNSPECIFIED SubscriptionEvent_Type = 0
+	SubscriptionEvent_TYPE_CREATED     SubscriptionEvent_Type = 1
+	SubscriptionEvent_TYPE_UPDATED     SubscriptionEvent_Type = 2
+	SubscriptionEvent_TYPE_DELETED     SubscriptionEvent_Type = 3
+)
+
+// Enum value maps for SubscriptionEvent_Type.
+var (
+	SubscriptionEvent_Type_name = map[int32]string{
+		0: "TYPE_UNSPECIFIED",
+		1: "TYPE_CREATED",
+		2: "TYPE_UPDATED",
+		3: "TYPE_DELETED",
+	}
+	SubscriptionEvent_Type_value = map[string]int32{
+		"TYPE_UNSPECIFIED": 0,
+		"TYPE_CREATED":     1,
+		"TYPE_UPDATED":     2,
+		"TYPE_DELETED":     3,
+	}
+)
+
+func (x SubscriptionEvent_Type) Enum() *SubscriptionEvent_Type {
+	p := new(SubscriptionEvent_Type)
+	*p = x
+	return p
+}
+
+func (x SubscriptionEvent_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SubscriptionEvent_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_subscription_v1_subscription_proto_enumTypes[4].Descriptor()
+}
+
+func (SubscriptionEvent_Type) Type() protoreflect.EnumType {
+	return &file_subscription_v1_subscription_proto_enumTypes[4]
+}
+
+func (x SubscriptionEvent_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SubscriptionEvent_Type.Descriptor instead.
+func (SubscriptionEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{27, 0}
+}
+
+// Month is a calendar month, subscriptions are billed monthly.
+type Month struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Year  int32                  `protobuf:"varint,1,opt,name=year,proto3" json:"year,omitempty"`
+	// 1 to 12.
+	Month         int32 `protobuf:"varint,2,opt,name=month,proto3" json:"month,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Month) Reset() {
+	*x = Month{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Month) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Month) ProtoMessage() {}
+
+func (x *Month) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Month.ProtoReflect.Descriptor instead.
+func (*Month) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Month) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *Month) GetMonth() int32 {
+	if x != nil {
+		return x.Month
+	}
+	return 0
+}
+
+type Subscription struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId      string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ServiceName string                 `protobuf:"bytes,3,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	// Monthly price in rubles from the start date until the first plan change.
+	Price     int64  `protobuf:"varint,4,opt,name=price,proto3" json:"price,omitempty"`
+	StartDate *Month `protobuf:"bytes,5,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	// Unset while the subscription is active.
+	EndDate *Month `protobuf:"bytes,6,opt,name=end_date,json=endDate,proto3,oneof" json:"end_date,omitempty"`
+	// Trial months from the start date, billed at trial_price; 0 without a trial.
+	TrialMonths int32 `protobuf:"varint,7,opt,name=trial_months,json=trialMonths,proto3" json:"trial_months,omitempty"`
+	TrialPrice  int64 `protobuf:"varint,8,opt,name=trial_price,json=trialPrice,proto3" json:"trial_price,omitempty"`
+	// Last month of the trial, unset without a trial.
+	TrialEndDate *Month `protobuf:"bytes,9,opt,name=trial_end_date,json=trialEndDate,proto3,oneof" json:"trial_end_date,omitempty"`
+	// State in the current month.
+	State Subscription_State `protobuf:"varint,10,opt,name=state,proto3,enum=subscription.v1.Subscription_State" json:"state,omitempty"`
+	// Catalog service the name is matched to.
+	ServiceId *string `protobuf:"bytes,11,opt,name=service_id,json=serviceId,proto3,oneof" json:"service_id,omitempty"`
+	// Plan from the start date until the first plan change.
+	PlanId *string `protobuf:"bytes,12,opt,name=plan_id,json=planId,proto3,oneof" json:"plan_id,omitempty"`
+	// Monthly price in the current month, after plan changes.
+	CurrentPrice int64  `protobuf:"varint,13,opt,name=current_price,json=currentPrice,proto3" json:"current_price,omitempty"`
+	Category     string `protobuf:"bytes,14,opt,name=category,proto3" json:"category,omitempty"`
+	// Lowercased, in alphabetical order.
+	Tags []string `protobuf:"bytes,15,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Account of the service, subscriptions of the same account do not overlap in time.
+	AccountLabel string `protobuf:"bytes,16,opt,name=account_label,json=accountLabel,proto3" json:"account_label,omitempty"`
+	// Day of month charges happen on, the last day in shorter months.
+	BillingDay int32 `protobuf:"varint,17,opt,name=billing_day,json=billingDay,proto3" json:"billing_day,omitempty"`
+	// Payment method of the user the subscription is charged to.
+	PaymentMethodId *string `protobuf:"bytes,18,opt,name=payment_method_id,json=paymentMethodId,proto3,oneof" json:"payment_method_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Subscription) Reset() {
+	*x = Subscription{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Subscription) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Subscription) ProtoMessage() {}
+
+func (x *Subscription) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Subscription.ProtoReflect.Descriptor instead.
+func (*Subscription) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Subscription) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Subscription) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Subscription) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *Subscription) GetPrice() int64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Subscription) GetStartDate() *Month {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *Subscription) GetEndDate() *Month {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+func (x *Subscription) GetTrialMonths() int32 {
+	if x != nil {
+		return x.TrialMonths
+	}
+	return 0
+}
+
+func (x *Subscription) GetTrialPrice() int64 {
+	if x != nil {
+		return x.TrialPrice
+	}
+	return 0
+}
+
+func (x *Subscription) GetTrialEndDate() *Month {
+	if x != nil {
+		return x.TrialEndDate
+	}
+	return nil
+}
+
+func (x *Subscription) GetState() Subscription_State {
+	if x != nil {
+		return x.State
+	}
+	return Subscription_STATE_UNSPECIFIED
+}
+
+func (x *Subscription) GetServiceId() string {
+	if x != nil && x.ServiceId != nil {
+		return *x.ServiceId
+	}
+	return ""
+}
+
+func (x *Subscription) GetPlanId() string {
+	if x != nil && x.PlanId != nil {
+		return *x.PlanId
+	}
+	return ""
+}
+
+func (x *Subscription) GetCurrentPrice() int64 {
+	if x != nil {
+		return x.CurrentPrice
+	}
+	return 0
+}
+
+func (x *Subscription) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *Subscription) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Subscription) GetAccountLabel() string {
+	if x != nil {
+		return x.AccountLabel
+	}
+	return ""
+}
+
+func (x *Subscription) GetBillingDay() int32 {
+	if x != nil {
+		return x.BillingDay
+	}
+	return 0
+}
+
+func (x *Subscription) GetPaymentMethodId() string {
+	if x != nil && x.PaymentMethodId != nil {
+		return *x.PaymentMethodId
+	}
+	return ""
+}
+
+type CreateSubscriptionRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	UserId      string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ServiceName string                 `protobuf:"bytes,2,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	// Monthly price, 0 for a free subscription. Defaults to the price of plan_id, required without it.
+	Price       *int64 `protobuf:"varint,3,opt,name=price,proto3,oneof" json:"price,omitempty"`
+	StartDate   *Month `protobuf:"bytes,4,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate     *Month `protobuf:"bytes,5,opt,name=end_date,json=endDate,proto3,oneof" json:"end_date,omitempty"`
+	TrialMonths int32  `protobuf:"varint,6,opt,name=trial_months,json=trialMonths,proto3" json:"trial_months,omitempty"`
+	// Monthly price during the trial, 0 for a free trial.
+	TrialPrice int64 `protobuf:"varint,7,opt,name=trial_price,json=trialPrice,proto3" json:"trial_price,omitempty"`
+	// Catalog service, service_name may be empty then. Without it the name is matched to the catalog.
+	ServiceId *string `protobuf:"bytes,8,opt,name=service_id,json=serviceId,proto3,oneof" json:"service_id,omitempty"`
+	// Plan of a catalog service, it sets the service and, if price is not set, the price.
+	PlanId *string `protobuf:"bytes,9,opt,name=plan_id,json=planId,proto3,oneof" json:"plan_id,omitempty"`
+	// Defaults to the category of the catalog service.
+	Category string   `protobuf:"bytes,10,opt,name=category,proto3" json:"category,omitempty"`
+	Tags     []string `protobuf:"bytes,11,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Distinguishes several accounts of a user to the same service.
+	AccountLabel string `protobuf:"bytes,12,opt,name=account_label,json=accountLabel,proto3" json:"account_label,omitempty"`
+	// Day of month charges happen on, 1 to 31; 1 by default.
+	BillingDay int32 `protobuf:"varint,13,opt,name=billing_day,json=billingDay,proto3" json:"billing_day,omitempty"`
+	// Payment method of the user the subscription is charged to.
+	PaymentMethodId *string `protobuf:"bytes,14,opt,name=payment_method_id,json=paymentMethodId,proto3,oneof" json:"payment_method_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CreateSubscriptionRequest) Reset() {
+	*x = CreateSubscriptionRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSubscriptionRequest) ProtoMessage() {}
+
+func (x *CreateSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*CreateSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateSubscriptionRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CreateSubscriptionRequest) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *CreateSubscriptionRequest) GetPrice() int64 {
+	if x != nil && x.Price != nil {
+		return *x.Price
+	}
+	return 0
+}
+
+func (x *CreateSubscriptionRequest) GetStartDate() *Month {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *CreateSubscriptionRequest) GetEndDate() *Month {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+func (x *CreateSubscriptionRequest) GetTrialMonths() int32 {
+	if x != nil {
+		return x.TrialMonths
+	}
+	return 0
+}
+
+func (x *CreateSubscriptionRequest) GetTrialPrice() int64 {
+	if x != nil {
+		return x.TrialPrice
+	}
+	return 0
+}
+
+func (x *CreateSubscriptionRequest) GetServiceId() string {
+	if x != nil && x.ServiceId != nil {
+		return *x.ServiceId
+	}
+	return ""
+}
+
+func (x *CreateSubscriptionRequest) GetPlanId() string {
+	if x != nil && x.PlanId != nil {
+		return *x.PlanId
+	}
+	return ""
+}
+
+func (x *CreateSubscriptionRequest) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *CreateSubscriptionRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *CreateSubscriptionRequest) GetAccountLabel() string {
+	if x != nil {
+		return x.AccountLabel
+	}
+	return ""
+}
+
+func (x *CreateSubscriptionRequest) GetBillingDay() int32 {
+	if x != nil {
+		return x.BillingDay
+	}
+	return 0
+}
+
+func (x *CreateSubscriptionRequest) GetPaymentMethodId() string {
+	if x != nil && x.PaymentMethodId != nil {
+		return *x.PaymentMethodId
+	}
+	return ""
+}
+
+type GetSubscriptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSubscriptionRequest) Reset() {
+	*x = GetSubscriptionRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSubscriptionRequest) ProtoMessage() {}
+
+func (x *GetSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*GetSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetSubscriptionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListSubscriptionsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Subscriptions fetched per page, 100 by default.
+	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Maximum number of subscriptions to stream, all by default.
+	Limit int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Start date and ID of the last subscription already received, to resume a list.
+	AfterStartDate *Month  `protobuf:"bytes,3,opt,name=after_start_date,json=afterStartDate,proto3,oneof" json:"after_start_date,omitempty"`
+	AfterId        *string `protobuf:"bytes,4,opt,name=after_id,json=afterId,proto3,oneof" json:"after_id,omitempty"`
+	// Only subscriptions having all of the tags.
+	Tags          []string `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSubscriptionsRequest) Reset() {
+	*x = ListSubscriptionsRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSubscriptionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubscriptionsRequest) ProtoMessage() {}
+
+func (x *ListSubscriptionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubscriptionsRequest.ProtoReflect.Descriptor instead.
+func (*ListSubscriptionsRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListSubscriptionsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListSubscriptionsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListSubscriptionsRequest) GetAfterStartDate() *Month {
+	if x != nil {
+		return x.AfterStartDate
+	}
+	return nil
+}
+
+func (x *ListSubscriptionsRequest) GetAfterId() string {
+	if x != nil && x.AfterId != nil {
+		return *x.AfterId
+	}
+	return ""
+}
+
+func (x *ListSubscriptionsRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+// UpdateSubscriptionRequest changes the set fields, at least one is required.
+type UpdateSubscriptionRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ServiceName *string                `protobuf:"bytes,2,opt,name=service_name,json=serviceName,proto3,oneof" json:"service_name,omitempty"`
+	Price       *int64                 `protobuf:"varint,3,opt,name=price,proto3,oneof" json:"price,omitempty"`
+	EndDate     *Month                 `protobuf:"bytes,4,opt,name=end_date,json=endDate,proto3,oneof" json:"end_date,omitempty"`
+	TrialMonths *int32                 `protobuf:"varint,5,opt,name=trial_months,json=trialMonths,proto3,oneof" json:"trial_months,omitempty"`
+	TrialPrice  *int64                 `protobuf:"varint,6,opt,name=trial_price,json=trialPrice,proto3,oneof" json:"trial_price,omitempty"`
+	ServiceId   *string                `protobuf:"bytes,7,opt,name=service_id,json=serviceId,proto3,oneof" json:"service_id,omitempty"`
+	// Plan from the start date, the price defaults to its monthly price.
+	PlanId *string `protobuf:"bytes,8,opt,name=plan_id,json=planId,proto3,oneof" json:"plan_id,omitempty"`
+	// An empty category clears it.
+	Category *string `protobuf:"bytes,9,opt,name=category,proto3,oneof" json:"category,omitempty"`
+	// Tags to add and remove, the other tags are kept.
+	AddTags    []string `protobuf:"bytes,10,rep,name=add_tags,json=addTags,proto3" json:"add_tags,omitempty"`
+	RemoveTags []string `protobuf:"bytes,11,rep,name=remove_tags,json=removeTags,proto3" json:"remove_tags,omitempty"`
+	// An empty label clears it.
+	AccountLabel *string `protobuf:"bytes,12,opt,name=account_label,json=accountLabel,proto3,oneof" json:"account_label,omitempty"`
+	BillingDay   *int32  `protobuf:"varint,13,opt,name=billing_day,json=billingDay,proto3,oneof" json:"billing_day,omitempty"`
+	// An empty payment method unlinks the subscription from it.
+	PaymentMethodId *string `protobuf:"bytes,14,opt,name=payment_method_id,json=paymentMethodId,proto3,oneof" json:"payment_method_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UpdateSubscriptionRequest) Reset() {
+	*x = UpdateSubscriptionRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateSubscriptionRequest) ProtoMessage() {}
+
+func (x *UpdateSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*UpdateSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *UpdateSubscriptionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateSubscriptionRequest) GetServiceName() string {
+	if x != nil && x.ServiceName != nil {
+		return *x.ServiceName
+	}
+	return ""
+}
+
+func (x *UpdateSubscriptionRequest) GetPrice() int64 {
+	if x != nil && x.Price != nil {
+		return *x.Price
+	}
+	return 0
+}
+
+func (x *UpdateSubscriptionRequest) GetEndDate() *Month {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+func (x *UpdateSubscriptionRequest) GetTrialMonths() int32 {
+	if x != nil && x.TrialMonths != nil {
+		return *x.TrialMonths
+	}
+	return 0
+}
+
+func (x *UpdateSubscriptionRequest) GetTrialPrice() int64 {
+	if x != nil && x.TrialPrice != nil {
+		return *x.TrialPrice
+	}
+	return 0
+}
+
+func (x *UpdateSubscriptionRequest) GetServiceId() string {
+	if x != nil && x.ServiceId != nil {
+		return *x.ServiceId
+	}
+	return ""
+}
+
+func (x *UpdateSubscriptionRequest) GetPlanId() string {
+	if x != nil && x.PlanId != nil {
+		return *x.PlanId
+	}
+	return ""
+}
+
+func (x *UpdateSubscriptionRequest) GetCategory() string {
+	if x != nil && x.Category != nil {
+		return *x.Category
+	}
+	return ""
+}
+
+func (x *UpdateSubscriptionRequest) GetAddTags() []string {
+	if x != nil {
+		return x.AddTags
+	}
+	return nil
+}
+
+func (x *UpdateSubscriptionRequest) GetRemoveTags() []string {
+	if x != nil {
+		return x.RemoveTags
+	}
+	return nil
+}
+
+func (x *UpdateSubscriptionRequest) GetAccountLabel() string {
+	if x != nil && x.AccountLabel != nil {
+		return *x.AccountLabel
+	}
+	return ""
+}
+
+func (x *UpdateSubscriptionRequest) GetBillingDay() int32 {
+	if x != nil && x.BillingDay != nil {
+		return *x.BillingDay
+	}
+	return 0
+}
+
+func (x *UpdateSubscriptionRequest) GetPaymentMethodId() string {
+	if x != nil && x.PaymentMethodId != nil {
+		return *x.PaymentMethodId
+	}
+	return ""
+}
+
+type PauseSubscriptionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// First paused month, the current month by default.
+	StartDate *Month `protobuf:"bytes,2,opt,name=start_date,json=startDate,proto3,oneof" json:"start_date,omitempty"`
+	// Last paused month, unset to pause until resumed.
+	EndDate       *Month `protobuf:"bytes,3,opt,name=end_date,json=endDate,proto3,oneof" json:"end_date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseSubscriptionRequest) Reset() {
+	*x = PauseSubscriptionRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseSubscriptionRequest) ProtoMessage() {}
+
+func (x *PauseSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*PauseSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PauseSubscriptionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PauseSubscriptionRequest) GetStartDate() *Month {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *PauseSubscriptionRequest) GetEndDate() *Month {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+type ResumeSubscriptionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// First month billed again, the current month by default.
+	Month         *Month `protobuf:"bytes,2,opt,name=month,proto3,oneof" json:"month,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeSubscriptionRequest) Reset() {
+	*x = ResumeSubscriptionRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeSubscriptionRequest) ProtoMessage() {}
+
+func (x *ResumeSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*ResumeSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ResumeSubscriptionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ResumeSubscriptionRequest) GetMonth() *Month {
+	if x != nil {
+		return x.Month
+	}
+	return nil
+}
+
+type ChangePlanRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	PlanId string                 `protobuf:"bytes,2,opt,name=plan_id,json=planId,proto3" json:"plan_id,omitempty"`
+	// Monthly price, the monthly price of the plan by default.
+	Price *int64 `protobuf:"varint,3,opt,name=price,proto3,oneof" json:"price,omitempty"`
+	// First month of the new plan, the current month by default.
+	StartDate     *Month `protobuf:"bytes,4,opt,name=start_date,json=startDate,proto3,oneof" json:"start_date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangePlanRequest) Reset() {
+	*x = ChangePlanRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePlanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePlanRequest) ProtoMessage() {}
+
+func (x *ChangePlanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePlanRequest.ProtoReflect.Descriptor instead.
+func (*ChangePlanRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ChangePlanRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ChangePlanRequest) GetPlanId() string {
+	if x != nil {
+		return x.PlanId
+	}
+	return ""
+}
+
+func (x *ChangePlanRequest) GetPrice() int64 {
+	if x != nil && x.Price != nil {
+		return *x.Price
+	}
+	return 0
+}
+
+func (x *ChangePlanRequest) GetStartDate() *Month {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+type ListPlanChangesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPlanChangesRequest) Reset() {
+	*x = ListPlanChangesRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPlanChangesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPlanChangesRequest) ProtoMessage() {}
+
+func (x *ListPlanChangesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPlanChangesRequest.ProtoReflect.Descriptor instead.
+func (*ListPlanChangesRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListPlanChangesRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type PlanChange struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Unset if the plan was deleted from the catalog.
+	PlanId *string `protobuf:"bytes,2,opt,name=plan_id,json=planId,proto3,oneof" json:"plan_id,omitempty"`
+	Price  int64   `protobuf:"varint,3,opt,name=price,proto3" json:"price,omitempty"`
+	// First month of the plan.
+	StartDate     *Month `protobuf:"bytes,4,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlanChange) Reset() {
+	*x = PlanChange{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlanChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlanChange) ProtoMessage() {}
+
+func (x *PlanChange) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlanChange.ProtoReflect.Descriptor instead.
+func (*PlanChange) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *PlanChange) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PlanChange) GetPlanId() string {
+	if x != nil && x.PlanId != nil {
+		return *x.PlanId
+	}
+	return ""
+}
+
+func (x *PlanChange) GetPrice() int64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *PlanChange) GetStartDate() *Month {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+type ListPlanChangesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// In chronological order.
+	Changes       []*PlanChange `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPlanChangesResponse) Reset() {
+	*x = ListPlanChangesResponse{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPlanChangesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPlanChangesResponse) ProtoMessage() {}
+
+func (x *ListPlanChangesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPlanChangesResponse.ProtoReflect.Descriptor instead.
+func (*ListPlanChangesResponse) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListPlanChangesResponse) GetChanges() []*PlanChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+type ScheduleChangeRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Action ScheduledAction        `protobuf:"varint,2,opt,name=action,proto3,enum=subscription.v1.ScheduledAction" json:"action,omitempty"`
+	// Last billed month of a cancellation or first month of a price change.
+	Month *Month `protobuf:"bytes,3,opt,name=month,proto3" json:"month,omitempty"`
+	// Monthly price, required for SCHEDULED_ACTION_PRICE.
+	Price         *int64 `protobuf:"varint,4,opt,name=price,proto3,oneof" json:"price,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduleChangeRequest) Reset() {
+	*x = ScheduleChangeRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduleChangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduleChangeRequest) ProtoMessage() {}
+
+func (x *ScheduleChangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduleChangeRequest.ProtoReflect.Descriptor instead.
+func (*ScheduleChangeRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ScheduleChangeRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ScheduleChangeRequest) GetAction() ScheduledAction {
+	if x != nil {
+		return x.Action
+	}
+	return ScheduledAction_SCHEDULED_ACTION_UNSPECIFIED
+}
+
+func (x *ScheduleChangeRequest) GetMonth() *Month {
+	if x != nil {
+		return x.Month
+	}
+	return nil
+}
+
+func (x *ScheduleChangeRequest) GetPrice() int64 {
+	if x != nil && x.Price != nil {
+		return *x.Price
+	}
+	return 0
+}
+
+type ScheduledChange struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	SubscriptionId string                 `protobuf:"bytes,2,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	Action         ScheduledAction        `protobuf:"varint,3,opt,name=action,proto3,enum=subscription.v1.ScheduledAction" json:"action,omitempty"`
+	Month          *Month                 `protobuf:"bytes,4,opt,name=month,proto3" json:"month,omitempty"`
+	// Set for SCHEDULED_ACTION_PRICE.
+	Price     *int64                 `protobuf:"varint,5,opt,name=price,proto3,oneof" json:"price,omitempty"`
+	Status    ScheduledChange_Status `protobuf:"varint,6,opt,name=status,proto3,enum=subscription.v1.ScheduledChange_Status" json:"status,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// Set once the scheduler has applied the change.
+	AppliedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=applied_at,json=appliedAt,proto3" json:"applied_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduledChange) Reset() {
+	*x = ScheduledChange{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduledChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduledChange) ProtoMessage() {}
+
+func (x *ScheduledChange) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduledChange.ProtoReflect.Descriptor instead.
+func (*ScheduledChange) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ScheduledChange) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ScheduledChange) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+func (x *ScheduledChange) GetAction() ScheduledAction {
+	if x != nil {
+		return x.Action
+	}
+	return ScheduledAction_SCHEDULED_ACTION_UNSPECIFIED
+}
+
+func (x *ScheduledChange) GetMonth() *Month {
+	if x != nil {
+		return x.Month
+	}
+	return nil
+}
+
+func (x *ScheduledChange) GetPrice() int64 {
+	if x != nil && x.Price != nil {
+		return *x.Price
+	}
+	return 0
+}
+
+func (x *ScheduledChange) GetStatus() ScheduledChange_Status {
+	if x != nil {
+		return x.Status
+	}
+	return ScheduledChange_STATUS_UNSPECIFIED
+}
+
+func (x *ScheduledChange) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ScheduledChange) GetAppliedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AppliedAt
+	}
+	return nil
+}
+
+type ListScheduledChangesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListScheduledChangesRequest) Reset() {
+	*x = ListScheduledChangesRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListScheduledChangesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListScheduledChangesRequest) ProtoMessage() {}
+
+func (x *ListScheduledChangesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListScheduledChangesRequest.ProtoReflect.Descriptor instead.
+func (*ListScheduledChangesRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListScheduledChangesRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListScheduledChangesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// By month.
+	Changes       []*ScheduledChange `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListScheduledChangesResponse) Reset() {
+	*x = ListScheduledChangesResponse{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListScheduledChangesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListScheduledChangesResponse) ProtoMessage() {}
+
+func (x *ListScheduledChangesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListScheduledChangesResponse.ProtoReflect.Descriptor instead.
+func (*ListScheduledChangesResponse) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListScheduledChangesResponse) GetChanges() []*ScheduledChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+type CancelScheduledChangeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ChangeId      string                 `protobuf:"bytes,2,opt,name=change_id,json=changeId,proto3" json:"change_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelScheduledChangeRequest) Reset() {
+	*x = CancelScheduledChangeRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelScheduledChangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelScheduledChangeRequest) ProtoMessage() {}
+
+func (x *CancelScheduledChangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelScheduledChangeRequest.ProtoReflect.Descriptor instead.
+func (*CancelScheduledChangeRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CancelScheduledChangeRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CancelScheduledChangeRequest) GetChangeId() string {
+	if x != nil {
+		return x.ChangeId
+	}
+	return ""
+}
+
+type CancelScheduledChangeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelScheduledChangeResponse) Reset() {
+	*x = CancelScheduledChangeResponse{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelScheduledChangeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelScheduledChangeResponse) ProtoMessage() {}
+
+func (x *CancelScheduledChangeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelScheduledChangeResponse.ProtoReflect.Descriptor instead.
+func (*CancelScheduledChangeResponse) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{17}
+}
+
+type Member struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Percent or rubles a month by the split rule, unused for SPLIT_RULE_EQUAL.
+	Share int64 `protobuf:"varint,2,opt,name=share,proto3" json:"share,omitempty"`
+	// Share of the current price, set in responses.
+	CurrentShare  int64 `protobuf:"varint,3,opt,name=current_share,json=currentShare,proto3" json:"current_share,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Member) Reset() {
+	*x = Member{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Member) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Member) ProtoMessage() {}
+
+func (x *Member) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Member.ProtoReflect.Descriptor instead.
+func (*Member) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Member) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Member) GetShare() int64 {
+	if x != nil {
+		return x.Share
+	}
+	return 0
+}
+
+func (x *Member) GetCurrentShare() int64 {
+	if x != nil {
+		return x.CurrentShare
+	}
+	return 0
+}
+
+type GetMembersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMembersRequest) Reset() {
+	*x = GetMembersRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMembersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMembersRequest) ProtoMessage() {}
+
+func (x *GetMembersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMembersRequest.ProtoReflect.Descriptor instead.
+func (*GetMembersRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetMembersRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type SetMembersRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	SplitRule SplitRule              `protobuf:"varint,2,opt,name=split_rule,json=splitRule,proto3,enum=subscription.v1.SplitRule" json:"split_rule,omitempty"`
+	// Members other than the owner, empty to stop sharing.
+	Members       []*Member `protobuf:"bytes,3,rep,name=members,proto3" json:"members,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetMembersRequest) Reset() {
+	*x = SetMembersRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetMembersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetMembersRequest) ProtoMessage() {}
+
+func (x *SetMembersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetMembersRequest.ProtoReflect.Descriptor instead.
+func (*SetMembersRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *SetMembersRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SetMembersRequest) GetSplitRule() SplitRule {
+	if x != nil {
+		return x.SplitRule
+	}
+	return SplitRule_SPLIT_RULE_UNSPECIFIED
+}
+
+func (x *SetMembersRequest) GetMembers() []*Member {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+type Members struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	SplitRule SplitRule              `protobuf:"varint,1,opt,name=split_rule,json=splitRule,proto3,enum=subscription.v1.SplitRule" json:"split_rule,omitempty"`
+	OwnerId   string                 `protobuf:"bytes,2,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	// Share of the current price paid by the owner.
+	OwnerShare int64 `protobuf:"varint,3,opt,name=owner_share,json=ownerShare,proto3" json:"owner_share,omitempty"`
+	// Ordered by user ID.
+	Members       []*Member `protobuf:"bytes,4,rep,name=members,proto3" json:"members,omitempty"`
+	CurrentPrice  int64     `protobuf:"varint,5,opt,name=current_price,json=currentPrice,proto3" json:"current_price,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Members) Reset() {
+	*x = Members{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Members) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Members) ProtoMessage() {}
+
+func (x *Members) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Members.ProtoReflect.Descriptor instead.
+func (*Members) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *Members) GetSplitRule() SplitRule {
+	if x != nil {
+		return x.SplitRule
+	}
+	return SplitRule_SPLIT_RULE_UNSPECIFIED
+}
+
+func (x *Members) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+func (x *Members) GetOwnerShare() int64 {
+	if x != nil {
+		return x.OwnerShare
+	}
+	return 0
+}
+
+func (x *Members) GetMembers() []*Member {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+func (x *Members) GetCurrentPrice() int64 {
+	if x != nil {
+		return x.CurrentPrice
+	}
+	return 0
+}
+
+type DeleteSubscriptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSubscriptionRequest) Reset() {
+	*x = DeleteSubscriptionRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSubscriptionRequest) ProtoMessage() {}
+
+func (x *DeleteSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*DeleteSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *DeleteSubscriptionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteSubscriptionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSubscriptionResponse) Reset() {
+	*x = DeleteSubscriptionResponse{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSubscriptionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSubscriptionResponse) ProtoMessage() {}
+
+func (x *DeleteSubscriptionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSubscriptionResponse.ProtoReflect.Descriptor instead.
+func (*DeleteSubscriptionResponse) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{23}
+}
+
+// GetTotalCostRequest filters subscriptions and sets the period, both bounds inclusive.
+type GetTotalCostRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId *string                `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3,oneof" json:"user_id,omitempty"`
+	// Partial match.
+	ServiceName *string `protobuf:"bytes,2,opt,name=service_name,json=serviceName,proto3,oneof" json:"service_name,omitempty"`
+	// Defaults to the start of each subscription.
+	StartDate *Month `protobuf:"bytes,3,opt,name=start_date,json=startDate,proto3,oneof" json:"start_date,omitempty"`
+	// Defaults to the current month.
+	EndDate   *Month  `protobuf:"bytes,4,opt,name=end_date,json=endDate,proto3,oneof" json:"end_date,omitempty"`
+	ServiceId *string `protobuf:"bytes,5,opt,name=service_id,json=serviceId,proto3,oneof" json:"service_id,omitempty"`
+	// Only subscriptions having all of the tags.
+	Tags []string `protobuf:"bytes,6,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Ignoring case.
+	Category      *string `protobuf:"bytes,7,opt,name=category,proto3,oneof" json:"category,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTotalCostRequest) Reset() {
+	*x = GetTotalCostRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTotalCostRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTotalCostRequest) ProtoMessage() {}
+
+func (x *GetTotalCostRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTotalCostRequest.ProtoReflect.Descriptor instead.
+func (*GetTotalCostRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetTotalCostRequest) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+func (x *GetTotalCostRequest) GetServiceName() string {
+	if x != nil && x.ServiceName != nil {
+		return *x.ServiceName
+	}
+	return ""
+}
+
+func (x *GetTotalCostRequest) GetStartDate() *Month {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *GetTotalCostRequest) GetEndDate() *Month {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+func (x *GetTotalCostRequest) GetServiceId() string {
+	if x != nil && x.ServiceId != nil {
+		return *x.ServiceId
+	}
+	return ""
+}
+
+func (x *GetTotalCostRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *GetTotalCostRequest) GetCategory() string {
+	if x != nil && x.Category != nil {
+		return *x.Category
+	}
+	return ""
+}
+
+type GetTotalCostResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TotalCost     int64                  `protobuf:"varint,1,opt,name=total_cost,json=totalCost,proto3" json:"total_cost,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTotalCostResponse) Reset() {
+	*x = GetTotalCostResponse{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTotalCostResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTotalCostResponse) ProtoMessage() {}
+
+func (x *GetTotalCostResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTotalCostResponse.ProtoReflect.Descriptor instead.
+func (*GetTotalCostResponse) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetTotalCostResponse) GetTotalCost() int64 {
+	if x != nil {
+		return x.TotalCost
+	}
+	return 0
+}
+
+type WatchSubscriptionsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only changes of this user's subscriptions. Non-admin callers always watch their own.
+	UserId        *string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3,oneof" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchSubscriptionsRequest) Reset() {
+	*x = WatchSubscriptionsRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchSubscriptionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchSubscriptionsRequest) ProtoMessage() {}
+
+func (x *WatchSubscriptionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchSubscriptionsRequest.ProtoReflect.Descriptor instead.
+func (*WatchSubscriptionsRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *WatchSubscriptionsRequest) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+type SubscriptionEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Type  SubscriptionEvent_Type `protobuf:"varint,1,opt,name=type,proto3,enum=subscription.v1.SubscriptionEvent_Type" json:"type,omitempty"`
+	// State after the change, or before it for deletions.
+	Subscription  *Subscription          `protobuf:"bytes,2,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=time,proto3" json:"time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscriptionEvent) Reset() {
+	*x = SubscriptionEvent{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscriptionEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscriptionEvent) ProtoMessage() {}
+
+func (x *SubscriptionEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscriptionEvent.ProtoReflect.Descriptor instead.
+func (*SubscriptionEvent) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *SubscriptionEvent) GetType() SubscriptionEvent_Type {
+	if x != nil {
+		return x.Type
+	}
+	return SubscriptionEvent_TYPE_UNSPECIFIED
+}
+
+func (x *SubscriptionEvent) GetSubscription() *Subscription {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+func (x *SubscriptionEvent) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+var File_subscription_v1_subscription_proto protoreflect.FileDescriptor
+
+const file_subscription_v1_subscription_proto_rawDesc = "" +
+	"\n" +
+	"\"subscription/v1/subscription.proto\x12\x0fsubscription.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"1\n" +
+	"\x05Month\x12\x12\n" +
+	"\x04year\x18\x01 \x01(\x05R\x04year\x12\x14\n" +
+	"\x05month\x18\x02 \x01(\x05R\x05month\"\xd5\x06\n" +
+	"\fSubscription\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12!\n" +
+	"\fservice_name\x18\x03 \x01(\tR\vserviceName\x12\x14\n" +
+	"\x05price\x18\x04 \x01(\x03R\x05price\x125\n" +
+	"\n" +
+	"start_date\x18\x05 \x01(\v2\x16.subscription.v1.MonthR\tstartDate\x126\n" +
+	"\bend_date\x18\x06 \x01(\v2\x16.subscription.v1.MonthH\x00R\aendDate\x88\x01\x01\x12!\n" +
+	"\ftrial_months\x18\a \x01(\x05R\vtrialMonths\x12\x1f\n" +
+	"\vtrial_price\x18\b \x01(\x03R\n" +
+	"trialPrice\x12A\n" +
+	"\x0etrial_end_date\x18\t \x01(\v2\x16.subscription.v1.MonthH\x01R\ftrialEndDate\x88\x01\x01\x129\n" +
+	"\x05state\x18\n" +
+	" \x01(\x0e2#.subscription.v1.Subscription.StateR\x05state\x12\"\n" +
+	"\n" +
+	"service_id\x18\v \x01(\tH\x02R\tserviceId\x88\x01\x01\x12\x1c\n" +
+	"\aplan_id\x18\f \x01(\tH\x03R\x06planId\x88\x01\x01\x12#\n" +
+	"\rcurrent_price\x18\r \x01(\x03R\fcurrentPrice\x12\x1a\n" +
+	"\bcategory\x18\x0e \x01(\tR\bcategory\x12\x12\n" +
+	"\x04tags\x18\x0f \x03(\tR\x04tags\x12#\n" +
+	"\raccount_label\x18\x10 \x01(\tR\faccountLabel\x12\x1f\n" +
+	"\vbilling_day\x18\x11 \x01(\x05R\n" +
+	"billingDay\x12/\n" +
+	"\x11payment_method_id\x18\x12 \x01(\tH\x04R\x0fpaymentMethodId\x88\x01\x01\"S\n" +
+	"\x05State\x12\x15\n" +
+	"\x11STATE_UNSPECIFIED\x10\x00\x12\x10\n" +
+	"\fSTATE_ACTIVE\x10\x01\x12\x10\n" +
+	"\fSTATE_PAUSED\x10\x02\x12\x0f\n" +
+	"\vSTATE_ENDED\x10\x03B\v\n" +
+	"\t_end_dateB\x11\n" +
+	"\x0f_trial_end_dateB\r\n" +
+	"\v_service_idB\n" +
+	"\n" +
+	"\b_plan_idB\x14\n" +
+	"\x12_payment_method_id\"\xd6\x04\n" +
+	"\x19CreateSubscriptionRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12!\n" +
+	"\fservice_name\x18\x02 \x01(\tR\vserviceName\x12\x19\n" +
+	"\x05price\x18\x03 \x01(\x03H\x00R\x05price\x88\x01\x01\x125\n" +
+	"\n" +
+	"start_date\x18\x04 \x01(\v2\x16.subscription.v1.MonthR\tstartDate\x126\n" +
+	"\bend_date\x18\x05 \x01(\v2\x16.subscription.v1.MonthH\x01R\aendDate\x88\x01\x01\x12!\n" +
+	"\ftrial_months\x18\x06 \x01(\x05R\vtrialMonths\x12\x1f\n" +
+	"\vtrial_price\x18\a \x01(\x03R\n" +
+	"trialPrice\x12\"\n" +
+	"\n" +
+	"service_id\x18\b \x01(\tH\x02R\tserviceId\x88\x01\x01\x12\x1c\n" +
+	"\aplan_id\x18\t \x01(\tH\x03R\x06planId\x88\x01\x01\x12\x1a\n" +
+	"\bcategory\x18\n" +
+	" \x01(\tR\bcategory\x12\x12\n" +
+	"\x04tags\x18\v \x03(\tR\x04tags\x12#\n" +
+	"\raccount_label\x18\f \x01(\tR\faccountLabel\x12\x1f\n" +
+	"\vbilling_day\x18\r \x01(\x05R\n" +
+	"billingDay\x12/\n" +
+	"\x11payment_method_id\x18\x0e \x01(\tH\x04R\x0fpaymentMethodId\x88\x01\x01B\b\n" +
+	"\x06_priceB\v\n" +
+	"\t_end_dateB\r\n" +
+	"\v_service_idB\n" +
+	"\n" +
+	"\b_plan_idB\x14\n" +
+	"\x12_payment_method_id\"(\n" +
+	"\x16GetSubscriptionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xea\x01\n" +
+	"\x18ListSubscriptionsRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12E\n" +
+	"\x10after_start_date\x18\x03 \x01(\v2\x16.subscription.v1.MonthH\x00R\x0eafterStartDate\x88\x01\x01\x12\x1e\n" +
+	"\bafter_id\x18\x04 \x01(\tH\x01R\aafterId\x88\x01\x01\x12\x12\n" +
+	"\x04tags\x18\x05 \x03(\tR\x04tagsB\x13\n" +
+	"\x11_after_start_dateB\v\n" +
+	"\t_after_id\"\xbd\x05\n" +
+	"\x19UpdateSubscriptionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12&\n" +
+	"\fservice_name\x18\x02 \x01(\tH\x00R\vserviceName\x88\x01\x01\x12\x19\n" +
+	"\x05price\x18\x03 \x01(\x03H\x01R\x05price\x88\x01\x01\x126\n" +
+	"\bend_date\x18\x04 \x01(\v2\x16.subscription.v1.MonthH\x02R\aendDate\x88\x01\x01\x12&\n" +
+	"\ftrial_months\x18\x05 \x01(\x05H\x03R\vtrialMonths\x88\x01\x01\x12$\n" +
+	"\vtrial_price\x18\x06 \x01(\x03H\x04R\n" +
+	"trialPrice\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"service_id\x18\a \x01(\tH\x05R\tserviceId\x88\x01\x01\x12\x1c\n" +
+	"\aplan_id\x18\b \x01(\tH\x06R\x06planId\x88\x01\x01\x12\x1f\n" +
+	"\bcategory\x18\t \x01(\tH\aR\bcategory\x88\x01\x01\x12\x19\n" +
+	"\badd_tags\x18\n" +
+	" \x03(\tR\aaddTags\x12\x1f\n" +
+	"\vremove_tags\x18\v \x03(\tR\n" +
+	"removeTags\x12(\n" +
+	"\raccount_label\x18\f \x01(\tH\bR\faccountLabel\x88\x01\x01\x12$\n" +
+	"\vbilling_day\x18\r \x01(\x05H\tR\n" +
+	"billingDay\x88\x01\x01\x12/\n" +
+	"\x11payment_method_id\x18\x0e \x01(\tH\n" +
+	"R\x0fpaymentMethodId\x88\x01\x01B\x0f\n" +
+	"\r_service_nameB\b\n" +
+	"\x06_priceB\v\n" +
+	"\t_end_dateB\x0f\n" +
+	"\r_trial_monthsB\x0e\n" +
+	"\f_trial_priceB\r\n" +
+	"\v_service_idB\n" +
+	"\n" +
+	"\b_plan_idB\v\n" +
+	"\t_categoryB\x10\n" +
+	"\x0e_account_labelB\x0e\n" +
+	"\f_billing_dayB\x14\n" +
+	"\x12_payment_method_id\"\xba\x01\n" +
+	"\x18PauseSubscriptionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12:\n" +
+	"\n" +
+	"start_date\x18\x02 \x01(\v2\x16.subscription.v1.MonthH\x00R\tstartDate\x88\x01\x01\x126\n" +
+	"\bend_date\x18\x03 \x01(\v2\x16.subscription.v1.MonthH\x01R\aendDate\x88\x01\x01B\r\n" +
+	"\v_start_dateB\v\n" +
+	"\t_end_date\"h\n" +
+	"\x19ResumeSubscriptionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x121\n" +
+	"\x05month\x18\x02 \x01(\v2\x16.subscription.v1.MonthH\x00R\x05month\x88\x01\x01B\b\n" +
+	"\x06_month\"\xac\x01\n" +
+	"\x11ChangePlanRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\aplan_id\x18\x02 \x01(\tR\x06planId\x12\x19\n" +
+	"\x05price\x18\x03 \x01(\x03H\x00R\x05price\x88\x01\x01\x12:\n" +
+	"\n" +
+	"start_date\x18\x04 \x01(\v2\x16.subscription.v1.MonthH\x01R\tstartDate\x88\x01\x01B\b\n" +
+	"\x06_priceB\r\n" +
+	"\v_start_date\"(\n" +
+	"\x16ListPlanChangesRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x93\x01\n" +
+	"\n" +
+	"PlanChange\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1c\n" +
+	"\aplan_id\x18\x02 \x01(\tH\x00R\x06planId\x88\x01\x01\x12\x14\n" +
+	"\x05price\x18\x03 \x01(\x03R\x05price\x125\n" +
+	"\n" +
+	"start_date\x18\x04 \x01(\v2\x16.subscription.v1.MonthR\tstartDateB\n" +
+	"\n" +
+	"\b_plan_id\"P\n" +
+	"\x17ListPlanChangesResponse\x125\n" +
+	"\achanges\x18\x01 \x03(\v2\x1b.subscription.v1.PlanChangeR\achanges\"\xb4\x01\n" +
+	"\x15ScheduleChangeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x128\n" +
+	"\x06action\x18\x02 \x01(\x0e2 .subscription.v1.ScheduledActionR\x06action\x12,\n" +
+	"\x05month\x18\x03 \x01(\v2\x16.subscription.v1.MonthR\x05month\x12\x19\n" +
+	"\x05price\x18\x04 \x01(\x03H\x00R\x05price\x88\x01\x01B\b\n" +
+	"\x06_price\"\xed\x03\n" +
+	"\x0fScheduledChange\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12'\n" +
+	"\x0fsubscription_id\x18\x02 \x01(\tR\x0esubscriptionId\x128\n" +
+	"\x06action\x18\x03 \x01(\x0e2 .subscription.v1.ScheduledActionR\x06action\x12,\n" +
+	"\x05month\x18\x04 \x01(\v2\x16.subscription.v1.MonthR\x05month\x12\x19\n" +
+	"\x05price\x18\x05 \x01(\x03H\x00R\x05price\x88\x01\x01\x12?\n" +
+	"\x06status\x18\x06 \x01(\x0e2'.subscription.v1.ScheduledChange.StatusR\x06status\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"applied_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tappliedAt\"]\n" +
+	"\x06Status\x12\x16\n" +
+	"\x12STATUS_UNSPECIFIED\x10\x00\x12\x12\n" +
+	"\x0eSTATUS_PENDING\x10\x01\x12\x12\n" +
+	"\x0eSTATUS_APPLIED\x10\x02\x12\x13\n" +
+	"\x0fSTATUS_CANCELED\x10\x03B\b\n" +
+	"\x06_price\"-\n" +
+	"\x1bListScheduledChangesRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"Z\n" +
+	"\x1cListScheduledChangesResponse\x12:\n" +
+	"\achanges\x18\x01 \x03(\v2 .subscription.v1.ScheduledChangeR\achanges\"K\n" +
+	"\x1cCancelScheduledChangeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tchange_id\x18\x02 \x01(\tR\bchangeId\"\x1f\n" +
+	"\x1dCancelScheduledChangeResponse\"\\\n" +
+	"\x06Member\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05share\x18\x02 \x01(\x03R\x05share\x12#\n" +
+	"\rcurrent_share\x18\x03 \x01(\x03R\fcurrentShare\"#\n" +
+	"\x11GetMembersRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x91\x01\n" +
+	"\x11SetMembersRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x129\n" +
+	"\n" +
+	"split_rule\x18\x02 \x01(\x0e2\x1a.subscription.v1.SplitRuleR\tsplitRule\x121\n" +
+	"\amembers\x18\x03 \x03(\v2\x17.subscription.v1.MemberR\amembers\"\xd8\x01\n" +
+	"\aMembers\x129\n" +
+	"\n" +
+	"split_rule\x18\x01 \x01(\x0e2\x1a.subscription.v1.SplitRuleR\tsplitRule\x12\x19\n" +
+	"\bowner_id\x18\x02 \x01(\tR\aownerId\x12\x1f\n" +
+	"\vowner_share\x18\x03 \x01(\x03R\n" +
+	"ownerShare\x121\n" +
+	"\amembers\x18\x04 \x03(\v2\x17.subscription.v1.MemberR\amembers\x12#\n" +
+	"\rcurrent_price\x18\x05 \x01(\x03R\fcurrentPrice\"+\n" +
+	"\x19DeleteSubscriptionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x1c\n" +
+	"\x1aDeleteSubscriptionResponse\"\xfd\x02\n" +
+	"\x13GetTotalCostRequest\x12\x1c\n" +
+	"\auser_id\x18\x01 \x01(\tH\x00R\x06userId\x88\x01\x01\x12&\n" +
+	"\fservice_name\x18\x02 \x01(\tH\x01R\vserviceName\x88\x01\x01\x12:\n" +
+	"\n" +
+	"start_date\x18\x03 \x01(\v2\x16.subscription.v1.MonthH\x02R\tstartDate\x88\x01\x01\x126\n" +
+	"\bend_date\x18\x04 \x01(\v2\x16.subscription.v1.MonthH\x03R\aendDate\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"service_id\x18\x05 \x01(\tH\x04R\tserviceId\x88\x01\x01\x12\x12\n" +
+	"\x04tags\x18\x06 \x03(\tR\x04tags\x12\x1f\n" +
+	"\bcategory\x18\a \x01(\tH\x05R\bcategory\x88\x01\x01B\n" +
+	"\n" +
+	"\b_user_idB\x0f\n" +
+	"\r_service_nameB\r\n" +
+	"\v_start_dateB\v\n" +
+	"\t_end_dateB\r\n" +
+	"\v_service_idB\v\n" +
+	"\t_category\"5\n" +
+	"\x14GetTotalCostResponse\x12\x1d\n" +
+	"\n" +
+	"total_cost\x18\x01 \x01(\x03R\ttotalCost\"E\n" +
+	"\x19WatchSubscriptionsRequest\x12\x1c\n" +
+	"\auser_id\x18\x01 \x01(\tH\x00R\x06userId\x88\x01\x01B\n" +
+	"\n" +
+	"\b_user_id\"\x97\x02\n" +
+	"\x11SubscriptionEvent\x12;\n" +
+	"\x04type\x18\x01 \x01(\x0e2'.subscription.v1.SubscriptionEvent.TypeR\x04type\x12A\n" +
+	"\fsubscription\x18\x02 \x01(\v2\x1d.subscription.v1.SubscriptionR\fsubscription\x12.\n" +
+	"\x04time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\"R\n" +
+	"\x04Type\x12\x14\n" +
+	"\x10TYPE_UNSPECIFIED\x10\x00\x12\x10\n" +
+	"\fTYPE_CREATED\x10\x01\x12\x10\n" +
+	"\fTYPE_UPDATED\x10\x02\x12\x10\n" +
+	"\fTYPE_DELETED\x10\x03*l\n" +
+	"\x0fScheduledAction\x12 \n" +
+	"\x1cSCHEDULED_ACTION_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17SCHEDULED_ACTION_CANCEL\x10\x01\x12\x1a\n" +
+	"\x16SCHEDULED_ACTION_PRICE\x10\x02*k\n" +
+	"\tSplitRule\x12\x1a\n" +
+	"\x16SPLIT_RULE_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10SPLIT_RULE_EQUAL\x10\x01\x12\x16\n" +
+	"\x12SPLIT_RULE_PERCENT\x10\x02\x12\x14\n" +
+	"\x10SPLIT_RULE_FIXED\x10\x032\x9f\f\n" +
+	"\x13SubscriptionService\x12_\n" +
+	"\x12CreateSubscription\x12*.subscription.v1.CreateSubscriptionRequest\x1a\x1d.subscription.v1.Subscription\x12Y\n" +
+	"\x0fGetSubscription\x12'.subscription.v1.GetSubscriptionRequest\x1a\x1d.subscription.v1.Subscription\x12_\n" +
+	"\x11ListSubscriptions\x12).subscription.v1.ListSubscriptionsRequest\x1a\x1d.subscription.v1.Subscription0\x01\x12_\n" +
+	"\x12UpdateSubscription\x12*.subscription.v1.UpdateSubscriptionRequest\x1a\x1d.subscription.v1.Subscription\x12m\n" +
+	"\x12DeleteSubscription\x12*.subscription.v1.DeleteSubscriptionRequest\x1a+.subscription.v1.DeleteSubscriptionResponse\x12[\n" +
+	"\fGetTotalCost\x12$.subscription.v1.GetTotalCostRequest\x1a%.subscription.v1.GetTotalCostResponse\x12]\n" +
+	"\x11PauseSubscription\x12).subscription.v1.PauseSubscriptionRequest\x1a\x1d.subscription.v1.Subscription\x12_\n" +
+	"\x12ResumeSubscription\x12*.subscription.v1.ResumeSubscriptionRequest\x1a\x1d.subscription.v1.Subscription\x12O\n" +
+	"\n" +
+	"ChangePlan\x12\".subscription.v1.ChangePlanRequest\x1a\x1d.subscription.v1.Subscription\x12d\n" +
+	"\x0fListPlanChanges\x12'.subscription.v1.ListPlanChangesRequest\x1a(.subscription.v1.ListPlanChangesResponse\x12Z\n" +
+	"\x0eScheduleChange\x12&.subscription.v1.ScheduleChangeRequest\x1a .subscription.v1.ScheduledChange\x12s\n" +
+	"\x14ListScheduledChanges\x12,.subscription.v1.ListScheduledChangesRequest\x1a-.subscription.v1.ListScheduledChangesResponse\x12v\n" +
+	"\x15CancelScheduledChange\x12-.subscription.v1.CancelScheduledChangeRequest\x1a..subscription.v1.CancelScheduledChangeResponse\x12J\n" +
+	"\n" +
+	"GetMembers\x12\".subscription.v1.GetMembersRequest\x1a\x18.subscription.v1.Members\x12J\n" +
+	"\n" +
+	"SetMembers\x12\".subscription.v1.SetMembersRequest\x1a\x18.subscription.v1.Members\x12f\n" +
+	"\x12WatchSubscriptions\x12*.subscription.v1.WatchSubscriptionsRequest\x1a\".subscription.v1.SubscriptionEvent0\x01B\x99\x01\n" +
+	".com.github.trustmeimanengineer.subscription.v1P\x01Zegithub.com/trust-me-im-an-engineer/demo-subscription-agregator/pkg/api/subscription/v1;subscriptionv1b\x06proto3"
+
+var (
+	file_subscription_v1_subscription_proto_rawDescOnce sync.Once
+	file_subscription_v1_subscription_proto_rawDescData []byte
+)
+
+func file_subscription_v1_subscription_proto_rawDescGZIP() []byte {
+	file_subscription_v1_subscription_proto_rawDescOnce.Do(func() {
+		file_subscription_v1_subscription_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_subscription_v1_subscription_proto_rawDesc), len(file_subscription_v1_subscription_proto_rawDesc)))
+	})
+	return file_subscription_v1_subscription_proto_rawDescData
+}
+
+var file_subscription_v1_subscription_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_subscription_v1_subscription_proto_msgTypes = make([]protoimpl.MessageInfo, 28)
+var file_subscription_v1_subscription_proto_goTypes = []any{
+	(ScheduledAction)(0),                  // 0: subscription.v1.ScheduledAction
+	(SplitRule)(0),                        // 1: subscription.v1.SplitRule
+	(Subscription_State)(0),               // 2: subscription.v1.Subscription.State
+	(ScheduledChange_Status)(0),           // 3: subscription.v1.ScheduledChange.Status
+	(SubscriptionEvent_Type)(0),           // 4: subscription.v1.SubscriptionEvent.Type
+	(*Month)(nil),                         // 5: subscription.v1.Month
+	(*Subscription)(nil),                  // 6: subscription.v1.Subscription
+	(*CreateSubscriptionRequest)(nil),     // 7: subscription.v1.CreateSubscriptionRequest
+	(*GetSubscriptionRequest)(nil),        // 8: subscription.v1.GetSubscriptionRequest
+	(*ListSubscriptionsRequest)(nil),      // 9: subscription.v1.ListSubscriptionsRequest
+	(*UpdateSubscriptionRequest)(nil),     // 10: subscription.v1.UpdateSubscriptionRequest
+	(*PauseSubscriptionRequest)(nil),      // 11: subscription.v1.PauseSubscriptionRequest
+	(*ResumeSubscriptionRequest)(nil),     // 12: subscription.v1.ResumeSubscriptionRequest
+	(*ChangePlanRequest)(nil),             // 13: subscription.v1.ChangePlanRequest
+	(*ListPlanChangesRequest)(nil),        // 14: subscription.v1.ListPlanChangesRequest
+	(*PlanChange)(nil),                    // 15: subscription.v1.PlanChange
+	(*ListPlanChangesResponse)(nil),       // 16: subscription.v1.ListPlanChangesResponse
+	(*ScheduleChangeRequest)(nil),         // 17: subscription.v1.ScheduleChangeRequest
+	(*ScheduledChange)(nil),               // 18: subscription.v1.ScheduledChange
+	(*ListScheduledChangesRequest)(nil),   // 19: subscription.v1.ListScheduledChangesRequest
+	(*ListScheduledChangesResponse)(nil),  // 20: subscription.v1.ListScheduledChangesResponse
+	(*CancelScheduledChangeRequest)(nil),  // 21: subscription.v1.CancelScheduledChangeRequest
+	(*CancelScheduledChangeResponse)(nil), // 22: subscription.v1.CancelScheduledChangeResponse
+	(*Member)(nil),                        // 23: subscription.v1.Member
+	(*GetMembersRequest)(nil),             // 24: subscription.v1.GetMembersRequest
+	(*SetMembersRequest)(nil),             // 25: subscription.v1.SetMembersRequest
+	(*Members)(nil),                       // 26: subscription.v1.Members
+	(*DeleteSubscriptionRequest)(nil),     // 27: subscription.v1.DeleteSubscriptionRequest
+	(*DeleteSubscriptionResponse)(nil),    // 28: subscription.v1.DeleteSubscriptionResponse
+	(*GetTotalCostRequest)(nil),           // 29: subscription.v1.GetTotalCostRequest
+	(*GetTotalCostResponse)(nil),          // 30: subscription.v1.GetTotalCostResponse
+	(*WatchSubscriptionsRequest)(nil),     // 31: subscription.v1.WatchSubscriptionsRequest
+	(*SubscriptionEvent)(nil),             // 32: subscription.v1.SubscriptionEvent
+	(*timestamppb.Timestamp)(nil),         // 33: google.protobuf.Timestamp
+}
+var file_subscription_v1_subscription_proto_depIdxs = []int32{
+	5,  // 0: subscription.v1.Subscription.start_date:type_name -> subscription.v1.Month
+	5,  // 1: subscription.v1.Subscription.end_date:type_name -> subscription.v1.Month
+	5,  // 2: subscription.v1.Subscription.trial_end_date:type_name -> subscription.v1.Month
+	2,  // 3: subscription.v1.Subscription.state:type_name -> subscription.v1.Subscription.State
+	5,  // 4: subscription.v1.CreateSubscriptionRequest.start_date:type_name -> subscription.v1.Month
+	5,  // 5: subscription.v1.CreateSubscriptionRequest.end_date:type_name -> subscription.v1.Month
+	5,  // 6: subscription.v1.ListSubscriptionsRequest.after_start_date:type_name -> subscription.v1.Month
+	5,  // 7: subscription.v1.UpdateSubscriptionRequest.end_date:type_name -> subscription.v1.Month
+	5,  // 8: subscription.v1.PauseSubscriptionRequest.start_date:type_name -> subscription.v1.Month
+	5,  // 9: subscription.v1.PauseSubscriptionRequest.end_date:type_name -> subscription.v1.Month
+	5,  // 10: subscription.v1.ResumeSubscriptionRequest.month:type_name -> subscription.v1.Month
+	5,  // 11: subscription.v1.ChangePlanRequest.start_date:type_name -> subscription.v1.Month
+	5,  // 12: subscription.v1.PlanChange.start_date:type_name -> subscription.v1.Month
+	15, // 13: subscription.v1.ListPlanChangesResponse.changes:type_name -> subscription.v1.PlanChange
+	0,  // 14: subscription.v1.ScheduleChangeRequest.action:type_name -> subscription.v1.ScheduledAction
+	5,  // 15: subscription.v1.ScheduleChangeRequest.month:type_name -> subscription.v1.Month
+	0,  // 16: subscription.v1.ScheduledChange.action:type_name -> subscription.v1.ScheduledAction
+	5,  // 17: subscription.v1.ScheduledChange.month:type_name -> subscription.v1.Month
+	3,  // 18: subscription.v1.ScheduledChange.status:type_name -> subscription.v1.ScheduledChange.Status
+	33, // 19: subscription.v1.ScheduledChange.created_at:type_name -> google.protobuf.Timestamp
+	33, // 20: subscription.v1.ScheduledChange.applied_at:type_name -> google.protobuf.Timestamp
+	18, // 21: subscription.v1.ListScheduledChangesResponse.changes:type_name -> subscription.v1.ScheduledChange
+	1,  // 22: subscription.v1.SetMembersRequest.split_rule:type_name -> subscription.v1.SplitRule
+	23, // 23: subscription.v1.SetMembersRequest.members:type_name -> subscription.v1.Member
+	1,  // 24: subscription.v1.Members.split_rule:type_name -> subscription.v1.SplitRule
+	23, // 25: subscription.v1.Members.members:type_name -> subscription.v1.Member
+	5,  // 26: subscription.v1.GetTotalCostRequest.start_date:type_name -> subscription.v1.Month
+	5,  // 27: subscription.v1.GetTotalCostRequest.end_date:type_name -> subscription.v1.Month
+	4,  // 28: subscription.v1.SubscriptionEvent.type:type_name -> subscription.v1.SubscriptionEvent.Type
+	6,  // 29: subscription.v1.SubscriptionEvent.subscription:type_name -> subscription.v1.Subscription
+	33, // 30: subscription.v1.SubscriptionEvent.time:type_name -> google.protobuf.Timestamp
+	7,  // 31: subscription.v1.SubscriptionService.CreateSubscription:input_type -> subscription.v1.CreateSubscriptionRequest
+	8,  // 32: subscription.v1.SubscriptionService.GetSubscription:input_type -> subscription.v1.GetSubscriptionRequest
+	9,  // 33: subscription.v1.SubscriptionService.ListSubscriptions:input_type -> subscription.v1.ListSubscriptionsRequest
+	10, // 34: subscription.v1.SubscriptionService.UpdateSubscription:input_type -> subscription.v1.UpdateSubscriptionRequest
+	27, // 35: subscription.v1.SubscriptionService.DeleteSubscription:input_type -> subscription.v1.DeleteSubscriptionRequest
+	29, // 36: subscription.v1.SubscriptionService.GetTotalCost:input_type -> subscription.v1.GetTotalCostRequest
+	11, // 37: subscription.v1.SubscriptionService.PauseSubscription:input_type -> subscription.v1.PauseSubscriptionRequest
+	12, // 38: subscription.v1.SubscriptionService.ResumeSubscription:input_type -> subscription.v1.ResumeSubscriptionRequest
+	13, // 39: subscription.v1.SubscriptionService.ChangePlan:input_type -> subscription.v1.ChangePlanRequest
+	14, // 40: subscription.v1.SubscriptionService.ListPlanChanges:input_type -> subscription.v1.ListPlanChangesRequest
+	17, // 41: subscription.v1.SubscriptionService.ScheduleChange:input_type -> subscription.v1.ScheduleChangeRequest
+	19, // 42: subscription.v1.SubscriptionService.ListScheduledChanges:input_type -> subscription.v1.ListScheduledChangesRequest
+	21, // 43: subscription.v1.SubscriptionService.CancelScheduledChange:input_type -> subscription.v1.CancelScheduledChangeRequest
+	24, // 44: subscription.v1.SubscriptionService.GetMembers:input_type -> subscription.v1.GetMembersRequest
+	25, // 45: subscription.v1.SubscriptionService.SetMembers:input_type -> subscription.v1.SetMembersRequest
+	31, // 46: subscription.v1.SubscriptionService.WatchSubscriptions:input_type -> subscription.v1.WatchSubscriptionsRequest
+	6,  // 47: subscription.v1.SubscriptionService.CreateSubscription:output_type -> subscription.v1.Subscription
+	6,  // 48: subscription.v1.SubscriptionService.GetSubscription:output_type -> subscription.v1.Subscription
+	6,  // 49: subscription.v1.SubscriptionService.ListSubscriptions:output_type -> subscription.v1.Subscription
+	6,  // 50: subscription.v1.SubscriptionService.UpdateSubscription:output_type -> subscription.v1.Subscription
+	28, // 51: subscription.v1.SubscriptionService.DeleteSubscription:output_type -> subscription.v1.DeleteSubscriptionResponse
+	30, // 52: subscription.v1.SubscriptionService.GetTotalCost:output_type -> subscription.v1.GetTotalCostResponse
+	6,  // 53: subscription.v1.SubscriptionService.PauseSubscription:output_type -> subscription.v1.Subscription
+	6,  // 54: subscription.v1.SubscriptionService.ResumeSubscription:output_type -> subscription.v1.Subscription
+	6,  // 55: subscription.v1.SubscriptionService.ChangePlan:output_type -> subscription.v1.Subscription
+	16, // 56: subscription.v1.SubscriptionService.ListPlanChanges:output_type -> subscription.v1.ListPlanChangesResponse
+	18, // 57: subscription.v1.SubscriptionService.ScheduleChange:output_type -> subscription.v1.ScheduledChange
+	20, // 58: subscription.v1.SubscriptionService.ListScheduledChanges:output_type -> subscription.v1.ListScheduledChangesResponse
+	22, // 59: subscription.v1.SubscriptionService.CancelScheduledChange:output_type -> subscription.v1.CancelScheduledChangeResponse
+	26, // 60: subscription.v1.SubscriptionService.GetMembers:output_type -> subscription.v1.Members
+	26, // 61: subscription.v1.SubscriptionService.SetMembers:output_type -> subscription.v1.Members
+	32, // 62: subscription.v1.SubscriptionService.WatchSubscriptions:output_type -> subscription.v1.SubscriptionEvent
+	47, // [47:63] is the sub-list for method output_type
+	31, // [31:47] is the sub-list for method input_type
+	31, // [31:31] is the sub-list for extension type_name
+	31, // [31:31] is the sub-list for extension extendee
+	0,  // [0:31] is the sub-list for field type_name
+}
+
+func init() { file_subscription_v1_subscription_proto_init() }
+func file_subscription_v1_subscription_proto_init() {
+	if File_subscription_v1_subscription_proto != nil {
+		return
+	}
+	file_subscription_v1_subscription_proto_msgTypes[1].OneofWrappers = []any{}
+	file_subscription_v1_subscription_proto_msgTypes[2].OneofWrappers = []any{}
+	file_subscription_v1_subscription_proto_msgTypes[4].OneofWrappers = []any{}
+	file_subscription_v1_subscription_proto_msgTypes[5].OneofWrappers = []any{}
+	file_subscription_v1_subscription_proto_msgTypes[6].OneofWrappers = []any{}
+	file_subscription_v1_subscription_proto_msgTypes[7].OneofWrappers = []any{}
+	file_subscription_v1_subscription_proto_msgTypes[8].OneofWrappers = []any{}
+	file_subscription_v1_subscription_proto_msgTypes[10].OneofWrappers = []any{}
+	file_subscription_v1_subscription_proto_msgTypes[12].OneofWrappers = []any{}
+	file_subscription_v1_subscription_proto_msgTypes[13].OneofWrappers = []any{}
+	file_subscription_v1_subscription_proto_msgTypes[24].OneofWrappers = []any{}
+	file_subscription_v1_subscription_proto_msgTypes[26].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_subscription_v1_subscription_proto_rawDesc), len(file_subscription_v1_subscription_proto_rawDesc)),
+			NumEnums:      5,
+			NumMessages:   28,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_subscription_v1_subscription_proto_goTypes,
+		DependencyIndexes: file_subscription_v1_subscription_proto_depIdxs,
+		EnumInfos:         file_subscription_v1_subscription_proto_enumTypes,
+		MessageInfos:      file_subscription_v1_subscription_proto_msgTypes,
+	}.Build()
+	File_subscription_v1_subscription_proto = out.File
+	file_subscription_v1_subscription_proto_goTypes = nil
+	file_subscription_v1_subscription_proto_depIdxs = nil
+}